@@ -54,6 +54,12 @@ type EnvironmentSpec struct {
 
 	// RemoteCluster flag indicates if the Environment is deployed in a separate cluster to the Development Environment
 	RemoteCluster bool `json:"remoteCluster,omitempty" protobuf:"bytes,12,opt,name=remoteCluster"`
+
+	// IssueTransition is the status, such as "Deployed to Staging", issues are moved to when promoted to this Environment. It overrides the IssueTransitions of the team settings
+	IssueTransition string `json:"issueTransition,omitempty" protobuf:"bytes,13,opt,name=issueTransition"`
+
+	// Protected flag indicates releases are only promoted to the Environment if their images have no critical vulnerabilities
+	Protected bool `json:"protected,omitempty" protobuf:"bytes,14,opt,name=protected"`
 }
 
 // EnvironmentStatus is the status for an Environment resource
@@ -250,6 +256,79 @@ type TeamSettings struct {
 
 	// DeployOptions configures options for how to deploy applications by default such as using canary rollouts (progressive delivery) or using horizontal pod autoscaler
 	DeployOptions *DeployOptions `json:"deployOptions,omitempty" protobuf:"bytes,32,opt,name=deployOptions"`
+
+	// ChatSettings configures the chat channel the team is notified on about failed pipelines and promotions
+	ChatSettings *ChatSettings `json:"chat,omitempty" protobuf:"bytes,33,opt,name=chat"`
+
+	// IssueTransitions maps the names of Environments to the status, such as "Done", issues are moved to when promoted to them
+	IssueTransitions map[string]string `json:"issueTransitions,omitempty" protobuf:"bytes,34,rep,name=issueTransitions"`
+
+	// PipelineEvents configures where changes to PipelineActivities and Releases are sent
+	PipelineEvents *PipelineEventsSettings `json:"pipelineEvents,omitempty" protobuf:"bytes,35,opt,name=pipelineEvents"`
+
+	// CVEScanner configures the scanner used to find the vulnerabilities of images
+	CVEScanner *CVEScannerSettings `json:"cveScanner,omitempty" protobuf:"bytes,36,opt,name=cveScanner"`
+
+	// LogMasking configures the masking of secrets in build logs
+	LogMasking *LogMaskingSettings `json:"logMasking,omitempty" protobuf:"bytes,37,opt,name=logMasking"`
+}
+
+// ChatSettings the chat service and channel used to notify the team
+type ChatSettings struct {
+	// Kind the kind of chat provider such as slack
+	Kind string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+
+	// URL the URL of the chat server
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+
+	// Channel the channel to post notifications to
+	Channel string `json:"channel,omitempty" protobuf:"bytes,3,opt,name=channel"`
+
+	// PromotionChannel the channel to post promotion notifications to. Defaults to Channel if not specified
+	PromotionChannel string `json:"promotionChannel,omitempty" protobuf:"bytes,4,opt,name=promotionChannel"`
+}
+
+// PipelineEventsSettings the sink changes to PipelineActivities and Releases are sent to
+type PipelineEventsSettings struct {
+	// Kind the kind of sink such as elasticsearch, cloudevents, webhook or kafka
+	Kind string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+
+	// URL the URL events are sent to. For kafka this is the URL of a Kafka REST proxy
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+
+	// Topic the Kafka topic events are produced to
+	Topic string `json:"topic,omitempty" protobuf:"bytes,3,opt,name=topic"`
+
+	// Source the source of CloudEvents
+	Source string `json:"source,omitempty" protobuf:"bytes,4,opt,name=source"`
+
+	// MaxRetries how many times sending an event is retried before it is dead lettered. Defaults to 5
+	MaxRetries int32 `json:"maxRetries,omitempty" protobuf:"bytes,5,opt,name=maxRetries"`
+
+	// DeadLetterURL the URL events which could not be sent are posted to as JSON
+	DeadLetterURL string `json:"deadLetterUrl,omitempty" protobuf:"bytes,6,opt,name=deadLetterUrl"`
+}
+
+// CVEScannerSettings the scanner used to find the Common Vulnerabilities and Exposures (CVEs) of images
+type CVEScannerSettings struct {
+	// Kind the kind of scanner such as anchore, trivy or clair. Defaults to anchore
+	Kind string `json:"kind,omitempty" protobuf:"bytes,1,opt,name=kind"`
+
+	// URL the URL of the scanner server. Defaults to the URL of the anchore addon service
+	URL string `json:"url,omitempty" protobuf:"bytes,2,opt,name=url"`
+
+	// Severity the lowest severity of vulnerabilities which fail a pipeline scanning an image. Defaults to High
+	Severity string `json:"severity,omitempty" protobuf:"bytes,3,opt,name=severity"`
+}
+
+// LogMaskingSettings contains the settings for masking secrets in build logs in addition to the values of the
+// Secrets in the namespace of the build and the well-known secret formats
+type LogMaskingSettings struct {
+	// Patterns regular expressions matching secrets to mask
+	Patterns []string `json:"patterns,omitempty" protobuf:"bytes,1,rep,name=patterns"`
+
+	// Secrets the names of secrets in the secret store, such as Vault, whose values are masked
+	Secrets []string `json:"secrets,omitempty" protobuf:"bytes,2,rep,name=secrets"`
 }
 
 // StorageLocation
@@ -417,6 +496,14 @@ func (t *TeamSettings) GetDeployOptions() DeployOptions {
 	return DeployOptions{}
 }
 
+// GetPromotionChannel returns the channel to notify about promotions
+func (c *ChatSettings) GetPromotionChannel() string {
+	if c.PromotionChannel != "" {
+		return c.PromotionChannel
+	}
+	return c.Channel
+}
+
 // DefaultMissingValues defaults any missing values
 func (t *TeamSettings) DefaultMissingValues() {
 	if t.BuildPackURL == "" {
@@ -122,4 +122,20 @@ const (
 const (
 	FactTypeCoverage              = "jx.coverage"
 	FactTypeStaticProgramAnalysis = "jx.staticProgramAnalysis"
+	FactTypeCVE                   = "jx.cve"
 )
+
+// Recommended labels of Facts about a pipeline
+const (
+	FactLabelSubjectKind  = "subjectkind"
+	FactLabelPipelineName = "pipelineName"
+	FactLabelOrg          = "org"
+	FactLabelRepo         = "repo"
+	FactLabelBranch       = "branch"
+	FactLabelBuildNumber  = "buildNumber"
+)
+
+// CVEStatementSeverityThreshold the statement of a CVE scan which is true if no vulnerability of the image is at or
+// above the severity threshold in the statement type. The measurements of the scan count the vulnerabilities of each
+// severity
+const CVEStatementSeverityThreshold = "SeverityThreshold"
@@ -126,6 +126,43 @@ type PromoteActivityStep struct {
 	PullRequest    *PromotePullRequestStep `json:"pullRequest,omitempty" protobuf:"bytes,2,opt,name=pullRequest"`
 	Update         *PromoteUpdateStep      `json:"update,omitempty" protobuf:"bytes,3,opt,name=update"`
 	ApplicationURL string                  `json:"applicationURL,omitempty" protobuf:"bytes,4,opt,name=environment"`
+
+	// VulnerabilityCheck the check of the vulnerabilities of the release before promoting it to a protected Environment
+	VulnerabilityCheck *PromoteVulnerabilityCheck `json:"vulnerabilityCheck,omitempty" protobuf:"bytes,5,opt,name=vulnerabilityCheck"`
+}
+
+// VulnerabilityCheckDecisionType is the decision of a vulnerability check
+type VulnerabilityCheckDecisionType string
+
+const (
+	// VulnerabilityCheckDecisionAllowed the images have no vulnerabilities at or above the severity
+	VulnerabilityCheckDecisionAllowed VulnerabilityCheckDecisionType = "Allowed"
+	// VulnerabilityCheckDecisionDenied the promotion was refused as the images have vulnerabilities at or above the
+	// severity or could not be checked
+	VulnerabilityCheckDecisionDenied VulnerabilityCheckDecisionType = "Denied"
+	// VulnerabilityCheckDecisionOverridden the promotion would have been denied but the check was overridden
+	VulnerabilityCheckDecisionOverridden VulnerabilityCheckDecisionType = "Overridden"
+)
+
+// PromoteVulnerabilityCheck records the vulnerabilities found in the images of a release before promoting it
+type PromoteVulnerabilityCheck struct {
+	// Decision whether the promotion was allowed, denied or the check overridden
+	Decision VulnerabilityCheckDecisionType `json:"decision,omitempty" protobuf:"bytes,1,opt,name=decision"`
+
+	// Severity the lowest severity of vulnerabilities which deny the promotion
+	Severity string `json:"severity,omitempty" protobuf:"bytes,2,opt,name=severity"`
+
+	// Source where the vulnerabilities were found, either the Facts of the pipeline or the kind of CVE provider
+	Source string `json:"source,omitempty" protobuf:"bytes,3,opt,name=source"`
+
+	// Images the images of the release which were checked
+	Images []string `json:"images,omitempty" protobuf:"bytes,4,rep,name=images"`
+
+	// Vulnerabilities the number of vulnerabilities at or above the severity found in the images
+	Vulnerabilities int32 `json:"vulnerabilities,omitempty" protobuf:"bytes,5,opt,name=vulnerabilities"`
+
+	// Message describes the decision
+	Message string `json:"message,omitempty" protobuf:"bytes,6,opt,name=message"`
 }
 
 // GitStatus the status of a git commit in terms of CI/CD
@@ -201,6 +238,8 @@ const (
 	ActivityStatusTypeAborted ActivityStatusType = "Aborted"
 	// ActivityStatusTypeNotExecuted if the workflow was not executed
 	ActivityStatusTypeNotExecuted ActivityStatusType = "NotExecuted"
+	// ActivityStatusTypeSkipped if a stage was skipped because its when conditions were not met
+	ActivityStatusTypeSkipped ActivityStatusType = "Skipped"
 )
 
 type Attachment struct {
@@ -210,7 +249,7 @@ type Attachment struct {
 
 // IsTerminated returns true if this activity has stopped executing
 func (s ActivityStatusType) IsTerminated() bool {
-	return s == ActivityStatusTypeSucceeded || s == ActivityStatusTypeFailed || s == ActivityStatusTypeError || s == ActivityStatusTypeAborted
+	return s == ActivityStatusTypeSucceeded || s == ActivityStatusTypeFailed || s == ActivityStatusTypeError || s == ActivityStatusTypeAborted || s == ActivityStatusTypeSkipped
 }
 
 func (s ActivityStatusType) String() string {
@@ -29,7 +29,8 @@ type PipelineStructure struct {
 // to the stage if it has steps, a list of sequential stage names nested within this stage, or a list of parallel stage
 // names nested within this stage, and information on this stage's depth within the PipelineStructure as a whole, the
 // name of its parent stage, if any, the name of the stage before it in execution order, if any, and the name of the
-// stage after it in execution order, if any.
+// stage after it in execution order, if any. Stages which were skipped because their when conditions weren't met
+// have no Task and are marked as skipped.
 type PipelineStructureStage struct {
 	Name string `json:"name" protobuf:"bytes,1,opt,name=name"`
 
@@ -51,6 +52,8 @@ type PipelineStructureStage struct {
 	Previous *string `json:"previous,omitempty" protobuf:"bytes,8,opt,name=previous"`
 	// +optional
 	Next *string `json:"next,omitempty" protobuf:"bytes,9,opt,name=next"`
+	// +optional
+	Skipped bool `json:"skipped,omitempty" protobuf:"bytes,10,opt,name=skipped"`
 }
 
 // GetStage will get the PipelineStructureStage with the given name, if it exists.
@@ -119,7 +122,7 @@ func (ps *PipelineStructure) GetAllStagesWithSteps() []PipelineStructureStage {
 	var stages []PipelineStructureStage
 
 	for _, s := range ps.Stages {
-		if len(s.Stages) == 0 && len(s.Parallel) == 0 {
+		if len(s.Stages) == 0 && len(s.Parallel) == 0 && !s.Skipped {
 			stages = append(stages, s)
 		}
 	}
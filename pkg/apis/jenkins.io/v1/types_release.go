@@ -84,6 +84,8 @@ type CommitSummary struct {
 	Committer *UserDetails `json:"committer,omitempty"  protobuf:"bytes,5,opt,name=committer"`
 	Branch    string       `json:"branch,omitempty"  protobuf:"bytes,6,opt,name=branch"`
 	IssueIDs  []string     `json:"issueIds,omitempty"  protobuf:"bytes,7,opt,name=issueIds"`
+	// Timestamp is when the commit was committed
+	Timestamp *metav1.Time `json:"timestamp,omitempty"  protobuf:"bytes,8,opt,name=timestamp"`
 }
 
 // ReleaseStatusType is the status of a release; usually deployed or failed at completion
@@ -365,6 +365,38 @@ func (in *ChartRef) DeepCopy() *ChartRef {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CVEScannerSettings) DeepCopyInto(out *CVEScannerSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CVEScannerSettings.
+func (in *CVEScannerSettings) DeepCopy() *CVEScannerSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(CVEScannerSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ChatSettings) DeepCopyInto(out *ChatSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ChatSettings.
+func (in *ChatSettings) DeepCopy() *ChatSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(ChatSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CommitStatus) DeepCopyInto(out *CommitStatus) {
 	*out = *in
@@ -521,6 +553,10 @@ func (in *CommitSummary) DeepCopyInto(out *CommitSummary) {
 		*out = make([]string, len(*in))
 		copy(*out, *in)
 	}
+	if in.Timestamp != nil {
+		in, out := &in.Timestamp, &out.Timestamp
+		*out = (*in).DeepCopy()
+	}
 	return
 }
 
@@ -1748,6 +1784,32 @@ func (in *Lgtm) DeepCopy() *Lgtm {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *LogMaskingSettings) DeepCopyInto(out *LogMaskingSettings) {
+	*out = *in
+	if in.Patterns != nil {
+		in, out := &in.Patterns, &out.Patterns
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Secrets != nil {
+		in, out := &in.Secrets, &out.Secrets
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new LogMaskingSettings.
+func (in *LogMaskingSettings) DeepCopy() *LogMaskingSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(LogMaskingSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Measurement) DeepCopyInto(out *Measurement) {
 	*out = *in
@@ -2068,6 +2130,22 @@ func (in *PipelineActivityStep) DeepCopy() *PipelineActivityStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PipelineEventsSettings) DeepCopyInto(out *PipelineEventsSettings) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PipelineEventsSettings.
+func (in *PipelineEventsSettings) DeepCopy() *PipelineEventsSettings {
+	if in == nil {
+		return nil
+	}
+	out := new(PipelineEventsSettings)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PipelineExtension) DeepCopyInto(out *PipelineExtension) {
 	*out = *in
@@ -2558,6 +2636,11 @@ func (in *PromoteActivityStep) DeepCopyInto(out *PromoteActivityStep) {
 		*out = new(PromoteUpdateStep)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.VulnerabilityCheck != nil {
+		in, out := &in.VulnerabilityCheck, &out.VulnerabilityCheck
+		*out = new(PromoteVulnerabilityCheck)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2610,6 +2693,27 @@ func (in *PromoteUpdateStep) DeepCopy() *PromoteUpdateStep {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PromoteVulnerabilityCheck) DeepCopyInto(out *PromoteVulnerabilityCheck) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PromoteVulnerabilityCheck.
+func (in *PromoteVulnerabilityCheck) DeepCopy() *PromoteVulnerabilityCheck {
+	if in == nil {
+		return nil
+	}
+	out := new(PromoteVulnerabilityCheck)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PromoteWorkflowStep) DeepCopyInto(out *PromoteWorkflowStep) {
 	*out = *in
@@ -3634,6 +3738,33 @@ func (in *TeamSettings) DeepCopyInto(out *TeamSettings) {
 		*out = new(DeployOptions)
 		**out = **in
 	}
+	if in.ChatSettings != nil {
+		in, out := &in.ChatSettings, &out.ChatSettings
+		*out = new(ChatSettings)
+		**out = **in
+	}
+	if in.IssueTransitions != nil {
+		in, out := &in.IssueTransitions, &out.IssueTransitions
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.PipelineEvents != nil {
+		in, out := &in.PipelineEvents, &out.PipelineEvents
+		*out = new(PipelineEventsSettings)
+		**out = **in
+	}
+	if in.CVEScanner != nil {
+		in, out := &in.CVEScanner, &out.CVEScanner
+		*out = new(CVEScannerSettings)
+		**out = **in
+	}
+	if in.LogMasking != nil {
+		in, out := &in.LogMasking, &out.LogMasking
+		*out = new(LogMaskingSettings)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -0,0 +1,164 @@
+// Package buildnum contains stuff to do with generating build numbers.
+package buildnum
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	v1 "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/typed/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+const (
+	// BuildNumberConfigMapPrefix the prefix of the names of the ConfigMaps storing the last build number of a pipeline
+	BuildNumberConfigMapPrefix = "jx-build-number-"
+
+	// BuildNumberConfigMapKey the key of the last build number in the ConfigMap of a pipeline
+	BuildNumberConfigMapKey = "build"
+
+	// PipelineConfigMapKey the key of the pipeline ID in the ConfigMap of a pipeline
+	PipelineConfigMapKey = "pipeline"
+
+	// DefaultMaxAttempts the default number of times a conflicting build number update is retried
+	DefaultMaxAttempts = 100
+
+	// maxConfigMapNameLength the maximum length of the name of a ConfigMap
+	maxConfigMapNameLength = 253
+
+	// configMapNameHashLength the number of hex digits of the hash of the pipeline ID in the name of its ConfigMap
+	configMapNameHashLength = 16
+)
+
+// ConfigMapBuildNumIssuer issues build numbers by atomically incrementing a counter stored in a ConfigMap per pipeline,
+// so that pipelines do not contend with each other. Concurrent issuers are safe as the counter is updated using
+// optimistic concurrency, retrying on conflicts. Build numbers are only unique if all the builds of a pipeline get
+// their numbers from this issuer.
+type ConfigMapBuildNumIssuer struct {
+	configMaps typedcorev1.ConfigMapInterface
+	activities v1.PipelineActivityInterface
+	// MaxAttempts the number of times an update conflicting with another issuer is retried
+	MaxAttempts int
+}
+
+// NewConfigMapBuildNumIssuer creates a new ConfigMapBuildNumIssuer storing its counters in the given namespace.
+// Pipelines without a counter yet continue from the highest build number of their PipelineActivities.
+func NewConfigMapBuildNumIssuer(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) *ConfigMapBuildNumIssuer {
+	return &ConfigMapBuildNumIssuer{
+		configMaps:  kubeClient.CoreV1().ConfigMaps(ns),
+		activities:  jxClient.JenkinsV1().PipelineActivities(ns),
+		MaxAttempts: DefaultMaxAttempts,
+	}
+}
+
+// Ready returns true as the issuer reads the counters from the ConfigMaps on each request.
+func (g *ConfigMapBuildNumIssuer) Ready() bool {
+	return true
+}
+
+// NextBuildNumber returns the next build number for the specified pipeline ID, incrementing the counter in its
+// ConfigMap. Returns the build number, or an error if the counter could not be updated.
+func (g *ConfigMapBuildNumIssuer) NextBuildNumber(pipeline kube.PipelineID) (string, error) {
+	name := ConfigMapName(pipeline.ID)
+	for i := 0; i < g.MaxAttempts; i++ {
+		cm, err := g.configMaps.Get(name, metav1.GetOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return "", errors.Wrapf(err, "getting ConfigMap %s", name)
+		}
+		if err != nil {
+			cm = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: name,
+					Labels: map[string]string{
+						kube.LabelKind:      kube.ValueKindBuildNumber,
+						kube.LabelCreatedBy: kube.ValueCreatedByJX,
+					},
+				},
+			}
+		} else {
+			cm = cm.DeepCopy()
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+
+		last, err := g.lastBuildNumber(cm, pipeline)
+		if err != nil {
+			return "", err
+		}
+		next := strconv.Itoa(last + 1)
+		cm.Data[PipelineConfigMapKey] = pipeline.ID
+		cm.Data[BuildNumberConfigMapKey] = next
+
+		if cm.ResourceVersion == "" {
+			_, err = g.configMaps.Create(cm)
+		} else {
+			_, err = g.configMaps.Update(cm)
+		}
+		if err == nil {
+			return next, nil
+		}
+		if !apierrors.IsConflict(err) && !apierrors.IsAlreadyExists(err) {
+			return "", errors.Wrapf(err, "saving build number %s for pipeline %s", next, pipeline.ID)
+		}
+		log.Logger().Debugf("Build number %s for pipeline %s was issued concurrently, retrying", next, pipeline.ID)
+	}
+	return "", errors.Errorf("failed to issue a build number for pipeline %s after %d conflicting attempts", pipeline.ID, g.MaxAttempts)
+}
+
+// lastBuildNumber returns the last build number issued for the pipeline, falling back to the highest build number of
+// its PipelineActivities when there is no counter for it yet.
+func (g *ConfigMapBuildNumIssuer) lastBuildNumber(cm *corev1.ConfigMap, pipeline kube.PipelineID) (int, error) {
+	value, ok := cm.Data[BuildNumberConfigMapKey]
+	if ok {
+		last, err := strconv.Atoi(value)
+		if err != nil {
+			return 0, errors.Wrapf(err, "parsing build number %s of pipeline %s in ConfigMap %s", value, pipeline.ID, cm.Name)
+		}
+		return last, nil
+	}
+
+	activities, err := g.activities.List(metav1.ListOptions{})
+	if err != nil {
+		return 0, errors.Wrap(err, "listing PipelineActivities")
+	}
+	calc := buildNumCalc{pipeline: pipeline}
+	for i := range activities.Items {
+		calc.processPipelineActivity(&activities.Items[i])
+	}
+	return calc.lastBuildNum, nil
+}
+
+// ConfigMapName returns the name of the ConfigMap storing the counter of the pipeline with the given ID, such as
+// myorg/myapp/master. Only lower case alphanumerics and '-' are kept from the ID so its hash is appended to keep the
+// names of different pipelines distinct, truncating IDs which would be too long for a name.
+func ConfigMapName(pipelineID string) string {
+	name := strings.Builder{}
+	for _, r := range strings.ToLower(pipelineID) {
+		if r >= 'a' && r <= 'z' || r >= '0' && r <= '9' {
+			name.WriteRune(r)
+		} else {
+			name.WriteRune('-')
+		}
+	}
+	hash := fmt.Sprintf("%x", sha256.Sum256([]byte(pipelineID)))[:configMapNameHashLength]
+	answer := name.String()
+	maxLength := maxConfigMapNameLength - len(BuildNumberConfigMapPrefix) - len(hash) - 1
+	if len(answer) > maxLength {
+		answer = answer[:maxLength]
+	}
+	answer = strings.Trim(answer, "-")
+	if answer == "" {
+		return BuildNumberConfigMapPrefix + hash
+	}
+	return BuildNumberConfigMapPrefix + answer + "-" + hash
+}
@@ -0,0 +1,226 @@
+// +build unit
+
+package buildnum
+
+import (
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	jxfake "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
+	kubefake "k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "jx"
+
+// newOptimisticKubeClient returns a fake kube client storing ConfigMaps which rejects updates with a stale
+// resourceVersion, like the API server does, as the fake object tracker does not check it.
+func newOptimisticKubeClient() *kubefake.Clientset {
+	client := kubefake.NewSimpleClientset()
+	gr := schema.GroupResource{Resource: "configmaps"}
+	mutex := &sync.Mutex{}
+	configMaps := map[string]*corev1.ConfigMap{}
+
+	client.PrependReactor("get", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		name := action.(k8stesting.GetAction).GetName()
+		cm, ok := configMaps[name]
+		if !ok {
+			return true, nil, apierrors.NewNotFound(gr, name)
+		}
+		return true, cm.DeepCopy(), nil
+	})
+	client.PrependReactor("create", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		cm := action.(k8stesting.CreateAction).GetObject().(*corev1.ConfigMap).DeepCopy()
+		if _, ok := configMaps[cm.Name]; ok {
+			return true, nil, apierrors.NewAlreadyExists(gr, cm.Name)
+		}
+		cm.ResourceVersion = "1"
+		configMaps[cm.Name] = cm
+		return true, cm.DeepCopy(), nil
+	})
+	client.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+		cm := action.(k8stesting.UpdateAction).GetObject().(*corev1.ConfigMap).DeepCopy()
+		existing, ok := configMaps[cm.Name]
+		if !ok {
+			return true, nil, apierrors.NewNotFound(gr, cm.Name)
+		}
+		if cm.ResourceVersion != existing.ResourceVersion {
+			return true, nil, apierrors.NewConflict(gr, cm.Name, nil)
+		}
+		version, _ := strconv.Atoi(existing.ResourceVersion)
+		cm.ResourceVersion = strconv.Itoa(version + 1)
+		configMaps[cm.Name] = cm
+		return true, cm.DeepCopy(), nil
+	})
+	return client
+}
+
+func TestConfigMapBuildNumIssuerConcurrentIssuers(t *testing.T) {
+	kubeClient := newOptimisticKubeClient()
+	jxClient := jxfake.NewSimpleClientset()
+
+	pipelines := []kube.PipelineID{
+		kube.NewPipelineID("owner1", "repo1", "master"),
+		kube.NewPipelineID("owner1", "repo2", "PR-1"),
+	}
+	issuers := 10
+	buildsPerIssuer := 20
+
+	mutex := &sync.Mutex{}
+	issued := map[string][]string{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < issuers; i++ {
+		// each issuer simulates a separate controller or webhook replica
+		issuer := NewConfigMapBuildNumIssuer(kubeClient, jxClient, testNamespace)
+		issuer.MaxAttempts = 10000
+		for _, pipeline := range pipelines {
+			wg.Add(1)
+			go func(pipeline kube.PipelineID) {
+				defer wg.Done()
+				for j := 0; j < buildsPerIssuer; j++ {
+					build, err := issuer.NextBuildNumber(pipeline)
+					assert.NoError(t, err)
+					mutex.Lock()
+					issued[pipeline.ID] = append(issued[pipeline.ID], build)
+					mutex.Unlock()
+				}
+			}(pipeline)
+		}
+	}
+	wg.Wait()
+
+	for _, pipeline := range pipelines {
+		builds := issued[pipeline.ID]
+		require.Len(t, builds, issuers*buildsPerIssuer, "pipeline %s", pipeline.ID)
+
+		seen := map[string]bool{}
+		for _, build := range builds {
+			assert.False(t, seen[build], "build number %s was issued twice for pipeline %s", build, pipeline.ID)
+			seen[build] = true
+		}
+		for n := 1; n <= issuers*buildsPerIssuer; n++ {
+			assert.True(t, seen[strconv.Itoa(n)], "build number %d was skipped for pipeline %s", n, pipeline.ID)
+		}
+	}
+
+	for _, pipeline := range pipelines {
+		cm, err := kubeClient.CoreV1().ConfigMaps(testNamespace).Get(ConfigMapName(pipeline.ID), metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, pipeline.ID, cm.Data[PipelineConfigMapKey])
+		assert.Equal(t, strconv.Itoa(issuers*buildsPerIssuer), cm.Data[BuildNumberConfigMapKey])
+	}
+}
+
+func TestConfigMapBuildNumIssuerContinuesFromActivities(t *testing.T) {
+	pipeline := kube.NewPipelineID("owner1", "repo1", "master")
+	activities := []runtime.Object{}
+	for _, build := range []string{"3", "7", "5"} {
+		activities = append(activities, &v1.PipelineActivity{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      pipeline.GetActivityName(build),
+				Namespace: testNamespace,
+			},
+			Spec: v1.PipelineActivitySpec{
+				Pipeline: pipeline.ID,
+				Build:    build,
+			},
+		})
+	}
+	issuer := NewConfigMapBuildNumIssuer(newOptimisticKubeClient(), jxfake.NewSimpleClientset(activities...), testNamespace)
+
+	build, err := issuer.NextBuildNumber(pipeline)
+	require.NoError(t, err)
+	assert.Equal(t, "8", build)
+
+	build, err = issuer.NextBuildNumber(pipeline)
+	require.NoError(t, err)
+	assert.Equal(t, "9", build)
+
+	build, err = issuer.NextBuildNumber(kube.NewPipelineID("owner1", "repo1", "PR-2"))
+	require.NoError(t, err)
+	assert.Equal(t, "1", build)
+}
+
+func TestConfigMapBuildNumIssuerGivesUpAfterMaxAttempts(t *testing.T) {
+	pipeline := kube.NewPipelineID("owner1", "repo1", "master")
+	name := ConfigMapName(pipeline.ID)
+	kubeClient := kubefake.NewSimpleClientset(&corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            name,
+			Namespace:       testNamespace,
+			ResourceVersion: "1",
+		},
+		Data: map[string]string{
+			BuildNumberConfigMapKey: "1",
+		},
+	})
+	attempts := 0
+	kubeClient.PrependReactor("update", "configmaps", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		attempts++
+		return true, nil, apierrors.NewConflict(schema.GroupResource{Resource: "configmaps"}, name, nil)
+	})
+	issuer := NewConfigMapBuildNumIssuer(kubeClient, jxfake.NewSimpleClientset(), testNamespace)
+	issuer.MaxAttempts = 3
+
+	_, err := issuer.NextBuildNumber(pipeline)
+	assert.Error(t, err)
+	assert.Equal(t, 3, attempts)
+}
+
+func TestConfigMapBuildNumIssuerKeepsPipelinesWithTheSameNameApart(t *testing.T) {
+	first := kube.NewPipelineID("my-org", "app", "master")
+	second := kube.NewPipelineID("my", "org-app", "master")
+	require.Equal(t, first.Name, second.Name)
+	issuer := NewConfigMapBuildNumIssuer(newOptimisticKubeClient(), jxfake.NewSimpleClientset(), testNamespace)
+
+	for _, expected := range []string{"1", "2"} {
+		build, err := issuer.NextBuildNumber(first)
+		require.NoError(t, err)
+		assert.Equal(t, expected, build)
+	}
+	build, err := issuer.NextBuildNumber(second)
+	require.NoError(t, err)
+	assert.Equal(t, "1", build)
+}
+
+func TestConfigMapName(t *testing.T) {
+	ids := []string{
+		"myorg/myapp/master",
+		"MyOrg/myapp/master",
+		"MyOrg/my_app/feature/new-ui",
+		"my_org/app/master",
+		"my/org_app/master",
+		"___/___/___",
+		"myorg/myapp/" + strings.Repeat("long-branch/", 30),
+	}
+	names := map[string]string{}
+	for _, id := range ids {
+		name := ConfigMapName(id)
+		assert.Empty(t, validation.IsDNS1123Subdomain(name), "invalid name %s for pipeline %s", name, id)
+		assert.True(t, strings.HasPrefix(name, BuildNumberConfigMapPrefix), "name %s for pipeline %s", name, id)
+		if other, ok := names[name]; ok {
+			t.Errorf("pipelines %s and %s have the same ConfigMap %s", other, id, name)
+		}
+		names[name] = id
+	}
+	assert.Equal(t, ConfigMapName("myorg/myapp/master"), ConfigMapName("myorg/myapp/master"))
+	assert.Regexp(t, "^jx-build-number-myorg-myapp-master-[0-9a-f]{16}$", ConfigMapName("myorg/myapp/master"))
+}
@@ -1,7 +1,12 @@
 // Package buildnum contains stuff to do with generating build numbers.
 package buildnum
 
-import "github.com/jenkins-x/jx/v2/pkg/kube"
+import (
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"k8s.io/client-go/kubernetes"
+)
 
 // BuildNumberIssuer generates build numbers for activities.
 //go:generate pegomock generate github.com/jenkins-x/jx/v2/pkg/buildnum BuildNumberIssuer -o mocks/build_num.go
@@ -14,3 +19,26 @@ type BuildNumberIssuer interface {
 	// Ready returns true if the generator is ready to generate build numbers, otherwise false.
 	Ready() bool
 }
+
+const (
+	// IssuerConfigMap issues build numbers from counters stored in a ConfigMap per pipeline. The numbers are only unique
+	// if every build of a pipeline gets its number from the issuer.
+	IssuerConfigMap = "configmap"
+	// IssuerActivity issues build numbers from the existing PipelineActivities
+	IssuerActivity = "activity"
+)
+
+// BuildNumberIssuers the supported kinds of build number issuer
+var BuildNumberIssuers = []string{IssuerConfigMap, IssuerActivity}
+
+// NewBuildNumberIssuer creates the build number issuer of the given kind for the pipelines of the namespace
+func NewBuildNumberIssuer(kind string, kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string) (BuildNumberIssuer, error) {
+	switch kind {
+	case IssuerConfigMap:
+		return NewConfigMapBuildNumIssuer(kubeClient, jxClient, ns), nil
+	case IssuerActivity:
+		return NewCRDBuildNumGen(jxClient, ns), nil
+	default:
+		return nil, util.InvalidOption("issuer", kind, BuildNumberIssuers)
+	}
+}
@@ -0,0 +1,79 @@
+// Package buildnum contains stuff to do with generating build numbers.
+package buildnum
+
+import (
+	"context"
+	"time"
+
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	// LeaderElectionConfigMapName the name of the ConfigMap used to elect the build number server leader
+	LeaderElectionConfigMapName = "jx-build-numbers-leader"
+
+	leaseDuration = 15 * time.Second
+	renewDeadline = 10 * time.Second
+	retryPeriod   = 2 * time.Second
+)
+
+// RunAsLeader blocks until the given identity is elected leader among the build number servers of the namespace and
+// then invokes run, returning its result. An error is returned if the leadership is lost, so the caller can exit and
+// be restarted.
+func RunAsLeader(kubeClient kubernetes.Interface, ns string, identity string, run func() error) error {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: kubeClient.CoreV1().Events(ns)})
+	recorder := broadcaster.NewRecorder(scheme.Scheme, corev1.EventSource{Component: LeaderElectionConfigMapName})
+
+	lock, err := resourcelock.New(resourcelock.ConfigMapsResourceLock, ns, LeaderElectionConfigMapName, kubeClient.CoreV1(),
+		resourcelock.ResourceLockConfig{
+			Identity:      identity,
+			EventRecorder: recorder,
+		})
+	if err != nil {
+		return errors.Wrap(err, "creating the leader election lock")
+	}
+
+	lost := make(chan struct{})
+	done := make(chan error, 1)
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:          lock,
+		LeaseDuration: leaseDuration,
+		RenewDeadline: renewDeadline,
+		RetryPeriod:   retryPeriod,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(ctx context.Context) {
+				log.Logger().Infof("Elected leader as %s", identity)
+				done <- run()
+			},
+			OnStoppedLeading: func() {
+				close(lost)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					log.Logger().Infof("Build numbers are served by leader %s", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return errors.Wrap(err, "creating the leader elector")
+	}
+
+	log.Logger().Infof("Waiting to be elected leader as %s", identity)
+	go elector.Run(context.Background())
+	select {
+	case err = <-done:
+		return err
+	case <-lost:
+		return errors.Errorf("%s lost the build number server leadership", identity)
+	}
+}
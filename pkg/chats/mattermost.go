@@ -0,0 +1,106 @@
+package chats
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// MattermostChatProvider posts messages to Mattermost via an incoming webhook
+type MattermostChatProvider struct {
+	Client     *http.Client
+	Server     *auth.AuthServer
+	UserAuth   *auth.UserAuth
+	WebhookURL string
+}
+
+// mattermostPayload is the Slack compatible payload of a Mattermost incoming webhook
+type mattermostPayload struct {
+	Channel     string                 `json:"channel,omitempty"`
+	Text        string                 `json:"text,omitempty"`
+	Attachments []mattermostAttachment `json:"attachments,omitempty"`
+}
+
+type mattermostAttachment struct {
+	Fallback  string            `json:"fallback,omitempty"`
+	Color     string            `json:"color,omitempty"`
+	Title     string            `json:"title,omitempty"`
+	TitleLink string            `json:"title_link,omitempty"`
+	Text      string            `json:"text,omitempty"`
+	Footer    string            `json:"footer,omitempty"`
+	Fields    []mattermostField `json:"fields,omitempty"`
+}
+
+type mattermostField struct {
+	Title string `json:"title"`
+	Value string `json:"value"`
+	Short bool   `json:"short"`
+}
+
+// CreateMattermostChatProvider creates a chat provider for Mattermost. The API token of the user is the key of the
+// incoming webhook (or its full URL)
+func CreateMattermostChatProvider(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool) (ChatProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.IsInvalid() || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No incoming webhook found for Mattermost server %s", u)
+	}
+	return &MattermostChatProvider{
+		Client:     util.GetClient(),
+		Server:     server,
+		UserAuth:   userAuth,
+		WebhookURL: incomingWebhookURL(server, userAuth, "hooks"),
+	}, nil
+}
+
+// GetChannelMetrics is not supported as incoming webhooks cannot query channels
+func (c *MattermostChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, error) {
+	return nil, fmt.Errorf("channel metrics are not supported for Mattermost server %s", c.Server.URL)
+}
+
+// PostMessage posts the message to the given channel. Incoming webhooks cannot reply to threads so any ThreadID is
+// ignored and the message is posted at the top level of the channel
+func (c *MattermostChatProvider) PostMessage(channel string, message *Message) (*PostedMessage, error) {
+	if message == nil {
+		return nil, fmt.Errorf("no message to post to channel %s", channel)
+	}
+	channel = strings.TrimPrefix(channel, "#")
+	payload := &mattermostPayload{
+		Channel: channel,
+		Text:    message.Text,
+	}
+	for _, a := range message.Attachments {
+		attachment := mattermostAttachment{
+			Fallback:  a.Title,
+			Color:     a.Color,
+			Title:     a.Title,
+			TitleLink: a.TitleLink,
+			Text:      a.Text,
+			Footer:    a.Footer,
+		}
+		if attachment.Fallback == "" {
+			attachment.Fallback = a.Text
+		}
+		for _, f := range a.Fields {
+			attachment.Fields = append(attachment.Fields, mattermostField{
+				Title: f.Title,
+				Value: f.Value,
+				Short: f.Short,
+			})
+		}
+		payload.Attachments = append(payload.Attachments, attachment)
+	}
+	err := util.DoJSON(c.Client, http.MethodPost, c.WebhookURL, nil, payload, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "posting message to Mattermost channel %s", channel)
+	}
+	return &PostedMessage{
+		Channel: channel,
+	}, nil
+}
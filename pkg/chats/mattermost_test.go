@@ -0,0 +1,97 @@
+// +build unit
+
+package chats_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/chats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMattermostPostMessage(t *testing.T) {
+	var payload map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, "/hooks/xxx-generatedkey-xxx", r.URL.Path)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &payload))
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	provider, err := chats.CreateChatProvider(chats.Mattermost, &auth.AuthServer{URL: server.URL, Kind: chats.Mattermost},
+		&auth.UserAuth{Username: "jenkins-x", ApiToken: "xxx-generatedkey-xxx"}, true)
+	require.NoError(t, err)
+
+	message := &chats.Message{
+		Text: "Pipeline failed",
+		Attachments: []chats.Attachment{
+			{
+				Title:     "jenkins-x/jx/master #12",
+				TitleLink: "https://example.com/logs/12",
+				Color:     "danger",
+				Fields: []chats.AttachmentField{
+					{Title: "Branch", Value: "master", Short: true},
+				},
+			},
+		},
+	}
+	posted, err := provider.PostMessage("#builds", message)
+	require.NoError(t, err)
+	assert.Equal(t, "builds", posted.Channel)
+
+	assert.Equal(t, "builds", payload["channel"])
+	assert.Equal(t, "Pipeline failed", payload["text"])
+	attachments := payload["attachments"].([]interface{})
+	require.Len(t, attachments, 1)
+	attachment := attachments[0].(map[string]interface{})
+	assert.Equal(t, "jenkins-x/jx/master #12", attachment["title"])
+	assert.Equal(t, "https://example.com/logs/12", attachment["title_link"])
+	assert.Equal(t, "danger", attachment["color"])
+	fields := attachment["fields"].([]interface{})
+	require.Len(t, fields, 1)
+	assert.Equal(t, map[string]interface{}{"title": "Branch", "value": "master", "short": true}, fields[0])
+}
+
+func TestMattermostPostMessageFullWebhookURL(t *testing.T) {
+	called := false
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+		assert.Equal(t, "/custom/hooks/abc", r.URL.Path)
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	provider, err := chats.CreateMattermostChatProvider(&auth.AuthServer{URL: "https://mattermost.example.com"},
+		&auth.UserAuth{Username: "jenkins-x", ApiToken: server.URL + "/custom/hooks/abc"}, true)
+	require.NoError(t, err)
+
+	_, err = provider.PostMessage("town-square", &chats.Message{Text: "hello"})
+	require.NoError(t, err)
+	assert.True(t, called, "the incoming webhook was not called")
+}
+
+func TestMattermostPostMessageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"id":"web.incoming_webhook.channel.app_error","message":"Couldn't find the channel."}`))
+	}))
+	defer server.Close()
+
+	provider, err := chats.CreateMattermostChatProvider(&auth.AuthServer{URL: server.URL},
+		&auth.UserAuth{Username: "jenkins-x", ApiToken: "xxx-generatedkey-xxx"}, true)
+	require.NoError(t, err)
+
+	_, err = provider.PostMessage("does-not-exist", &chats.Message{Text: "hello"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Couldn't find the channel.")
+}
@@ -0,0 +1,140 @@
+package chats
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const msTeamsSchemaContext = "https://schema.org/extensions"
+
+// MSTeamsChatProvider posts messages to a Microsoft Teams channel via an incoming webhook connector
+type MSTeamsChatProvider struct {
+	Client     *http.Client
+	Server     *auth.AuthServer
+	UserAuth   *auth.UserAuth
+	WebhookURL string
+}
+
+// msTeamsMessageCard is the legacy actionable message card accepted by Microsoft Teams incoming webhooks
+type msTeamsMessageCard struct {
+	Type            string                 `json:"@type"`
+	Context         string                 `json:"@context"`
+	Summary         string                 `json:"summary,omitempty"`
+	Text            string                 `json:"text,omitempty"`
+	ThemeColor      string                 `json:"themeColor,omitempty"`
+	Sections        []msTeamsSection       `json:"sections,omitempty"`
+	PotentialAction []msTeamsOpenURIAction `json:"potentialAction,omitempty"`
+}
+
+type msTeamsSection struct {
+	ActivityTitle    string        `json:"activityTitle,omitempty"`
+	ActivitySubtitle string        `json:"activitySubtitle,omitempty"`
+	Text             string        `json:"text,omitempty"`
+	Facts            []msTeamsFact `json:"facts,omitempty"`
+}
+
+type msTeamsFact struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type msTeamsOpenURIAction struct {
+	Type    string          `json:"@type"`
+	Name    string          `json:"name"`
+	Targets []msTeamsTarget `json:"targets"`
+}
+
+type msTeamsTarget struct {
+	OS  string `json:"os"`
+	URI string `json:"uri"`
+}
+
+// CreateMSTeamsChatProvider creates a chat provider for Microsoft Teams. The API token of the user is the URL of the
+// incoming webhook of the channel (or its path relative to the server URL)
+func CreateMSTeamsChatProvider(server *auth.AuthServer, userAuth *auth.UserAuth, batchMode bool) (ChatProvider, error) {
+	u := server.URL
+	if u == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if userAuth == nil || userAuth.IsInvalid() || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("No incoming webhook found for Microsoft Teams server %s", u)
+	}
+	return &MSTeamsChatProvider{
+		Client:     util.GetClient(),
+		Server:     server,
+		UserAuth:   userAuth,
+		WebhookURL: incomingWebhookURL(server, userAuth, ""),
+	}, nil
+}
+
+// GetChannelMetrics is not supported as incoming webhooks cannot query channels
+func (c *MSTeamsChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, error) {
+	return nil, fmt.Errorf("channel metrics are not supported for Microsoft Teams server %s", c.Server.URL)
+}
+
+// PostMessage posts the message to the channel of the incoming webhook. A Microsoft Teams webhook is bound to a
+// single channel and cannot reply to threads so the channel is only used for error messages and any ThreadID is ignored
+func (c *MSTeamsChatProvider) PostMessage(channel string, message *Message) (*PostedMessage, error) {
+	if message == nil {
+		return nil, fmt.Errorf("no message to post to channel %s", channel)
+	}
+	card := &msTeamsMessageCard{
+		Type:    "MessageCard",
+		Context: msTeamsSchemaContext,
+		Summary: message.Text,
+		Text:    message.Text,
+	}
+	for _, a := range message.Attachments {
+		if card.ThemeColor == "" {
+			card.ThemeColor = msTeamsThemeColor(a.Color)
+		}
+		section := msTeamsSection{
+			ActivityTitle:    a.Title,
+			ActivitySubtitle: a.Footer,
+			Text:             a.Text,
+		}
+		for _, f := range a.Fields {
+			section.Facts = append(section.Facts, msTeamsFact{
+				Name:  f.Title,
+				Value: f.Value,
+			})
+		}
+		card.Sections = append(card.Sections, section)
+		if a.TitleLink != "" {
+			name := a.Title
+			if name == "" {
+				name = "View"
+			}
+			card.PotentialAction = append(card.PotentialAction, msTeamsOpenURIAction{
+				Type:    "OpenUri",
+				Name:    name,
+				Targets: []msTeamsTarget{{OS: "default", URI: a.TitleLink}},
+			})
+		}
+	}
+	err := util.DoJSON(c.Client, http.MethodPost, c.WebhookURL, nil, card, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "posting message to Microsoft Teams channel %s", channel)
+	}
+	return &PostedMessage{
+		Channel: channel,
+	}, nil
+}
+
+// msTeamsThemeColor converts the Slack style named colors into the hex colors used by Microsoft Teams
+func msTeamsThemeColor(color string) string {
+	switch color {
+	case "good":
+		return "2EB886"
+	case "warning":
+		return "DAA038"
+	case "danger":
+		return "A30200"
+	}
+	return strings.TrimPrefix(color, "#")
+}
@@ -0,0 +1,96 @@
+// +build unit
+
+package chats_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/chats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const msTeamsWebhookPath = "/webhook/a1b2c3@d4e5f6/IncomingWebhook/0123456789/abcdef"
+
+func TestMSTeamsPostMessage(t *testing.T) {
+	var card map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.Equal(t, msTeamsWebhookPath, r.URL.Path)
+		body, err := ioutil.ReadAll(r.Body)
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(body, &card))
+		_, _ = w.Write([]byte("1"))
+	}))
+	defer server.Close()
+
+	provider, err := chats.CreateChatProvider(chats.MSTeams, &auth.AuthServer{URL: server.URL, Kind: chats.MSTeams},
+		&auth.UserAuth{Username: "jenkins-x", ApiToken: msTeamsWebhookPath}, true)
+	require.NoError(t, err)
+
+	message := &chats.Message{
+		Text: "Promoted myapp version 1.2.3 to Production",
+		Attachments: []chats.Attachment{
+			{
+				Title:     "Promoted myapp version 1.2.3 to Production",
+				TitleLink: "https://github.com/myorg/environment-production/pull/5",
+				Color:     "good",
+				Fields: []chats.AttachmentField{
+					{Title: "Application", Value: "myapp", Short: true},
+					{Title: "Version", Value: "1.2.3", Short: true},
+				},
+			},
+		},
+	}
+	posted, err := provider.PostMessage("releases", message)
+	require.NoError(t, err)
+	assert.Equal(t, "releases", posted.Channel)
+
+	assert.Equal(t, "MessageCard", card["@type"])
+	assert.Equal(t, "Promoted myapp version 1.2.3 to Production", card["summary"])
+	assert.Equal(t, "2EB886", card["themeColor"])
+
+	sections := card["sections"].([]interface{})
+	require.Len(t, sections, 1)
+	section := sections[0].(map[string]interface{})
+	assert.Equal(t, "Promoted myapp version 1.2.3 to Production", section["activityTitle"])
+	facts := section["facts"].([]interface{})
+	require.Len(t, facts, 2)
+	assert.Equal(t, map[string]interface{}{"name": "Version", "value": "1.2.3"}, facts[1])
+
+	actions := card["potentialAction"].([]interface{})
+	require.Len(t, actions, 1)
+	action := actions[0].(map[string]interface{})
+	assert.Equal(t, "OpenUri", action["@type"])
+	targets := action["targets"].([]interface{})
+	assert.Equal(t, "https://github.com/myorg/environment-production/pull/5", targets[0].(map[string]interface{})["uri"])
+}
+
+func TestMSTeamsPostMessageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte("Summary or Text is required."))
+	}))
+	defer server.Close()
+
+	provider, err := chats.CreateMSTeamsChatProvider(&auth.AuthServer{URL: "https://outlook.office.com"},
+		&auth.UserAuth{Username: "jenkins-x", ApiToken: server.URL + msTeamsWebhookPath}, true)
+	require.NoError(t, err)
+
+	_, err = provider.PostMessage("releases", &chats.Message{})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Summary or Text is required.")
+}
+
+func TestCreateChatProviderRequiresWebhook(t *testing.T) {
+	for _, kind := range []string{chats.Mattermost, chats.MSTeams} {
+		_, err := chats.CreateChatProvider(kind, &auth.AuthServer{URL: "https://chat.example.com", Kind: kind},
+			&auth.UserAuth{Username: "jenkins-x"}, true)
+		assert.Error(t, err, "kind %s", kind)
+	}
+}
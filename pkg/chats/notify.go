@@ -0,0 +1,154 @@
+package chats
+
+import (
+	"fmt"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/pkg/errors"
+)
+
+const (
+	colorGood    = "good"
+	colorDanger  = "danger"
+	colorWarning = "warning"
+)
+
+// Notifier posts notifications about pipelines and promotions to a team chat channel
+type Notifier struct {
+	Provider ChatProvider
+	Channel  string
+}
+
+// NewNotifier creates a notifier which posts to the given channel
+func NewNotifier(provider ChatProvider, channel string) *Notifier {
+	return &Notifier{
+		Provider: provider,
+		Channel:  channel,
+	}
+}
+
+// PipelineActivityStatusChanged posts a message if the activity has transitioned from the old status into a terminal
+// status which the team should know about. Returns nil if no message was posted
+func (n *Notifier) PipelineActivityStatusChanged(activity *v1.PipelineActivity, oldStatus v1.ActivityStatusType) (*PostedMessage, error) {
+	if activity == nil {
+		return nil, nil
+	}
+	status := activity.Spec.Status
+	if status == oldStatus {
+		return nil, nil
+	}
+	message := PipelineActivityMessage(activity)
+	if message == nil {
+		return nil, nil
+	}
+	return n.post(message)
+}
+
+// Promotion posts a message for the promotion of the given app version to an environment. If err is not nil the
+// promotion is reported as failed
+func (n *Notifier) Promotion(app string, version string, env *v1.Environment, pullRequestURL string, err error) (*PostedMessage, error) {
+	return n.post(PromotionMessage(app, version, env, pullRequestURL, err))
+}
+
+func (n *Notifier) post(message *Message) (*PostedMessage, error) {
+	if n.Provider == nil || n.Channel == "" {
+		return nil, nil
+	}
+	posted, err := n.Provider.PostMessage(n.Channel, message)
+	if err != nil {
+		return nil, errors.Wrapf(err, "notifying chat channel %s", n.Channel)
+	}
+	return posted, nil
+}
+
+// PipelineActivityMessage creates the message for a pipeline activity which has failed, errored or been aborted.
+// Returns nil if the status of the activity is not worth notifying the team about
+func PipelineActivityMessage(activity *v1.PipelineActivity) *Message {
+	spec := &activity.Spec
+	var verb, color string
+	switch spec.Status {
+	case v1.ActivityStatusTypeFailed, v1.ActivityStatusTypeError:
+		verb = "failed"
+		color = colorDanger
+	case v1.ActivityStatusTypeAborted:
+		verb = "was aborted"
+		color = colorWarning
+	default:
+		return nil
+	}
+	pipeline := spec.Pipeline
+	if pipeline == "" {
+		pipeline = strings.Join([]string{spec.GitOwner, spec.GitRepository, spec.GitBranch}, "/")
+	}
+	title := fmt.Sprintf("Pipeline %s #%s %s", pipeline, spec.Build, verb)
+	link := spec.BuildLogsURL
+	if link == "" {
+		link = spec.BuildURL
+	}
+	attachment := Attachment{
+		Title:     title,
+		TitleLink: link,
+		Text:      spec.LastCommitMessage,
+		Color:     color,
+	}
+	if spec.GitBranch != "" {
+		attachment.Fields = append(attachment.Fields, AttachmentField{Title: "Branch", Value: spec.GitBranch, Short: true})
+	}
+	if spec.Author != "" {
+		attachment.Fields = append(attachment.Fields, AttachmentField{Title: "Author", Value: spec.Author, Short: true})
+	}
+	if spec.LastCommitSHA != "" {
+		attachment.Fields = append(attachment.Fields, AttachmentField{Title: "Commit", Value: shortSHA(spec.LastCommitSHA), Short: true})
+	}
+	if spec.PullTitle != "" {
+		attachment.Fields = append(attachment.Fields, AttachmentField{Title: "Pull Request", Value: spec.PullTitle})
+	}
+	return &Message{
+		Text:        title,
+		Attachments: []Attachment{attachment},
+	}
+}
+
+// PromotionMessage creates the message for the promotion of an app version to an environment
+func PromotionMessage(app string, version string, env *v1.Environment, pullRequestURL string, err error) *Message {
+	envName := ""
+	if env != nil {
+		envName = env.Spec.Label
+		if envName == "" {
+			envName = env.Name
+		}
+	}
+	if version == "" {
+		version = "latest"
+	}
+	title := fmt.Sprintf("Promoted %s version %s to %s", app, version, envName)
+	color := colorGood
+	text := ""
+	if err != nil {
+		title = fmt.Sprintf("Failed to promote %s version %s to %s", app, version, envName)
+		color = colorDanger
+		text = err.Error()
+	}
+	attachment := Attachment{
+		Title:     title,
+		TitleLink: pullRequestURL,
+		Text:      text,
+		Color:     color,
+		Fields: []AttachmentField{
+			{Title: "Application", Value: app, Short: true},
+			{Title: "Version", Value: version, Short: true},
+		},
+	}
+	return &Message{
+		Text:        title,
+		Attachments: []Attachment{attachment},
+	}
+}
+
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[0:7]
+	}
+	return sha
+}
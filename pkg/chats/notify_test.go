@@ -0,0 +1,97 @@
+// +build unit
+
+package chats_test
+
+import (
+	"fmt"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/chats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type fakeChatProvider struct {
+	channels []string
+	messages []*chats.Message
+}
+
+func (f *fakeChatProvider) GetChannelMetrics(name string) (*chats.ChannelMetrics, error) {
+	return &chats.ChannelMetrics{Name: name}, nil
+}
+
+func (f *fakeChatProvider) PostMessage(channel string, message *chats.Message) (*chats.PostedMessage, error) {
+	f.channels = append(f.channels, channel)
+	f.messages = append(f.messages, message)
+	return &chats.PostedMessage{Channel: channel, ID: fmt.Sprintf("%d", len(f.messages))}, nil
+}
+
+func TestNotifyPipelineActivityFailed(t *testing.T) {
+	provider := &fakeChatProvider{}
+	notifier := chats.NewNotifier(provider, "#builds")
+
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins-x-jx-master-12"},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline:      "jenkins-x/jx/master",
+			Build:         "12",
+			GitBranch:     "master",
+			Author:        "jstrachan",
+			LastCommitSHA: "5ad3f1ef0ac2c5c82e8aa7b6d2ddd5a2a4e12f50",
+			BuildLogsURL:  "https://example.com/logs/12",
+			Status:        v1.ActivityStatusTypeFailed,
+		},
+	}
+	posted, err := notifier.PipelineActivityStatusChanged(activity, v1.ActivityStatusTypeRunning)
+	require.NoError(t, err)
+	require.NotNil(t, posted)
+	require.Len(t, provider.messages, 1)
+	assert.Equal(t, "#builds", provider.channels[0])
+
+	message := provider.messages[0]
+	assert.Equal(t, "Pipeline jenkins-x/jx/master #12 failed", message.Text)
+	require.Len(t, message.Attachments, 1)
+	attachment := message.Attachments[0]
+	assert.Equal(t, "danger", attachment.Color)
+	assert.Equal(t, "https://example.com/logs/12", attachment.TitleLink)
+	assert.Contains(t, attachment.Fields, chats.AttachmentField{Title: "Commit", Value: "5ad3f1e", Short: true})
+
+	// the same status again should not notify twice
+	posted, err = notifier.PipelineActivityStatusChanged(activity, v1.ActivityStatusTypeFailed)
+	require.NoError(t, err)
+	assert.Nil(t, posted)
+
+	// successful pipelines are not worth notifying the team about
+	activity.Spec.Status = v1.ActivityStatusTypeSucceeded
+	posted, err = notifier.PipelineActivityStatusChanged(activity, v1.ActivityStatusTypeRunning)
+	require.NoError(t, err)
+	assert.Nil(t, posted)
+	assert.Len(t, provider.messages, 1)
+}
+
+func TestNotifyPromotion(t *testing.T) {
+	provider := &fakeChatProvider{}
+	notifier := chats.NewNotifier(provider, "#releases")
+
+	env := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec: v1.EnvironmentSpec{
+			Label: "Production",
+		},
+	}
+	_, err := notifier.Promotion("myapp", "1.2.3", env, "https://github.com/myorg/environment-production/pull/5", nil)
+	require.NoError(t, err)
+	_, err = notifier.Promotion("myapp", "1.2.4", env, "", fmt.Errorf("helm upgrade failed"))
+	require.NoError(t, err)
+
+	require.Len(t, provider.messages, 2)
+	assert.Equal(t, "Promoted myapp version 1.2.3 to Production", provider.messages[0].Text)
+	assert.Equal(t, "good", provider.messages[0].Attachments[0].Color)
+	assert.Equal(t, "https://github.com/myorg/environment-production/pull/5", provider.messages[0].Attachments[0].TitleLink)
+
+	assert.Equal(t, "Failed to promote myapp version 1.2.4 to Production", provider.messages[1].Text)
+	assert.Equal(t, "danger", provider.messages[1].Attachments[0].Color)
+	assert.Equal(t, "helm upgrade failed", provider.messages[1].Attachments[0].Text)
+}
@@ -10,6 +10,42 @@ import (
 // ChatProvider represents an integration interface to chat
 type ChatProvider interface {
 	GetChannelMetrics(name string) (*ChannelMetrics, error)
+
+	// PostMessage posts the message to the given channel, replying in a thread if the message has a ThreadID
+	PostMessage(channel string, message *Message) (*PostedMessage, error)
+}
+
+// Message a message to be posted to a chat channel
+type Message struct {
+	Text string
+	// ThreadID if specified the message is posted as a reply to the thread of the given message
+	ThreadID    string
+	Attachments []Attachment
+}
+
+// Attachment a rich attachment rendered below the text of a message
+type Attachment struct {
+	Title     string
+	TitleLink string
+	Text      string
+	// Color the color of the attachment such as "good", "warning", "danger" or a hex color like "#439FE0"
+	Color  string
+	Footer string
+	Fields []AttachmentField
+}
+
+// AttachmentField a field displayed in a table inside an attachment
+type AttachmentField struct {
+	Title string
+	Value string
+	Short bool
+}
+
+// PostedMessage the details of a message which has been posted
+type PostedMessage struct {
+	Channel string
+	// ID the ID of the message which can be used as the ThreadID of replies
+	ID string
 }
 
 // ChannelMetrics metrics for a channel
@@ -30,16 +66,31 @@ func CreateChatProvider(kind string, server *auth.AuthServer, userAuth *auth.Use
 	switch kind {
 	case Slack:
 		return CreateSlackChatProvider(server, userAuth, batchMode)
+	case Mattermost:
+		return CreateMattermostChatProvider(server, userAuth, batchMode)
+	case MSTeams:
+		return CreateMSTeamsChatProvider(server, userAuth, batchMode)
 	default:
 		return nil, fmt.Errorf("Unsupported chat provider kind: %s", kind)
 	}
 }
 
+// ProviderAccessTokenURL returns the URL to create the token for the given kind of chat server
 func ProviderAccessTokenURL(kind string, url string) string {
 	switch kind {
 	case Slack:
 		return "https://my.slack.com/services/new/bot"
+	case Mattermost:
+		return "https://docs.mattermost.com/developer/webhooks-incoming.html"
+	case MSTeams:
+		return "https://docs.microsoft.com/en-us/microsoftteams/platform/webhooks-and-connectors/how-to/add-incoming-webhook"
 	default:
 		return ""
 	}
 }
+
+// IsIncomingWebhookKind returns true if the given kind of chat server is accessed via an incoming webhook
+// rather than an API token
+func IsIncomingWebhookKind(kind string) bool {
+	return kind == Mattermost || kind == MSTeams
+}
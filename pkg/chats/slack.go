@@ -8,6 +8,7 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/nlopes/slack"
+	"github.com/pkg/errors"
 )
 
 type SlackChatProvider struct {
@@ -62,3 +63,54 @@ func (c *SlackChatProvider) GetChannelMetrics(name string) (*ChannelMetrics, err
 	metrics.URL = util.UrlJoin(c.Server.URL, "messages", info.ID)
 	return metrics, nil
 }
+
+// PostMessage posts the message to the given channel
+func (c *SlackChatProvider) PostMessage(channel string, message *Message) (*PostedMessage, error) {
+	if message == nil {
+		return nil, fmt.Errorf("no message to post to channel %s", channel)
+	}
+	options := []slack.MsgOption{
+		slack.MsgOptionText(message.Text, false),
+		slack.MsgOptionAsUser(true),
+	}
+	if len(message.Attachments) > 0 {
+		attachments := make([]slack.Attachment, 0, len(message.Attachments))
+		for _, a := range message.Attachments {
+			attachments = append(attachments, toSlackAttachment(a))
+		}
+		options = append(options, slack.MsgOptionAttachments(attachments...))
+	}
+	if message.ThreadID != "" {
+		options = append(options, slack.MsgOptionTS(message.ThreadID))
+	}
+	respChannel, timestamp, _, err := c.SlackClient.SendMessage(channel, options...)
+	if err != nil {
+		return nil, errors.Wrapf(err, "posting message to Slack channel %s", channel)
+	}
+	return &PostedMessage{
+		Channel: respChannel,
+		ID:      timestamp,
+	}, nil
+}
+
+func toSlackAttachment(a Attachment) slack.Attachment {
+	answer := slack.Attachment{
+		Title:     a.Title,
+		TitleLink: a.TitleLink,
+		Text:      a.Text,
+		Fallback:  a.Title,
+		Color:     a.Color,
+		Footer:    a.Footer,
+	}
+	if answer.Fallback == "" {
+		answer.Fallback = a.Text
+	}
+	for _, f := range a.Fields {
+		answer.Fields = append(answer.Fields, slack.AttachmentField{
+			Title: f.Title,
+			Value: f.Value,
+			Short: f.Short,
+		})
+	}
+	return answer
+}
@@ -0,0 +1,95 @@
+// +build unit
+
+package chats_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/chats"
+	"github.com/nlopes/slack"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackPostMessage(t *testing.T) {
+	var posted map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/chat.postMessage", r.URL.Path)
+		require.NoError(t, r.ParseForm())
+		posted = map[string]string{}
+		for k := range r.PostForm {
+			posted[k] = r.PostForm.Get(k)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": true, "channel": "C1234", "ts": "1503435956.000247"}`))
+	}))
+	defer server.Close()
+
+	oldAPI := slack.SLACK_API
+	slack.SLACK_API = server.URL + "/"
+	defer func() {
+		slack.SLACK_API = oldAPI
+	}()
+
+	provider, err := chats.CreateSlackChatProvider(&auth.AuthServer{URL: "https://myteam.slack.com", Kind: chats.Slack},
+		&auth.UserAuth{Username: "jenkins-x", ApiToken: "xoxb-test"}, true)
+	require.NoError(t, err)
+
+	message := &chats.Message{
+		Text:     "Pipeline failed",
+		ThreadID: "1503435900.000100",
+		Attachments: []chats.Attachment{
+			{
+				Title:     "jenkins-x/jx/master #12",
+				TitleLink: "https://example.com/logs/12",
+				Color:     "danger",
+				Fields: []chats.AttachmentField{
+					{Title: "Branch", Value: "master", Short: true},
+				},
+			},
+		},
+	}
+	answer, err := provider.PostMessage("#builds", message)
+	require.NoError(t, err)
+	assert.Equal(t, "C1234", answer.Channel)
+	assert.Equal(t, "1503435956.000247", answer.ID)
+
+	assert.Equal(t, "#builds", posted["channel"])
+	assert.Equal(t, "Pipeline failed", posted["text"])
+	assert.Equal(t, "1503435900.000100", posted["thread_ts"])
+
+	var attachments []slack.Attachment
+	require.NoError(t, json.Unmarshal([]byte(posted["attachments"]), &attachments))
+	require.Len(t, attachments, 1)
+	assert.Equal(t, "jenkins-x/jx/master #12", attachments[0].Title)
+	assert.Equal(t, "https://example.com/logs/12", attachments[0].TitleLink)
+	assert.Equal(t, "danger", attachments[0].Color)
+	require.Len(t, attachments[0].Fields, 1)
+	assert.Equal(t, "master", attachments[0].Fields[0].Value)
+}
+
+func TestSlackPostMessageError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"ok": false, "error": "channel_not_found"}`))
+	}))
+	defer server.Close()
+
+	oldAPI := slack.SLACK_API
+	slack.SLACK_API = server.URL + "/"
+	defer func() {
+		slack.SLACK_API = oldAPI
+	}()
+
+	provider, err := chats.CreateSlackChatProvider(&auth.AuthServer{URL: "https://myteam.slack.com", Kind: chats.Slack},
+		&auth.UserAuth{Username: "jenkins-x", ApiToken: "xoxb-test"}, true)
+	require.NoError(t, err)
+
+	_, err = provider.PostMessage("#does-not-exist", &chats.Message{Text: "hello"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "channel_not_found")
+}
@@ -0,0 +1,21 @@
+package chats
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+)
+
+// incomingWebhookURL returns the URL of the incoming webhook for the user. The API token of the user is either
+// the full webhook URL or the path of the webhook relative to the given base path on the server
+func incomingWebhookURL(server *auth.AuthServer, userAuth *auth.UserAuth, basePath string) string {
+	token := userAuth.ApiToken
+	if strings.HasPrefix(token, "http://") || strings.HasPrefix(token, "https://") {
+		return token
+	}
+	if basePath == "" {
+		return util.UrlJoin(server.URL, token)
+	}
+	return util.UrlJoin(server.URL, basePath, token)
+}
@@ -26,7 +26,9 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.BuildPack":                           schema_pkg_apis_jenkinsio_v1_BuildPack(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.BuildPackList":                       schema_pkg_apis_jenkinsio_v1_BuildPackList(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.BuildPackSpec":                       schema_pkg_apis_jenkinsio_v1_BuildPackSpec(ref),
+		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.CVEScannerSettings":                  schema_pkg_apis_jenkinsio_v1_CVEScannerSettings(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ChartRef":                            schema_pkg_apis_jenkinsio_v1_ChartRef(ref),
+		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ChatSettings":                        schema_pkg_apis_jenkinsio_v1_ChatSettings(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.CommitStatus":                        schema_pkg_apis_jenkinsio_v1_CommitStatus(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.CommitStatusCommitReference":         schema_pkg_apis_jenkinsio_v1_CommitStatusCommitReference(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.CommitStatusDetails":                 schema_pkg_apis_jenkinsio_v1_CommitStatusDetails(ref),
@@ -81,6 +83,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.IssueSummary":                        schema_pkg_apis_jenkinsio_v1_IssueSummary(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.JobBase":                             schema_pkg_apis_jenkinsio_v1_JobBase(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.Lgtm":                                schema_pkg_apis_jenkinsio_v1_Lgtm(ref),
+		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.LogMaskingSettings":                  schema_pkg_apis_jenkinsio_v1_LogMaskingSettings(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.Measurement":                         schema_pkg_apis_jenkinsio_v1_Measurement(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.Merger":                              schema_pkg_apis_jenkinsio_v1_Merger(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.Original":                            schema_pkg_apis_jenkinsio_v1_Original(ref),
@@ -91,6 +94,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineActivitySpec":                schema_pkg_apis_jenkinsio_v1_PipelineActivitySpec(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineActivityStatus":              schema_pkg_apis_jenkinsio_v1_PipelineActivityStatus(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineActivityStep":                schema_pkg_apis_jenkinsio_v1_PipelineActivityStep(ref),
+		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineEventsSettings":              schema_pkg_apis_jenkinsio_v1_PipelineEventsSettings(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineExtension":                   schema_pkg_apis_jenkinsio_v1_PipelineExtension(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineStructure":                   schema_pkg_apis_jenkinsio_v1_PipelineStructure(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineStructureList":               schema_pkg_apis_jenkinsio_v1_PipelineStructureList(ref),
@@ -107,6 +111,7 @@ func GetOpenAPIDefinitions(ref common.ReferenceCallback) map[string]common.OpenA
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteActivityStep":                 schema_pkg_apis_jenkinsio_v1_PromoteActivityStep(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromotePullRequestStep":              schema_pkg_apis_jenkinsio_v1_PromotePullRequestStep(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteUpdateStep":                   schema_pkg_apis_jenkinsio_v1_PromoteUpdateStep(ref),
+		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteVulnerabilityCheck":           schema_pkg_apis_jenkinsio_v1_PromoteVulnerabilityCheck(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteWorkflowStep":                 schema_pkg_apis_jenkinsio_v1_PromoteWorkflowStep(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ProtectionPolicies":                  schema_pkg_apis_jenkinsio_v1_ProtectionPolicies(ref),
 		"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ProtectionPolicy":                    schema_pkg_apis_jenkinsio_v1_ProtectionPolicy(ref),
@@ -634,6 +639,41 @@ func schema_pkg_apis_jenkinsio_v1_BuildPackSpec(ref common.ReferenceCallback) co
 	}
 }
 
+func schema_pkg_apis_jenkinsio_v1_CVEScannerSettings(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "CVEScannerSettings the scanner used to find the Common Vulnerabilities and Exposures (CVEs) of images",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind the kind of scanner such as anchore, trivy or clair. Defaults to anchore",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL the URL of the scanner server. Defaults to the URL of the anchore addon service",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity the lowest severity of vulnerabilities which fail a pipeline scanning an image. Defaults to High",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
 func schema_pkg_apis_jenkinsio_v1_ChartRef(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -665,6 +705,48 @@ func schema_pkg_apis_jenkinsio_v1_ChartRef(ref common.ReferenceCallback) common.
 	}
 }
 
+func schema_pkg_apis_jenkinsio_v1_ChatSettings(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "ChatSettings the chat service and channel used to notify the team",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind the kind of chat provider such as slack",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL the URL of the chat server",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"channel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Channel the channel to post notifications to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"promotionChannel": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PromotionChannel the channel to post promotion notifications to. Defaults to Channel if not specified",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
 func schema_pkg_apis_jenkinsio_v1_CommitStatus(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -946,11 +1028,17 @@ func schema_pkg_apis_jenkinsio_v1_CommitSummary(ref common.ReferenceCallback) co
 							},
 						},
 					},
+					"timestamp": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Timestamp is when the commit was committed",
+							Ref:         ref("k8s.io/apimachinery/pkg/apis/meta/v1.Time"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.UserDetails"},
+			"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.UserDetails", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -1752,6 +1840,20 @@ func schema_pkg_apis_jenkinsio_v1_EnvironmentSpec(ref common.ReferenceCallback)
 							Format:      "",
 						},
 					},
+					"issueTransition": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IssueTransition is the status, such as \"Deployed to Staging\", issues are moved to when promoted to this Environment. It overrides the IssueTransitions of the team settings",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"protected": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Protected flag indicates releases are only promoted to the Environment if their images have no critical vulnerabilities",
+							Type:        []string{"boolean"},
+							Format:      "",
+						},
+					},
 				},
 			},
 		},
@@ -3074,6 +3176,47 @@ func schema_pkg_apis_jenkinsio_v1_Lgtm(ref common.ReferenceCallback) common.Open
 	}
 }
 
+func schema_pkg_apis_jenkinsio_v1_LogMaskingSettings(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "LogMaskingSettings contains the settings for masking secrets in build logs in addition to the values of the Secrets in the namespace of the build and the well-known secret formats",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"patterns": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Patterns regular expressions matching secrets to mask",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"secrets": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Secrets the names of secrets in the secret store, such as Vault, whose values are masked",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_jenkinsio_v1_Measurement(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3651,6 +3794,62 @@ func schema_pkg_apis_jenkinsio_v1_PipelineActivityStep(ref common.ReferenceCallb
 	}
 }
 
+func schema_pkg_apis_jenkinsio_v1_PipelineEventsSettings(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PipelineEventsSettings the sink changes to PipelineActivities and Releases are sent to",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"kind": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Kind the kind of sink such as elasticsearch, cloudevents, webhook or kafka",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"url": {
+						SchemaProps: spec.SchemaProps{
+							Description: "URL the URL events are sent to. For kafka this is the URL of a Kafka REST proxy",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"topic": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Topic the Kafka topic events are produced to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Source the source of CloudEvents",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"maxRetries": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MaxRetries how many times sending an event is retried before it is dead lettered. Defaults to 5",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"deadLetterUrl": {
+						SchemaProps: spec.SchemaProps{
+							Description: "DeadLetterURL the URL events which could not be sent are posted to as JSON",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+		Dependencies: []string{},
+	}
+}
+
 func schema_pkg_apis_jenkinsio_v1_PipelineExtension(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -3885,6 +4084,12 @@ func schema_pkg_apis_jenkinsio_v1_PipelineStructureStage(ref common.ReferenceCal
 							Format: "",
 						},
 					},
+					"skipped": {
+						SchemaProps: spec.SchemaProps{
+							Type:   []string{"boolean"},
+							Format: "",
+						},
+					},
 				},
 				Required: []string{"name", "depth"},
 			},
@@ -4441,11 +4646,17 @@ func schema_pkg_apis_jenkinsio_v1_PromoteActivityStep(ref common.ReferenceCallba
 							Format: "",
 						},
 					},
+					"vulnerabilityCheck": {
+						SchemaProps: spec.SchemaProps{
+							Description: "VulnerabilityCheck the check of the vulnerabilities of the release before promoting it to a protected Environment",
+							Ref:         ref("github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteVulnerabilityCheck"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromotePullRequestStep", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteUpdateStep", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
+			"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromotePullRequestStep", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteUpdateStep", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PromoteVulnerabilityCheck", "k8s.io/apimachinery/pkg/apis/meta/v1.Time"},
 	}
 }
 
@@ -4559,6 +4770,68 @@ func schema_pkg_apis_jenkinsio_v1_PromoteUpdateStep(ref common.ReferenceCallback
 	}
 }
 
+func schema_pkg_apis_jenkinsio_v1_PromoteVulnerabilityCheck(ref common.ReferenceCallback) common.OpenAPIDefinition {
+	return common.OpenAPIDefinition{
+		Schema: spec.Schema{
+			SchemaProps: spec.SchemaProps{
+				Description: "PromoteVulnerabilityCheck records the vulnerabilities found in the images of a release before promoting it",
+				Type:        []string{"object"},
+				Properties: map[string]spec.Schema{
+					"decision": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Decision whether the promotion was allowed, denied or the check overridden",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"severity": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Severity the lowest severity of vulnerabilities which deny the promotion",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"source": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Source where the vulnerabilities were found, either the Facts of the pipeline or the kind of CVE provider",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"images": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Images the images of the release which were checked",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"vulnerabilities": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Vulnerabilities the number of vulnerabilities at or above the severity found in the images",
+							Type:        []string{"integer"},
+							Format:      "int32",
+						},
+					},
+					"message": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Message describes the decision",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
 func schema_pkg_apis_jenkinsio_v1_PromoteWorkflowStep(ref common.ReferenceCallback) common.OpenAPIDefinition {
 	return common.OpenAPIDefinition{
 		Schema: spec.Schema{
@@ -6304,11 +6577,49 @@ func schema_pkg_apis_jenkinsio_v1_TeamSettings(ref common.ReferenceCallback) com
 							Ref:         ref("github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.DeployOptions"),
 						},
 					},
+					"chat": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ChatSettings configures the chat channel the team is notified on about failed pipelines and promotions",
+							Ref:         ref("github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ChatSettings"),
+						},
+					},
+					"issueTransitions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IssueTransitions maps the names of Environments to the status, such as \"Done\", issues are moved to when promoted to them",
+							Type:        []string{"object"},
+							AdditionalProperties: &spec.SchemaOrBool{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"pipelineEvents": {
+						SchemaProps: spec.SchemaProps{
+							Description: "PipelineEvents configures where changes to PipelineActivities and Releases are sent",
+							Ref:         ref("github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineEventsSettings"),
+						},
+					},
+					"cveScanner": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CVEScanner configures the scanner used to find the vulnerabilities of images",
+							Ref:         ref("github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.CVEScannerSettings"),
+						},
+					},
+					"logMasking": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LogMasking configures the masking of secrets in build logs",
+							Ref:         ref("github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.LogMaskingSettings"),
+						},
+					},
 				},
 			},
 		},
 		Dependencies: []string{
-			"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.DeployOptions", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.QuickStartLocation", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ResourceReference", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.StorageLocation", "k8s.io/api/batch/v1.Job"},
+			"github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.CVEScannerSettings", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ChatSettings", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.DeployOptions", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.LogMaskingSettings", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.PipelineEventsSettings", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.QuickStartLocation", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.ResourceReference", "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1.StorageLocation", "k8s.io/api/batch/v1.Job"},
 	}
 }
 
@@ -0,0 +1,89 @@
+package aks
+
+import (
+	"encoding/json"
+
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// managedClusterResourceType the Azure resource type of AKS clusters
+	managedClusterResourceType = "Microsoft.ContainerService/managedClusters"
+)
+
+// Cluster the details of an AKS cluster
+type Cluster struct {
+	Name     string            `json:"name"`
+	Group    string            `json:"group"`
+	Location string            `json:"location"`
+	Status   string            `json:"status"`
+	Tags     map[string]string `json:"tags"`
+}
+
+// ListClusters lists the AKS clusters of the subscription, only returning the clusters in the resource group if
+// one is specified
+func (az *AzureRunner) ListClusters(subscription string, resourceGroup string) ([]Cluster, error) {
+	args := []string{"aks", "list", "--query", "[].{name:name,group:resourceGroup,location:location,status:provisioningState,tags:tags}"}
+	args = withResourceGroupAndSubscription(args, subscription, resourceGroup)
+	clusterstr, err := az.azureCLI(args...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the AKS clusters")
+	}
+
+	clusters := []Cluster{}
+	err = json.Unmarshal([]byte(clusterstr), &clusters)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the AKS clusters from %s", clusterstr)
+	}
+	return clusters, nil
+}
+
+// ConnectToCluster merges the credentials of the AKS cluster into the kube config and makes it the current context
+func (az *AzureRunner) ConnectToCluster(subscription string, resourceGroup string, name string) error {
+	args := []string{"aks", "get-credentials", "--resource-group", resourceGroup, "--name", name, "--overwrite-existing"}
+	args = withResourceGroupAndSubscription(args, subscription, "")
+	_, err := az.azureCLI(args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to AKS cluster %s in resource group %s", name, resourceGroup)
+	}
+	return nil
+}
+
+// DeleteCluster deletes the AKS cluster
+func (az *AzureRunner) DeleteCluster(subscription string, resourceGroup string, name string) error {
+	args := []string{"aks", "delete", "--resource-group", resourceGroup, "--name", name, "--yes"}
+	args = withResourceGroupAndSubscription(args, subscription, "")
+	_, err := az.azureCLI(args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete AKS cluster %s in resource group %s", name, resourceGroup)
+	}
+	return nil
+}
+
+// SetClusterTags replaces the tags of the AKS cluster with the given tags
+func (az *AzureRunner) SetClusterTags(subscription string, resourceGroup string, name string, tags map[string]string) error {
+	args := []string{"resource", "tag", "--resource-group", resourceGroup, "--name", name, "--resource-type", managedClusterResourceType, "--tags"}
+	keyValues := util.MapToKeyValues(tags)
+	if len(keyValues) == 0 {
+		// an empty value removes all the tags
+		keyValues = []string{""}
+	}
+	args = append(args, keyValues...)
+	args = withResourceGroupAndSubscription(args, subscription, "")
+	_, err := az.azureCLI(args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to tag AKS cluster %s in resource group %s", name, resourceGroup)
+	}
+	return nil
+}
+
+func withResourceGroupAndSubscription(args []string, subscription string, resourceGroup string) []string {
+	if resourceGroup != "" {
+		args = append(args, "--resource-group", resourceGroup)
+	}
+	if subscription != "" {
+		args = append(args, "--subscription", subscription)
+	}
+	return args
+}
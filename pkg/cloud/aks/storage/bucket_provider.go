@@ -0,0 +1,326 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/v2/pkg/config"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// AzureStorageAccountEnvVar the environment variable for the name of the storage account
+	AzureStorageAccountEnvVar = "AZURE_STORAGE_ACCOUNT"
+	// AzureStorageKeyEnvVar the environment variable for the access key of the storage account
+	AzureStorageKeyEnvVar = "AZURE_STORAGE_KEY"
+	// AzureStorageUploadTimeoutEnvVar the environment variable for the timeout of the uploads, such as 10m
+	AzureStorageUploadTimeoutEnvVar = "AZURE_STORAGE_UPLOAD_TIMEOUT"
+
+	// the size of the blocks that the uploads are streamed in
+	uploadBufferSize = 4 * 1024 * 1024
+	// the number of blocks that are uploaded in parallel
+	uploadMaxBuffers = 4
+)
+
+var (
+	defaultBucketTimeout = 20 * time.Second
+	defaultUploadTimeout = 5 * time.Minute
+)
+
+// AKSBucketProvider the bucket provider for Azure Blob Storage, where the buckets are containers of a storage account
+// and are referenced by azblob://container URLs
+type AKSBucketProvider struct {
+	Requirements  *config.RequirementsConfig
+	UploadTimeout time.Duration
+	serviceURL    *azblob.ServiceURL
+}
+
+func (b *AKSBucketProvider) service() (azblob.ServiceURL, error) {
+	if b.serviceURL != nil {
+		return *b.serviceURL, nil
+	}
+	accountName := os.Getenv(AzureStorageAccountEnvVar)
+	if b.Requirements != nil && b.Requirements.Cluster.AzureConfig != nil && b.Requirements.Cluster.AzureConfig.StorageAccountName != "" {
+		accountName = b.Requirements.Cluster.AzureConfig.StorageAccountName
+	}
+	if accountName == "" {
+		return azblob.ServiceURL{}, fmt.Errorf("requirements do not specify an Azure storage account and $%s is not set", AzureStorageAccountEnvVar)
+	}
+	accountKey := os.Getenv(AzureStorageKeyEnvVar)
+	if accountKey == "" {
+		return azblob.ServiceURL{}, fmt.Errorf("no access key for Azure storage account %s as $%s is not set", accountName, AzureStorageKeyEnvVar)
+	}
+	credential, err := azblob.NewSharedKeyCredential(accountName, accountKey)
+	if err != nil {
+		return azblob.ServiceURL{}, errors.Wrapf(err, "creating the credentials of Azure storage account %s", accountName)
+	}
+	u, err := url.Parse(fmt.Sprintf("https://%s.blob.core.windows.net", accountName))
+	if err != nil {
+		return azblob.ServiceURL{}, errors.Wrapf(err, "creating the URL of Azure storage account %s", accountName)
+	}
+	serviceURL := azblob.NewServiceURL(*u, azblob.NewPipeline(credential, azblob.PipelineOptions{}))
+	b.serviceURL = &serviceURL
+	return serviceURL, nil
+}
+
+func (b *AKSBucketProvider) container(bucketURL string) (azblob.ContainerURL, *url.URL, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return azblob.ContainerURL{}, nil, errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", bucketURL)
+	}
+	if u.Host == "" {
+		return azblob.ContainerURL{}, nil, fmt.Errorf("the provided bucket location has no container: %s", bucketURL)
+	}
+	svc, err := b.service()
+	if err != nil {
+		return azblob.ContainerURL{}, nil, err
+	}
+	return svc.NewContainerURL(u.Host), u, nil
+}
+
+// CreateNewBucketForCluster creates a new dynamic bucket
+func (b *AKSBucketProvider) CreateNewBucketForCluster(clusterName string, bucketKind string) (string, error) {
+	uuid4, _ := uuid.NewV4()
+	containerName := strings.ToLower(fmt.Sprintf("%s-%s-%s", clusterName, bucketKind, uuid4.String()))
+
+	// Max length is 63, https://docs.microsoft.com/en-us/rest/api/storageservices/naming-and-referencing-containers--blobs--and-metadata
+	if len(containerName) > 63 {
+		containerName = containerName[:63]
+	}
+	containerName = strings.TrimRight(containerName, "-")
+	bucketURL := "azblob://" + containerName
+	err := b.EnsureBucketIsCreated(bucketURL)
+	if err != nil {
+		return bucketURL, errors.Wrapf(err, "failed to create bucket %s", bucketURL)
+	}
+
+	return bucketURL, nil
+}
+
+// EnsureBucketIsCreated ensures the bucket URL is created
+func (b *AKSBucketProvider) EnsureBucketIsCreated(bucketURL string) error {
+	container, u, err := b.container(bucketURL)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+
+	_, err = container.GetProperties(ctx, azblob.LeaseAccessConditions{})
+	if err == nil {
+		return nil // container already exists
+	}
+	if !isServiceCode(err, azblob.ServiceCodeContainerNotFound) {
+		return errors.Wrapf(err, "failed to check if %s container exists already", u.Host)
+	}
+
+	infoBucketURL := util.ColorInfo(bucketURL)
+	log.Logger().Infof("The bucket %s does not exist so lets create it", infoBucketURL)
+
+	_, err = container.Create(ctx, azblob.Metadata{}, azblob.PublicAccessNone)
+	if err != nil && !isServiceCode(err, azblob.ServiceCodeContainerAlreadyExists) {
+		return errors.Wrapf(err, "there was a problem creating the container %s in Azure", u.Host)
+	}
+	return nil
+}
+
+// UploadFileToBucket uploads a file to the container of the provided bucket URL with the provided outputName
+func (b *AKSBucketProvider) UploadFileToBucket(reader io.Reader, outputName string, bucketURL string) (string, error) {
+	container, _, err := b.container(bucketURL)
+	if err != nil {
+		return "", err
+	}
+	timeout := b.UploadTimeout
+	if timeout <= 0 {
+		timeout = defaultUploadTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	// stream the contents in blocks rather than reading them all in memory
+	blob := container.NewBlockBlobURL(outputName)
+	_, err = azblob.UploadStreamToBlockBlob(ctx, reader, blob, azblob.UploadStreamToBlockBlobOptions{
+		BufferSize: uploadBufferSize,
+		MaxBuffers: uploadMaxBuffers,
+		BlobHTTPHeaders: azblob.BlobHTTPHeaders{
+			ContentType: util.ContentTypeForFileName(outputName),
+		},
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "uploading %s to the bucket %s", outputName, bucketURL)
+	}
+	location := strings.TrimSuffix(bucketURL, "/") + "/" + outputName
+	log.Logger().Debugf("The file was uploaded successfully, location: %s", location)
+	return location, nil
+}
+
+// DownloadFileFromBucket downloads a file from an Azure Blob Storage container and converts the contents to a bufio.Scanner
+func (b *AKSBucketProvider) DownloadFileFromBucket(bucketURL string) (*bufio.Scanner, error) {
+	container, u, err := b.container(bucketURL)
+	if err != nil {
+		return nil, errors.Wrap(err, "there was a problem downloading from the bucket")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+
+	blob := container.NewBlobURL(strings.TrimPrefix(u.Path, "/"))
+	resp, err := blob.Download(ctx, 0, azblob.CountToEnd, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		return nil, errors.Wrapf(err, "downloading %s", bucketURL)
+	}
+	body := resp.Body(azblob.RetryReaderOptions{})
+	defer body.Close()
+	data, err := ioutil.ReadAll(body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading the contents of %s", bucketURL)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Split(bufio.ScanLines)
+	return scanner, nil
+}
+
+// ListObjects lists the blobs of the container whose names start with the given prefix
+func (b *AKSBucketProvider) ListObjects(bucketURL string, prefix string) ([]buckets.ObjectAttributes, error) {
+	container, u, err := b.container(bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+
+	rootURL := "azblob://" + u.Host
+	options := azblob.ListBlobsSegmentOptions{
+		Prefix:  buckets.KeyPrefix(u.Path, prefix),
+		Details: azblob.BlobListingDetails{Metadata: true},
+	}
+	var objects []buckets.ObjectAttributes
+	for marker := (azblob.Marker{}); marker.NotDone(); {
+		resp, err := container.ListBlobsFlatSegment(ctx, marker, options)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the blobs with prefix %s in container %s", options.Prefix, u.Host)
+		}
+		for _, item := range resp.Segment.BlobItems {
+			object := buckets.ObjectAttributes{
+				Key:      item.Name,
+				URL:      buckets.ObjectURL(rootURL, item.Name),
+				ModTime:  item.Properties.LastModified,
+				Metadata: item.Metadata,
+			}
+			if item.Properties.ContentLength != nil {
+				object.Size = *item.Properties.ContentLength
+			}
+			if item.Properties.ContentType != nil {
+				object.ContentType = *item.Properties.ContentType
+			}
+			objects = append(objects, object)
+		}
+		marker = resp.NextMarker
+	}
+	return objects, nil
+}
+
+// StatObject returns the attributes of the blob at the given URL or nil if it does not exist
+func (b *AKSBucketProvider) StatObject(objectURL string) (*buckets.ObjectAttributes, error) {
+	container, u, err := b.container(objectURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+
+	key := strings.TrimPrefix(u.Path, "/")
+	resp, err := container.NewBlobURL(key).GetProperties(ctx, azblob.BlobAccessConditions{})
+	if err != nil {
+		if isServiceCode(err, azblob.ServiceCodeBlobNotFound) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the properties of blob %s in container %s", key, u.Host)
+	}
+	return &buckets.ObjectAttributes{
+		Key:         key,
+		URL:         buckets.ObjectURL("azblob://"+u.Host, key),
+		ContentType: resp.ContentType(),
+		Size:        resp.ContentLength(),
+		ModTime:     resp.LastModified(),
+		Metadata:    resp.NewMetadata(),
+	}, nil
+}
+
+// DeleteObject deletes the blob at the given URL, doing nothing if it does not exist
+func (b *AKSBucketProvider) DeleteObject(objectURL string) error {
+	container, u, err := b.container(objectURL)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+
+	key := strings.TrimPrefix(u.Path, "/")
+	_, err = container.NewBlobURL(key).Delete(ctx, azblob.DeleteSnapshotsOptionInclude, azblob.BlobAccessConditions{})
+	if err != nil && !isServiceCode(err, azblob.ServiceCodeBlobNotFound) {
+		return errors.Wrapf(err, "failed to delete blob %s in container %s", key, u.Host)
+	}
+	return nil
+}
+
+// ReadObjectRange streams length bytes of the blob at the given URL starting at the offset
+func (b *AKSBucketProvider) ReadObjectRange(objectURL string, offset int64, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("the offset %d to read %s from must not be negative", offset, objectURL)
+	}
+	if length == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+	if length < 0 {
+		length = azblob.CountToEnd
+	}
+	container, u, err := b.container(objectURL)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), buckets.ReadObjectRangeTimeout)
+	key := strings.TrimPrefix(u.Path, "/")
+	resp, err := container.NewBlobURL(key).Download(ctx, offset, length, azblob.BlobAccessConditions{}, false)
+	if err != nil {
+		cancel()
+		return nil, errors.Wrapf(err, "failed to read blob %s in container %s", key, u.Host)
+	}
+	return buckets.NewReleasingReader(resp.Body(azblob.RetryReaderOptions{}), cancel), nil
+}
+
+func isServiceCode(err error, code azblob.ServiceCodeType) bool {
+	storageErr, ok := err.(azblob.StorageError)
+	return ok && storageErr.ServiceCode() == code
+}
+
+// NewAKSBucketProvider create a new provider for Azure Blob Storage, whose uploads time out after the duration of
+// $AZURE_STORAGE_UPLOAD_TIMEOUT if set
+func NewAKSBucketProvider(requirements *config.RequirementsConfig) buckets.Provider {
+	provider := &AKSBucketProvider{
+		Requirements:  requirements,
+		UploadTimeout: defaultUploadTimeout,
+	}
+	if value := os.Getenv(AzureStorageUploadTimeoutEnvVar); value != "" {
+		timeout, err := time.ParseDuration(value)
+		if err != nil || timeout <= 0 {
+			log.Logger().Warnf("Ignoring the invalid upload timeout $%s=%s", AzureStorageUploadTimeoutEnvVar, value)
+		} else {
+			provider.UploadTimeout = timeout
+		}
+	}
+	return provider
+}
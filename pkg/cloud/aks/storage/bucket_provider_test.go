@@ -0,0 +1,292 @@
+// +build unit
+
+package storage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Azure/azure-storage-blob-go/azblob"
+	"github.com/jenkins-x/jx/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeBlobService a minimal Azure Blob Storage service keeping containers and blobs in memory
+type fakeBlobService struct {
+	lock       sync.Mutex
+	containers map[string]map[string][]byte
+	blocks     map[string][]byte
+}
+
+func (s *fakeBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	paths := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	blobs, exists := s.containers[paths[0]]
+	if r.URL.Query().Get("restype") == "container" {
+		switch {
+		case r.Method == http.MethodGet && exists && r.URL.Query().Get("comp") == "list":
+			s.listBlobs(w, blobs, r.URL.Query().Get("prefix"))
+		case r.Method == http.MethodPut && exists:
+			writeBlobError(w, http.StatusConflict, azblob.ServiceCodeContainerAlreadyExists)
+		case r.Method == http.MethodPut:
+			s.containers[paths[0]] = map[string][]byte{}
+			w.WriteHeader(http.StatusCreated)
+		case exists:
+			w.WriteHeader(http.StatusOK)
+		default:
+			writeBlobError(w, http.StatusNotFound, azblob.ServiceCodeContainerNotFound)
+		}
+		return
+	}
+	if !exists || len(paths) < 2 {
+		writeBlobError(w, http.StatusNotFound, azblob.ServiceCodeContainerNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := ioutil.ReadAll(r.Body)
+		switch r.URL.Query().Get("comp") {
+		case "block":
+			s.blocks[r.URL.Query().Get("blockid")] = data
+		case "blocklist":
+			blockList := struct {
+				Latest []string `xml:"Latest"`
+			}{}
+			err := xml.Unmarshal(data, &blockList)
+			if err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			blob := []byte{}
+			for _, id := range blockList.Latest {
+				blob = append(blob, s.blocks[id]...)
+			}
+			blobs[paths[1]] = blob
+		default:
+			blobs[paths[1]] = data
+		}
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodGet, http.MethodHead:
+		data, ok := blobs[paths[1]]
+		if !ok {
+			writeBlobError(w, http.StatusNotFound, azblob.ServiceCodeBlobNotFound)
+			return
+		}
+		if rangeHeader := r.Header.Get("x-ms-range"); rangeHeader != "" {
+			r.Header.Set("Range", rangeHeader)
+		}
+		http.ServeContent(w, r, paths[1], time.Time{}, bytes.NewReader(data))
+	case http.MethodDelete:
+		if _, ok := blobs[paths[1]]; !ok {
+			writeBlobError(w, http.StatusNotFound, azblob.ServiceCodeBlobNotFound)
+			return
+		}
+		delete(blobs, paths[1])
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (s *fakeBlobService) listBlobs(w http.ResponseWriter, blobs map[string][]byte, prefix string) {
+	var names []string
+	for name := range blobs {
+		if strings.HasPrefix(name, prefix) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	w.Header().Set("Content-Type", "application/xml")
+	fmt.Fprint(w, `<?xml version="1.0" encoding="utf-8"?><EnumerationResults><Blobs>`)
+	for _, name := range names {
+		fmt.Fprintf(w, `<Blob><Name>%s</Name><Properties><Content-Length>%d</Content-Length></Properties></Blob>`, name, len(blobs[name]))
+	}
+	fmt.Fprint(w, `</Blobs><NextMarker/></EnumerationResults>`)
+}
+
+func writeBlobError(w http.ResponseWriter, status int, code azblob.ServiceCodeType) {
+	w.Header().Set("x-ms-error-code", string(code))
+	w.WriteHeader(status)
+}
+
+func newTestProvider(t *testing.T, containers ...string) (*AKSBucketProvider, *httptest.Server) {
+	service := &fakeBlobService{containers: map[string]map[string][]byte{}, blocks: map[string][]byte{}}
+	for _, c := range containers {
+		service.containers[c] = map[string][]byte{}
+	}
+	server := httptest.NewServer(service)
+
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	serviceURL := azblob.NewServiceURL(*u, azblob.NewPipeline(azblob.NewAnonymousCredential(), azblob.PipelineOptions{}))
+	return &AKSBucketProvider{
+		Requirements: &config.RequirementsConfig{},
+		serviceURL:   &serviceURL,
+	}, server
+}
+
+func TestAKSBucketProvider_EnsureBucketIsCreated(t *testing.T) {
+	p, server := newTestProvider(t, "existing")
+	defer server.Close()
+
+	err := p.EnsureBucketIsCreated("azblob://existing")
+	assert.NoError(t, err)
+
+	err = p.EnsureBucketIsCreated("azblob://logs")
+	assert.NoError(t, err)
+
+	_, err = p.UploadFileToBucket(strings.NewReader("hello"), "hello.txt", "azblob://logs")
+	assert.NoError(t, err, "the container should have been created")
+}
+
+func TestAKSBucketProvider_CreateNewBucketForCluster(t *testing.T) {
+	p, server := newTestProvider(t)
+	defer server.Close()
+
+	bucketURL, err := p.CreateNewBucketForCluster("My-Cluster", "logs")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(bucketURL, "azblob://my-cluster-logs-"), "unexpected bucket URL %s", bucketURL)
+
+	longName := strings.Repeat("a", 62)
+	bucketURL, err = p.CreateNewBucketForCluster(longName+"-cluster", "logs")
+	require.NoError(t, err)
+	assert.Equal(t, "azblob://"+longName, bucketURL)
+}
+
+func TestAKSBucketProvider_UploadAndDownloadFile(t *testing.T) {
+	p, server := newTestProvider(t, "logs")
+	defer server.Close()
+
+	location, err := p.UploadFileToBucket(strings.NewReader("line 1\nline 2\n"), "jenkins-x/logs/myapp/1.log", "azblob://logs")
+	require.NoError(t, err)
+	assert.Equal(t, "azblob://logs/jenkins-x/logs/myapp/1.log", location)
+
+	scanner, err := p.DownloadFileFromBucket(location)
+	require.NoError(t, err)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.Equal(t, []string{"line 1", "line 2"}, lines)
+
+	_, err = p.DownloadFileFromBucket("azblob://logs/missing.log")
+	assert.Error(t, err)
+}
+
+func TestAKSBucketProvider_UploadLargeFile(t *testing.T) {
+	p, server := newTestProvider(t, "logs")
+	defer server.Close()
+
+	data := bytes.Repeat([]byte("0123456789abcdef"), uploadBufferSize/8+1)
+	location, err := p.UploadFileToBucket(bytes.NewReader(data), "jenkins-x/logs/myapp/1.log", "azblob://logs")
+	require.NoError(t, err)
+
+	reader, err := p.ReadObjectRange(location, 0, -1)
+	require.NoError(t, err)
+	actual, err := ioutil.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, data, actual, "the file should have been uploaded in several blocks")
+}
+
+func TestNewAKSBucketProviderUploadTimeout(t *testing.T) {
+	original, exists := os.LookupEnv(AzureStorageUploadTimeoutEnvVar)
+	defer func() {
+		if exists {
+			os.Setenv(AzureStorageUploadTimeoutEnvVar, original)
+		} else {
+			os.Unsetenv(AzureStorageUploadTimeoutEnvVar)
+		}
+	}()
+
+	os.Unsetenv(AzureStorageUploadTimeoutEnvVar)
+	assert.Equal(t, defaultUploadTimeout, NewAKSBucketProvider(nil).(*AKSBucketProvider).UploadTimeout)
+
+	os.Setenv(AzureStorageUploadTimeoutEnvVar, "10m")
+	assert.Equal(t, 10*time.Minute, NewAKSBucketProvider(nil).(*AKSBucketProvider).UploadTimeout)
+
+	os.Setenv(AzureStorageUploadTimeoutEnvVar, "soon")
+	assert.Equal(t, defaultUploadTimeout, NewAKSBucketProvider(nil).(*AKSBucketProvider).UploadTimeout)
+}
+
+func TestAKSBucketProvider_Objects(t *testing.T) {
+	p, server := newTestProvider(t, "logs")
+	defer server.Close()
+
+	for _, name := range []string{"jenkins-x/logs/myapp/1.log", "jenkins-x/logs/myapp/2.log", "jenkins-x/tests/myapp/1.xml"} {
+		_, err := p.UploadFileToBucket(strings.NewReader("0123456789"), name, "azblob://logs")
+		require.NoError(t, err)
+	}
+
+	objects, err := p.ListObjects("azblob://logs/jenkins-x", "logs/")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "jenkins-x/logs/myapp/1.log", objects[0].Key)
+	assert.Equal(t, "azblob://logs/jenkins-x/logs/myapp/1.log", objects[0].URL)
+	assert.Equal(t, int64(10), objects[0].Size)
+
+	attributes, err := p.StatObject(objects[1].URL)
+	require.NoError(t, err)
+	require.NotNil(t, attributes)
+	assert.Equal(t, "jenkins-x/logs/myapp/2.log", attributes.Key)
+	assert.Equal(t, int64(10), attributes.Size)
+
+	reader, err := p.ReadObjectRange(objects[1].URL, 2, 3)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "234", string(data))
+
+	err = p.DeleteObject(objects[1].URL)
+	require.NoError(t, err)
+	err = p.DeleteObject(objects[1].URL)
+	assert.NoError(t, err, "deleting a missing blob should succeed")
+
+	attributes, err = p.StatObject(objects[1].URL)
+	require.NoError(t, err)
+	assert.Nil(t, attributes)
+}
+
+func TestAKSBucketProvider_serviceWithNoAccount(t *testing.T) {
+	defer restoreEnv(AzureStorageAccountEnvVar, os.Getenv(AzureStorageAccountEnvVar))
+	defer restoreEnv(AzureStorageKeyEnvVar, os.Getenv(AzureStorageKeyEnvVar))
+	os.Unsetenv(AzureStorageAccountEnvVar) //nolint:errcheck
+	p := &AKSBucketProvider{
+		Requirements: &config.RequirementsConfig{},
+	}
+
+	_, err := p.service()
+	assert.Error(t, err)
+
+	p.Requirements.Cluster.AzureConfig = &config.AzureConfig{
+		StorageAccountName: "jxstorage",
+	}
+	os.Setenv(AzureStorageKeyEnvVar, "a2V5") //nolint:errcheck
+	svc, err := p.service()
+	require.NoError(t, err)
+	u := svc.URL()
+	assert.Equal(t, "jxstorage.blob.core.windows.net", u.Host)
+}
+
+func restoreEnv(name string, value string) {
+	if value == "" {
+		os.Unsetenv(name) //nolint:errcheck
+		return
+	}
+	os.Setenv(name, value) //nolint:errcheck
+}
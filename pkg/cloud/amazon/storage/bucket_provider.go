@@ -191,6 +191,42 @@ func (b *AmazonBucketProvider) DownloadFileFromBucket(bucketURL string) (*bufio.
 	return scanner, nil
 }
 
+// ListObjects lists the objects of the S3 bucket whose keys start with the given prefix
+func (b *AmazonBucketProvider) ListObjects(bucketURL string, prefix string) ([]buckets.ObjectAttributes, error) {
+	svc, err := b.s3()
+	if err != nil {
+		return nil, err
+	}
+	return listObjects(svc, bucketURL, prefix)
+}
+
+// StatObject returns the attributes of the object at the given S3 URL or nil if it does not exist
+func (b *AmazonBucketProvider) StatObject(objectURL string) (*buckets.ObjectAttributes, error) {
+	svc, err := b.s3()
+	if err != nil {
+		return nil, err
+	}
+	return statObject(svc, objectURL)
+}
+
+// DeleteObject deletes the object at the given S3 URL, doing nothing if it does not exist
+func (b *AmazonBucketProvider) DeleteObject(objectURL string) error {
+	svc, err := b.s3()
+	if err != nil {
+		return err
+	}
+	return deleteObject(svc, objectURL)
+}
+
+// ReadObjectRange streams length bytes of the object at the given S3 URL starting at the offset
+func (b *AmazonBucketProvider) ReadObjectRange(objectURL string, offset int64, length int64) (io.ReadCloser, error) {
+	svc, err := b.s3()
+	if err != nil {
+		return nil, err
+	}
+	return readObjectRange(svc, objectURL, offset, length)
+}
+
 // NewAmazonBucketProvider create a new provider for AWS
 func NewAmazonBucketProvider(requirements *config.RequirementsConfig) buckets.Provider {
 	return &AmazonBucketProvider{
@@ -6,10 +6,12 @@ import (
 	"bytes"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"strings"
 	"testing"
 
 	"github.com/acarl005/stripansi"
+	"github.com/aws/aws-sdk-go/aws"
 	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/s3"
 	"github.com/aws/aws-sdk-go/service/s3/s3iface"
@@ -70,6 +72,40 @@ func (m mockedS3) CreateBucket(input *s3.CreateBucketInput) (*s3.CreateBucketOut
 	return nil, nil
 }
 
+func (m mockedS3) ListObjectsV2Pages(input *s3.ListObjectsV2Input, fn func(*s3.ListObjectsV2Output, bool) bool) error {
+	pages := [][]string{{"logs/myapp/1.log", "logs/myapp/2.log"}, {"logs/other/1.log"}}
+	for i, keys := range pages {
+		page := &s3.ListObjectsV2Output{}
+		for _, key := range keys {
+			if strings.HasPrefix(key, *input.Prefix) {
+				page.Contents = append(page.Contents, &s3.Object{Key: aws.String(key), Size: aws.Int64(int64(len(key)))})
+			}
+		}
+		if !fn(page, i == len(pages)-1) {
+			break
+		}
+	}
+	return nil
+}
+
+func (m mockedS3) HeadObject(input *s3.HeadObjectInput) (*s3.HeadObjectOutput, error) {
+	if *input.Key == "logs/myapp/1.log" {
+		return &s3.HeadObjectOutput{
+			ContentLength: aws.Int64(16),
+			ContentType:   aws.String("text/plain; charset=utf-8"),
+		}, nil
+	}
+	return nil, FakeRequestFailure{
+		awserr.NewRequestFailure(awserr.New("NotFound", "", nil), 404, ""),
+	}
+}
+
+func (m mockedS3) GetObject(input *s3.GetObjectInput) (*s3.GetObjectOutput, error) {
+	return &s3.GetObjectOutput{
+		Body: ioutil.NopCloser(strings.NewReader(aws.StringValue(input.Range))),
+	}, nil
+}
+
 func TestAmazonBucketProvider_EnsureBucketIsCreated(t *testing.T) {
 	p := AmazonBucketProvider{
 		Requirements: &config.RequirementsConfig{
@@ -191,3 +227,62 @@ func TestAmazonBucketProvider_DownloadFileFromBucket(t *testing.T) {
 
 	assert.Equal(t, expectedBucketContents, bucketContent, "the returned contents should be match")
 }
+
+func TestAmazonBucketProvider_ListObjects(t *testing.T) {
+	p := AmazonBucketProvider{
+		api: &mockedS3{},
+	}
+
+	objects, err := p.ListObjects("s3://bucket", "logs/")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 3, "all the pages should be listed")
+
+	objects, err = p.ListObjects("s3://bucket/logs", "myapp/")
+	assert.NoError(t, err)
+	assert.Len(t, objects, 2)
+	assert.Equal(t, "logs/myapp/1.log", objects[0].Key)
+	assert.Equal(t, "s3://bucket/logs/myapp/1.log", objects[0].URL)
+	assert.Equal(t, int64(16), objects[0].Size)
+}
+
+func TestAmazonBucketProvider_StatObject(t *testing.T) {
+	p := AmazonBucketProvider{
+		api: &mockedS3{},
+	}
+
+	attributes, err := p.StatObject("s3://bucket/logs/myapp/1.log")
+	assert.NoError(t, err)
+	if assert.NotNil(t, attributes) {
+		assert.Equal(t, "logs/myapp/1.log", attributes.Key)
+		assert.Equal(t, int64(16), attributes.Size)
+		assert.Equal(t, "text/plain; charset=utf-8", attributes.ContentType)
+	}
+
+	attributes, err = p.StatObject("s3://bucket/logs/myapp/missing.log")
+	assert.NoError(t, err)
+	assert.Nil(t, attributes)
+}
+
+func TestAmazonBucketProvider_ReadObjectRange(t *testing.T) {
+	p := AmazonBucketProvider{
+		api: &mockedS3{},
+	}
+
+	tests := []struct {
+		offset      int64
+		length      int64
+		rangeHeader string
+	}{
+		{offset: 0, length: -1, rangeHeader: ""},
+		{offset: 10, length: -1, rangeHeader: "bytes=10-"},
+		{offset: 10, length: 5, rangeHeader: "bytes=10-14"},
+		{offset: 10, length: 0, rangeHeader: ""},
+	}
+	for _, test := range tests {
+		reader, err := p.ReadObjectRange("s3://bucket/logs/myapp/1.log", test.offset, test.length)
+		assert.NoError(t, err)
+		data, err := ioutil.ReadAll(reader)
+		assert.NoError(t, err)
+		assert.Equal(t, test.rangeHeader, string(data), "offset %d length %d", test.offset, test.length)
+	}
+}
@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
+	"github.com/pkg/errors"
+)
+
+// splitObjectURL returns the URL of the bucket without any path, the bucket name and the key of an S3 URL
+func splitObjectURL(objectURL string) (string, string, string, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return "", "", "", errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", objectURL)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	u.Path = ""
+	return u.String(), u.Host, key, nil
+}
+
+// listObjects lists the objects of the S3 bucket whose keys start with the given prefix
+func listObjects(api s3iface.S3API, bucketURL string, prefix string) ([]buckets.ObjectAttributes, error) {
+	rootURL, bucketName, key, err := splitObjectURL(bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	prefix = buckets.KeyPrefix(key, prefix)
+	var objects []buckets.ObjectAttributes
+	err = api.ListObjectsV2Pages(&s3.ListObjectsV2Input{
+		Bucket: aws.String(bucketName),
+		Prefix: aws.String(prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, obj := range page.Contents {
+			key := aws.StringValue(obj.Key)
+			objects = append(objects, buckets.ObjectAttributes{
+				Key:     key,
+				URL:     buckets.ObjectURL(rootURL, key),
+				Size:    aws.Int64Value(obj.Size),
+				ModTime: aws.TimeValue(obj.LastModified),
+			})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the objects with prefix %s in bucket %s", prefix, bucketName)
+	}
+	return objects, nil
+}
+
+// statObject returns the attributes of the S3 object or nil if it does not exist
+func statObject(api s3iface.S3API, objectURL string) (*buckets.ObjectAttributes, error) {
+	rootURL, bucketName, key, err := splitObjectURL(objectURL)
+	if err != nil {
+		return nil, err
+	}
+	output, err := api.HeadObject(&s3.HeadObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		reqFailure, ok := err.(s3.RequestFailure)
+		if ok && reqFailure.StatusCode() == 404 {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the attributes of key %s in bucket %s", key, bucketName)
+	}
+	return &buckets.ObjectAttributes{
+		Key:         key,
+		URL:         buckets.ObjectURL(rootURL, key),
+		ContentType: aws.StringValue(output.ContentType),
+		Size:        aws.Int64Value(output.ContentLength),
+		ModTime:     aws.TimeValue(output.LastModified),
+		Metadata:    aws.StringValueMap(output.Metadata),
+	}, nil
+}
+
+// deleteObject deletes the S3 object, which succeeds if it does not exist
+func deleteObject(api s3iface.S3API, objectURL string) error {
+	_, bucketName, key, err := splitObjectURL(objectURL)
+	if err != nil {
+		return err
+	}
+	_, err = api.DeleteObject(&s3.DeleteObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete key %s in bucket %s", key, bucketName)
+	}
+	return nil
+}
+
+// readObjectRange returns a reader of length bytes of the S3 object starting at the offset, reading to the end of the
+// object if the length is negative
+func readObjectRange(api s3iface.S3API, objectURL string, offset int64, length int64) (io.ReadCloser, error) {
+	if offset < 0 {
+		return nil, fmt.Errorf("the offset %d to read %s from must not be negative", offset, objectURL)
+	}
+	if length == 0 {
+		return ioutil.NopCloser(bytes.NewReader(nil)), nil
+	}
+	_, bucketName, key, err := splitObjectURL(objectURL)
+	if err != nil {
+		return nil, err
+	}
+	input := &s3.GetObjectInput{
+		Bucket: aws.String(bucketName),
+		Key:    aws.String(key),
+	}
+	if length > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+	} else if offset > 0 {
+		input.Range = aws.String(fmt.Sprintf("bytes=%d-", offset))
+	}
+	output, err := api.GetObject(input)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read key %s in bucket %s", key, bucketName)
+	}
+	return output.Body, nil
+}
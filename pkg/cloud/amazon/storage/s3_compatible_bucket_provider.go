@@ -0,0 +1,296 @@
+package storage
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3iface"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
+	"github.com/jenkins-x/jx/v2/pkg/config"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	uuid "github.com/satori/go.uuid"
+)
+
+const (
+	// EndpointQueryParameter the bucket URL query parameter for the endpoint of an S3 compatible storage
+	EndpointQueryParameter = "endpoint"
+	// RegionQueryParameter the bucket URL query parameter for the region of an S3 compatible storage
+	RegionQueryParameter = "region"
+	// DisableSSLQueryParameter the bucket URL query parameter to use http to talk to an S3 compatible storage
+	DisableSSLQueryParameter = "disableSSL"
+	// ForcePathStyleQueryParameter the bucket URL query parameter to use path style addressing of the buckets
+	ForcePathStyleQueryParameter = "s3ForcePathStyle"
+
+	defaultS3CompatibleRegion = "us-east-1"
+)
+
+// S3CompatibleBucketProvider the bucket provider for S3 compatible storage such as MinIO. The storage is configured
+// with the query parameters of the bucket URLs in the same way as the gocloud s3blob driver,
+// e.g. s3://logs?endpoint=minio.jx.svc.cluster.local:9000&disableSSL=true&s3ForcePathStyle=true
+type S3CompatibleBucketProvider struct {
+	Requirements *config.RequirementsConfig
+	apis         map[string]s3iface.S3API
+	apisLock     sync.Mutex
+}
+
+// IsS3CompatibleURL returns true if the URL is an s3 bucket URL with a custom endpoint
+func IsS3CompatibleURL(u *url.URL) bool {
+	return u != nil && u.Scheme == "s3" && u.Query().Get(EndpointQueryParameter) != ""
+}
+
+func (b *S3CompatibleBucketProvider) s3(u *url.URL) (s3iface.S3API, error) {
+	query := u.Query()
+	endpoint := query.Get(EndpointQueryParameter)
+	if endpoint == "" {
+		return nil, fmt.Errorf("the bucket URL %s does not specify an %s", u.String(), EndpointQueryParameter)
+	}
+	b.apisLock.Lock()
+	defer b.apisLock.Unlock()
+	if api, ok := b.apis[u.RawQuery]; ok {
+		return api, nil
+	}
+	region := query.Get(RegionQueryParameter)
+	if region == "" && b.Requirements != nil {
+		region = b.Requirements.Cluster.Region
+	}
+	if region == "" {
+		region = defaultS3CompatibleRegion
+	}
+	disableSSL, err := queryBool(u, DisableSSLQueryParameter)
+	if err != nil {
+		return nil, err
+	}
+	forcePathStyle, err := queryBool(u, ForcePathStyleQueryParameter)
+	if err != nil {
+		return nil, err
+	}
+	sess, err := session.NewSession(&aws.Config{
+		Region:           aws.String(region),
+		Endpoint:         aws.String(endpoint),
+		DisableSSL:       aws.Bool(disableSSL),
+		S3ForcePathStyle: aws.Bool(forcePathStyle),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create a session for the S3 compatible storage %s", endpoint)
+	}
+	api := s3.New(sess)
+	if b.apis == nil {
+		b.apis = map[string]s3iface.S3API{}
+	}
+	b.apis[u.RawQuery] = api
+	return api, nil
+}
+
+func queryBool(u *url.URL, name string) (bool, error) {
+	text := u.Query().Get(name)
+	if text == "" {
+		return false, nil
+	}
+	value, err := strconv.ParseBool(text)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing the %s of bucket URL %s", name, u.String())
+	}
+	return value, nil
+}
+
+// storageQuery returns the query of the first S3 compatible storage URL of the requirements
+func (b *S3CompatibleBucketProvider) storageQuery() string {
+	if b.Requirements == nil {
+		return ""
+	}
+	storage := b.Requirements.Storage
+	for _, entry := range []config.StorageEntryConfig{storage.Logs, storage.Reports, storage.Repository, storage.Backup} {
+		if entry.URL == "" {
+			continue
+		}
+		u, err := url.Parse(entry.URL)
+		if err == nil && IsS3CompatibleURL(u) {
+			return u.RawQuery
+		}
+	}
+	return ""
+}
+
+// CreateNewBucketForCluster creates a new dynamic bucket on the S3 compatible storage of the requirements
+func (b *S3CompatibleBucketProvider) CreateNewBucketForCluster(clusterName string, bucketKind string) (string, error) {
+	query := b.storageQuery()
+	if query == "" {
+		return "", fmt.Errorf("the requirements have no storage URL with an %s to create the bucket on", EndpointQueryParameter)
+	}
+	uuid4, _ := uuid.NewV4()
+	bucketName := strings.ToLower(fmt.Sprintf("%s-%s-%s", clusterName, bucketKind, uuid4.String()))
+
+	// Max length is 63, https://docs.aws.amazon.com/AmazonS3/latest/dev/BucketRestrictions.html
+	if len(bucketName) > 63 {
+		bucketName = bucketName[:63]
+	}
+	bucketName = strings.TrimRight(bucketName, "-")
+	bucketURL := "s3://" + bucketName + "?" + query
+	err := b.EnsureBucketIsCreated(bucketURL)
+	if err != nil {
+		return bucketURL, errors.Wrapf(err, "failed to create bucket %s", bucketURL)
+	}
+
+	return bucketURL, nil
+}
+
+// EnsureBucketIsCreated ensures the bucket URL is created
+func (b *S3CompatibleBucketProvider) EnsureBucketIsCreated(bucketURL string) error {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse bucket name from %s", bucketURL)
+	}
+	svc, err := b.s3(u)
+	if err != nil {
+		return err
+	}
+	bucketName := u.Host
+
+	// Check if bucket exists already
+	_, err = svc.HeadBucket(&s3.HeadBucketInput{Bucket: aws.String(bucketName)})
+	if err == nil {
+		return nil // bucket already exists
+	}
+	reqFailure, ok := err.(s3.RequestFailure)
+	if !ok || reqFailure.StatusCode() != 404 {
+		return errors.Wrapf(err, "failed to check if %s bucket exists already", bucketName)
+	}
+
+	infoBucketURL := util.ColorInfo(bucketURL)
+	log.Logger().Infof("The bucket %s does not exist so lets create it", infoBucketURL)
+
+	_, err = svc.CreateBucket(&s3.CreateBucketInput{
+		Bucket: aws.String(bucketName),
+	})
+	if err != nil {
+		return errors.Wrapf(err, "there was a problem creating the bucket %s in the S3 compatible storage", bucketName)
+	}
+	return nil
+}
+
+// UploadFileToBucket uploads a file to the bucket of the provided bucket URL with the provided outputName, returning
+// the URL of the file which keeps the query of the bucket URL
+func (b *S3CompatibleBucketProvider) UploadFileToBucket(reader io.Reader, outputName string, bucketURL string) (string, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", bucketURL)
+	}
+	svc, err := b.s3(u)
+	if err != nil {
+		return "", err
+	}
+	key := strings.TrimPrefix(strings.TrimSuffix(u.Path, "/")+"/"+outputName, "/")
+	uploader := s3manager.NewUploaderWithClient(svc)
+	_, err = uploader.Upload(&s3manager.UploadInput{
+		Bucket:      aws.String(u.Host),
+		Key:         aws.String(key),
+		ContentType: aws.String(util.ContentTypeForFileName(outputName)),
+		Body:        reader,
+	})
+	if err != nil {
+		return "", errors.Wrapf(err, "uploading %s to the bucket %s", outputName, u.Host)
+	}
+	u.Path = "/" + key
+	log.Logger().Debugf("The file was uploaded successfully, location: %s", u.String())
+	return u.String(), nil
+}
+
+// DownloadFileFromBucket downloads a file from an S3 compatible bucket and converts the contents to a bufio.Scanner
+func (b *S3CompatibleBucketProvider) DownloadFileFromBucket(bucketURL string) (*bufio.Scanner, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", bucketURL)
+	}
+	svc, err := b.s3(u)
+	if err != nil {
+		return nil, errors.Wrap(err, "there was a problem downloading from the bucket")
+	}
+	output, err := svc.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(u.Host),
+		Key:    aws.String(strings.TrimPrefix(u.Path, "/")),
+	})
+	if err != nil {
+		return nil, errors.Wrapf(err, "downloading %s", bucketURL)
+	}
+	defer output.Body.Close()
+	data, err := ioutil.ReadAll(output.Body)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading the contents of %s", bucketURL)
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Split(bufio.ScanLines)
+	return scanner, nil
+}
+
+// ListObjects lists the objects of the S3 compatible bucket whose keys start with the given prefix
+func (b *S3CompatibleBucketProvider) ListObjects(bucketURL string, prefix string) ([]buckets.ObjectAttributes, error) {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", bucketURL)
+	}
+	svc, err := b.s3(u)
+	if err != nil {
+		return nil, err
+	}
+	return listObjects(svc, bucketURL, prefix)
+}
+
+// StatObject returns the attributes of the object at the given URL or nil if it does not exist
+func (b *S3CompatibleBucketProvider) StatObject(objectURL string) (*buckets.ObjectAttributes, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", objectURL)
+	}
+	svc, err := b.s3(u)
+	if err != nil {
+		return nil, err
+	}
+	return statObject(svc, objectURL)
+}
+
+// DeleteObject deletes the object at the given URL, doing nothing if it does not exist
+func (b *S3CompatibleBucketProvider) DeleteObject(objectURL string) error {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", objectURL)
+	}
+	svc, err := b.s3(u)
+	if err != nil {
+		return err
+	}
+	return deleteObject(svc, objectURL)
+}
+
+// ReadObjectRange streams length bytes of the object at the given URL starting at the offset
+func (b *S3CompatibleBucketProvider) ReadObjectRange(objectURL string, offset int64, length int64) (io.ReadCloser, error) {
+	u, err := url.Parse(objectURL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "the provided bucket location is not a valid URL: %s", objectURL)
+	}
+	svc, err := b.s3(u)
+	if err != nil {
+		return nil, err
+	}
+	return readObjectRange(svc, objectURL, offset, length)
+}
+
+// NewS3CompatibleBucketProvider create a new provider for S3 compatible storage
+func NewS3CompatibleBucketProvider(requirements *config.RequirementsConfig) buckets.Provider {
+	return &S3CompatibleBucketProvider{
+		Requirements: requirements,
+	}
+}
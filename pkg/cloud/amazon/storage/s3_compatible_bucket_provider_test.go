@@ -0,0 +1,279 @@
+// +build unit
+
+package storage
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/v2/pkg/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3Server a minimal S3 compatible server using path style addressing keeping buckets and objects in memory
+type fakeS3Server struct {
+	lock    sync.Mutex
+	buckets map[string]map[string][]byte
+}
+
+func (s *fakeS3Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	paths := strings.SplitN(strings.TrimPrefix(r.URL.Path, "/"), "/", 2)
+	objects, exists := s.buckets[paths[0]]
+	if len(paths) < 2 || paths[1] == "" {
+		switch {
+		case r.Method == http.MethodGet && exists && r.URL.Query().Get("list-type") == "2":
+			s.listObjects(w, objects, r.URL.Query().Get("prefix"))
+		case r.Method == http.MethodPut:
+			s.buckets[paths[0]] = map[string][]byte{}
+			w.WriteHeader(http.StatusOK)
+		case exists:
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+		return
+	}
+	if !exists {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		data, _ := ioutil.ReadAll(r.Body)
+		objects[paths[1]] = data
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		data, ok := objects[paths[1]]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		http.ServeContent(w, r, paths[1], time.Time{}, bytes.NewReader(data))
+	case http.MethodDelete:
+		delete(objects, paths[1])
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+type listBucketResult struct {
+	XMLName     xml.Name `xml:"http://s3.amazonaws.com/doc/2006-03-01/ ListBucketResult"`
+	Prefix      string
+	KeyCount    int
+	IsTruncated bool
+	Contents    []listBucketObject
+}
+
+type listBucketObject struct {
+	Key  string
+	Size int
+}
+
+func (s *fakeS3Server) listObjects(w http.ResponseWriter, objects map[string][]byte, prefix string) {
+	result := listBucketResult{Prefix: prefix}
+	for key, data := range objects {
+		if strings.HasPrefix(key, prefix) {
+			result.Contents = append(result.Contents, listBucketObject{Key: key, Size: len(data)})
+		}
+	}
+	sort.Slice(result.Contents, func(i, j int) bool {
+		return result.Contents[i].Key < result.Contents[j].Key
+	})
+	result.KeyCount = len(result.Contents)
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result) //nolint:errcheck
+}
+
+func newTestS3Server(t *testing.T) (*httptest.Server, string) {
+	server := httptest.NewServer(&fakeS3Server{buckets: map[string]map[string][]byte{}})
+	u, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	query := url.Values{}
+	query.Set(EndpointQueryParameter, u.Host)
+	query.Set(DisableSSLQueryParameter, "true")
+	query.Set(ForcePathStyleQueryParameter, "true")
+	return server, query.Encode()
+}
+
+func setTestAWSCredentials() func() {
+	values := map[string]string{
+		"AWS_ACCESS_KEY_ID":         "minio",
+		"AWS_SECRET_ACCESS_KEY":     "minio123",
+		"AWS_EC2_METADATA_DISABLED": "true",
+	}
+	old := map[string]string{}
+	for name, value := range values {
+		old[name] = os.Getenv(name)
+		os.Setenv(name, value) //nolint:errcheck
+	}
+	return func() {
+		for name, value := range old {
+			if value == "" {
+				os.Unsetenv(name) //nolint:errcheck
+				continue
+			}
+			os.Setenv(name, value) //nolint:errcheck
+		}
+	}
+}
+
+func TestIsS3CompatibleURL(t *testing.T) {
+	for text, expected := range map[string]bool{
+		"s3://logs?endpoint=minio:9000": true,
+		"s3://logs":                     false,
+		"gs://logs?endpoint=minio:9000": false,
+	} {
+		u, err := url.Parse(text)
+		require.NoError(t, err)
+		assert.Equal(t, expected, IsS3CompatibleURL(u), text)
+	}
+}
+
+func TestS3CompatibleBucketProvider_UploadAndDownloadFile(t *testing.T) {
+	defer setTestAWSCredentials()()
+	server, query := newTestS3Server(t)
+	defer server.Close()
+	p := &S3CompatibleBucketProvider{
+		Requirements: &config.RequirementsConfig{},
+	}
+
+	err := p.EnsureBucketIsCreated("s3://logs?" + query)
+	require.NoError(t, err)
+	err = p.EnsureBucketIsCreated("s3://logs?" + query)
+	require.NoError(t, err)
+
+	location, err := p.UploadFileToBucket(strings.NewReader("line 1\nline 2\n"), "jenkins-x/logs/myapp/1.log", "s3://logs?"+query)
+	require.NoError(t, err)
+	assert.Equal(t, "s3://logs/jenkins-x/logs/myapp/1.log?"+query, location)
+
+	scanner, err := p.DownloadFileFromBucket(location)
+	require.NoError(t, err)
+	var lines []string
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+	}
+	assert.Equal(t, []string{"line 1", "line 2"}, lines)
+
+	_, err = p.DownloadFileFromBucket("s3://logs/missing.log?" + query)
+	assert.Error(t, err)
+}
+
+func TestS3CompatibleBucketProvider_ConcurrentUploads(t *testing.T) {
+	defer setTestAWSCredentials()()
+	server, query := newTestS3Server(t)
+	defer server.Close()
+	err := NewS3CompatibleBucketProvider(&config.RequirementsConfig{}).EnsureBucketIsCreated("s3://logs?" + query)
+	require.NoError(t, err)
+	p := NewS3CompatibleBucketProvider(&config.RequirementsConfig{})
+
+	uploads := 10
+	wg := sync.WaitGroup{}
+	for i := 0; i < uploads; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// the uploads share the cached client of the storage, which none of them has created yet
+			bucketURL := fmt.Sprintf("s3://logs/myapp/%d?%s", i, query)
+			_, err := p.UploadFileToBucket(strings.NewReader("step log"), "1.log", bucketURL)
+			assert.NoError(t, err)
+		}(i)
+	}
+	wg.Wait()
+
+	objects, err := p.ListObjects("s3://logs?"+query, "myapp/")
+	require.NoError(t, err)
+	assert.Len(t, objects, uploads)
+}
+
+func TestS3CompatibleBucketProvider_CreateNewBucketForCluster(t *testing.T) {
+	defer setTestAWSCredentials()()
+	server, query := newTestS3Server(t)
+	defer server.Close()
+	p := &S3CompatibleBucketProvider{
+		Requirements: &config.RequirementsConfig{},
+	}
+
+	_, err := p.CreateNewBucketForCluster("test-cluster", "logs")
+	assert.Error(t, err, "the requirements have no S3 compatible storage")
+
+	p.Requirements.Storage.Logs = config.StorageEntryConfig{
+		Enabled: true,
+		URL:     "s3://logs?" + query,
+	}
+	bucketURL, err := p.CreateNewBucketForCluster("Test-Cluster", "reports")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(bucketURL, "s3://test-cluster-reports-"), "unexpected bucket URL %s", bucketURL)
+	assert.True(t, strings.HasSuffix(bucketURL, "?"+query), "unexpected bucket URL %s", bucketURL)
+
+	_, err = p.UploadFileToBucket(strings.NewReader("report"), "report.xml", bucketURL)
+	assert.NoError(t, err, "the bucket should have been created")
+}
+
+func TestS3CompatibleBucketProvider_s3WithNoEndpoint(t *testing.T) {
+	p := &S3CompatibleBucketProvider{
+		Requirements: &config.RequirementsConfig{},
+	}
+
+	u, err := url.Parse("s3://logs")
+	require.NoError(t, err)
+	_, err = p.s3(u)
+	assert.Error(t, err)
+}
+
+func TestS3CompatibleBucketProvider_Objects(t *testing.T) {
+	defer setTestAWSCredentials()()
+	server, query := newTestS3Server(t)
+	defer server.Close()
+	p := &S3CompatibleBucketProvider{
+		Requirements: &config.RequirementsConfig{},
+	}
+
+	bucketURL := "s3://logs?" + query
+	err := p.EnsureBucketIsCreated(bucketURL)
+	require.NoError(t, err)
+	for _, name := range []string{"jenkins-x/logs/myapp/1.log", "jenkins-x/logs/myapp/2.log", "jenkins-x/tests/myapp/1.xml"} {
+		_, err = p.UploadFileToBucket(strings.NewReader("0123456789"), name, bucketURL)
+		require.NoError(t, err)
+	}
+
+	objects, err := p.ListObjects(bucketURL, "jenkins-x/logs/")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "jenkins-x/logs/myapp/1.log", objects[0].Key)
+	assert.Equal(t, "s3://logs/jenkins-x/logs/myapp/1.log?"+query, objects[0].URL)
+	assert.Equal(t, int64(10), objects[0].Size)
+
+	attributes, err := p.StatObject(objects[1].URL)
+	require.NoError(t, err)
+	require.NotNil(t, attributes)
+	assert.Equal(t, int64(10), attributes.Size)
+
+	reader, err := p.ReadObjectRange(objects[1].URL, 2, 3)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "234", string(data))
+
+	err = p.DeleteObject(objects[1].URL)
+	require.NoError(t, err)
+	attributes, err = p.StatObject(objects[1].URL)
+	require.NoError(t, err)
+	assert.Nil(t, attributes)
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"io/ioutil"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/v2/pkg/cloud/gke"
@@ -20,10 +21,15 @@ import (
 	_ "gocloud.dev/blob/s3blob"
 )
 
+var (
+	defaultBucketTimeout = 20 * time.Second
+)
+
 // LegacyBucketProvider is the default provider for non boot clusters
 type LegacyBucketProvider struct {
 	gcloud     gke.GClouder
 	bucket     *blob.Bucket
+	bucketURL  string
 	classifier string
 }
 
@@ -64,6 +70,61 @@ func (p LegacyBucketProvider) UploadFileToBucket(reader io.Reader, outputName st
 	return u, nil
 }
 
+// ListObjects lists the objects of the bucket whose keys start with the given prefix
+func (p *LegacyBucketProvider) ListObjects(bucketURL string, prefix string) ([]ObjectAttributes, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+	bucket, bucketURL, key, err := p.openBucket(ctx, bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	return ListBucketObjects(ctx, bucket, bucketURL, KeyPrefix(key, prefix))
+}
+
+// StatObject returns the attributes of the object at the given URL or nil if it does not exist
+func (p *LegacyBucketProvider) StatObject(objectURL string) (*ObjectAttributes, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+	bucket, bucketURL, key, err := p.openBucket(ctx, objectURL)
+	if err != nil {
+		return nil, err
+	}
+	return StatBucketObject(ctx, bucket, bucketURL, key)
+}
+
+// DeleteObject deletes the object at the given URL, doing nothing if it does not exist
+func (p *LegacyBucketProvider) DeleteObject(objectURL string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketTimeout)
+	defer cancel()
+	bucket, bucketURL, key, err := p.openBucket(ctx, objectURL)
+	if err != nil {
+		return err
+	}
+	return DeleteBucketObject(ctx, bucket, bucketURL, key)
+}
+
+// ReadObjectRange streams length bytes of the object at the given URL starting at the offset
+func (p *LegacyBucketProvider) ReadObjectRange(objectURL string, offset int64, length int64) (io.ReadCloser, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), ReadObjectRangeTimeout)
+	bucket, bucketURL, key, err := p.openBucket(ctx, objectURL)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return ReadBucketObjectRange(ctx, bucket, bucketURL, key, offset, length, cancel)
+}
+
+// openBucket returns the initialized bucket if the URL is within it, otherwise it opens the bucket of the URL
+func (p *LegacyBucketProvider) openBucket(ctx context.Context, urlText string) (*blob.Bucket, string, string, error) {
+	if p.bucket != nil && p.bucketURL != "" {
+		bucketURL := strings.TrimSuffix(p.bucketURL, "/")
+		if urlText == bucketURL || strings.HasPrefix(urlText, bucketURL+"/") {
+			return p.bucket, bucketURL, strings.TrimPrefix(strings.TrimPrefix(urlText, bucketURL), "/"), nil
+		}
+	}
+	return OpenBucketURL(ctx, urlText)
+}
+
 func (LegacyBucketProvider) createContext() context.Context {
 	ctx, _ := context.WithTimeout(context.Background(), time.Second*20)
 	return ctx
@@ -80,6 +141,7 @@ func (p *LegacyBucketProvider) Initialize(bucketURL string, classifier string) e
 		return errors.Wrapf(err, "failed to open bucket %s", bucketURL)
 	}
 	p.bucket = bucket
+	p.bucketURL = bucketURL
 	p.classifier = classifier
 	return nil
 }
@@ -3,8 +3,25 @@ package buckets
 import (
 	"bufio"
 	"io"
+	"time"
 )
 
+// ObjectAttributes the attributes of an object stored in a bucket
+type ObjectAttributes struct {
+	// Key the key of the object within the bucket
+	Key string
+	// URL the full URL of the object which can be passed to the other functions of the Provider
+	URL string
+	// ContentType the MIME type of the object, which may be empty when listing objects
+	ContentType string
+	// Size the size of the object in bytes
+	Size int64
+	// ModTime the time the object was last modified
+	ModTime time.Time
+	// Metadata the user metadata of the object, which may be empty when listing objects
+	Metadata map[string]string
+}
+
 // Provider represents a bucket provider
 //go:generate pegomock generate github.com/jenkins-x/jx/v2/pkg/cloud/buckets Provider -o mocks/buckets_interface.go
 type Provider interface {
@@ -13,4 +30,13 @@ type Provider interface {
 	EnsureBucketIsCreated(bucketURL string) error
 	UploadFileToBucket(r io.Reader, outputName string, bucketURL string) (string, error)
 	DownloadFileFromBucket(bucketURL string) (*bufio.Scanner, error)
+	// ListObjects lists the objects of the bucket whose keys start with the given prefix
+	ListObjects(bucketURL string, prefix string) ([]ObjectAttributes, error)
+	// StatObject returns the attributes of the object at the given URL or nil if it does not exist
+	StatObject(objectURL string) (*ObjectAttributes, error)
+	// DeleteObject deletes the object at the given URL, doing nothing if it does not exist
+	DeleteObject(objectURL string) error
+	// ReadObjectRange streams length bytes of the object at the given URL starting at the offset. A negative length
+	// reads to the end of the object. The caller must close the returned reader
+	ReadObjectRange(objectURL string, offset int64, length int64) (io.ReadCloser, error)
 }
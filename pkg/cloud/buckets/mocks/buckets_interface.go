@@ -9,6 +9,7 @@ import (
 	"reflect"
 	"time"
 
+	buckets "github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
 	pegomock "github.com/petergtz/pegomock"
 )
 
@@ -46,6 +47,21 @@ func (mock *MockProvider) CreateNewBucketForCluster(_param0 string, _param1 stri
 	return ret0, ret1
 }
 
+func (mock *MockProvider) DeleteObject(_param0 string) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockProvider().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteObject", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockProvider) DownloadFileFromBucket(_param0 string) (*bufio.Scanner, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockProvider().")
@@ -80,6 +96,63 @@ func (mock *MockProvider) EnsureBucketIsCreated(_param0 string) error {
 	return ret0
 }
 
+func (mock *MockProvider) ListObjects(_param0 string, _param1 string) ([]buckets.ObjectAttributes, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListObjects", params, []reflect.Type{reflect.TypeOf((*[]buckets.ObjectAttributes)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []buckets.ObjectAttributes
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].([]buckets.ObjectAttributes)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockProvider) ReadObjectRange(_param0 string, _param1 int64, _param2 int64) (io.ReadCloser, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockProvider().")
+	}
+	params := []pegomock.Param{_param0, _param1, _param2}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ReadObjectRange", params, []reflect.Type{reflect.TypeOf((*io.ReadCloser)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 io.ReadCloser
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(io.ReadCloser)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
+func (mock *MockProvider) StatObject(_param0 string) (*buckets.ObjectAttributes, error) {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockProvider().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("StatObject", params, []reflect.Type{reflect.TypeOf((**buckets.ObjectAttributes)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 *buckets.ObjectAttributes
+	var ret1 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(*buckets.ObjectAttributes)
+		}
+		if result[1] != nil {
+			ret1 = result[1].(error)
+		}
+	}
+	return ret0, ret1
+}
+
 func (mock *MockProvider) UploadFileToBucket(_param0 io.Reader, _param1 string, _param2 string) (string, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockProvider().")
@@ -167,6 +240,33 @@ func (c *MockProvider_CreateNewBucketForCluster_OngoingVerification) GetAllCaptu
 	return
 }
 
+func (verifier *VerifierMockProvider) DeleteObject(_param0 string) *MockProvider_DeleteObject_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeleteObject", params, verifier.timeout)
+	return &MockProvider_DeleteObject_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockProvider_DeleteObject_OngoingVerification struct {
+	mock              *MockProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockProvider_DeleteObject_OngoingVerification) GetCapturedArguments() string {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *MockProvider_DeleteObject_OngoingVerification) GetAllCapturedArguments() (_param0 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockProvider) DownloadFileFromBucket(_param0 string) *MockProvider_DownloadFileFromBucket_OngoingVerification {
 	params := []pegomock.Param{_param0}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DownloadFileFromBucket", params, verifier.timeout)
@@ -221,6 +321,99 @@ func (c *MockProvider_EnsureBucketIsCreated_OngoingVerification) GetAllCapturedA
 	return
 }
 
+func (verifier *VerifierMockProvider) ListObjects(_param0 string, _param1 string) *MockProvider_ListObjects_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ListObjects", params, verifier.timeout)
+	return &MockProvider_ListObjects_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockProvider_ListObjects_OngoingVerification struct {
+	mock              *MockProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockProvider_ListObjects_OngoingVerification) GetCapturedArguments() (string, string) {
+	_param0, _param1 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1]
+}
+
+func (c *MockProvider_ListObjects_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]string, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(string)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierMockProvider) ReadObjectRange(_param0 string, _param1 int64, _param2 int64) *MockProvider_ReadObjectRange_OngoingVerification {
+	params := []pegomock.Param{_param0, _param1, _param2}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ReadObjectRange", params, verifier.timeout)
+	return &MockProvider_ReadObjectRange_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockProvider_ReadObjectRange_OngoingVerification struct {
+	mock              *MockProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockProvider_ReadObjectRange_OngoingVerification) GetCapturedArguments() (string, int64, int64) {
+	_param0, _param1, _param2 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1], _param1[len(_param1)-1], _param2[len(_param2)-1]
+}
+
+func (c *MockProvider_ReadObjectRange_OngoingVerification) GetAllCapturedArguments() (_param0 []string, _param1 []int64, _param2 []int64) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+		_param1 = make([]int64, len(c.methodInvocations))
+		for u, param := range params[1] {
+			_param1[u] = param.(int64)
+		}
+		_param2 = make([]int64, len(c.methodInvocations))
+		for u, param := range params[2] {
+			_param2[u] = param.(int64)
+		}
+	}
+	return
+}
+
+func (verifier *VerifierMockProvider) StatObject(_param0 string) *MockProvider_StatObject_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "StatObject", params, verifier.timeout)
+	return &MockProvider_StatObject_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockProvider_StatObject_OngoingVerification struct {
+	mock              *MockProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockProvider_StatObject_OngoingVerification) GetCapturedArguments() string {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *MockProvider_StatObject_OngoingVerification) GetAllCapturedArguments() (_param0 []string) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]string, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(string)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockProvider) UploadFileToBucket(_param0 io.Reader, _param1 string, _param2 string) *MockProvider_UploadFileToBucket_OngoingVerification {
 	params := []pegomock.Param{_param0, _param1, _param2}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "UploadFileToBucket", params, verifier.timeout)
@@ -0,0 +1,138 @@
+package buckets
+
+import (
+	"context"
+	"io"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"gocloud.dev/blob"
+)
+
+// ReadObjectRangeTimeout the timeout of reading a range of an object, which includes the time spent reading from the
+// returned reader
+const ReadObjectRangeTimeout = 5 * time.Minute
+
+// ObjectURL returns the URL of the object with the given key appended to the path of the bucket URL, keeping the
+// query of the bucket URL
+func ObjectURL(bucketURL string, key string) string {
+	u, err := url.Parse(bucketURL)
+	if err != nil {
+		return strings.TrimSuffix(bucketURL, "/") + "/" + strings.TrimPrefix(key, "/")
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + strings.TrimPrefix(key, "/")
+	return u.String()
+}
+
+// KeyPrefix returns the prefix of the keys to list given the path of a bucket URL and a prefix within that path
+func KeyPrefix(bucketPath string, prefix string) string {
+	bucketPath = strings.Trim(bucketPath, "/")
+	if bucketPath == "" {
+		return prefix
+	}
+	return bucketPath + "/" + strings.TrimPrefix(prefix, "/")
+}
+
+// OpenBucketURL opens the bucket of a URL of the form 's3://bucketName/foo/bar/whatnot.txt?param=123' returning the
+// bucket, the URL of the bucket and the path within the bucket
+func OpenBucketURL(ctx context.Context, urlText string) (*blob.Bucket, string, string, error) {
+	u, err := url.Parse(urlText)
+	if err != nil {
+		return nil, "", "", errors.Wrapf(err, "failed to parse URL %s", urlText)
+	}
+	bucketURL, key := SplitBucketURL(u)
+	bucket, err := blob.Open(ctx, bucketURL)
+	if err != nil {
+		return nil, bucketURL, key, errors.Wrapf(err, "failed to open bucket %s", bucketURL)
+	}
+	return bucket, bucketURL, key, nil
+}
+
+// ListBucketObjects lists the objects of the bucket whose keys start with the given prefix, using the bucket URL to
+// create the URLs of the objects
+func ListBucketObjects(ctx context.Context, bucket *blob.Bucket, bucketURL string, prefix string) ([]ObjectAttributes, error) {
+	var objects []ObjectAttributes
+	it := bucket.List(&blob.ListOptions{Prefix: prefix})
+	for {
+		obj, err := it.Next(ctx)
+		if err == io.EOF {
+			return objects, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list the objects with prefix %s in bucket %s", prefix, bucketURL)
+		}
+		if obj.IsDir {
+			continue
+		}
+		objects = append(objects, ObjectAttributes{
+			Key:     obj.Key,
+			URL:     ObjectURL(bucketURL, obj.Key),
+			Size:    obj.Size,
+			ModTime: obj.ModTime,
+		})
+	}
+}
+
+// StatBucketObject returns the attributes of the object with the key in the bucket or nil if it does not exist
+func StatBucketObject(ctx context.Context, bucket *blob.Bucket, bucketURL string, key string) (*ObjectAttributes, error) {
+	attributes, err := bucket.Attributes(ctx, key)
+	if err != nil {
+		if blob.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get the attributes of key %s in bucket %s", key, bucketURL)
+	}
+	return &ObjectAttributes{
+		Key:         key,
+		URL:         ObjectURL(bucketURL, key),
+		ContentType: attributes.ContentType,
+		Size:        attributes.Size,
+		ModTime:     attributes.ModTime,
+		Metadata:    attributes.Metadata,
+	}, nil
+}
+
+// DeleteBucketObject deletes the object with the key in the bucket, doing nothing if it does not exist
+func DeleteBucketObject(ctx context.Context, bucket *blob.Bucket, bucketURL string, key string) error {
+	err := bucket.Delete(ctx, key)
+	if err != nil && !blob.IsNotExist(err) {
+		return errors.Wrapf(err, "failed to delete key %s in bucket %s", key, bucketURL)
+	}
+	return nil
+}
+
+// ReadBucketObjectRange returns a reader of length bytes of the object with the key in the bucket starting at the
+// offset. A negative length reads to the end of the object. The release function is called once the reader is
+// closed, or straight away if the object cannot be read, such as to cancel the context of the reader
+func ReadBucketObjectRange(ctx context.Context, bucket *blob.Bucket, bucketURL string, key string, offset int64, length int64, release func()) (io.ReadCloser, error) {
+	reader, err := bucket.NewRangeReader(ctx, key, offset, length, nil)
+	if err != nil {
+		release()
+		return nil, errors.Wrapf(err, "failed to read key %s in bucket %s", key, bucketURL)
+	}
+	return NewReleasingReader(reader, release), nil
+}
+
+// releasingReader a reader calling a release function once closed
+type releasingReader struct {
+	io.ReadCloser
+	release func()
+}
+
+// NewReleasingReader returns a reader which calls the release function once it is closed, such as to cancel the
+// context the reader was created with
+func NewReleasingReader(reader io.ReadCloser, release func()) io.ReadCloser {
+	return &releasingReader{
+		ReadCloser: reader,
+		release:    release,
+	}
+}
+
+// Close closes the reader then calls the release function
+func (r *releasingReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.release()
+	return err
+}
@@ -0,0 +1,99 @@
+// +build unit
+
+package buckets_test
+
+import (
+	"io/ioutil"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObjectURL(t *testing.T) {
+	assert.Equal(t, "gs://mybucket/jenkins-x/logs/1.log", buckets.ObjectURL("gs://mybucket", "jenkins-x/logs/1.log"))
+	assert.Equal(t, "gs://mybucket/jenkins-x/logs/1.log", buckets.ObjectURL("gs://mybucket/", "/jenkins-x/logs/1.log"))
+	assert.Equal(t, "s3://logs/1.log?endpoint=minio%3A9000", buckets.ObjectURL("s3://logs?endpoint=minio%3A9000", "1.log"))
+	assert.Equal(t, "file:///tmp/logs/1.log", buckets.ObjectURL("file:///tmp/logs", "1.log"))
+}
+
+func TestKeyPrefix(t *testing.T) {
+	assert.Equal(t, "jenkins-x/logs/", buckets.KeyPrefix("", "jenkins-x/logs/"))
+	assert.Equal(t, "jenkins-x/logs/", buckets.KeyPrefix("/jenkins-x/", "logs/"))
+	assert.Equal(t, "jenkins-x/", buckets.KeyPrefix("/jenkins-x", ""))
+}
+
+func TestLegacyBucketProviderObjects(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-legacy-bucket-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	bucketURL := "file://" + dir
+	provider := buckets.NewLegacyBucketProvider()
+	err = provider.(*buckets.LegacyBucketProvider).Initialize(bucketURL, "logs")
+	require.NoError(t, err)
+
+	for _, name := range []string{"jenkins-x/logs/myapp/1.log", "jenkins-x/logs/myapp/2.log", "jenkins-x/tests/myapp/1.xml"} {
+		_, err = provider.UploadFileToBucket(strings.NewReader("0123456789"), name, bucketURL)
+		require.NoError(t, err)
+	}
+
+	objects, err := provider.ListObjects(bucketURL, "jenkins-x/logs/")
+	require.NoError(t, err)
+	require.Len(t, objects, 2)
+	assert.Equal(t, "jenkins-x/logs/myapp/1.log", objects[0].Key)
+	assert.Equal(t, bucketURL+"/jenkins-x/logs/myapp/1.log", objects[0].URL)
+	assert.Equal(t, int64(10), objects[0].Size)
+
+	attributes, err := provider.StatObject(objects[1].URL)
+	require.NoError(t, err)
+	require.NotNil(t, attributes)
+	assert.Equal(t, "jenkins-x/logs/myapp/2.log", attributes.Key)
+	assert.Equal(t, int64(10), attributes.Size)
+	assert.Equal(t, "logs", attributes.Metadata["classification"])
+
+	reader, err := provider.ReadObjectRange(objects[1].URL, 2, 3)
+	require.NoError(t, err)
+	data, err := ioutil.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "234", string(data))
+
+	reader, err = provider.ReadObjectRange(objects[1].URL, 7, -1)
+	require.NoError(t, err)
+	data, err = ioutil.ReadAll(reader)
+	reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "789", string(data))
+
+	err = provider.DeleteObject(objects[1].URL)
+	require.NoError(t, err)
+	err = provider.DeleteObject(objects[1].URL)
+	assert.NoError(t, err, "deleting a missing object should succeed")
+
+	attributes, err = provider.StatObject(objects[1].URL)
+	require.NoError(t, err)
+	assert.Nil(t, attributes)
+
+	objects, err = provider.ListObjects(bucketURL+"/jenkins-x", "")
+	require.NoError(t, err)
+	assert.Len(t, objects, 2)
+}
+
+func TestNewReleasingReader(t *testing.T) {
+	released := 0
+	reader := buckets.NewReleasingReader(ioutil.NopCloser(strings.NewReader("0123456789")), func() {
+		released++
+	})
+	data, err := ioutil.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, "0123456789", string(data))
+	assert.Equal(t, 0, released, "the reader should not be released before it is closed")
+
+	err = reader.Close()
+	require.NoError(t, err)
+	assert.Equal(t, 1, released)
+}
@@ -18,6 +18,11 @@ const (
 	ICP        = "icp"
 	JX_INFRA   = "jx-infra"
 	ALIBABA    = "alibaba"
+
+	// KIND local clusters created with kind which are only supported by the cluster clients
+	KIND = "kind"
+	// K3D local clusters created with k3d which are only supported by the cluster clients
+	K3D = "k3d"
 )
 
 // KubernetesProviders list of all available Kubernetes providers
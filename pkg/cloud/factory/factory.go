@@ -1,8 +1,12 @@
 package factory
 
 import (
+	"net/url"
+	"os"
+
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/v2/pkg/cloud"
+	aksStorage "github.com/jenkins-x/jx/v2/pkg/cloud/aks/storage"
 	amazonStorage "github.com/jenkins-x/jx/v2/pkg/cloud/amazon/storage"
 	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
 	"github.com/jenkins-x/jx/v2/pkg/cloud/gke/storage"
@@ -14,8 +18,18 @@ import (
 	"github.com/pkg/errors"
 )
 
-// NewBucketProvider creates a new bucket provider for a given Kubernetes provider
+// NewBucketProvider creates a new bucket provider for the storage URLs of the requirements, falling back to the
+// bucket provider of the Kubernetes provider
 func NewBucketProvider(requirements *config.RequirementsConfig) buckets.Provider {
+	u := storageURL(requirements)
+	if u != nil {
+		switch {
+		case u.Scheme == "azblob":
+			return newAKSBucketProvider(requirements)
+		case amazonStorage.IsS3CompatibleURL(u):
+			return amazonStorage.NewS3CompatibleBucketProvider(requirements)
+		}
+	}
 	switch requirements.Cluster.Provider {
 	case cloud.GKE:
 		return storage.NewGKEBucketProvider(requirements)
@@ -23,13 +37,42 @@ func NewBucketProvider(requirements *config.RequirementsConfig) buckets.Provider
 		fallthrough
 	case cloud.AWS:
 		return amazonStorage.NewAmazonBucketProvider(requirements)
+	case cloud.AKS:
+		return newAKSBucketProvider(requirements)
 	default:
-		// we have an implementation for GKE / EKS but not for AKS so we should fall back to default
+		// we have no implementation for the other providers so we should fall back to default
 		// but we don't have every func implemented
 		return buckets.NewLegacyBucketProvider()
 	}
 }
 
+// newAKSBucketProvider creates a bucket provider for Azure Blob Storage if the access key of the storage account is
+// available, falling back to the legacy bucket provider otherwise
+func newAKSBucketProvider(requirements *config.RequirementsConfig) buckets.Provider {
+	if os.Getenv(aksStorage.AzureStorageKeyEnvVar) == "" {
+		log.Logger().Debugf("$%s is not set so using the legacy bucket provider for Azure", aksStorage.AzureStorageKeyEnvVar)
+		return buckets.NewLegacyBucketProvider()
+	}
+	return aksStorage.NewAKSBucketProvider(requirements)
+}
+
+// storageURL returns the first storage URL of the requirements or nil if there is none
+func storageURL(requirements *config.RequirementsConfig) *url.URL {
+	storage := requirements.Storage
+	for _, entry := range []config.StorageEntryConfig{storage.Logs, storage.Reports, storage.Repository, storage.Backup} {
+		if entry.URL == "" {
+			continue
+		}
+		u, err := url.Parse(entry.URL)
+		if err != nil {
+			log.Logger().Warnf("Ignoring the invalid storage URL %s: %s", entry.URL, err)
+			continue
+		}
+		return u
+	}
+	return nil
+}
+
 // NewBucketProviderFromTeamSettingsConfiguration returns a bucket provider based on the jx-requirements file embedded in TeamSettings
 func NewBucketProviderFromTeamSettingsConfiguration(factory clients.Factory) (buckets.Provider, error) {
 	jxClient, ns, err := factory.CreateJXClient()
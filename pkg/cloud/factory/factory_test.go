@@ -4,6 +4,7 @@ package factory
 
 import (
 	"io/ioutil"
+	"os"
 	"path"
 	"testing"
 
@@ -11,6 +12,7 @@ import (
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/v2/pkg/cloud"
+	aksStorage "github.com/jenkins-x/jx/v2/pkg/cloud/aks/storage"
 	amazonStorage "github.com/jenkins-x/jx/v2/pkg/cloud/amazon/storage"
 	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
 	"github.com/jenkins-x/jx/v2/pkg/cloud/gke/storage"
@@ -23,6 +25,7 @@ import (
 )
 
 func TestNewBucketProviderFromTeamSettingsConfiguration(t *testing.T) {
+	defer setStorageKey("secret")()
 	var fac clients.Factory
 	fac = &fake.FakeFactory{}
 	jxClient, ns, err := fac.CreateJXClient()
@@ -67,6 +70,10 @@ func TestNewBucketProviderFromTeamSettingsConfiguration(t *testing.T) {
 			provider:     cloud.EKS,
 			providerType: &amazonStorage.AmazonBucketProvider{},
 		},
+		{
+			provider:     cloud.AKS,
+			providerType: &aksStorage.AKSBucketProvider{},
+		},
 		{
 			provider:     "NonSupportedProvider",
 			providerType: &buckets.LegacyBucketProvider{},
@@ -83,6 +90,67 @@ func TestNewBucketProviderFromTeamSettingsConfiguration(t *testing.T) {
 		})
 	}
 }
+
+func TestNewBucketProviderFromStorageURL(t *testing.T) {
+	defer setStorageKey("secret")()
+	testCases := []struct {
+		name         string
+		provider     string
+		url          string
+		providerType buckets.Provider
+	}{
+		{
+			name:         "azblob",
+			provider:     "kubernetes",
+			url:          "azblob://logs",
+			providerType: &aksStorage.AKSBucketProvider{},
+		},
+		{
+			name:         "minio",
+			provider:     "kubernetes",
+			url:          "s3://logs?endpoint=minio.jx.svc.cluster.local:9000&disableSSL=true&s3ForcePathStyle=true",
+			providerType: &amazonStorage.S3CompatibleBucketProvider{},
+		},
+		{
+			name:         "s3",
+			provider:     cloud.EKS,
+			url:          "s3://logs",
+			providerType: &amazonStorage.AmazonBucketProvider{},
+		},
+		{
+			name:         "gs",
+			provider:     cloud.GKE,
+			url:          "gs://logs",
+			providerType: &storage.GKEBucketProvider{},
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			requirements := &config.RequirementsConfig{}
+			requirements.Cluster.Provider = tt.provider
+			requirements.Storage.Reports = config.StorageEntryConfig{
+				Enabled: true,
+				URL:     tt.url,
+			}
+			assert.IsType(t, tt.providerType, NewBucketProvider(requirements))
+		})
+	}
+}
+
+func TestNewBucketProviderForAzureWithoutStorageKey(t *testing.T) {
+	defer setStorageKey("")()
+
+	requirements := &config.RequirementsConfig{}
+	requirements.Cluster.Provider = cloud.AKS
+	assert.IsType(t, &buckets.LegacyBucketProvider{}, NewBucketProvider(requirements))
+
+	requirements.Storage.Logs = config.StorageEntryConfig{
+		Enabled: true,
+		URL:     "azblob://logs",
+	}
+	assert.IsType(t, &buckets.LegacyBucketProvider{}, NewBucketProvider(requirements))
+}
+
 func TestNewBucketProviderFromTeamSettingsConfigurationOrDefault(t *testing.T) {
 	var fac clients.Factory
 	fac = &fake.FakeFactory{}
@@ -125,3 +193,16 @@ func updateDevEnvironment(jxClient versioned.Interface, ns string, requirements
 	})
 	return err
 }
+
+// setStorageKey sets the access key of the Azure storage account, returning a function restoring its previous value
+func setStorageKey(key string) func() {
+	original, exists := os.LookupEnv(aksStorage.AzureStorageKeyEnvVar)
+	os.Setenv(aksStorage.AzureStorageKeyEnvVar, key)
+	return func() {
+		if exists {
+			os.Setenv(aksStorage.AzureStorageKeyEnvVar, original)
+		} else {
+			os.Unsetenv(aksStorage.AzureStorageKeyEnvVar)
+		}
+	}
+}
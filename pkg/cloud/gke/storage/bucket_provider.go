@@ -2,6 +2,7 @@ package storage
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -95,6 +96,50 @@ func (b *GKEBucketProvider) DownloadFileFromBucket(bucketURL string) (*bufio.Sca
 	return gke.StreamTransferFileFromBucket(bucketURL)
 }
 
+// ListObjects lists the objects of the GCS bucket whose keys start with the given prefix
+func (b *GKEBucketProvider) ListObjects(bucketURL string, prefix string) ([]buckets.ObjectAttributes, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketWriteTimeout)
+	defer cancel()
+	bucket, rootURL, key, err := buckets.OpenBucketURL(ctx, bucketURL)
+	if err != nil {
+		return nil, err
+	}
+	return buckets.ListBucketObjects(ctx, bucket, rootURL, buckets.KeyPrefix(key, prefix))
+}
+
+// StatObject returns the attributes of the object at the given GCS URL or nil if it does not exist
+func (b *GKEBucketProvider) StatObject(objectURL string) (*buckets.ObjectAttributes, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketWriteTimeout)
+	defer cancel()
+	bucket, rootURL, key, err := buckets.OpenBucketURL(ctx, objectURL)
+	if err != nil {
+		return nil, err
+	}
+	return buckets.StatBucketObject(ctx, bucket, rootURL, key)
+}
+
+// DeleteObject deletes the object at the given GCS URL, doing nothing if it does not exist
+func (b *GKEBucketProvider) DeleteObject(objectURL string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), defaultBucketWriteTimeout)
+	defer cancel()
+	bucket, rootURL, key, err := buckets.OpenBucketURL(ctx, objectURL)
+	if err != nil {
+		return err
+	}
+	return buckets.DeleteBucketObject(ctx, bucket, rootURL, key)
+}
+
+// ReadObjectRange streams length bytes of the object at the given GCS URL starting at the offset
+func (b *GKEBucketProvider) ReadObjectRange(objectURL string, offset int64, length int64) (io.ReadCloser, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), buckets.ReadObjectRangeTimeout)
+	bucket, rootURL, key, err := buckets.OpenBucketURL(ctx, objectURL)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	return buckets.ReadBucketObjectRange(ctx, bucket, rootURL, key, offset, length, cancel)
+}
+
 // NewGKEBucketProvider create a new provider for GKE
 func NewGKEBucketProvider(requirements *config.RequirementsConfig) buckets.Provider {
 	return &GKEBucketProvider{
@@ -0,0 +1,116 @@
+package aks
+
+import (
+	"fmt"
+	"os"
+
+	azure "github.com/jenkins-x/jx/v2/pkg/cloud/aks"
+	"github.com/jenkins-x/jx/v2/pkg/cluster"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/pkg/errors"
+)
+
+type aksClient struct {
+	subscription  string
+	resourceGroup string
+	azureCLI      *azure.AzureRunner
+}
+
+// NewAKS create a new client for working with AKS clusters in the given subscription and resource group. If no
+// resource group is specified the clusters in every resource group of the subscription are used
+func NewAKS(subscription string, resourceGroup string) (cluster.Client, error) {
+	return &aksClient{
+		subscription:  subscription,
+		resourceGroup: resourceGroup,
+		azureCLI:      azure.NewAzureRunner(),
+	}, nil
+}
+
+// NewAKSFromEnv create a new client for working with AKS clusters using environment variables to define the
+// subscription/resource group
+func NewAKSFromEnv() (cluster.Client, error) {
+	return NewAKS(os.Getenv(cluster.EnvAKSSubscription), os.Getenv(cluster.EnvAKSResourceGroup))
+}
+
+// List lists the clusters
+func (c *aksClient) List() ([]*cluster.Cluster, error) {
+	items, err := c.azureCLI.ListClusters(c.subscription, c.resourceGroup)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list clusters in resource group %s", c.resourceGroup)
+	}
+	var answer []*cluster.Cluster
+
+	for _, item := range items {
+		answer = append(answer, &cluster.Cluster{
+			Name:     item.Name,
+			Labels:   item.Tags,
+			Status:   item.Status,
+			Location: item.Location,
+		})
+	}
+	return answer, nil
+}
+
+// ListFilter lists the clusters with a filter
+func (c *aksClient) ListFilter(labels map[string]string) ([]*cluster.Cluster, error) {
+	return cluster.ListFilter(c, labels)
+}
+
+// Connect connects to a cluster
+func (c *aksClient) Connect(cluster *cluster.Cluster) error {
+	resourceGroup, err := c.clusterResourceGroup(cluster.Name)
+	if err != nil {
+		return err
+	}
+	return c.azureCLI.ConnectToCluster(c.subscription, resourceGroup, cluster.Name)
+}
+
+// String return the string representation
+func (c *aksClient) String() string {
+	if c.resourceGroup == "" {
+		return "AKS Cluster client"
+	}
+	return fmt.Sprintf("AKS resource group: %s", c.resourceGroup)
+}
+
+// Get looks up a cluster by name
+func (c *aksClient) Get(name string) (*cluster.Cluster, error) {
+	return cluster.GetCluster(c, name)
+}
+
+// Delete deletes the cluster from AKS
+func (c *aksClient) Delete(cluster *cluster.Cluster) error {
+	resourceGroup, err := c.clusterResourceGroup(cluster.Name)
+	if err != nil {
+		return err
+	}
+	log.Logger().Infof("Attempting to delete cluster %s", cluster.Name)
+	return c.azureCLI.DeleteCluster(c.subscription, resourceGroup, cluster.Name)
+}
+
+// SetClusterLabels labels the given cluster
+func (c *aksClient) SetClusterLabels(cluster *cluster.Cluster, labels map[string]string) error {
+	resourceGroup, err := c.clusterResourceGroup(cluster.Name)
+	if err != nil {
+		return err
+	}
+	// AKS works with Tags, should be equal
+	return c.azureCLI.SetClusterTags(c.subscription, resourceGroup, cluster.Name, labels)
+}
+
+// clusterResourceGroup returns the resource group of the client or looks up the resource group of the named cluster
+func (c *aksClient) clusterResourceGroup(name string) (string, error) {
+	if c.resourceGroup != "" {
+		return c.resourceGroup, nil
+	}
+	items, err := c.azureCLI.ListClusters(c.subscription, "")
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the resource group of cluster %s", name)
+	}
+	for _, item := range items {
+		if item.Name == name {
+			return item.Group, nil
+		}
+	}
+	return "", fmt.Errorf("could not find AKS cluster %s", name)
+}
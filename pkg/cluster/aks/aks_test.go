@@ -0,0 +1,96 @@
+// +build unit
+
+package aks
+
+import (
+	"testing"
+
+	azure "github.com/jenkins-x/jx/v2/pkg/cloud/aks"
+	"github.com/jenkins-x/jx/v2/pkg/cluster"
+	mocks "github.com/jenkins-x/jx/v2/pkg/util/mocks"
+	. "github.com/petergtz/pegomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testClusters = `[
+	{
+		"group": "jx-bdd",
+		"location": "westeurope",
+		"name": "pr-123-1-boot",
+		"status": "Succeeded",
+		"tags": {
+			"branch": "pr-123",
+			"cluster": "boot"
+		}
+	},
+	{
+		"group": "jx-production",
+		"location": "westus2",
+		"name": "production",
+		"status": "Succeeded",
+		"tags": null
+	}
+]`
+
+func TestAKSClient_List(t *testing.T) {
+	RegisterMockTestingT(t)
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn(testClusters, nil)
+	c := &aksClient{
+		resourceGroup: "jx-bdd",
+		azureCLI:      azure.NewAzureRunnerWithCommander(runner),
+	}
+
+	clusters, err := c.ListFilter(map[string]string{"cluster": "boot"})
+	require.NoError(t, err)
+	require.Len(t, clusters, 1)
+	assert.Equal(t, &cluster.Cluster{
+		Name:     "pr-123-1-boot",
+		Labels:   map[string]string{"branch": "pr-123", "cluster": "boot"},
+		Status:   "Succeeded",
+		Location: "westeurope",
+	}, clusters[0])
+
+	args := runner.VerifyWasCalledOnce().SetArgs(AnyStringSlice()).GetCapturedArguments()
+	assert.Equal(t, []string{"aks", "list", "--query", "[].{name:name,group:resourceGroup,location:location,status:provisioningState,tags:tags}",
+		"--resource-group", "jx-bdd"}, args)
+}
+
+func TestAKSClient_ConnectLooksUpResourceGroup(t *testing.T) {
+	RegisterMockTestingT(t)
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn(testClusters, nil).ThenReturn("", nil)
+	c := &aksClient{
+		subscription: "my-subscription",
+		azureCLI:     azure.NewAzureRunnerWithCommander(runner),
+	}
+
+	err := c.Connect(&cluster.Cluster{Name: "production"})
+	require.NoError(t, err)
+
+	args := runner.VerifyWasCalled(Times(2)).SetArgs(AnyStringSlice()).GetAllCapturedArguments()
+	assert.Equal(t, []string{"aks", "get-credentials", "--resource-group", "jx-production", "--name", "production",
+		"--overwrite-existing", "--subscription", "my-subscription"}, args[1])
+}
+
+func TestAKSClient_SetClusterLabels(t *testing.T) {
+	RegisterMockTestingT(t)
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn("", nil)
+	c := &aksClient{
+		resourceGroup: "jx-bdd",
+		azureCLI:      azure.NewAzureRunnerWithCommander(runner),
+	}
+
+	err := c.SetClusterLabels(&cluster.Cluster{Name: "pr-123-1-boot"}, map[string]string{"locked": "abc", "branch": "pr-123"})
+	require.NoError(t, err)
+	err = c.SetClusterLabels(&cluster.Cluster{Name: "pr-123-1-boot"}, map[string]string{})
+	require.NoError(t, err)
+
+	args := runner.VerifyWasCalled(Times(2)).SetArgs(AnyStringSlice()).GetAllCapturedArguments()
+	assert.Equal(t, []string{"resource", "tag", "--resource-group", "jx-bdd", "--name", "pr-123-1-boot",
+		"--resource-type", "Microsoft.ContainerService/managedClusters", "--tags", "branch=pr-123", "locked=abc"}, args[0])
+	assert.Equal(t, []string{"resource", "tag", "--resource-group", "jx-bdd", "--name", "pr-123-1-boot",
+		"--resource-type", "Microsoft.ContainerService/managedClusters", "--tags", ""}, args[1])
+}
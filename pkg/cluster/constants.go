@@ -7,4 +7,13 @@ const (
 
 	// EnvGKERegion the environment variable for the GKE region
 	EnvGKERegion = "GKE_REGION"
+
+	// EnvAKSResourceGroup the environment variable for the AKS resource group
+	EnvAKSResourceGroup = "AKS_RESOURCE_GROUP"
+
+	// EnvAKSSubscription the environment variable for the optional AKS subscription
+	EnvAKSSubscription = "AKS_SUBSCRIPTION"
+
+	// EnvKindDistribution the environment variable for the distribution of local clusters, either kind or k3d
+	EnvKindDistribution = "KIND_DISTRIBUTION"
 )
@@ -0,0 +1,253 @@
+package kind
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/v2/pkg/cluster"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	// DistributionKind local clusters created with kind
+	DistributionKind = "kind"
+
+	// DistributionK3d local clusters created with k3d
+	DistributionK3d = "k3d"
+
+	// StatusRunning the status of local clusters which are listed
+	StatusRunning = "Running"
+
+	// labelsFileName the name of the file in the jx home dir which stores the labels of local clusters
+	labelsFileName = "local-cluster-labels.yml"
+)
+
+// localClient a client for working with local clusters which stores the cluster labels in a local file as the
+// clusters have no labels of their own
+type localClient struct {
+	distribution string
+	runner       util.Commander
+	labelsFile   string
+}
+
+// NewKind create a new client for working with the local clusters of the kind or k3d distribution
+func NewKind(distribution string) (cluster.Client, error) {
+	configDir, err := util.ConfigDir()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to find the jx home directory")
+	}
+	return NewKindWithCommander(distribution, &util.Command{}, filepath.Join(configDir, labelsFileName))
+}
+
+// NewKindFromEnv create a new client for working with local clusters using an environment variable to define the
+// distribution which defaults to kind
+func NewKindFromEnv() (cluster.Client, error) {
+	distribution := os.Getenv(cluster.EnvKindDistribution)
+	if distribution == "" {
+		distribution = DistributionKind
+	}
+	return NewKind(distribution)
+}
+
+// NewKindWithCommander create a new client for working with local clusters using the given command runner and file
+// to store the cluster labels
+func NewKindWithCommander(distribution string, runner util.Commander, labelsFile string) (cluster.Client, error) {
+	switch distribution {
+	case DistributionKind, DistributionK3d:
+	default:
+		return nil, fmt.Errorf("unsupported local cluster distribution %s, supported distributions are %s", distribution,
+			strings.Join([]string{DistributionKind, DistributionK3d}, ", "))
+	}
+	return &localClient{
+		distribution: distribution,
+		runner:       runner,
+		labelsFile:   labelsFile,
+	}, nil
+}
+
+// List lists the clusters
+func (c *localClient) List() ([]*cluster.Cluster, error) {
+	names, err := c.listNames()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the %s clusters", c.distribution)
+	}
+	labels, err := c.loadLabels()
+	if err != nil {
+		return nil, err
+	}
+	var answer []*cluster.Cluster
+	for _, name := range names {
+		answer = append(answer, &cluster.Cluster{
+			Name:     name,
+			Labels:   labels[name],
+			Status:   StatusRunning,
+			Location: c.distribution,
+		})
+	}
+	return answer, nil
+}
+
+// ListFilter lists the clusters with a filter
+func (c *localClient) ListFilter(labels map[string]string) ([]*cluster.Cluster, error) {
+	return cluster.ListFilter(c, labels)
+}
+
+// Connect connects to a cluster by making it the current context of the kube config
+func (c *localClient) Connect(cluster *cluster.Cluster) error {
+	args := []string{"export", "kubeconfig", "--name", cluster.Name}
+	if c.distribution == DistributionK3d {
+		args = []string{"kubeconfig", "merge", cluster.Name, "--kubeconfig-switch-context"}
+	}
+	_, err := c.run(args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to connect to %s cluster %s", c.distribution, cluster.Name)
+	}
+	return nil
+}
+
+// String return the string representation
+func (c *localClient) String() string {
+	return fmt.Sprintf("local %s cluster client", c.distribution)
+}
+
+// Get looks up a cluster by name
+func (c *localClient) Get(name string) (*cluster.Cluster, error) {
+	return cluster.GetCluster(c, name)
+}
+
+// Delete deletes the local cluster and its labels
+func (c *localClient) Delete(cluster *cluster.Cluster) error {
+	log.Logger().Infof("Attempting to delete cluster %s", cluster.Name)
+	args := []string{"delete", "cluster", "--name", cluster.Name}
+	if c.distribution == DistributionK3d {
+		args = []string{"cluster", "delete", cluster.Name}
+	}
+	_, err := c.run(args...)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete %s cluster %s", c.distribution, cluster.Name)
+	}
+	return c.SetClusterLabels(cluster, nil)
+}
+
+// SetClusterLabels replaces the labels of the given cluster
+func (c *localClient) SetClusterLabels(cluster *cluster.Cluster, labels map[string]string) error {
+	allLabels, err := c.loadLabels()
+	if err != nil {
+		return err
+	}
+	if len(labels) == 0 {
+		delete(allLabels, cluster.Name)
+	} else {
+		allLabels[cluster.Name] = labels
+	}
+	return c.saveLabels(allLabels)
+}
+
+// listNames returns the names of the local clusters
+func (c *localClient) listNames() ([]string, error) {
+	names := []string{}
+	if c.distribution == DistributionK3d {
+		out, err := c.run("cluster", "list", "--output", "json")
+		if err != nil {
+			return nil, err
+		}
+		// ignore any warnings logged before the JSON
+		idx := strings.Index(out, "[")
+		if idx < 0 {
+			return names, nil
+		}
+		clusters := []struct {
+			Name string `json:"name"`
+		}{}
+		err = json.Unmarshal([]byte(out[idx:]), &clusters)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the k3d clusters from %s", out)
+		}
+		for _, k3dCluster := range clusters {
+			names = append(names, k3dCluster.Name)
+		}
+		return names, nil
+	}
+
+	out, err := c.run("get", "clusters")
+	if err != nil {
+		return nil, err
+	}
+	for _, line := range strings.Split(out, "\n") {
+		line = strings.TrimSpace(line)
+		// kind logs a message rather than an empty list if there are no clusters
+		if line == "" || strings.Contains(line, " ") {
+			continue
+		}
+		names = append(names, line)
+	}
+	return names, nil
+}
+
+// loadLabels loads the labels of the clusters of the distribution keyed by cluster name
+func (c *localClient) loadLabels() (map[string]map[string]string, error) {
+	all, err := c.loadAllLabels()
+	if err != nil {
+		return nil, err
+	}
+	answer := all[c.distribution]
+	if answer == nil {
+		answer = map[string]map[string]string{}
+	}
+	return answer, nil
+}
+
+// saveLabels saves the labels of the clusters of the distribution keyed by cluster name
+func (c *localClient) saveLabels(labels map[string]map[string]string) error {
+	all, err := c.loadAllLabels()
+	if err != nil {
+		return err
+	}
+	all[c.distribution] = labels
+	data, err := yaml.Marshal(all)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the local cluster labels")
+	}
+	err = ioutil.WriteFile(c.labelsFile, data, util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to save the local cluster labels to %s", c.labelsFile)
+	}
+	return nil
+}
+
+// loadAllLabels loads the labels of the clusters keyed by distribution then cluster name
+func (c *localClient) loadAllLabels() (map[string]map[string]map[string]string, error) {
+	answer := map[string]map[string]map[string]string{}
+	exists, err := util.FileExists(c.labelsFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file %s exists", c.labelsFile)
+	}
+	if !exists {
+		return answer, nil
+	}
+	data, err := ioutil.ReadFile(c.labelsFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to load the local cluster labels from %s", c.labelsFile)
+	}
+	err = yaml.Unmarshal(data, &answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the local cluster labels from %s", c.labelsFile)
+	}
+	if answer == nil {
+		answer = map[string]map[string]map[string]string{}
+	}
+	return answer, nil
+}
+
+func (c *localClient) run(args ...string) (string, error) {
+	c.runner.SetName(c.distribution)
+	c.runner.SetArgs(args)
+	return c.runner.RunWithoutRetry()
+}
@@ -0,0 +1,96 @@
+// +build unit
+
+package kind_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/cluster"
+	"github.com/jenkins-x/jx/v2/pkg/cluster/kind"
+	mocks "github.com/jenkins-x/jx/v2/pkg/util/mocks"
+	. "github.com/petergtz/pegomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKindClientLabels(t *testing.T) {
+	RegisterMockTestingT(t)
+	dir, err := ioutil.TempDir("", "test-kind-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn("pr-123-1-boot\nkind", nil)
+	client, err := kind.NewKindWithCommander(kind.DistributionKind, runner, filepath.Join(dir, "labels.yml"))
+	require.NoError(t, err)
+
+	clusters, err := client.List()
+	require.NoError(t, err)
+	require.Len(t, clusters, 2)
+	assert.Equal(t, &cluster.Cluster{Name: "pr-123-1-boot", Status: kind.StatusRunning, Location: kind.DistributionKind}, clusters[0])
+
+	lockLabels := map[string]string{"locked": "abc"}
+	locked, err := cluster.LockCluster(client, lockLabels, map[string]string{})
+	require.NoError(t, err)
+	require.NotNil(t, locked)
+	assert.Equal(t, "pr-123-1-boot", locked.Name)
+	assert.Equal(t, lockLabels, locked.Labels)
+
+	clusters, err = client.ListFilter(lockLabels)
+	require.NoError(t, err)
+	assert.Len(t, clusters, 1)
+
+	_, err = cluster.RemoveLabels(client, locked, []string{"locked"})
+	require.NoError(t, err)
+	clusters, err = client.ListFilter(lockLabels)
+	require.NoError(t, err)
+	assert.Len(t, clusters, 0)
+}
+
+func TestKindClientNoClusters(t *testing.T) {
+	RegisterMockTestingT(t)
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn("No kind clusters found.", nil)
+	client, err := kind.NewKindWithCommander(kind.DistributionKind, runner, filepath.Join("test_data", "missing.yml"))
+	require.NoError(t, err)
+
+	clusters, err := client.List()
+	require.NoError(t, err)
+	assert.Len(t, clusters, 0)
+}
+
+func TestK3dClient(t *testing.T) {
+	RegisterMockTestingT(t)
+	dir, err := ioutil.TempDir("", "test-kind-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn(`[{"name":"k3s-default","serversRunning":1}]`, nil)
+	client, err := kind.NewKindWithCommander(kind.DistributionK3d, runner, filepath.Join(dir, "labels.yml"))
+	require.NoError(t, err)
+
+	c, err := client.Get("k3s-default")
+	require.NoError(t, err)
+	require.NotNil(t, c)
+	assert.Equal(t, kind.DistributionK3d, c.Location)
+
+	err = client.Connect(c)
+	require.NoError(t, err)
+	err = client.Delete(c)
+	require.NoError(t, err)
+
+	names := runner.VerifyWasCalled(Times(3)).SetName(AnyString()).GetAllCapturedArguments()
+	assert.Equal(t, []string{"k3d", "k3d", "k3d"}, names)
+	args := runner.VerifyWasCalled(Times(3)).SetArgs(AnyStringSlice()).GetAllCapturedArguments()
+	assert.Equal(t, []string{"kubeconfig", "merge", "k3s-default", "--kubeconfig-switch-context"}, args[1])
+	assert.Equal(t, []string{"cluster", "delete", "k3s-default"}, args[2])
+}
+
+func TestUnsupportedDistribution(t *testing.T) {
+	_, err := kind.NewKindWithCommander("minikube", mocks.NewMockCommander(), "")
+	assert.Error(t, err)
+}
@@ -5,6 +5,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
@@ -13,6 +14,7 @@ import (
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/spf13/cobra"
@@ -77,6 +79,19 @@ func (o *ControllerBackupOptions) Run() error {
 		return err
 	}
 
+	apisClient, err := o.ApiExtensionsClient()
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterSourceRepositoryCRD(apisClient)
+	if err != nil {
+		return err
+	}
+	err = kube.RegisterAppCRD(apisClient)
+	if err != nil {
+		return err
+	}
+
 	jxClient, devNs, err := o.JXClientAndDevNamespace()
 	if err != nil {
 		return err
@@ -89,7 +104,7 @@ func (o *ControllerBackupOptions) Run() error {
 
 	dir, err := o.getOrCreateBackupRepository()
 
-	log.Logger().Infof("Watching for users/teams/environments in namespace %s", util.ColorInfo(ns))
+	log.Logger().Infof("Watching for users/teams/environments/sourcerepositories/apps/schedulers in namespace %s", util.ColorInfo(ns))
 
 	_, environmentController := cache.NewInformer(
 		&cache.ListWatch{
@@ -170,6 +185,84 @@ func (o *ControllerBackupOptions) Run() error {
 
 	go userController.Run(stop)
 
+	_, sourceRepositoryController := cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(lo meta_v1.ListOptions) (runtime.Object, error) {
+				return jxClient.JenkinsV1().SourceRepositories(ns).List(lo)
+			},
+			WatchFunc: func(lo meta_v1.ListOptions) (watch.Interface, error) {
+				return jxClient.JenkinsV1().SourceRepositories(ns).Watch(lo)
+			},
+		},
+		&v1.SourceRepository{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onSourceRepositoryChange(obj, ns, dir)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onSourceRepositoryChange(newObj, ns, dir)
+			},
+			DeleteFunc: func(obj interface{}) {
+				o.onSourceRepositoryDelete(obj, ns, dir)
+			},
+		},
+	)
+
+	go sourceRepositoryController.Run(stop)
+
+	_, appController := cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(lo meta_v1.ListOptions) (runtime.Object, error) {
+				return jxClient.JenkinsV1().Apps(ns).List(lo)
+			},
+			WatchFunc: func(lo meta_v1.ListOptions) (watch.Interface, error) {
+				return jxClient.JenkinsV1().Apps(ns).Watch(lo)
+			},
+		},
+		&v1.App{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onAppChange(obj, ns, dir)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onAppChange(newObj, ns, dir)
+			},
+			DeleteFunc: func(obj interface{}) {
+				o.onAppDelete(obj, ns, dir)
+			},
+		},
+	)
+
+	go appController.Run(stop)
+
+	_, schedulerController := cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(lo meta_v1.ListOptions) (runtime.Object, error) {
+				return jxClient.JenkinsV1().Schedulers(ns).List(lo)
+			},
+			WatchFunc: func(lo meta_v1.ListOptions) (watch.Interface, error) {
+				return jxClient.JenkinsV1().Schedulers(ns).Watch(lo)
+			},
+		},
+		&v1.Scheduler{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onSchedulerChange(obj, ns, dir)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onSchedulerChange(newObj, ns, dir)
+			},
+			DeleteFunc: func(obj interface{}) {
+				o.onSchedulerDelete(obj, ns, dir)
+			},
+		},
+	)
+
+	go schedulerController.Run(stop)
+
 	// Wait forever
 	select {}
 }
@@ -201,6 +294,69 @@ func (o *ControllerBackupOptions) onUserChange(obj interface{}, ns string, dir s
 	o.writeResourceToBackupFile(env, "user", env.ObjectMeta.Name, ns, dir)
 }
 
+func (o *ControllerBackupOptions) onSourceRepositoryChange(obj interface{}, ns string, dir string) {
+	repo, ok := obj.(*v1.SourceRepository)
+	if !ok {
+		log.Logger().Infof("Object is not a SourceRepository %#v", obj)
+		return
+	}
+	o.writeResourceToBackupFile(repo, "sourcerepository", repo.ObjectMeta.Name, ns, dir)
+}
+
+func (o *ControllerBackupOptions) onAppChange(obj interface{}, ns string, dir string) {
+	app, ok := obj.(*v1.App)
+	if !ok {
+		log.Logger().Infof("Object is not an App %#v", obj)
+		return
+	}
+	o.writeResourceToBackupFile(app, "app", app.ObjectMeta.Name, ns, dir)
+}
+
+func (o *ControllerBackupOptions) onSchedulerChange(obj interface{}, ns string, dir string) {
+	scheduler, ok := obj.(*v1.Scheduler)
+	if !ok {
+		log.Logger().Infof("Object is not a Scheduler %#v", obj)
+		return
+	}
+	o.writeResourceToBackupFile(scheduler, "scheduler", scheduler.ObjectMeta.Name, ns, dir)
+}
+
+func (o *ControllerBackupOptions) onSourceRepositoryDelete(obj interface{}, ns string, dir string) {
+	repo, ok := deletedObject(obj).(*v1.SourceRepository)
+	if !ok {
+		log.Logger().Infof("Object is not a SourceRepository %#v", obj)
+		return
+	}
+	o.removeResourceBackupFile("sourcerepository", repo.ObjectMeta.Name, ns, dir)
+}
+
+func (o *ControllerBackupOptions) onAppDelete(obj interface{}, ns string, dir string) {
+	app, ok := deletedObject(obj).(*v1.App)
+	if !ok {
+		log.Logger().Infof("Object is not an App %#v", obj)
+		return
+	}
+	o.removeResourceBackupFile("app", app.ObjectMeta.Name, ns, dir)
+}
+
+func (o *ControllerBackupOptions) onSchedulerDelete(obj interface{}, ns string, dir string) {
+	scheduler, ok := deletedObject(obj).(*v1.Scheduler)
+	if !ok {
+		log.Logger().Infof("Object is not a Scheduler %#v", obj)
+		return
+	}
+	o.removeResourceBackupFile("scheduler", scheduler.ObjectMeta.Name, ns, dir)
+}
+
+// deletedObject returns the deleted resource, unwrapping it if the informer missed the deletion and only saw that the
+// resource had gone when relisting
+func deletedObject(obj interface{}) interface{} {
+	if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		return tombstone.Obj
+	}
+	return obj
+}
+
 func (o *ControllerBackupOptions) writeResourceToBackupFile(obj interface{}, resource string, key string, ns string, dir string) {
 	out, err := yaml.Marshal(obj)
 	if err != nil {
@@ -211,7 +367,7 @@ func (o *ControllerBackupOptions) writeResourceToBackupFile(obj interface{}, res
 	log.Logger().Debugf("Dumping %s with key %s...", util.ColorInfo(resource), util.ColorInfo(key))
 	log.Logger().Debugf("%s", string(out))
 
-	nsDir := path.Join(dir, fmt.Sprintf("%ss", resource), ns)
+	nsDir := path.Join(dir, backupDirName(resource), ns)
 	err = os.MkdirAll(nsDir, os.FileMode(0755))
 	if err != nil {
 		log.Logger().Errorf("Unable to create directory %s", err)
@@ -228,6 +384,21 @@ func (o *ControllerBackupOptions) writeResourceToBackupFile(obj interface{}, res
 	o.commitDirIfChanges(dir, fmt.Sprintf("Updating %s %s", resource, key))
 }
 
+func (o *ControllerBackupOptions) removeResourceBackupFile(resource string, key string, ns string, dir string) {
+	backupFile := path.Join(dir, backupDirName(resource), ns, fmt.Sprintf("%s.yaml", key))
+	err := os.Remove(backupFile)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			log.Logger().Errorf("Unable to remove file %s", err)
+		}
+		return
+	}
+
+	log.Logger().Debugf("Removed %s with key %s", util.ColorInfo(resource), util.ColorInfo(key))
+
+	o.commitDirIfChanges(dir, fmt.Sprintf("Removing %s %s", resource, key))
+}
+
 func (o *ControllerBackupOptions) commitDirIfChanges(dir string, message string) {
 	changes, err := o.Git().HasChanges(dir)
 	if err != nil {
@@ -353,3 +524,12 @@ func (o *ControllerBackupOptions) getOrCreateBackupRepository() (string, error)
 
 	return dir, nil
 }
+
+// backupDirName returns the directory of the backup repository the resources of the given kind are written to, which
+// is the plural of the kind such as environments or sourcerepositories
+func backupDirName(resource string) string {
+	if strings.HasSuffix(resource, "y") {
+		return strings.TrimSuffix(resource, "y") + "ies"
+	}
+	return resource + "s"
+}
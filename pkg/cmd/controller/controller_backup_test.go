@@ -0,0 +1,57 @@
+// +build unit
+
+package controller
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+func TestBackupRemovesDeletedResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-backup-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	commonOpts := &opts.CommonOptions{}
+	commonOpts.SetGit(gits.NewGitFake())
+	o := &ControllerBackupOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	repo := &v1.SourceRepository{ObjectMeta: metav1.ObjectMeta{Name: "myorg-myapp"}}
+	app := &v1.App{ObjectMeta: metav1.ObjectMeta{Name: "jx-app-sso"}}
+	scheduler := &v1.Scheduler{ObjectMeta: metav1.ObjectMeta{Name: "default-scheduler"}}
+	o.onSourceRepositoryChange(repo, "jx", dir)
+	o.onAppChange(app, "jx", dir)
+	o.onSchedulerChange(scheduler, "jx", dir)
+
+	repoFile := filepath.Join(dir, "sourcerepositories", "jx", "myorg-myapp.yaml")
+	appFile := filepath.Join(dir, "apps", "jx", "jx-app-sso.yaml")
+	schedulerFile := filepath.Join(dir, "schedulers", "jx", "default-scheduler.yaml")
+	assert.FileExists(t, repoFile)
+	assert.FileExists(t, appFile)
+	assert.FileExists(t, schedulerFile)
+
+	o.onSourceRepositoryDelete(repo, "jx", dir)
+	o.onAppDelete(cache.DeletedFinalStateUnknown{Key: "jx/jx-app-sso", Obj: app}, "jx", dir)
+	assert.NoFileExists(t, repoFile)
+	assert.NoFileExists(t, appFile)
+	assert.FileExists(t, schedulerFile)
+
+	o.onSchedulerDelete(scheduler, "jx", dir)
+	assert.NoFileExists(t, schedulerFile)
+	// deleting a resource which was never backed up does nothing
+	o.onSchedulerDelete(scheduler, "jx", dir)
+}
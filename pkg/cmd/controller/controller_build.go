@@ -31,6 +31,7 @@ import (
 
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/v2/pkg/builds"
+	"github.com/jenkins-x/jx/v2/pkg/chats"
 	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
@@ -67,6 +68,10 @@ type ControllerBuildOptions struct {
 
 	// private fields added for easier testing
 	gitHubProvider gits.GitProvider
+	chatNotifier   *chats.Notifier
+
+	// chatNotifications the names of the PipelineActivities to notify the team chat channel about
+	chatNotifications chan string
 
 	// private field to record whether the lighthouse-foghorn deployment is present - if so, we skip status reporting
 	foghornPresent bool
@@ -77,7 +82,6 @@ type ControllerBuildOptions struct {
 type LongTermStorageLogWriter struct {
 	data       []byte
 	kubeClient kubernetes.Interface
-	logMasker  *kube.LogMasker
 }
 
 // WriteLog will receive a logs.LogLine value and append its bytes to the LongTermStorageLogWriter stored bytes
@@ -87,7 +91,7 @@ func (w *LongTermStorageLogWriter) WriteLog(logLine logs.LogLine, lch chan<- log
 }
 
 // StreamLog will receive a logs channel and an errors channel which the logs producer will send
-// it will mask the lines marked as ShouldMask then it will append the line's bytes to the already stored ones
+// it will append the line's bytes, which are masked by the logs producer, to the already stored ones
 func (w *LongTermStorageLogWriter) StreamLog(lch <-chan logs.LogLine, ech <-chan error) error {
 	for {
 		select {
@@ -95,9 +99,6 @@ func (w *LongTermStorageLogWriter) StreamLog(lch <-chan logs.LogLine, ech <-chan
 			if !ok {
 				return nil
 			}
-			if w.logMasker != nil && l.ShouldMask {
-				l.Line = w.logMasker.MaskLog(l.Line)
-			}
 			line := []byte(l.Line)
 			line = append(line, '\n')
 			w.data = append(w.data, line...)
@@ -209,6 +210,10 @@ func (o *ControllerBuildOptions) Run() error {
 		log.Logger().Warnf("failed to label the legacy PipelineActivity resources: %s", err)
 	}
 
+	// chat notifications are posted outside of the informer callbacks so that slow chat services don't block them
+	o.chatNotifications = make(chan string, 100)
+	go o.runChatNotifications(jxClient, ns)
+
 	pod := &corev1.Pod{}
 	log.Logger().Infof("Watching for Pods in namespace %s", util.ColorInfo(ns))
 	listWatch := cache.NewListWatchFromClient(kubeClient.CoreV1().RESTClient(), "pods", ns, fields.Everything())
@@ -272,6 +277,7 @@ func (o *ControllerBuildOptions) handleStandalonePod(pod *corev1.Pod, kubeClient
 							return err
 						}
 					}
+					o.queueChatNotification(a)
 					return nil
 				})
 				if err != nil {
@@ -347,6 +353,7 @@ func (o *ControllerBuildOptions) onPipelinePod(obj interface{}, kubeClient kuber
 								return err
 							}
 						}
+						o.queueChatNotification(a)
 						return nil
 					})
 					if err != nil {
@@ -548,7 +555,7 @@ func (o *ControllerBuildOptions) updatePipelineActivity(kubeClient kubernetes.In
 		step := &spec.Steps[i]
 		stage := step.Stage
 		if stage != nil {
-			stageFinished := spec.Status.IsTerminated()
+			stageFinished := spec.Status.IsTerminated() || stage.Status == v1.ActivityStatusTypeSkipped
 			if stage.StartedTimestamp != nil && spec.StartedTimestamp == nil {
 				spec.StartedTimestamp = stage.StartedTimestamp
 			}
@@ -563,7 +570,7 @@ func (o *ControllerBuildOptions) updatePipelineActivity(kubeClient kubernetes.In
 			}
 			if stageFinished {
 				switch stage.Status {
-				case v1.ActivityStatusTypeSucceeded, v1.ActivityStatusTypeNotExecuted:
+				case v1.ActivityStatusTypeSucceeded, v1.ActivityStatusTypeNotExecuted, v1.ActivityStatusTypeSkipped:
 					// stage did not fail
 				default:
 					failed = true
@@ -616,7 +623,7 @@ func (o *ControllerBuildOptions) updatePipelineActivity(kubeClient kubernetes.In
 					log.Logger().Warnf("No GitURL on PipelineActivity %s", activity.Name)
 				}
 			}
-			masker, err := kube.NewLogMasker(kubeClient, ns)
+			masker, err := o.CreateLogMasker(kubeClient, ns, settings)
 			if err != nil {
 				log.Logger().Warnf("Failed to create LogMasker in namespace %s: %s", ns, err.Error())
 			}
@@ -664,7 +671,7 @@ func (o *ControllerBuildOptions) updatePipelineActivityForRun(kubeClient kuberne
 		step := &spec.Steps[i]
 		stage := step.Stage
 		if stage != nil {
-			stageFinished := spec.Status.IsTerminated()
+			stageFinished := spec.Status.IsTerminated() || stage.Status == v1.ActivityStatusTypeSkipped
 			if stage.StartedTimestamp != nil && spec.StartedTimestamp == nil {
 				spec.StartedTimestamp = stage.StartedTimestamp
 			}
@@ -679,7 +686,7 @@ func (o *ControllerBuildOptions) updatePipelineActivityForRun(kubeClient kuberne
 			}
 			if stageFinished {
 				switch stage.Status {
-				case v1.ActivityStatusTypeSucceeded, v1.ActivityStatusTypeNotExecuted:
+				case v1.ActivityStatusTypeSucceeded, v1.ActivityStatusTypeNotExecuted, v1.ActivityStatusTypeSkipped:
 					// stage did not fail
 				default:
 					failed = true
@@ -749,7 +756,7 @@ func (o *ControllerBuildOptions) updatePipelineActivityForRun(kubeClient kuberne
 					}
 				}
 
-				masker, err := kube.NewLogMasker(kubeClient, ns)
+				masker, err := o.CreateLogMasker(kubeClient, ns, settings)
 				if err != nil {
 					log.Logger().Warnf("Failed to create LogMasker in namespace %s: %s", ns, err.Error())
 				}
@@ -791,8 +798,101 @@ func (o *ControllerBuildOptions) updatePipelineActivityForRun(kubeClient kuberne
 	return originYaml != newYaml
 }
 
+// queueChatNotification queues the activity so that the team chat channel is notified of its status outside of the
+// informer callbacks if it has failed and the team has not been notified of its status yet
+func (o *ControllerBuildOptions) queueChatNotification(activity *v1.PipelineActivity) {
+	if o.chatNotifications == nil || !needsChatNotification(activity) {
+		return
+	}
+	select {
+	case o.chatNotifications <- activity.Name:
+	default:
+		log.Logger().Warnf("Dropping the chat notification for PipelineActivity %s as too many notifications are queued", activity.Name)
+	}
+}
+
+func (o *ControllerBuildOptions) runChatNotifications(jxClient versioned.Interface, ns string) {
+	for name := range o.chatNotifications {
+		o.notifyChat(jxClient, ns, name)
+	}
+}
+
+// notifyChat posts to the team chat channel if the activity has failed and the team has not been notified of its
+// status yet. The notified status is saved on the activity before posting so that the team is only notified once
+func (o *ControllerBuildOptions) notifyChat(jxClient versioned.Interface, ns string, name string) {
+	if o.DryRun {
+		return
+	}
+	notifier, err := o.createChatNotifier()
+	if err != nil {
+		log.Logger().Warnf("Failed to create the chat notifier for PipelineActivity %s: %s", name, err)
+		return
+	}
+	if notifier == nil {
+		return
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	var activity *v1.PipelineActivity
+	var notifiedStatus v1.ActivityStatusType
+	err = util.Retry(time.Second*20, func() error {
+		a, err := activities.Get(name, metav1.GetOptions{})
+		if err != nil {
+			return err
+		}
+		if !needsChatNotification(a) {
+			return nil
+		}
+		if a.Annotations == nil {
+			a.Annotations = map[string]string{}
+		}
+		notifiedStatus = v1.ActivityStatusType(a.Annotations[kube.AnnotationChatNotifiedStatus])
+		a.Annotations[kube.AnnotationChatNotifiedStatus] = string(a.Spec.Status)
+		activity, err = activities.Update(a)
+		return err
+	})
+	if err != nil {
+		log.Logger().Warnf("Failed to update PipelineActivity %s with the chat notification status: %s", name, err)
+		return
+	}
+	if activity == nil {
+		return
+	}
+	_, err = notifier.PipelineActivityStatusChanged(activity, notifiedStatus)
+	if err != nil {
+		log.Logger().Warnf("Failed to notify chat about PipelineActivity %s: %s", name, err)
+	}
+}
+
+func (o *ControllerBuildOptions) createChatNotifier() (*chats.Notifier, error) {
+	if o.chatNotifier != nil {
+		return o.chatNotifier, nil
+	}
+	if o.EnvironmentCache == nil {
+		return nil, nil
+	}
+	devEnv := o.EnvironmentCache.Item(kube.LabelValueDevEnvironment)
+	if devEnv == nil {
+		return nil, nil
+	}
+	settings := devEnv.Spec.TeamSettings.ChatSettings
+	if settings == nil {
+		return nil, nil
+	}
+	return o.CreateChatNotifier(settings, settings.Channel)
+}
+
+// needsChatNotification returns true if the activity has a status the team should know about which it has not been
+// notified of yet
+func needsChatNotification(activity *v1.PipelineActivity) bool {
+	return chats.PipelineActivityMessage(activity) != nil && activity.Annotations[kube.AnnotationChatNotifiedStatus] != string(activity.Spec.Status)
+}
+
 func updateForStage(si *tekton.StageInfo, a *v1.PipelineActivity) {
 	_, stage, _ := kube.GetOrCreateStage(a, si.GetStageNameIncludingParents())
+	if si.Skipped {
+		stage.Status = v1.ActivityStatusTypeSkipped
+		return
+	}
 	containersTerminated := false
 
 	if si.Pod != nil {
@@ -824,8 +924,9 @@ func updateForStage(si *tekton.StageInfo, a *v1.PipelineActivity) {
 			step.Description = createStepDescription(container.Name, pod)
 
 			if terminated != nil {
-				if terminated.ExitCode == 0 {
-					if didPreviousStepFail(i, stageSteps) {
+				// steps of stages with post conditions exit successfully when they fail so that the post steps run
+				if terminated.ExitCode == 0 && strings.TrimSpace(terminated.Message) != syntax.PostStepFailedMessage {
+					if didPreviousStepFail(i, stageSteps) && !strings.HasPrefix(container.Name, "step-"+syntax.PostStepNamePrefix) {
 						step.Status = v1.ActivityStatusTypeNotExecuted
 					} else {
 						step.Status = v1.ActivityStatusTypeSucceeded
@@ -889,7 +990,7 @@ func updateForStage(si *tekton.StageInfo, a *v1.PipelineActivity) {
 				}
 			}
 			if childFinished {
-				if child.Status != v1.ActivityStatusTypeSucceeded {
+				if child.Status != v1.ActivityStatusTypeSucceeded && child.Status != v1.ActivityStatusTypeSkipped {
 					childrenFailed = true
 				}
 			} else {
@@ -1083,7 +1184,6 @@ func (o *ControllerBuildOptions) generateBuildLogURL(podInterface typedcorev1.Po
 	w := LongTermStorageLogWriter{
 		data:       []byte{},
 		kubeClient: kubeClient,
-		logMasker:  logMasker,
 	}
 	logWriter = &w
 
@@ -1093,6 +1193,7 @@ func (o *ControllerBuildOptions) generateBuildLogURL(podInterface typedcorev1.Po
 		TektonClient: tektonClient,
 		Namespace:    ns,
 		LogWriter:    logWriter,
+		LogMasker:    logMasker,
 	}
 
 	log.Logger().Debugf("Capturing running build logs for %s", activity.Name)
@@ -11,13 +11,17 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/chats"
+	jxfake "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/fake"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/testhelpers"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
 	"github.com/jenkins-x/jx/v2/pkg/kube"
 	"github.com/jenkins-x/jx/v2/pkg/tekton"
+	"github.com/jenkins-x/jx/v2/pkg/tekton/syntax"
 	"github.com/jenkins-x/jx/v2/pkg/tekton/tekton_helpers_test"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	tektonfake "github.com/tektoncd/pipeline/pkg/client/clientset/versioned/fake"
 
 	corev1 "k8s.io/api/core/v1"
@@ -148,6 +152,57 @@ func TestUpdateForStage(t *testing.T) {
 	}
 }
 
+func TestUpdateForStageWithPostSteps(t *testing.T) {
+	finishedAt := metav1.NewTime(time.Now())
+	terminated := func(exitCode int32, message string) corev1.ContainerState {
+		return corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{
+			ExitCode:   exitCode,
+			Message:    message,
+			StartedAt:  finishedAt,
+			FinishedAt: finishedAt,
+		}}
+	}
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "jenkins-x-jx-master-1-build-pod"},
+		Spec: corev1.PodSpec{
+			Containers: []corev1.Container{
+				{Name: "step-step2"},
+				{Name: "step-step3"},
+				{Name: "step-post-failure-command-1"},
+				{Name: "step-post-result"},
+			},
+		},
+		Status: corev1.PodStatus{
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "step-step2", State: terminated(0, syntax.PostStepFailedMessage+"\n")},
+				{Name: "step-step3", State: terminated(0, "")},
+				{Name: "step-post-failure-command-1", State: terminated(0, "")},
+				{Name: "step-post-result", State: terminated(1, "")},
+			},
+		},
+	}
+	si := &tekton.StageInfo{
+		Name: "build",
+		Pod:  pod,
+	}
+	act := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "jenkins-x-jx-master-1",
+		},
+	}
+
+	updateForStage(si, act)
+
+	require.Len(t, act.Spec.Steps, 1)
+	stage := act.Spec.Steps[0].Stage
+	require.NotNil(t, stage)
+	require.Len(t, stage.Steps, 4)
+	assert.Equal(t, v1.ActivityStatusTypeFailed, stage.Steps[0].Status)
+	assert.Equal(t, v1.ActivityStatusTypeNotExecuted, stage.Steps[1].Status)
+	assert.Equal(t, v1.ActivityStatusTypeSucceeded, stage.Steps[2].Status)
+	assert.Equal(t, v1.ActivityStatusTypeFailed, stage.Steps[3].Status)
+}
+
 func TestCreateReportTargetURL(t *testing.T) {
 	params := ReportParams{
 		Owner:      "jstrachan",
@@ -354,3 +409,53 @@ func parseTime(t *testing.T, timeString string) *time.Time {
 	}
 	return nil
 }
+
+type fakeChatProvider struct {
+	messages []*chats.Message
+}
+
+func (f *fakeChatProvider) GetChannelMetrics(name string) (*chats.ChannelMetrics, error) {
+	return &chats.ChannelMetrics{Name: name}, nil
+}
+
+func (f *fakeChatProvider) PostMessage(channel string, message *chats.Message) (*chats.PostedMessage, error) {
+	f.messages = append(f.messages, message)
+	return &chats.PostedMessage{Channel: channel}, nil
+}
+
+func TestNotifyChatOnlyOnce(t *testing.T) {
+	activity := &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "jenkins-x-jx-master-12",
+			Namespace: "jx",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline: "jenkins-x/jx/master",
+			Build:    "12",
+			Status:   v1.ActivityStatusTypeFailed,
+		},
+	}
+	jxClient := jxfake.NewSimpleClientset(activity)
+	provider := &fakeChatProvider{}
+	o := &ControllerBuildOptions{
+		chatNotifier:      chats.NewNotifier(provider, "#builds"),
+		chatNotifications: make(chan string, 10),
+	}
+
+	o.queueChatNotification(activity)
+	o.queueChatNotification(activity)
+	require.Len(t, o.chatNotifications, 2)
+	close(o.chatNotifications)
+	o.runChatNotifications(jxClient, "jx")
+
+	require.Len(t, provider.messages, 1)
+	assert.Equal(t, "Pipeline jenkins-x/jx/master #12 failed", provider.messages[0].Text)
+
+	found, err := jxClient.JenkinsV1().PipelineActivities("jx").Get(activity.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, string(v1.ActivityStatusTypeFailed), found.Annotations[kube.AnnotationChatNotifiedStatus])
+
+	o.chatNotifications = make(chan string, 10)
+	o.queueChatNotification(found)
+	assert.Len(t, o.chatNotifications, 0)
+}
@@ -1,8 +1,13 @@
 package controller
 
 import (
+	"fmt"
+	"os"
+	"strings"
+
 	"github.com/jenkins-x/jx/v2/pkg/buildnum"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/pkg/errors"
 
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
@@ -20,6 +25,8 @@ type ControllerBuildNumbersOptions struct {
 	*opts.CommonOptions
 	BindAddress string
 	Port        int
+	Issuer      string
+	LeaderElect bool
 }
 
 var (
@@ -49,6 +56,12 @@ func NewCmdControllerBuildNumbers(commonOpts *opts.CommonOptions) *cobra.Command
 	cmd.Flags().IntVarP(&options.Port, optionPort, "", 8080, "The TCP port to listen on.")
 	cmd.Flags().StringVarP(&options.BindAddress, optionBind, "", "",
 		"The interface address to bind to (by default, will listen on all interfaces/addresses).")
+	cmd.Flags().StringVarP(&options.Issuer, "issuer", "", buildnum.IssuerActivity,
+		fmt.Sprintf("The kind of build number issuer to use. One of: %s. The %s issuer only coordinates the builds which get their numbers from it",
+			strings.Join(buildnum.BuildNumberIssuers, ", "), buildnum.IssuerConfigMap))
+	cmd.Flags().BoolVarP(&options.LeaderElect, "leader-elect", "", false,
+		"Only serve build numbers from the replica elected leader. Requires permission to get, create and update the ConfigMap "+
+			buildnum.LeaderElectionConfigMapName+" and to create Events.")
 	return cmd
 }
 
@@ -58,8 +71,24 @@ func (o *ControllerBuildNumbersOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	buildNumGen := buildnum.NewCRDBuildNumGen(jxClient, ns)
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+
+	buildNumGen, err := buildnum.NewBuildNumberIssuer(o.Issuer, kubeClient, jxClient, ns)
+	if err != nil {
+		return err
+	}
 
 	httpBuildNumServer := buildnum.NewHTTPBuildNumberServer(o.BindAddress, o.Port, buildNumGen)
-	return httpBuildNumServer.Start()
+	if !o.LeaderElect {
+		return httpBuildNumServer.Start()
+	}
+
+	identity, err := os.Hostname()
+	if err != nil {
+		return errors.Wrap(err, "getting the hostname to identify this replica")
+	}
+	return buildnum.RunAsLeader(kubeClient, ns, identity, httpBuildNumServer.Start)
 }
@@ -0,0 +1,255 @@
+package controller
+
+import (
+	"time"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	pipelineevents "github.com/jenkins-x/jx/v2/pkg/pipeline_events"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// ControllerPipelineEventsOptions are the flags for the commands
+type ControllerPipelineEventsOptions struct {
+	ControllerOptions
+
+	Namespace    string
+	SendExisting bool
+
+	Provider  pipelineevents.PipelineEventsProvider
+	StartTime time.Time
+
+	// events holds the changed PipelineActivities and Releases until they are sent so that slow or failing sinks
+	// don't block the informers
+	events workqueue.Interface
+}
+
+var (
+	controllerPipelineEventsLong = templates.LongDesc(`
+		Runs the controller which sends changes to PipelineActivities and Releases to the pipeline events sink
+		configured in the team settings, such as CloudEvents over HTTP, a webhook or a Kafka REST proxy.
+
+		Events which cannot be sent are retried and then posted to the dead letter URL of the settings, if there is one.
+`)
+
+	controllerPipelineEventsExample = templates.Examples(`
+		# send events for the changes to PipelineActivities and Releases in the current team
+		jx controller pipelineevents
+
+		# also send events for the PipelineActivities and Releases which already exist
+		jx controller pipelineevents --send-existing
+	`)
+)
+
+// NewCmdControllerPipelineEvents creates the command which streams pipeline events
+func NewCmdControllerPipelineEvents(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &ControllerPipelineEventsOptions{
+		ControllerOptions: ControllerOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "pipelineevents",
+		Short:   "Runs the controller which sends pipeline events as PipelineActivities and Releases change",
+		Long:    controllerPipelineEventsLong,
+		Example: controllerPipelineEventsExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+		Aliases: []string{"pipelineevent", "pipeline-events"},
+	}
+
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to watch or defaults to the current namespace")
+	cmd.Flags().BoolVarP(&options.SendExisting, "send-existing", "", false, "Sends events for the PipelineActivities and Releases which exist when the controller starts")
+
+	return cmd
+}
+
+// Run implements this command
+func (o *ControllerPipelineEventsOptions) Run() error {
+	// Always run in batch mode as a controller is never run interactively
+	o.BatchMode = true
+
+	err := o.RegisterPipelineActivityCRD()
+	if err != nil {
+		return err
+	}
+	err = o.RegisterReleaseCRD()
+	if err != nil {
+		return err
+	}
+
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+
+	if o.Provider == nil {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			return errors.Wrap(err, "loading the team settings")
+		}
+		o.Provider, err = o.CreatePipelineEventsProvider(teamSettings.PipelineEvents)
+		if err != nil {
+			return errors.Wrap(err, "creating the pipeline events provider")
+		}
+		if o.Provider == nil {
+			return errors.New("no pipeline events sink is configured in the team settings")
+		}
+	}
+	if o.StartTime.IsZero() {
+		o.StartTime = time.Now()
+	}
+
+	if o.events == nil {
+		o.events = workqueue.NewNamed("pipelineevents")
+	}
+	defer o.events.ShutDown()
+	go o.sendEvents()
+
+	log.Logger().Infof("Watching for PipelineActivities and Releases in namespace %s", util.ColorInfo(ns))
+
+	_, activityController := cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(lo metav1.ListOptions) (runtime.Object, error) {
+				return jxClient.JenkinsV1().PipelineActivities(ns).List(lo)
+			},
+			WatchFunc: func(lo metav1.ListOptions) (watch.Interface, error) {
+				return jxClient.JenkinsV1().PipelineActivities(ns).Watch(lo)
+			},
+		},
+		&v1.PipelineActivity{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onActivity(nil, obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onActivity(oldObj, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	stop := make(chan struct{})
+	go activityController.Run(stop)
+
+	_, releaseController := cache.NewInformer(
+		&cache.ListWatch{
+			ListFunc: func(lo metav1.ListOptions) (runtime.Object, error) {
+				return jxClient.JenkinsV1().Releases(ns).List(lo)
+			},
+			WatchFunc: func(lo metav1.ListOptions) (watch.Interface, error) {
+				return jxClient.JenkinsV1().Releases(ns).Watch(lo)
+			},
+		},
+		&v1.Release{},
+		time.Minute*10,
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				o.onRelease(nil, obj)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				o.onRelease(oldObj, newObj)
+			},
+			DeleteFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	go releaseController.Run(stop)
+
+	// Wait forever
+	select {}
+}
+
+func (o *ControllerPipelineEventsOptions) onActivity(oldObj interface{}, newObj interface{}) {
+	activity, ok := newObj.(*v1.PipelineActivity)
+	if !ok {
+		log.Logger().Warnf("pipeline events controller: unexpected type %v", newObj)
+		return
+	}
+	var old *metav1.ObjectMeta
+	if oldActivity, ok := oldObj.(*v1.PipelineActivity); ok {
+		old = &oldActivity.ObjectMeta
+	}
+	if !o.shouldSend(old, &activity.ObjectMeta) {
+		return
+	}
+	o.events.Add(activity)
+}
+
+func (o *ControllerPipelineEventsOptions) onRelease(oldObj interface{}, newObj interface{}) {
+	release, ok := newObj.(*v1.Release)
+	if !ok {
+		log.Logger().Warnf("pipeline events controller: unexpected type %v", newObj)
+		return
+	}
+	var old *metav1.ObjectMeta
+	if oldRelease, ok := oldObj.(*v1.Release); ok {
+		old = &oldRelease.ObjectMeta
+	}
+	if !o.shouldSend(old, &release.ObjectMeta) {
+		return
+	}
+	o.events.Add(release)
+}
+
+// sendEvents sends the queued changes one at a time, so the events of a resource are sent in order, until the queue
+// is shut down
+func (o *ControllerPipelineEventsOptions) sendEvents() {
+	for o.sendNextEvent() {
+	}
+}
+
+func (o *ControllerPipelineEventsOptions) sendNextEvent() bool {
+	item, shutdown := o.events.Get()
+	if shutdown {
+		return false
+	}
+	defer o.events.Done(item)
+
+	switch obj := item.(type) {
+	case *v1.PipelineActivity:
+		err := o.Provider.SendActivity(obj)
+		if err != nil {
+			log.Logger().Warnf("Failed to send the event for PipelineActivity %s: %s", obj.Name, err)
+		}
+	case *v1.Release:
+		err := o.Provider.SendRelease(obj)
+		if err != nil {
+			log.Logger().Warnf("Failed to send the event for Release %s: %s", obj.Name, err)
+		}
+	default:
+		log.Logger().Warnf("pipeline events controller: unexpected queued type %v", item)
+	}
+	return true
+}
+
+// shouldSend returns false for the periodic resyncs of the informers, which don't change the resource, and for
+// resources which existed before the controller started unless they should be sent too
+func (o *ControllerPipelineEventsOptions) shouldSend(old *metav1.ObjectMeta, current *metav1.ObjectMeta) bool {
+	if old != nil {
+		return old.ResourceVersion != current.ResourceVersion
+	}
+	return o.SendExisting || !current.CreationTimestamp.Time.Before(o.StartTime)
+}
@@ -0,0 +1,72 @@
+// +build unit
+
+package controller
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/workqueue"
+)
+
+type recordingEventsProvider struct {
+	activities []string
+	releases   []string
+}
+
+func (p *recordingEventsProvider) SendActivity(a *v1.PipelineActivity) error {
+	p.activities = append(p.activities, a.Name+"@"+a.ResourceVersion)
+	return nil
+}
+
+func (p *recordingEventsProvider) SendRelease(r *v1.Release) error {
+	p.releases = append(p.releases, r.Name+"@"+r.ResourceVersion)
+	return nil
+}
+
+func TestPipelineEventsControllerSendsChanges(t *testing.T) {
+	start := time.Now()
+	provider := &recordingEventsProvider{}
+	o := &ControllerPipelineEventsOptions{
+		Provider:  provider,
+		StartTime: start,
+		events:    workqueue.New(),
+	}
+	defer o.events.ShutDown()
+	sendQueued := func() {
+		for o.events.Len() > 0 {
+			o.sendNextEvent()
+		}
+	}
+	activity := func(name string, version string, created time.Time) *v1.PipelineActivity {
+		return &v1.PipelineActivity{ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			ResourceVersion:   version,
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+	}
+
+	existing := activity("existing", "1", start.Add(-time.Hour))
+	o.onActivity(nil, existing)
+	o.onActivity(nil, activity("created", "2", start.Add(time.Second)))
+	o.onActivity(existing, activity("existing", "3", start.Add(-time.Hour)))
+	// a resync of the informer does not change the resource
+	o.onActivity(existing, existing)
+
+	release := &v1.Release{ObjectMeta: metav1.ObjectMeta{Name: "demo-1.0.0", ResourceVersion: "4", CreationTimestamp: metav1.NewTime(start)}}
+	o.onRelease(nil, release)
+	o.onRelease(release, release)
+	assert.Empty(t, provider.activities, "events should only be sent from the queue")
+
+	sendQueued()
+	assert.Equal(t, []string{"created@2", "existing@3"}, provider.activities)
+	assert.Equal(t, []string{"demo-1.0.0@4"}, provider.releases)
+
+	o.SendExisting = true
+	o.onActivity(nil, existing)
+	sendQueued()
+	assert.Equal(t, []string{"created@2", "existing@3", "existing@1"}, provider.activities)
+}
@@ -3,6 +3,7 @@ package create
 import (
 	"fmt"
 
+	"github.com/jenkins-x/jx/v2/pkg/chats"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/create/options"
 
 	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
@@ -22,6 +23,10 @@ var (
 	createChatServer_example = templates.Examples(`
 		# Add a new chat server URL
 		jx create chat server slack https://myroom.slack.server
+
+		# Add a Mattermost or Microsoft Teams server
+		jx create chat server mattermost https://mattermost.example.com
+		jx create chat server msteams https://outlook.office.com
 	`)
 )
 
@@ -65,6 +70,9 @@ func (o *CreateChatServerOptions) Run() error {
 		return missingChatArguments()
 	}
 	kind := args[0]
+	if util.StringArrayIndex(chats.ChatKinds, kind) < 0 {
+		return util.InvalidArg(kind, chats.ChatKinds)
+	}
 	name := o.Name
 	if name == "" {
 		name = kind
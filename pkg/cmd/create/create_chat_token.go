@@ -29,6 +29,9 @@ var (
 
 		# As above with the password being passed in
 		jx create git token -n jira -p somePassword someUserName	
+
+		# Add the incoming webhook of a Mattermost or Microsoft Teams server as the token
+		jx create chat token -u https://mattermost.example.com -t https://mattermost.example.com/hooks/xxx-generatedkey-xxx jenkins-x-bot
 	`)
 )
 
@@ -103,7 +106,11 @@ func (o *CreateChatTokenOptions) Run() error {
 
 	if userAuth.IsInvalid() {
 		f := func(username string) error {
-			log.Logger().Infof("Please generate an API Token for %s server %s", server.Kind, server.Label())
+			if chats.IsIncomingWebhookKind(server.Kind) {
+				log.Logger().Infof("Please create an incoming webhook for %s server %s and use its URL as the API Token", server.Kind, server.Label())
+			} else {
+				log.Logger().Infof("Please generate an API Token for %s server %s", server.Kind, server.Label())
+			}
 			if tokenUrl != "" {
 				log.Logger().Infof("Click this URL %s\n", util.ColorInfo(tokenUrl))
 			}
@@ -106,6 +106,7 @@ func NewCmdCreateEnv(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVarP(&options.Options.Spec.Namespace, kube.OptionNamespace, "s", "", "The Kubernetes namespace for the Environment")
 	cmd.Flags().StringVarP(&options.Options.Spec.Cluster, "cluster", "c", "", "The Kubernetes cluster for the Environment. If blank and a namespace is specified assumes the current cluster")
 	cmd.Flags().BoolVarP(&options.Options.Spec.RemoteCluster, "remote", "", false, "Indicates the Environment resides in a separate cluster to the development cluster. If this is true then we don't perform release piplines in this git repository but we use the Environment Controller inside that cluster: https://jenkins-x.io/getting-started/multi-cluster/")
+	cmd.Flags().BoolVarP(&options.Options.Spec.Protected, "protected", "", false, "Indicates releases are only promoted to the Environment if their images have no critical vulnerabilities")
 	cmd.Flags().StringVarP(&options.Options.Spec.Source.URL, "git-url", "g", "", "The Git clone URL for the source code for GitOps based Environments")
 	cmd.Flags().StringVarP(&options.Options.Spec.Source.Ref, "git-ref", "r", "", "The Git repo reference for the source code for GitOps based Environments")
 	cmd.Flags().StringVarP(&options.GitRepositoryOptions.Owner, "git-owner", "", "", "Git organisation / owner")
@@ -355,8 +356,32 @@ func (o *CreateEnvOptions) RegisterEnvironment(env *v1.Environment, gitProvider
 		if err != nil {
 			return err
 		}
+		err = o.addPipelineUserAsCollaborator(gitProvider, server, user, gitInfo)
+		if err != nil {
+			return err
+		}
 		return o.CreateWebhookProw(gitURL, gitProvider)
 	}
 
 	return o.ImportProject(gitURL, envDir, jenkinsfile.Name, o.BranchPattern, o.EnvJobCredentials, false, gitProvider, authConfigSvc, true, o.BatchMode)
 }
+
+// addPipelineUserAsCollaborator grants the pipeline user access to the environment repository when the repository
+// was created by a different git user
+func (o *CreateEnvOptions) addPipelineUserAsCollaborator(gitProvider gits.GitProvider, server *auth.AuthServer, pipelineUser *auth.UserAuth, gitInfo *gits.GitRepository) error {
+	if pipelineUser.Username == gitProvider.CurrentUsername() {
+		return nil
+	}
+	githubAppMode, err := o.IsGitHubAppMode()
+	if err != nil {
+		return err
+	}
+	if githubAppMode {
+		return nil
+	}
+	pipelineUserProvider, err := gits.CreateProvider(server, pipelineUser, o.Git())
+	if err != nil {
+		return errors.Wrapf(err, "creating the git provider for the pipeline user %s", pipelineUser.Username)
+	}
+	return gits.AddCollaboratorAndAcceptInvitations(gitProvider, pipelineUserProvider, pipelineUser.Username, gitInfo.Organisation, gitInfo.Name)
+}
@@ -2,6 +2,7 @@ package create
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/jenkins-x/jx/v2/pkg/cmd/create/options"
 
@@ -9,6 +10,7 @@ import (
 
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/issues"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/spf13/cobra"
@@ -17,16 +19,32 @@ import (
 var (
 	createTrackerServer_long = templates.LongDesc(`
 		Adds a new Issue Tracker Server URL
+
+		The kind of the server is one of: ` + strings.Join(issues.IssueTrackerKinds, ", ") + `
+
+		For GitHub and GitLab the project of the issue tracker in your jenkins-x.yml is the owner/repository whose issues are used, which can be on a different server to your source code.
+		For Linear the URL is that of your workspace and the project is the key of your team.
 `)
 
 	createTrackerServer_example = templates.Examples(`
 		# Add a new issue tracker server URL
 		jx create tracker server jira myURL
+
+		# Add GitHub as an issue tracker
+		jx create tracker server github
+
+		# Add a Linear workspace as an issue tracker
+		jx create tracker server linear https://linear.app/acme
 	`)
 
 	trackerKindToServiceName = map[string]string{
 		"bitbucket": "bitbucket-bitbucket",
 	}
+
+	trackerKindToDefaultURL = map[string]string{
+		issues.GitHub: "https://github.com",
+		issues.GitLab: "https://gitlab.com",
+	}
 )
 
 // CreateTrackerServerOptions the options for the create spring command
@@ -87,6 +105,9 @@ func (o *CreateTrackerServerOptions) Run() error {
 			gitUrl = url
 		}
 	}
+	if gitUrl == "" {
+		gitUrl = trackerKindToDefaultURL[kind]
+	}
 
 	if gitUrl == "" {
 		return missingTrackerArguments()
@@ -57,6 +57,7 @@ func NewCmdDelete(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(NewCmdDeleteGit(commonOpts))
 	cmd.AddCommand(NewCmdDeleteGke(commonOpts))
 	cmd.AddCommand(NewCmdDeleteJenkins(commonOpts))
+	cmd.AddCommand(NewCmdDeleteLock(commonOpts))
 	cmd.AddCommand(NewCmdDeleteNamespace(commonOpts))
 	cmd.AddCommand(NewCmdDeletePreview(commonOpts))
 	cmd.AddCommand(NewCmdDeleteQuickstartLocation(commonOpts))
@@ -0,0 +1,101 @@
+package deletecmd
+
+import (
+	"fmt"
+
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	survey "gopkg.in/AlecAivazis/survey.v1"
+)
+
+// DeleteLockOptions are the flags for delete commands
+type DeleteLockOptions struct {
+	*opts.CommonOptions
+
+	Confirm bool
+}
+
+var (
+	deleteLockLong = templates.LongDesc(`
+		Breaks the build lock of a namespace, for example when the build holding it is stuck or finished without
+		releasing it. The lock is handed over to the next waiting build, or removed if no build is waiting.
+`)
+
+	deleteLockExample = templates.Examples(`
+		# Break the build lock of the staging namespace
+		jx delete lock jx-staging
+	`)
+)
+
+// NewCmdDeleteLock creates a command object for the "delete lock" command
+func NewCmdDeleteLock(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &DeleteLockOptions{
+		CommonOptions: commonOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "lock NAMESPACE",
+		Short:   "Breaks the build lock of a namespace",
+		Long:    deleteLockLong,
+		Example: deleteLockExample,
+		Aliases: []string{"locks"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().BoolVarP(&options.Confirm, "yes", "y", false, "Confirms we should break the lock")
+	return cmd
+}
+
+// Run implements this command
+func (o *DeleteLockOptions) Run() error {
+	if len(o.Args) != 1 {
+		return fmt.Errorf("Missing namespace argument")
+	}
+	namespace := o.Args[0]
+
+	kubeClient, devNs, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+
+	if o.BatchMode {
+		if !o.Confirm {
+			return fmt.Errorf("In batch mode you must specify the '-y' flag to confirm")
+		}
+	} else if !o.Confirm {
+		flag := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Are you sure you want to break the build lock of namespace %s?", namespace),
+			Default: false,
+		}
+		err = survey.AskOne(prompt, &flag, nil, survey.WithStdio(o.In, o.Out, o.Err))
+		if err != nil {
+			return err
+		}
+		if !flag {
+			return nil
+		}
+	}
+
+	lock, err := kube.BreakBuildLock(kubeClient, devNs, namespace)
+	if err != nil {
+		return errors.Wrapf(err, "breaking the build lock of namespace %s", namespace)
+	}
+	if lock == nil {
+		log.Logger().Infof("Removed the build lock of namespace %s", util.ColorInfo(namespace))
+		return nil
+	}
+	log.Logger().Infof("Handed the build lock of namespace %s over to %s/%s/%s #%s", util.ColorInfo(namespace),
+		lock.Holder.Owner, lock.Holder.Repository, lock.Holder.Branch, util.ColorInfo(lock.Holder.Build))
+	return nil
+}
@@ -173,7 +173,7 @@ func (o *GCActivitiesOptions) Run() error {
 	//
 	for _, a := range completedActivities {
 		branchName := a.BranchName()
-		isPR, isBatch := o.isPullRequestOrBatchBranch(branchName)
+		isPR, isBatch := isPullRequestOrBatchBranch(branchName)
 		maxAge, revisionHistory := o.ageAndHistoryLimits(isPR, isBatch)
 		// lets remove activities that are too old
 		if a.Spec.CompletedTimestamp != nil && a.Spec.CompletedTimestamp.Add(maxAge).Before(now) {
@@ -326,6 +326,7 @@ func (o *GCActivitiesOptions) ageAndHistoryLimits(isPR, isBatch bool) (time.Dura
 	return maxAge, revisionLimit
 }
 
-func (o *GCActivitiesOptions) isPullRequestOrBatchBranch(branchName string) (bool, bool) {
+// isPullRequestOrBatchBranch returns whether the branch is a Pull Request or a batch branch
+func isPullRequestOrBatchBranch(branchName string) (bool, bool) {
 	return strings.HasPrefix(branchName, "PR-"), branchName == "batch"
 }
@@ -0,0 +1,300 @@
+package gc
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/collector"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+)
+
+const (
+	// storageRootPath the path in the storage which logs, reports and attachments are stored under
+	storageRootPath = "jenkins-x/"
+)
+
+// GCStorageOptions the options for garbage collecting the logs, reports and attachments in long term storage
+type GCStorageOptions struct {
+	*opts.CommonOptions
+
+	DryRun                  bool
+	ReleaseHistoryLimit     int
+	PullRequestHistoryLimit int
+	ReleaseAgeLimit         time.Duration
+	PullRequestAgeLimit     time.Duration
+}
+
+var (
+	GCStorageLong = templates.LongDesc(`
+		Garbage collect the build logs, reports and attachments stored in the long term storage of the team.
+
+		The same per branch and per Pull Request retention rules as 'jx gc activities' are applied to the builds
+		stored in each bucket or git repository of the team storage locations.
+
+`)
+
+	GCStorageExample = templates.Examples(`
+		# garbage collect the stored build logs, reports and attachments
+		jx gc storage
+
+		# report what would be removed without removing anything
+		jx gc storage --dry-run
+`)
+)
+
+// storedBuild the artifacts stored for a single build of a repository branch
+type storedBuild struct {
+	Location   string
+	Classifier string
+	Repository string
+	Branch     string
+	Number     int
+	Paths      []string
+	Size       int64
+	ModTime    time.Time
+	Reason     string
+}
+
+// Name returns the name of the build
+func (b *storedBuild) Name() string {
+	return fmt.Sprintf("%s/%s/%d", b.Repository, b.Branch, b.Number)
+}
+
+// NewCmdGCStorage creates the command object for garbage collecting long term storage
+func NewCmdGCStorage(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GCStorageOptions{
+		CommonOptions: commonOpts,
+	}
+
+	cmd := &cobra.Command{
+		Use:     "storage",
+		Aliases: []string{"artifacts", "buckets"},
+		Short:   "garbage collection for the build logs, reports and attachments in long term storage",
+		Long:    GCStorageLong,
+		Example: GCStorageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "d", false, "Dry run mode. If enabled just report the builds that would be removed")
+	cmd.Flags().IntVarP(&options.ReleaseHistoryLimit, "release-history-limit", "l", 5, "Maximum number of builds to keep stored per repository release")
+	cmd.Flags().IntVarP(&options.PullRequestHistoryLimit, "pr-history-limit", "", 2, "Maximum number of builds to keep stored per repository Pull Request")
+	cmd.Flags().DurationVarP(&options.PullRequestAgeLimit, "pull-request-age", "p", time.Hour*48, "Maximum age to keep the stored builds of Pull Requests")
+	cmd.Flags().DurationVarP(&options.ReleaseAgeLimit, "release-age", "r", time.Hour*24*30, "Maximum age to keep the stored builds of Releases")
+	return cmd
+}
+
+// Run implements this command
+func (o *GCStorageOptions) Run() error {
+	settings, err := o.TeamSettings()
+	if err != nil {
+		return errors.Wrap(err, "failed to load the team settings")
+	}
+
+	descriptions := map[string]bool{}
+	var expired []*storedBuild
+	for _, location := range settings.StorageLocations {
+		if location.IsEmpty() {
+			continue
+		}
+		description := location.Description()
+		if descriptions[description] {
+			continue
+		}
+		descriptions[description] = true
+
+		coll, err := o.createCollector(location)
+		if err != nil {
+			return err
+		}
+		builds, err := o.gcCollector(coll, description, time.Now())
+		if err != nil {
+			return errors.Wrapf(err, "failed to garbage collect storage %s", description)
+		}
+		expired = append(expired, builds...)
+	}
+	if len(descriptions) == 0 {
+		log.Logger().Infof("no storage locations are configured for the team")
+		return nil
+	}
+	if o.DryRun {
+		o.report(expired)
+	}
+	return nil
+}
+
+func (o *GCStorageOptions) createCollector(location v1.StorageLocation) (collector.Collector, error) {
+	var gitKind string
+	if location.GitURL != "" {
+		gitInfo, err := gits.ParseGitURL(location.GitURL)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not parse git URL for storage URL %s", location.GitURL)
+		}
+		gitKind, err = o.GitServerKind(gitInfo)
+		if err != nil {
+			return nil, errors.Wrapf(err, "could not determine git kind for storage URL %s", location.GitURL)
+		}
+	}
+	coll, err := collector.NewCollector(location, o.Git(), gitKind)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to create the collector for storage settings %s", location.Description())
+	}
+	return coll, nil
+}
+
+// gcCollector removes the builds stored by the collector which have expired, returning the expired builds
+func (o *GCStorageOptions) gcCollector(coll collector.Collector, description string, now time.Time) ([]*storedBuild, error) {
+	artifacts, err := coll.ListArtifacts(storageRootPath)
+	if err != nil {
+		return nil, err
+	}
+	expired := o.expiredBuilds(groupStoredBuilds(description, artifacts), now)
+
+	if o.DryRun {
+		return expired, nil
+	}
+	var paths []string
+	for _, b := range expired {
+		log.Logger().Infof("deleting %d stored %s files of build %s from %s as it is %s", len(b.Paths), b.Classifier,
+			util.ColorInfo(b.Name()), description, b.Reason)
+		paths = append(paths, b.Paths...)
+	}
+	if len(paths) == 0 {
+		return expired, nil
+	}
+	err = coll.DeleteArtifacts(paths)
+	if err != nil {
+		return expired, err
+	}
+	log.Logger().Infof("deleted %d stored files of %d builds from %s", len(paths), len(expired), description)
+	return expired, nil
+}
+
+// expiredBuilds returns the builds which are older than the age limit or beyond the history limit of their branch
+func (o *GCStorageOptions) expiredBuilds(builds []*storedBuild, now time.Time) []*storedBuild {
+	// Sort with the newest builds of each branch first
+	sort.Slice(builds, func(i, j int) bool {
+		bi := builds[i]
+		bj := builds[j]
+		if bi.Location != bj.Location {
+			return bi.Location < bj.Location
+		}
+		if bi.Classifier != bj.Classifier {
+			return bi.Classifier < bj.Classifier
+		}
+		if bi.Repository != bj.Repository {
+			return bi.Repository < bj.Repository
+		}
+		if bi.Branch != bj.Branch {
+			return bi.Branch < bj.Branch
+		}
+		return bi.Number > bj.Number
+	})
+
+	counters := &buildsCount{}
+	var expired []*storedBuild
+	for _, b := range builds {
+		isPR, isBatch := isPullRequestOrBatchBranch(b.Branch)
+		maxAge, revisionHistory := o.ageAndHistoryLimits(isPR, isBatch)
+		// lets remove builds that are too old
+		if !b.ModTime.IsZero() && b.ModTime.Add(maxAge).Before(now) {
+			b.Reason = fmt.Sprintf("older than %s", maxAge.String())
+			expired = append(expired, b)
+			continue
+		}
+
+		c := counters.AddBuild(b.Location+"/"+b.Classifier+"/"+b.Repository+"/"+b.Branch, isPR)
+		if c > revisionHistory {
+			b.Reason = fmt.Sprintf("beyond the history limit of %d builds", revisionHistory)
+			expired = append(expired, b)
+		}
+	}
+	return expired
+}
+
+func (o *GCStorageOptions) ageAndHistoryLimits(isPR, isBatch bool) (time.Duration, int) {
+	maxAge := o.ReleaseAgeLimit
+	revisionLimit := o.ReleaseHistoryLimit
+	if isPR || isBatch {
+		maxAge = o.PullRequestAgeLimit
+		revisionLimit = o.PullRequestHistoryLimit
+	}
+	return maxAge, revisionLimit
+}
+
+func (o *GCStorageOptions) report(expired []*storedBuild) {
+	if len(expired) == 0 {
+		log.Logger().Infof("no stored builds would be deleted")
+		return
+	}
+	table := o.CreateTable()
+	table.AddRow("STORAGE", "KIND", "BUILD", "FILES", "SIZE", "REASON")
+	var size int64
+	files := 0
+	for _, b := range expired {
+		table.AddRow(b.Location, b.Classifier, b.Name(), strconv.Itoa(len(b.Paths)), strconv.FormatInt(b.Size, 10), b.Reason)
+		size += b.Size
+		files += len(b.Paths)
+	}
+	table.Render()
+	log.Logger().Infof("dry run: would delete %d files of %d builds totalling %d bytes", files, len(expired), size)
+}
+
+// groupStoredBuilds groups the artifacts into builds using the layout of the stored files:
+//
+//	jenkins-x/logs/<owner>/<repository>/<branch>/<build>.log
+//	jenkins-x/<classifier>/<owner>/<repository>/<branch>/<build>/...
+//
+// Any artifacts not matching the layout are ignored
+func groupStoredBuilds(location string, artifacts []collector.Artifact) []*storedBuild {
+	buildMap := map[string]*storedBuild{}
+	var builds []*storedBuild
+	for _, a := range artifacts {
+		paths := strings.Split(strings.TrimPrefix(a.Path, "/"), "/")
+		if len(paths) < 6 || paths[0]+"/" != storageRootPath {
+			continue
+		}
+		buildName := paths[5]
+		if len(paths) == 6 {
+			buildName = strings.TrimSuffix(buildName, ".log")
+		}
+		number, err := strconv.Atoi(buildName)
+		if err != nil {
+			continue
+		}
+		key := strings.Join(paths[1:5], "/") + "/" + buildName
+		b := buildMap[key]
+		if b == nil {
+			b = &storedBuild{
+				Location:   location,
+				Classifier: paths[1],
+				Repository: paths[2] + "/" + paths[3],
+				Branch:     paths[4],
+				Number:     number,
+			}
+			buildMap[key] = b
+			builds = append(builds, b)
+		}
+		b.Paths = append(b.Paths, a.Path)
+		b.Size += a.Size
+		if a.ModTime.After(b.ModTime) {
+			b.ModTime = a.ModTime
+		}
+	}
+	return builds
+}
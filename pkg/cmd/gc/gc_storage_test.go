@@ -0,0 +1,124 @@
+// +build unit
+
+package gc
+
+import (
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/v2/pkg/cmd/clients/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/testhelpers"
+	"github.com/jenkins-x/jx/v2/pkg/collector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeCollector keeps the artifacts in memory
+type fakeCollector struct {
+	artifacts map[string]collector.Artifact
+}
+
+func (c *fakeCollector) CollectFiles(patterns []string, outputPath string, basedir string) ([]string, error) {
+	return nil, nil
+}
+
+func (c *fakeCollector) CollectData(data []byte, outputPath string) (string, error) {
+	return "", nil
+}
+
+func (c *fakeCollector) ListArtifacts(prefix string) ([]collector.Artifact, error) {
+	var answer []collector.Artifact
+	for p, a := range c.artifacts {
+		if strings.HasPrefix(p, prefix) {
+			answer = append(answer, a)
+		}
+	}
+	return answer, nil
+}
+
+func (c *fakeCollector) DeleteArtifacts(paths []string) error {
+	for _, p := range paths {
+		delete(c.artifacts, p)
+	}
+	return nil
+}
+
+func (c *fakeCollector) add(path string, modTime time.Time) {
+	c.artifacts[path] = collector.Artifact{Path: path, Size: 10, ModTime: modTime}
+}
+
+func (c *fakeCollector) paths() []string {
+	var answer []string
+	for p := range c.artifacts {
+		answer = append(answer, p)
+	}
+	sort.Strings(answer)
+	return answer
+}
+
+func TestGCStorage(t *testing.T) {
+	t.Parallel()
+
+	commonOpts := opts.NewCommonOptionsWithFactory(fake.NewFakeFactory())
+	options := &commonOpts
+	testhelpers.ConfigureTestOptions(options, options.Git(), options.Helm())
+
+	now := time.Now()
+	coll := &fakeCollector{artifacts: map[string]collector.Artifact{}}
+	// the releases of master are within the age limit but beyond the history limit
+	for _, build := range []string{"1", "2", "3", "10"} {
+		coll.add("jenkins-x/logs/jstrachan/demo/master/"+build+".log", now.Add(-time.Hour))
+	}
+	// attachments and reports are grouped by their build directory
+	coll.add("jenkins-x/tests/jstrachan/demo/master/1/junit.xml", now.Add(-time.Hour))
+	coll.add("jenkins-x/tests/jstrachan/demo/master/1/coverage/index.html", now.Add(-time.Hour))
+	coll.add("jenkins-x/tests/jstrachan/demo/master/2/junit.xml", now.Add(-time.Hour))
+	// pull requests beyond the age limit
+	coll.add("jenkins-x/logs/jstrachan/demo/PR-1/1.log", now.Add(-time.Hour*72))
+	coll.add("jenkins-x/logs/jstrachan/demo/PR-1/2.log", now.Add(-time.Hour))
+	coll.add("jenkins-x/logs/jstrachan/demo/batch/1.log", now.Add(-time.Hour*72))
+	// builds with an unknown modification time only use the history limit
+	coll.add("jenkins-x/logs/jstrachan/other/PR-2/1.log", time.Time{})
+	// files not matching the storage layout are ignored
+	coll.add("jenkins-x/logs/jstrachan/demo/master/latest.log", now.Add(-time.Hour*24*365))
+	coll.add("jenkins-x/README.md", now.Add(-time.Hour*24*365))
+
+	o := &GCStorageOptions{
+		CommonOptions:           options,
+		DryRun:                  true,
+		ReleaseHistoryLimit:     2,
+		PullRequestHistoryLimit: 2,
+		ReleaseAgeLimit:         time.Hour * 24 * 30,
+		PullRequestAgeLimit:     time.Hour * 48,
+	}
+
+	expired, err := o.gcCollector(coll, "gs://my-logs", now)
+	require.NoError(t, err)
+	names := []string{}
+	for _, b := range expired {
+		names = append(names, b.Classifier+"/"+b.Name())
+	}
+	assert.Equal(t, []string{
+		"logs/jstrachan/demo/PR-1/1",
+		"logs/jstrachan/demo/batch/1",
+		"logs/jstrachan/demo/master/2",
+		"logs/jstrachan/demo/master/1",
+	}, names)
+	assert.Len(t, coll.artifacts, 13, "no artifacts should be deleted in dry run mode")
+
+	o.DryRun = false
+	o.ReleaseHistoryLimit = 1
+	_, err = o.gcCollector(coll, "gs://my-logs", now)
+	require.NoError(t, err)
+	assert.Equal(t, []string{
+		"jenkins-x/README.md",
+		"jenkins-x/logs/jstrachan/demo/PR-1/2.log",
+		"jenkins-x/logs/jstrachan/demo/master/10.log",
+		"jenkins-x/logs/jstrachan/demo/master/latest.log",
+		"jenkins-x/logs/jstrachan/other/PR-2/1.log",
+		"jenkins-x/tests/jstrachan/demo/master/2/junit.xml",
+	}, coll.paths())
+}
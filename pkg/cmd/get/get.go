@@ -92,6 +92,8 @@ func NewCmdGet(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(NewCmdGetIssues(commonOpts))
 	cmd.AddCommand(NewCmdGetLimits(commonOpts))
 	cmd.AddCommand(NewCmdGetLang(commonOpts))
+	cmd.AddCommand(NewCmdGetLocks(commonOpts))
+	cmd.AddCommand(NewCmdGetMetrics(commonOpts))
 	cmd.AddCommand(NewCmdGetPipeline(commonOpts))
 	cmd.AddCommand(NewCmdGetPostPreviewJob(commonOpts))
 	cmd.AddCommand(NewCmdGetPreview(commonOpts))
@@ -34,6 +34,7 @@ type GetBuildLogsOptions struct {
 	WaitForPipelineDuration time.Duration
 	TektonLogger            *logs.TektonLogger
 	FailIfPodFails          bool
+	Stage                   string
 }
 
 // CLILogWriter is an implementation of logs.LogWriter that will show logs in the standard output
@@ -65,6 +66,9 @@ var (
 
 		# View the build logs for a specific tekton build pod
 		jx get build log --pod my-pod-name
+
+		# View the build logs of a single stage, such as one combination of a matrix stage
+		jx get build log --repo cheese --stage "Test 11 linux"
 	`)
 )
 
@@ -103,6 +107,7 @@ func NewCmdGetBuildLogs(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVarP(&options.BuildFilter.GitURL, "giturl", "g", "", "The git URL to filter on. If you specify a link to a github repository or PR we can filter the query of build pods accordingly")
 	cmd.Flags().StringVarP(&options.BuildFilter.Context, "context", "", "", "Filters the context of the build")
 	cmd.Flags().BoolVarP(&options.CurrentFolder, "current", "c", false, "Display logs using current folder as repo name, and parent folder as owner")
+	cmd.Flags().StringVarP(&options.Stage, "stage", "", "", "Only display the logs of the pipeline stage with the given name while its build pod is still available")
 	options.AddBaseFlags(cmd)
 
 	return cmd
@@ -155,6 +160,14 @@ func (o *GetBuildLogsOptions) getProwBuildLog(kubeClient kubernetes.Interface, t
 	var err error
 
 	if o.TektonLogger == nil {
+		teamSettings, err := o.TeamSettings()
+		if err != nil {
+			log.Logger().Warnf("Failed to load the team settings to mask the logs: %s", err.Error())
+		}
+		masker, err := o.CreateLogMasker(kubeClient, ns, teamSettings)
+		if err != nil {
+			log.Logger().Warnf("Failed to create LogMasker in namespace %s: %s", ns, err.Error())
+		}
 		o.TektonLogger = &logs.TektonLogger{
 			KubeClient:   kubeClient,
 			TektonClient: tektonClient,
@@ -164,6 +177,8 @@ func (o *GetBuildLogsOptions) getProwBuildLog(kubeClient kubernetes.Interface, t
 				CommonOptions: o.CommonOptions,
 			},
 			FailIfPodFails: o.FailIfPodFails,
+			Stage:          o.Stage,
+			LogMasker:      masker,
 		}
 	}
 	var waitableCondition bool
@@ -1,7 +1,6 @@
 package get
 
 import (
-	"github.com/jenkins-x/jx/v2/pkg/cmd/create"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
 	"github.com/spf13/cobra"
 
@@ -29,6 +28,8 @@ var (
 	getCVELong = templates.LongDesc(`
 		Display Common Vulnerabilities and Exposures (CVEs)
 
+		The CVEs are found using the scanner configured in the team settings which may be anchore, trivy or clair. Defaults to the anchore addon
+
 `)
 
 	getCVEExample = templates.Examples(`
@@ -89,26 +90,21 @@ func (o *GetCVEOptions) Run() error {
 		return fmt.Errorf("cannot create jx client: %v", err)
 	}
 
-	externalURL, err := o.EnsureAddonServiceAvailable(kube.AddonServices[create.DefaultAnchoreName])
-	if err != nil {
-		log.Logger().Warnf("no CVE provider service found, are you in your teams dev environment?  Type `jx env` to switch.")
-		return fmt.Errorf("if no CVE provider running, try running `jx create addon anchore` in your teams dev environment: %v", err)
-	}
-
 	// if no flags are set try and guess the image name from the current directory
 	if o.ImageID == "" && o.ImageName == "" && o.Env == "" {
 		return fmt.Errorf("no --image-name, --image-id or --environment flags set\n")
 	}
 
-	server, auth, err := o.GetAddonAuthByKind(kube.ValueKindCVE, externalURL)
+	settings, err := o.CVEScannerSettings()
 	if err != nil {
-		return fmt.Errorf("error getting anchore engine auth details, %v", err)
+		return err
 	}
-
-	p, err := cve.NewAnchoreProvider(server, auth)
+	p, err := o.CreateCVEProvider(settings)
 	if err != nil {
-		return fmt.Errorf("error creating anchore provider, %v", err)
+		log.Logger().Warnf("no CVE provider service found, are you in your teams dev environment?  Type `jx env` to switch.")
+		return fmt.Errorf("error creating %s CVE provider, %v", settings.Kind, err)
 	}
+
 	table := o.CreateTable()
 	table.AddRow("Image", util.ColorInfo("Severity"), "Vulnerability", "URL", "Package", "Fix")
 
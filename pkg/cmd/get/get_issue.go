@@ -160,7 +160,7 @@ func (o *GetIssueOptions) findRelease(tracker issues.IssueProvider, issue *gits.
 
 func (o *GetIssueOptions) parseIssueIDs(issue v1.IssueSummary, issueKind string) []string {
 	regex := regexp.MustCompile(`(\#\d+)`)
-	if issueKind == issues.Jira {
+	if issueKind == issues.Jira || issueKind == issues.Linear {
 		regex = regexp.MustCompile(`[A-Z][A-Z]+-(\d+)`)
 	}
 	issues := []string{}
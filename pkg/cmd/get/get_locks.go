@@ -0,0 +1,87 @@
+package get
+
+import (
+	"strconv"
+
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/spf13/cobra"
+)
+
+// GetLocksOptions containers the CLI options
+type GetLocksOptions struct {
+	GetOptions
+}
+
+var (
+	getLocksLong = templates.LongDesc(`
+		Display the build locks serialising the deployments into the namespaces, with the build holding each lock
+		and the builds waiting for it in the order they will run.
+`)
+
+	getLocksExample = templates.Examples(`
+		# List the build locks
+		jx get locks
+
+		# List the build locks as YAML
+		jx get locks -o yaml
+	`)
+)
+
+// NewCmdGetLocks creates the new command for: jx get locks
+func NewCmdGetLocks(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GetLocksOptions{
+		GetOptions: GetOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "locks",
+		Short:   "Display the build locks with the builds holding and waiting for them",
+		Aliases: []string{"lock"},
+		Long:    getLocksLong,
+		Example: getLocksExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	options.AddGetFlags(cmd)
+	return cmd
+}
+
+// Run implements this command
+func (o *GetLocksOptions) Run() error {
+	kubeClient, devNs, err := o.KubeClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	locks, err := kube.GetBuildLocks(kubeClient, devNs)
+	if err != nil {
+		return err
+	}
+	if o.Output != "" {
+		return o.renderResult(locks, o.Output)
+	}
+	if len(locks) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+
+	table := o.CreateTable()
+	table.AddRow("NAMESPACE", "POSITION", "OWNER", "REPOSITORY", "BRANCH", "BUILD", "POD", "SINCE", "EXPIRES")
+	for _, lock := range locks {
+		table.AddRow(lock.Namespace, util.ColorInfo("holder"), lock.Holder.Owner, lock.Holder.Repository,
+			lock.Holder.Branch, lock.Holder.Build, lock.Holder.Pod, lock.Holder.Timestamp, lock.Expires)
+		for i, w := range lock.Queue {
+			table.AddRow(lock.Namespace, strconv.Itoa(i+1), w.Owner, w.Repository, w.Branch, w.Build, w.Pod,
+				w.Timestamp, "")
+		}
+	}
+	table.Render()
+	return nil
+}
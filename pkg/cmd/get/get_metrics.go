@@ -0,0 +1,39 @@
+package get
+
+import (
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/spf13/cobra"
+)
+
+// GetMetricsOptions containers the CLI options
+type GetMetricsOptions struct {
+	GetOptions
+}
+
+// NewCmdGetMetrics creates the new command for: jx get metrics
+func NewCmdGetMetrics(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GetMetricsOptions{
+		GetOptions: GetOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "metrics <kind>",
+		Short:   "Display metrics about the pipelines and deployments of the team",
+		Aliases: []string{"metric"},
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdGetMetricsDORA(commonOpts))
+	return cmd
+}
+
+// Run implements this command
+func (o *GetMetricsOptions) Run() error {
+	return o.Cmd.Help()
+}
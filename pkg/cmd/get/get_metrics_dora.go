@@ -0,0 +1,217 @@
+package get
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/reports"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// GetMetricsDORAOptions containers the CLI options
+type GetMetricsDORAOptions struct {
+	GetOptions
+
+	Days        int
+	Filter      string
+	Environment string
+	Namespace   string
+	OutDir      string
+	Now         time.Time
+}
+
+var (
+	getMetricsDORALong = templates.LongDesc(`
+		Display the four DORA metrics of each application in each environment over a number of days, calculated from the
+		promotions of PipelineActivities and the commits of Releases:
+
+		* deployment frequency: the number of successful deployments per day
+		* lead time for changes: the median time from a commit to its deployment
+		* change failure rate: the percentage of deployments which failed
+		* time to restore: the median time from a failed deployment to the next successful one
+`)
+
+	getMetricsDORAExample = templates.Examples(`
+		# Display the DORA metrics of the last 30 days
+		jx get metrics dora
+
+		# Display the DORA metrics of an application in production over the last week as JSON
+		jx get metrics dora -f myapp -e production --days 7 -o json
+
+		# Generate charts of the DORA metrics for a blog
+		jx get metrics dora -o html --out-dir ./blog/static/news/dora
+	`)
+)
+
+// NewCmdGetMetricsDORA creates the new command for: jx get metrics dora
+func NewCmdGetMetricsDORA(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &GetMetricsDORAOptions{
+		GetOptions: GetOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+	cmd := &cobra.Command{
+		Use:     "dora",
+		Short:   "Display the DORA metrics of the applications of the team",
+		Long:    getMetricsDORALong,
+		Example: getMetricsDORAExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().IntVarP(&options.Days, "days", "d", 30, "The number of days to calculate the metrics over")
+	cmd.Flags().StringVarP(&options.Filter, "filter", "f", "", "Filter the applications with the given text")
+	cmd.Flags().StringVarP(&options.Environment, "env", "e", "", "Only display the metrics of the given environment")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace of the PipelineActivities or defaults to the team namespace")
+	cmd.Flags().StringVarP(&options.OutDir, "out-dir", "", ".", "The directory the HTML and JavaScript of the charts are generated in when the output is html")
+	cmd.Flags().StringVarP(&options.Output, "output", "o", "", "The output format: table, json, yaml or html")
+	return cmd
+}
+
+// Run implements this command
+func (o *GetMetricsDORAOptions) Run() error {
+	if o.Days <= 0 {
+		return util.InvalidOptionf("days", o.Days, "must be greater than zero")
+	}
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return err
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	activityList, err := jxClient.JenkinsV1().PipelineActivities(ns).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "listing the PipelineActivities in namespace %s", ns)
+	}
+
+	// releases are created in the team namespace and copied into the namespace of each environment
+	namespaces := []string{ns}
+	envList, err := jxClient.JenkinsV1().Environments(devNs).List(metav1.ListOptions{})
+	if err != nil {
+		return errors.Wrapf(err, "listing the Environments in namespace %s", devNs)
+	}
+	for _, env := range envList.Items {
+		if env.Spec.Namespace != "" && util.StringArrayIndex(namespaces, env.Spec.Namespace) < 0 {
+			namespaces = append(namespaces, env.Spec.Namespace)
+		}
+	}
+	var releases []v1.Release
+	for _, releaseNs := range namespaces {
+		releaseList, err := jxClient.JenkinsV1().Releases(releaseNs).List(metav1.ListOptions{})
+		if err != nil {
+			log.Logger().Warnf("Failed to list the Releases in namespace %s: %s", releaseNs, err)
+			continue
+		}
+		releases = append(releases, releaseList.Items...)
+	}
+
+	to := o.Now
+	if to.IsZero() {
+		to = time.Now()
+	}
+	from := to.Add(-time.Duration(o.Days) * 24 * time.Hour)
+	var metrics []*reports.DORAMetrics
+	for _, m := range reports.CalculateDORAMetrics(activityList.Items, releases, from, to) {
+		if o.Filter != "" && !strings.Contains(m.Application, o.Filter) {
+			continue
+		}
+		if o.Environment != "" && m.Environment != o.Environment {
+			continue
+		}
+		metrics = append(metrics, m)
+	}
+
+	switch o.Output {
+	case "", "table":
+		return o.renderTable(metrics)
+	case "html":
+		return o.renderHTML(metrics)
+	default:
+		if metrics == nil {
+			metrics = []*reports.DORAMetrics{}
+		}
+		return o.renderResult(metrics, o.Output)
+	}
+}
+
+func (o *GetMetricsDORAOptions) renderTable(metrics []*reports.DORAMetrics) error {
+	if len(metrics) == 0 {
+		return outputEmptyListWarning(o.Out)
+	}
+	table := o.CreateTable()
+	table.AddRow("APPLICATION", "ENVIRONMENT", "DEPLOYMENTS", "PER DAY", "LEAD TIME", "FAILURE RATE", "TIME TO RESTORE")
+	for _, m := range metrics {
+		table.AddRow(m.Application, m.Environment, strconv.Itoa(m.Deployments), formatFloat(m.DeploymentFrequency),
+			formatDuration(m.LeadTime), formatFloat(m.ChangeFailureRate*100)+"%", formatDuration(m.TimeToRestore))
+	}
+	table.Render()
+	return nil
+}
+
+// renderHTML generates a bar chart of each metric in the format of the charts of jx step blog
+func (o *GetMetricsDORAOptions) renderHTML(metrics []*reports.DORAMetrics) error {
+	err := os.MkdirAll(o.OutDir, util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "creating directory %s", o.OutDir)
+	}
+	htmlFile := filepath.Join(o.OutDir, "dora.html")
+	f, err := os.Create(htmlFile)
+	if err != nil {
+		return errors.Wrapf(err, "creating %s", htmlFile)
+	}
+	defer f.Close()
+
+	charts := []struct {
+		name  string
+		value func(m *reports.DORAMetrics) float64
+	}{
+		{"deploymentsPerDay", func(m *reports.DORAMetrics) float64 { return m.DeploymentFrequency }},
+		{"leadTimeHours", func(m *reports.DORAMetrics) float64 { return m.LeadTime.Hours() }},
+		{"changeFailurePercent", func(m *reports.DORAMetrics) float64 { return m.ChangeFailureRate * 100 }},
+		{"timeToRestoreHours", func(m *reports.DORAMetrics) float64 { return m.TimeToRestore.Hours() }},
+	}
+	for _, c := range charts {
+		_, err = fmt.Fprintf(f, "\n<h3>%s</h3>\n", c.name)
+		if err != nil {
+			return err
+		}
+		report := reports.NewBlogBarReport(c.name, f, filepath.Join(o.OutDir, c.name+".js"), c.name+".js")
+		for _, m := range metrics {
+			report.AddText(m.Application+" "+m.Environment, formatFloat(c.value(m)))
+		}
+		err = report.Render()
+		if err != nil {
+			return errors.Wrapf(err, "rendering the %s chart", c.name)
+		}
+	}
+	log.Logger().Infof("Generated HTML %s", util.ColorInfo(htmlFile))
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', 2, 64)
+}
+
+func formatDuration(d time.Duration) string {
+	if d == 0 {
+		return ""
+	}
+	return d.Round(time.Minute).String()
+}
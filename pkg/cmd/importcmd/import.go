@@ -12,7 +12,6 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step/create/pr"
 	"github.com/jenkins-x/jx/v2/pkg/maven"
 
-	"github.com/cenkalti/backoff"
 	"github.com/denormal/go-gitignore"
 	gojenkins "github.com/jenkins-x/golang-jenkins"
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
@@ -716,18 +715,10 @@ func (options *ImportOptions) CreateNewRemoteRepository() error {
 
 		// If the user creating the repo is not the pipeline user, add the pipeline user as a contributor to the repo
 		if options.PipelineUserName != options.GitUserAuth.Username && options.GitServer != nil && options.GitServer.URL == options.PipelineServer {
-			// Make the invitation
-			err := options.GitProvider.AddCollaborator(options.PipelineUserName, details.Organisation, details.RepoName)
-			if err != nil {
-				return err
-			}
-
 			// If repo is put in an organisation that the pipeline user is not part of an invitation needs to be accepted.
 			// Create a new provider for the pipeline user
+			var pipelineUserProvider gits.GitProvider
 			authConfig := authConfigSvc.Config()
-			if err != nil {
-				return err
-			}
 			pipelineUserAuth := authConfig.FindUserAuth(options.GitServer.URL, options.PipelineUserName)
 			if pipelineUserAuth == nil {
 				log.Logger().Warnf("Pipeline Git user credentials not found. %s will need to accept the invitation to collaborate"+
@@ -735,37 +726,15 @@ func (options *ImportOptions) CreateNewRemoteRepository() error {
 					options.PipelineUserName, details.RepoName, options.PipelineUserName, details.Organisation)
 			} else {
 				pipelineServerAuth := authConfig.GetServer(authConfig.CurrentServer)
-				pipelineUserProvider, err := gits.CreateProvider(pipelineServerAuth, pipelineUserAuth, options.Git())
-				if err != nil {
-					return err
-				}
-
-				// Get all invitations for the pipeline user
-				// Wrapped in retry to not immediately fail the quickstart creation if APIs are flaky.
-				f := func() error {
-					invites, _, err := pipelineUserProvider.ListInvitations()
-					if err != nil {
-						return err
-					}
-					for _, x := range invites {
-						// Accept all invitations for the pipeline user
-						_, err = pipelineUserProvider.AcceptInvitation(*x.ID)
-						if err != nil {
-							return err
-						}
-					}
-					return nil
-				}
-				exponentialBackOff := backoff.NewExponentialBackOff()
-				timeout := 20 * time.Second
-				exponentialBackOff.MaxElapsedTime = timeout
-				exponentialBackOff.Reset()
-				err = backoff.Retry(f, exponentialBackOff)
+				pipelineUserProvider, err = gits.CreateProvider(pipelineServerAuth, pipelineUserAuth, options.Git())
 				if err != nil {
 					return err
 				}
 			}
-
+			err = gits.AddCollaboratorAndAcceptInvitations(options.GitProvider, pipelineUserProvider, options.PipelineUserName, details.Organisation, details.RepoName)
+			if err != nil {
+				return err
+			}
 		}
 	}
 
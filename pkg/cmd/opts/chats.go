@@ -0,0 +1,35 @@
+package opts
+
+import (
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/chats"
+	"github.com/pkg/errors"
+)
+
+// CreateChatNotifier creates a notifier for the given team chat settings posting to the given channel.
+// Returns nil if there are no chat settings or no channel configured
+func (o *CommonOptions) CreateChatNotifier(settings *v1.ChatSettings, channel string) (*chats.Notifier, error) {
+	if settings == nil || settings.URL == "" || channel == "" {
+		return nil, nil
+	}
+	u := settings.URL
+	authConfigSvc, err := o.CreateChatAuthConfigService(settings.Kind)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating the chat auth configuration service")
+	}
+	config := authConfigSvc.Config()
+	server := config.GetOrCreateServer(u)
+	if server.Kind == "" {
+		server.Kind = settings.Kind
+	}
+	// notifications are sent from pipelines and controllers so never prompt for a user
+	userAuth, err := config.PickServerUserAuth(server, "user to access the chat service at "+u, true, "", o.GetIOFileHandles())
+	if err != nil {
+		return nil, err
+	}
+	provider, err := chats.CreateChatProvider(server.Kind, server, userAuth, true)
+	if err != nil {
+		return nil, err
+	}
+	return chats.NewNotifier(provider, channel), nil
+}
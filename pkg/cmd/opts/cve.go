@@ -0,0 +1,59 @@
+package opts
+
+import (
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/pkg/errors"
+)
+
+// CVEScannerSettings returns the team settings of the CVE scanner, defaulting to the anchore addon
+func (o *CommonOptions) CVEScannerSettings() (*v1.CVEScannerSettings, error) {
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return nil, errors.Wrap(err, "getting the team settings")
+	}
+	settings := &v1.CVEScannerSettings{}
+	if teamSettings.CVEScanner != nil {
+		settings = teamSettings.CVEScanner.DeepCopy()
+	}
+	if settings.Kind == "" {
+		settings.Kind = cve.ProviderKindAnchore
+	}
+	if settings.Severity == "" {
+		settings.Severity = cve.SeverityHigh
+	}
+	return settings, nil
+}
+
+// CreateCVEProvider creates the CVE provider for the scanner settings using the credentials of the CVE addon for its
+// URL. The anchore addon service in the current namespace is used if the settings have no URL
+func (o *CommonOptions) CreateCVEProvider(settings *v1.CVEScannerSettings) (cve.CVEProvider, error) {
+	url := settings.URL
+	if url == "" {
+		if settings.Kind != cve.ProviderKindAnchore {
+			return nil, errors.Errorf("no URL configured in the team settings for the %s CVE scanner", settings.Kind)
+		}
+		var err error
+		url, err = o.EnsureAddonServiceAvailable(kube.AddonServices[cve.ProviderKindAnchore])
+		if err != nil {
+			return nil, errors.Wrap(err, "if no CVE provider running, try running `jx create addon anchore` in your teams dev environment")
+		}
+		if url == "" {
+			return nil, errors.New("no CVE provider service found, try running `jx create addon anchore` in your teams dev environment")
+		}
+	}
+
+	authConfigSvc, err := o.AddonAuthConfigService(kube.ValueKindCVE)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating the CVE auth configuration service")
+	}
+	config := authConfigSvc.Config()
+	server := config.GetOrCreateServer(url)
+	message := "user to access the " + settings.Kind + " CVE scanner at " + url
+	userAuth, err := config.PickServerUserAuth(server, message, true, "", o.GetIOFileHandles())
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting the user of the CVE scanner at %s", url)
+	}
+	return cve.NewCVEProvider(settings.Kind, server, userAuth)
+}
@@ -14,7 +14,9 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/versionstream"
 
 	"github.com/jenkins-x/jx/v2/pkg/secreturl"
+	"github.com/jenkins-x/jx/v2/pkg/secreturl/kubevault"
 	"github.com/jenkins-x/jx/v2/pkg/secreturl/localvault"
+	"github.com/jenkins-x/jx/v2/pkg/secreturl/sopsvault"
 	"github.com/pborman/uuid"
 
 	"github.com/jenkins-x/jx/v2/pkg/environments"
@@ -427,6 +429,18 @@ func (o *CommonOptions) GetSecretURLClient(location secrets.SecretsLocationKind)
 			return o.secretURLClient, errors.Wrapf(err, "getting the file system secrets directory")
 		}
 		o.secretURLClient = localvault.NewFileSystemClient(dir)
+	case secrets.KubeLocationKind:
+		kubeClient, ns, err := o.KubeClientAndDevNamespace()
+		if err != nil {
+			return o.secretURLClient, errors.Wrapf(err, "creating the kubernetes client")
+		}
+		o.secretURLClient = kubevault.NewClient(kubeClient, ns)
+	case secrets.SopsLocationKind:
+		dir, err := util.SopsSecretsDir()
+		if err != nil {
+			return o.secretURLClient, errors.Wrapf(err, "getting the sops secrets directory")
+		}
+		o.secretURLClient = sopsvault.NewFileSystemClient(dir)
 	case secrets.AutoLocationKind:
 		location := o.detectSecretsLocation()
 		o.secretURLClient, err = o.GetSecretURLClient(location)
@@ -437,8 +451,13 @@ func (o *CommonOptions) GetSecretURLClient(location secrets.SecretsLocationKind)
 	return o.secretURLClient, err
 }
 
-// detectSecretsLocation detects dynamically the secrets location by trying to create a vault client
+// detectSecretsLocation detects dynamically the secrets location using the location configured in the install
+// ConfigMap if any, otherwise by trying to create a vault client
 func (o *CommonOptions) detectSecretsLocation() secrets.SecretsLocationKind {
+	switch location := o.GetSecretsLocation(); location {
+	case secrets.VaultLocationKind, secrets.KubeLocationKind, secrets.SopsLocationKind:
+		return location
+	}
 	_, err := o.SystemVaultClient(o.devNamespace)
 	if err == nil {
 		return secrets.VaultLocationKind
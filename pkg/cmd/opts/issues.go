@@ -9,6 +9,7 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/config"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
 	"github.com/jenkins-x/jx/v2/pkg/issues"
+	"github.com/jenkins-x/jx/v2/pkg/log"
 )
 
 // CreateIssueTrackerAuthConfigService creates auth config service for issue tracker
@@ -70,5 +71,20 @@ func (o *CommonOptions) CreateIssueProvider(dir string) (issues.IssueProvider, e
 	if err != nil {
 		return nil, err
 	}
+	if bitbucketServer, ok := gitProvider.(*gits.BitbucketServerProvider); ok {
+		// Bitbucket Server has no issues of its own so lets use the Jira server linked to it
+		var authConfig *auth.AuthConfig
+		authConfigSvc, err := o.CreateIssueTrackerAuthConfigService(issues.Jira)
+		if err != nil {
+			log.Logger().Debugf("failed to load the issue tracker auth config: %s", err)
+		} else {
+			authConfig = authConfigSvc.Config()
+		}
+		issueProvider, err := issues.CreateBitbucketServerIssueProvider(bitbucketServer, authConfig, gitInfo.Organisation, o.BatchMode, o.Git())
+		if err == nil {
+			return issueProvider, nil
+		}
+		log.Logger().Warnf("Could not use the Jira server linked to %s: %s", gitProvider.ServerURL(), err)
+	}
 	return issues.CreateGitIssueProvider(gitProvider, gitInfo.Organisation, gitInfo.Name)
 }
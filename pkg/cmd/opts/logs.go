@@ -1,11 +1,41 @@
 package opts
 
 import (
+	"strings"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/io/secrets"
 	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/secreturl"
 	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
 )
 
 // TailLogs returns the logs from a given pod
 func (o *CommonOptions) TailLogs(ns string, pod string, containerName string) error {
 	return errors.WithStack(kube.TailLogs(ns, pod, containerName, o.Err, o.Out))
 }
+
+// CreateLogMasker creates a LogMasker for the Secrets in the namespace and the well-known secret formats, applying the
+// log masking settings of the team if any, whose secrets are read from Vault or the local secret store. If the secret
+// store cannot be reached the logs are masked without the values of its secrets
+func (o *CommonOptions) CreateLogMasker(kubeClient kubernetes.Interface, ns string, teamSettings *v1.TeamSettings) (*kube.LogMasker, error) {
+	var settings *v1.LogMaskingSettings
+	if teamSettings != nil {
+		settings = teamSettings.LogMasking
+	}
+	var secretURLClient secreturl.Client
+	if settings != nil && len(settings.Secrets) > 0 {
+		var err error
+		secretURLClient, err = o.GetSecretURLClient(secrets.AutoLocationKind)
+		if err != nil {
+			log.Logger().Warnf("Not masking the values of the secrets %s in the logs as the secret URL client could not be created: %s",
+				strings.Join(settings.Secrets, ", "), err)
+			withoutSecrets := *settings
+			withoutSecrets.Secrets = nil
+			settings = &withoutSecrets
+		}
+	}
+	return kube.NewTeamLogMasker(kubeClient, ns, settings, secretURLClient)
+}
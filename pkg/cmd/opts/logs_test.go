@@ -0,0 +1,56 @@
+// +build unit
+
+package opts
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestCreateLogMaskerWithoutSecretURLClient(t *testing.T) {
+	// a home directory which cannot be created so that no secret URL client can be created
+	file, err := ioutil.TempFile("", "test-log-masker-")
+	require.NoError(t, err)
+	file.Close()
+	defer os.Remove(file.Name())
+	originalHome := os.Getenv("HOME")
+	originalJxHome := os.Getenv("JX_HOME")
+	defer func() {
+		os.Setenv("HOME", originalHome)
+		os.Setenv("JX_HOME", originalJxHome)
+	}()
+	os.Setenv("HOME", file.Name())
+	os.Unsetenv("JX_HOME")
+
+	o := NewCommonOptionsWithFactory(nil)
+	_, err = o.GetSecretURLClient("auto")
+	require.Error(t, err)
+
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "nexus",
+			Namespace: "jx",
+		},
+		Data: map[string][]byte{
+			"password": []byte("nexuspwd"),
+		},
+	})
+	teamSettings := &v1.TeamSettings{
+		LogMasking: &v1.LogMaskingSettings{
+			Patterns: []string{`sonar_[0-9a-f]{8}`},
+			Secrets:  []string{"slack"},
+		},
+	}
+	masker, err := o.CreateLogMasker(kubeClient, "jx", teamSettings)
+	require.NoError(t, err)
+	assert.Equal(t, "******** slacktoken **************", masker.MaskLog("nexuspwd slacktoken sonar_0a1b2c3d"))
+	assert.Equal(t, []string{"slack"}, teamSettings.LogMasking.Secrets, "the team settings should not be modified")
+}
@@ -0,0 +1,40 @@
+package opts
+
+import (
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	pipelineevents "github.com/jenkins-x/jx/v2/pkg/pipeline_events"
+	"github.com/pkg/errors"
+)
+
+// CreatePipelineEventsProvider creates the provider for the team pipeline events settings, using any credentials of
+// the pipeline events addon for its URLs. Returns nil if there are no settings or no URL configured
+func (o *CommonOptions) CreatePipelineEventsProvider(settings *v1.PipelineEventsSettings) (pipelineevents.PipelineEventsProvider, error) {
+	if settings == nil || settings.URL == "" {
+		return nil, nil
+	}
+	authConfigSvc, err := o.AddonAuthConfigService(kube.ValueKindPipelineEvent)
+	if err != nil {
+		return nil, errors.Wrap(err, "creating the pipeline events auth configuration service")
+	}
+	config := authConfigSvc.Config()
+	userAuth, err := o.pipelineEventsUserAuth(config, settings.URL)
+	if err != nil {
+		return nil, err
+	}
+	var deadLetterAuth *auth.UserAuth
+	if settings.DeadLetterURL != "" {
+		deadLetterAuth, err = o.pipelineEventsUserAuth(config, settings.DeadLetterURL)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pipelineevents.CreatePipelineEventsProvider(settings, userAuth, deadLetterAuth)
+}
+
+func (o *CommonOptions) pipelineEventsUserAuth(config *auth.AuthConfig, u string) (*auth.UserAuth, error) {
+	server := config.GetOrCreateServer(u)
+	// events are sent from controllers so never prompt for a user
+	return config.PickServerUserAuth(server, "user to send pipeline events to "+u, true, "", o.GetIOFileHandles())
+}
@@ -27,12 +27,15 @@ import (
 	typev1 "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/typed/jenkins.io/v1"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
 	"github.com/jenkins-x/jx/v2/pkg/helm"
+	"github.com/jenkins-x/jx/v2/pkg/issues"
 	"github.com/jenkins-x/jx/v2/pkg/kube"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -70,6 +73,7 @@ type PromoteOptions struct {
 	PullRequestPollTime     string
 	Filter                  string
 	Alias                   string
+	IgnoreVulnerabilities   bool
 
 	// calculated fields
 	TimeoutDuration         *time.Duration
@@ -81,7 +85,9 @@ type PromoteOptions struct {
 	prow                    bool
 
 	// Used for testing
-	CloneDir string
+	CloneDir      string
+	CVEProvider   cve.CVEProvider
+	IssueProvider issues.IssueProvider
 }
 
 type ReleaseInfo struct {
@@ -163,6 +169,7 @@ func (o *PromoteOptions) AddPromoteOptions(cmd *cobra.Command) {
 	cmd.Flags().BoolVarP(&o.NoPoll, "no-poll", "", false, "Disables polling for Pull Request or Pipeline status")
 	cmd.Flags().BoolVarP(&o.NoWaitAfterMerge, "no-wait", "", false, "Disables waiting for completing promotion after the Pull request is merged")
 	cmd.Flags().BoolVarP(&o.IgnoreLocalFiles, "ignore-local-file", "", false, "Ignores the local file system when deducing the Git repository")
+	cmd.Flags().BoolVarP(&o.IgnoreVulnerabilities, optionIgnoreVulnerabilities, "", false, "Promotes to a protected Environment even if the images of the release have critical vulnerabilities")
 }
 
 func (o *PromoteOptions) hasApplicationFlag() bool {
@@ -338,18 +345,20 @@ func (o *PromoteOptions) Run() error {
 			return fmt.Errorf("Could not find an Environment called %s", o.Environment)
 		}
 	}
-	releaseInfo, err := o.Promote(targetNS, env, true)
-	if err != nil {
-		return err
-	}
+	return o.promoteAndWait(targetNS, env, true)
+}
 
-	o.ReleaseInfo = releaseInfo
-	if !o.NoPoll {
-		err = o.WaitForPromotion(targetNS, env, releaseInfo)
-		if err != nil {
-			return err
+// promoteAndWait promotes to the given environment, waits for the promotion to complete unless polling is disabled
+// and then notifies the team chat channel of the outcome
+func (o *PromoteOptions) promoteAndWait(targetNS string, env *v1.Environment, warnIfAuto bool) error {
+	releaseInfo, err := o.Promote(targetNS, env, warnIfAuto)
+	if err == nil {
+		o.ReleaseInfo = releaseInfo
+		if !o.NoPoll {
+			err = o.WaitForPromotion(targetNS, env, releaseInfo)
 		}
 	}
+	o.notifyChat(env, releaseInfo, err)
 	return err
 }
 
@@ -385,17 +394,10 @@ func (o *PromoteOptions) PromoteAllAutomatic() error {
 			if ns == "" {
 				return fmt.Errorf("No namespace for environment %s", env.Name)
 			}
-			releaseInfo, err := o.Promote(ns, &env, false)
+			err = o.promoteAndWait(ns, &env, false)
 			if err != nil {
 				return err
 			}
-			o.ReleaseInfo = releaseInfo
-			if !o.NoPoll {
-				err = o.WaitForPromotion(ns, &env, releaseInfo)
-				if err != nil {
-					return err
-				}
-			}
 		}
 	}
 	return nil
@@ -456,6 +458,10 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 		return releaseInfo, err
 	}
 	promoteKey := o.CreatePromoteKey(env)
+	_, err = o.CheckVulnerabilities(env, promoteKey)
+	if err != nil {
+		return releaseInfo, err
+	}
 	if env != nil {
 		source := &env.Spec.Source
 		if source.URL != "" && env.Spec.Kind.IsPermanent() {
@@ -530,7 +536,7 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 
 	err = o.InstallChartWithOptions(helmOptions)
 	if err == nil {
-		err = o.CommentOnIssues(targetNS, env, promoteKey)
+		err = o.UpdateIssues(targetNS, env, promoteKey)
 		if err != nil {
 			log.Logger().Warnf("Failed to comment on issues for release %s: %s", releaseName, err)
 		}
@@ -541,6 +547,48 @@ func (o *PromoteOptions) Promote(targetNS string, env *v1.Environment, warnIfAut
 	return releaseInfo, err
 }
 
+// notifyChat posts the outcome of the promotion to the promotion channel configured in the team settings
+func (o *PromoteOptions) notifyChat(env *v1.Environment, releaseInfo *ReleaseInfo, promoteErr error) {
+	if o.Application == "" {
+		return
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		log.Logger().Warnf("Failed to find the development namespace to notify chat: %s", err)
+		return
+	}
+	devEnv, err := kube.GetDevEnvironment(jxClient, ns)
+	if err != nil || devEnv == nil {
+		return
+	}
+	settings := devEnv.Spec.TeamSettings.ChatSettings
+	if settings == nil {
+		return
+	}
+	notifier, err := o.CreateChatNotifier(settings, settings.GetPromotionChannel())
+	if err != nil {
+		log.Logger().Warnf("Failed to create the chat notifier: %s", err)
+		return
+	}
+	if notifier == nil {
+		return
+	}
+	// the promotion may have failed before there was any release information
+	version := o.Version
+	prURL := ""
+	if releaseInfo != nil {
+		version = releaseInfo.Version
+		pr := releaseInfo.PullRequestInfo
+		if pr != nil && pr.PullRequest != nil {
+			prURL = pr.PullRequest.URL
+		}
+	}
+	_, err = notifier.Promotion(o.Application, version, env, prURL, promoteErr)
+	if err != nil {
+		log.Logger().Warnf("Failed to notify chat about the promotion of %s: %s", o.Application, err)
+	}
+}
+
 func (o *PromoteOptions) PromoteViaPullRequest(env *v1.Environment, releaseInfo *ReleaseInfo) error {
 	version := o.Version
 	versionName := version
@@ -743,7 +791,7 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 
 						if o.NoWaitForUpdatePipeline {
 							log.Logger().Info("Pull Request merged but we are not waiting for the update pipeline to complete!")
-							err = o.CommentOnIssues(ns, env, promoteKey)
+							err = o.UpdateIssues(ns, env, promoteKey)
 							if err == nil {
 								err = promoteKey.OnPromoteUpdate(kubeClient, jxClient, o.Namespace, kube.CompletePromotionUpdate)
 							}
@@ -810,7 +858,7 @@ func (o *PromoteOptions) waitForGitOpsPullRequest(ns string, env *v1.Environment
 								}
 								if succeeded {
 									log.Logger().Info("Merge status checks all passed so the promotion worked!")
-									err = o.CommentOnIssues(ns, env, promoteKey)
+									err = o.UpdateIssues(ns, env, promoteKey)
 									if err == nil {
 										err = promoteKey.OnPromoteUpdate(kubeClient, jxClient, o.Namespace, kube.CompletePromotionUpdate)
 									}
@@ -1001,6 +1049,17 @@ func (o *PromoteOptions) CreatePromoteKey(env *v1.Environment) *kube.PromoteStep
 	}
 }
 
+// UpdateIssues comments on any issues for a release that the fix is available in the given environment and updates
+// them in the issue tracker of the project
+func (o *PromoteOptions) UpdateIssues(targetNS string, environment *v1.Environment, promoteKey *kube.PromoteStepActivityKey) error {
+	err := o.CommentOnIssues(targetNS, environment, promoteKey)
+	trackerErr := o.UpdateIssueTracker(environment)
+	if trackerErr != nil {
+		log.Logger().Warnf("Failed to update the issue tracker for the issues of %s %s: %s", o.Application, o.Version, trackerErr)
+	}
+	return err
+}
+
 // CommentOnIssues comments on any issues for a release that the fix is available in the given environment
 func (o *PromoteOptions) CommentOnIssues(targetNS string, environment *v1.Environment, promoteKey *kube.PromoteStepActivityKey) error {
 	ens := environment.Spec.Namespace
@@ -1123,6 +1182,95 @@ func (o *PromoteOptions) CommentOnIssues(targetNS string, environment *v1.Enviro
 	return nil
 }
 
+// UpdateIssueTracker transitions the issues of the release promoted to the environment and sets their fix version, if
+// the issue tracker supports it
+func (o *PromoteOptions) UpdateIssueTracker(environment *v1.Environment) error {
+	ens := environment.Spec.Namespace
+	if ens == "" || o.Application == "" || o.Version == "" {
+		return nil
+	}
+	jxClient, _, err := o.JXClient()
+	if err != nil {
+		return err
+	}
+	releaseName := naming.ToValidNameWithDots(o.Application + "-" + o.Version)
+	release, err := jxClient.JenkinsV1().Releases(ens).Get(releaseName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			log.Logger().Debugf("No Release %s found in namespace %s so no issues to update", releaseName, ens)
+			return nil
+		}
+		return errors.Wrapf(err, "getting Release %s in namespace %s", releaseName, ens)
+	}
+	return o.TransitionIssues(environment, release)
+}
+
+// TransitionIssues moves the issues of the release to the status configured for the environment, if any, and sets
+// their fix version to the version of the release, if the issue tracker supports it
+func (o *PromoteOptions) TransitionIssues(environment *v1.Environment, release *v1.Release) error {
+	if len(release.Spec.Issues) == 0 {
+		return nil
+	}
+	teamSettings, err := o.TeamSettings()
+	if err != nil {
+		return err
+	}
+	status := IssueTransitionForEnvironment(environment, teamSettings)
+	version := release.Spec.Version
+	if version == "" {
+		version = o.Version
+	}
+	tracker, err := o.createIssueProvider()
+	if err != nil {
+		return err
+	}
+	transitioner, ok := tracker.(issues.IssueTransitioner)
+	if !ok {
+		if status != "" {
+			log.Logger().Warnf("The %s issue tracker does not support transitioning issues to %s", issues.GetIssueProvider(tracker), status)
+		}
+		return nil
+	}
+	for _, issue := range release.Spec.Issues {
+		if issue.ID == "" {
+			continue
+		}
+		if status != "" {
+			log.Logger().Infof("Transitioning issue %s to %s", util.ColorInfo(issue.ID), util.ColorInfo(status))
+			err = transitioner.TransitionIssue(issue.ID, status)
+			if err != nil {
+				log.Logger().Warnf("Failed to transition issue %s: %s", issue.ID, err)
+			}
+		}
+		if version != "" {
+			err = transitioner.SetFixVersion(issue.ID, version)
+			if err != nil {
+				log.Logger().Warnf("Failed to set the fix version of issue %s: %s", issue.ID, err)
+			}
+		}
+	}
+	return nil
+}
+
+func (o *PromoteOptions) createIssueProvider() (issues.IssueProvider, error) {
+	if o.IssueProvider != nil {
+		return o.IssueProvider, nil
+	}
+	return o.CreateIssueProvider(".")
+}
+
+// IssueTransitionForEnvironment returns the status issues are moved to when promoted to the environment, from the
+// environment itself or else the team settings
+func IssueTransitionForEnvironment(environment *v1.Environment, teamSettings *v1.TeamSettings) string {
+	if environment.Spec.IssueTransition != "" {
+		return environment.Spec.IssueTransition
+	}
+	if teamSettings == nil {
+		return ""
+	}
+	return teamSettings.IssueTransitions[environment.Name]
+}
+
 func (o *PromoteOptions) SearchForChart(filter string) (string, error) {
 	answer := ""
 	charts, err := o.Helm().SearchCharts(filter, false)
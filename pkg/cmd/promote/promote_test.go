@@ -3,16 +3,22 @@
 package promote_test
 
 import (
+	"os"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/jenkins-x/jx/v2/pkg/tests"
 
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	jxfake "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/clients/fake"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/promote"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -274,3 +280,121 @@ type TestEnv struct {
 	StagingRepo     *gits.FakeRepository
 	ProdRepo        *gits.FakeRepository
 }
+
+func TestIssueTransitionForEnvironment(t *testing.T) {
+	teamSettings := &v1.TeamSettings{
+		IssueTransitions: map[string]string{
+			"staging":    "Deployed to Staging",
+			"production": "Done",
+		},
+	}
+	staging := &v1.Environment{ObjectMeta: metav1.ObjectMeta{Name: "staging"}}
+	production := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{Name: "production"},
+		Spec:       v1.EnvironmentSpec{IssueTransition: "Released"},
+	}
+	qa := &v1.Environment{ObjectMeta: metav1.ObjectMeta{Name: "qa"}}
+
+	assert.Equal(t, "Deployed to Staging", promote.IssueTransitionForEnvironment(staging, teamSettings))
+	assert.Equal(t, "Released", promote.IssueTransitionForEnvironment(production, teamSettings))
+	assert.Equal(t, "", promote.IssueTransitionForEnvironment(qa, teamSettings))
+	assert.Equal(t, "", promote.IssueTransitionForEnvironment(staging, nil))
+}
+
+type fakeIssueTransitioner struct {
+	transitions map[string]string
+	fixVersions map[string]string
+}
+
+func (f *fakeIssueTransitioner) GetIssue(key string) (*gits.GitIssue, error) {
+	return &gits.GitIssue{Key: key}, nil
+}
+
+func (f *fakeIssueTransitioner) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	return nil, nil
+}
+
+func (f *fakeIssueTransitioner) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	return nil, nil
+}
+
+func (f *fakeIssueTransitioner) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	return issue, nil
+}
+
+func (f *fakeIssueTransitioner) CreateIssueComment(key string, comment string) error {
+	return nil
+}
+
+func (f *fakeIssueTransitioner) IssueURL(key string) string {
+	return "https://issues.acme.com/browse/" + key
+}
+
+func (f *fakeIssueTransitioner) HomeURL() string {
+	return "https://issues.acme.com"
+}
+
+func (f *fakeIssueTransitioner) TransitionIssue(key string, status string) error {
+	f.transitions[key] = status
+	return nil
+}
+
+func (f *fakeIssueTransitioner) SetFixVersion(key string, version string) error {
+	f.fixVersions[key] = version
+	return nil
+}
+
+func TestUpdateIssueTracker(t *testing.T) {
+	devEnv := kube.CreateDefaultDevEnvironment("jx")
+	devEnv.Namespace = "jx"
+	devEnv.Spec.TeamSettings.IssueTransitions = map[string]string{
+		"production": "Done",
+	}
+	staging := kube.NewPermanentEnvironment("staging")
+	production := kube.NewPermanentEnvironment("production")
+	release := func(ns string) *v1.Release {
+		return &v1.Release{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "myapp-1.2.3",
+				Namespace: ns,
+			},
+			Spec: v1.ReleaseSpec{
+				Version: "1.2.3",
+				Issues: []v1.IssueSummary{
+					{ID: "ENG-1"},
+					{ID: "ENG-2"},
+				},
+			},
+		}
+	}
+	jxClient := jxfake.NewSimpleClientset(devEnv, staging, production, release(staging.Spec.Namespace), release(production.Spec.Namespace))
+
+	commonOpts := opts.NewCommonOptionsWithFactory(fake.NewFakeFactory())
+	commonOpts.SetJxClient(jxClient)
+	commonOpts.SetDevNamespace("jx")
+	commonOpts.Out = os.Stdout
+	tracker := &fakeIssueTransitioner{
+		transitions: map[string]string{},
+		fixVersions: map[string]string{},
+	}
+	o := &promote.PromoteOptions{
+		CommonOptions: &commonOpts,
+		Application:   "myapp",
+		Version:       "1.2.3",
+		IssueProvider: tracker,
+	}
+
+	err := o.UpdateIssueTracker(staging)
+	require.NoError(t, err)
+	assert.Empty(t, tracker.transitions, "no issues should be transitioned without a transition for staging")
+	assert.Equal(t, map[string]string{"ENG-1": "1.2.3", "ENG-2": "1.2.3"}, tracker.fixVersions)
+
+	err = o.UpdateIssueTracker(production)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ENG-1": "Done", "ENG-2": "Done"}, tracker.transitions)
+
+	o.Version = "1.2.4"
+	err = o.UpdateIssueTracker(production)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"ENG-1": "1.2.3", "ENG-2": "1.2.3"}, tracker.fixVersions, "no Release should be found for 1.2.4")
+}
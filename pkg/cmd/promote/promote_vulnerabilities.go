@@ -0,0 +1,273 @@
+package promote
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/jenkins-x/jx/v2/pkg/helm"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/kube/naming"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"gopkg.in/yaml.v2"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// VulnerabilitySourceFacts is the source of a vulnerability check using the Facts recorded by the pipeline
+	VulnerabilitySourceFacts = "facts"
+
+	optionIgnoreVulnerabilities = "ignore-vulnerabilities"
+)
+
+// CheckVulnerabilities checks the images of the release have no critical vulnerabilities before promoting it to a
+// protected Environment, recording the decision on the Promote step of the PipelineActivity. An error is returned
+// if the promotion is denied
+func (o *PromoteOptions) CheckVulnerabilities(env *v1.Environment, promoteKey *kube.PromoteStepActivityKey) (*v1.PromoteVulnerabilityCheck, error) {
+	if env == nil || !env.Spec.Protected {
+		return nil, nil
+	}
+	check, err := o.findVulnerabilities(promoteKey)
+	if err != nil {
+		check = &v1.PromoteVulnerabilityCheck{
+			Decision: v1.VulnerabilityCheckDecisionDenied,
+			Severity: cve.SeverityCritical,
+			Message:  fmt.Sprintf("could not check the vulnerabilities of the release: %s", err),
+		}
+	} else if check.Vulnerabilities > 0 {
+		check.Decision = v1.VulnerabilityCheckDecisionDenied
+		check.Message = fmt.Sprintf("found %d vulnerabilities at or above severity %s in the images of the release", check.Vulnerabilities, check.Severity)
+	} else {
+		check.Decision = v1.VulnerabilityCheckDecisionAllowed
+		check.Message = fmt.Sprintf("found no vulnerabilities at or above severity %s in the images of the release", check.Severity)
+	}
+	if check.Decision == v1.VulnerabilityCheckDecisionDenied && o.IgnoreVulnerabilities {
+		check.Decision = v1.VulnerabilityCheckDecisionOverridden
+		log.Logger().Warnf("Promoting to protected Environment %s even though the check failed as --%s was specified: %s", env.Name, optionIgnoreVulnerabilities, check.Message)
+	}
+
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return check, err
+	}
+	if o.Namespace != "" {
+		ns = o.Namespace
+	}
+	recordCheck := func(a *v1.PipelineActivity, s *v1.PipelineActivityStep, ps *v1.PromoteActivityStep) error {
+		ps.VulnerabilityCheck = check
+		if check.Decision == v1.VulnerabilityCheckDecisionDenied {
+			ps.Status = v1.ActivityStatusTypeFailed
+			if ps.CompletedTimestamp == nil {
+				t := metav1.Now()
+				ps.CompletedTimestamp = &t
+			}
+		}
+		return nil
+	}
+	err = promoteKey.OnPromote(jxClient, ns, recordCheck)
+	if err != nil {
+		log.Logger().Warnf("Failed to record the vulnerability check on the PipelineActivity: %s", err)
+	}
+
+	if check.Decision == v1.VulnerabilityCheckDecisionDenied {
+		return check, fmt.Errorf("refusing to promote to protected Environment %s as %s. Use --%s to promote anyway", env.Name, check.Message, optionIgnoreVulnerabilities)
+	}
+	log.Logger().Infof("Vulnerability check for protected Environment %s: %s", util.ColorInfo(env.Name), check.Message)
+	return check, nil
+}
+
+// findVulnerabilities counts the critical vulnerabilities in the images of the release using the Facts recorded by
+// the pipeline, falling back to scanning the images of the chart with the CVE provider
+func (o *PromoteOptions) findVulnerabilities(promoteKey *kube.PromoteStepActivityKey) (*v1.PromoteVulnerabilityCheck, error) {
+	check, err := o.findFactVulnerabilities(promoteKey)
+	if err != nil || check != nil {
+		return check, err
+	}
+	return o.findProviderVulnerabilities()
+}
+
+// findFactVulnerabilities returns the critical vulnerabilities recorded as Facts by the pipeline or nil if the
+// pipeline has not recorded any
+func (o *PromoteOptions) findFactVulnerabilities(promoteKey *kube.PromoteStepActivityKey) (*v1.PromoteVulnerabilityCheck, error) {
+	if promoteKey == nil || promoteKey.Name == "" {
+		return nil, nil
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return nil, err
+	}
+	facts, err := jxClient.JenkinsV1().Facts(ns).List(metav1.ListOptions{
+		LabelSelector: v1.FactLabelPipelineName + "=" + naming.ToValidLabelValue(promoteKey.Name),
+	})
+	if err != nil {
+		log.Logger().Debugf("Could not list the Facts of pipeline %s: %s", promoteKey.Name, err)
+		return nil, nil
+	}
+	check := &v1.PromoteVulnerabilityCheck{
+		Severity: cve.SeverityCritical,
+		Source:   VulnerabilitySourceFacts,
+	}
+	for _, fact := range facts.Items {
+		if fact.Spec.FactType != v1.FactTypeCVE {
+			continue
+		}
+		check.Images = append(check.Images, fact.Spec.Name)
+		for _, m := range fact.Spec.Measurements {
+			if cve.IsAtOrAbove(m.Name, check.Severity) {
+				check.Vulnerabilities += int32(m.MeasurementValue)
+			}
+		}
+	}
+	if len(check.Images) == 0 {
+		return nil, nil
+	}
+	sort.Strings(check.Images)
+	return check, nil
+}
+
+// findProviderVulnerabilities scans the images in the values of the chart of the release with the CVE provider
+func (o *PromoteOptions) findProviderVulnerabilities() (*v1.PromoteVulnerabilityCheck, error) {
+	images, err := o.chartImages()
+	if err != nil {
+		return nil, errors.Wrap(err, "finding the images of the chart")
+	}
+	if len(images) == 0 {
+		return nil, fmt.Errorf("no vulnerabilities recorded by the pipeline and no images found in the chart %s", o.Application)
+	}
+
+	kind := ""
+	provider := o.CVEProvider
+	if provider == nil {
+		settings, err := o.CVEScannerSettings()
+		if err != nil {
+			return nil, err
+		}
+		kind = settings.Kind
+		provider, err = o.CreateCVEProvider(settings)
+		if err != nil {
+			return nil, errors.Wrap(err, "creating the CVE provider")
+		}
+	}
+	check := &v1.PromoteVulnerabilityCheck{
+		Severity: cve.SeverityCritical,
+		Source:   kind,
+		Images:   images,
+	}
+	for _, image := range images {
+		vulnerabilities, err := provider.GetImageVulnerabilities(cve.NewImageQuery(image))
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting the vulnerabilities of image %s", image)
+		}
+		for _, v := range vulnerabilities {
+			if cve.IsAtOrAbove(v.Severity, check.Severity) {
+				check.Vulnerabilities++
+			}
+		}
+	}
+	return check, nil
+}
+
+// chartImages fetches the chart of the release and returns the images in its values
+func (o *PromoteOptions) chartImages() ([]string, error) {
+	chart := o.Application
+	repo := o.HelmRepositoryURL
+	if repo == "" && o.LocalHelmRepoName != "" {
+		chart = o.LocalHelmRepoName + "/" + chart
+	}
+	var images []string
+	err := helm.InspectChart(chart, o.Version, repo, "", "", o.Helm(), func(dir string) error {
+		data, err := ioutil.ReadFile(filepath.Join(dir, helm.ValuesFileName))
+		if err != nil {
+			return errors.Wrapf(err, "reading the values of chart %s", chart)
+		}
+		images, err = ChartValuesImages(data)
+		return err
+	})
+	return images, err
+}
+
+// ChartValuesImages returns the images in the values of a chart, which are either the values of 'image' keys or
+// made from the 'repository' and 'tag' of 'image' maps
+func ChartValuesImages(data []byte) ([]string, error) {
+	values := chartValue{}
+	err := yaml.Unmarshal(data, &values)
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshalling the chart values")
+	}
+	var images []string
+	addValuesImages(values, &images)
+	sort.Strings(images)
+	return images, nil
+}
+
+// chartValue is a value of the chart values which keeps scalars as they are written, so that a tag such as 1.10 is
+// not read as the number 1.1
+type chartValue struct {
+	value interface{}
+}
+
+// UnmarshalYAML reads a map, a list or a scalar value as a string
+func (v *chartValue) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	m := map[string]chartValue{}
+	if err := unmarshal(&m); err == nil {
+		v.value = m
+		return nil
+	}
+	l := []chartValue{}
+	if err := unmarshal(&l); err == nil {
+		v.value = l
+		return nil
+	}
+	s := ""
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	v.value = s
+	return nil
+}
+
+func addValuesImages(value chartValue, images *[]string) {
+	switch v := value.value.(type) {
+	case map[string]chartValue:
+		for key, child := range v {
+			if key == "image" {
+				image := valuesImage(child)
+				if image != "" {
+					if util.StringArrayIndex(*images, image) < 0 {
+						*images = append(*images, image)
+					}
+					continue
+				}
+			}
+			addValuesImages(child, images)
+		}
+	case []chartValue:
+		for _, child := range v {
+			addValuesImages(child, images)
+		}
+	}
+}
+
+func valuesImage(value chartValue) string {
+	switch v := value.value.(type) {
+	case string:
+		return strings.TrimSpace(v)
+	case map[string]chartValue:
+		repository, _ := v["repository"].value.(string)
+		if repository == "" {
+			return ""
+		}
+		tag, _ := v["tag"].value.(string)
+		if tag == "" {
+			return repository
+		}
+		return repository + ":" + tag
+	}
+	return ""
+}
@@ -0,0 +1,216 @@
+// +build unit
+
+package promote_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	jxfake "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/clients/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/promote"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/kube/naming"
+	"github.com/jenkins-x/jx/v2/pkg/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+type stubCVEProvider struct {
+	vulnerabilities map[string][]cve.Vulnerability
+}
+
+func (p *stubCVEProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query cve.CVEQuery) error {
+	return nil
+}
+
+func (p *stubCVEProvider) GetImageVulnerabilities(query cve.CVEQuery) ([]cve.Vulnerability, error) {
+	image, err := query.ImageReference()
+	if err != nil {
+		return nil, err
+	}
+	return p.vulnerabilities[image], nil
+}
+
+func newVulnerabilityPromoteOptions(t *testing.T, jxClient versioned.Interface) *promote.PromoteOptions {
+	commonOpts := opts.NewCommonOptionsWithFactory(fake.NewFakeFactory())
+	commonOpts.SetJxClient(jxClient)
+	commonOpts.SetDevNamespace("jx")
+	commonOpts.Out = os.Stdout
+
+	chartDir, err := filepath.Abs(filepath.Join("test_data", "vulnerabilities", "myapp"))
+	require.NoError(t, err)
+	return &promote.PromoteOptions{
+		CommonOptions: &commonOpts,
+		Namespace:     "jx",
+		Application:   chartDir,
+		Version:       "1.2.3",
+	}
+}
+
+func newPromoteKey() *kube.PromoteStepActivityKey {
+	return &kube.PromoteStepActivityKey{
+		PipelineActivityKey: kube.PipelineActivityKey{
+			Name:     "myorg-myapp-master-3",
+			Pipeline: "myorg/myapp/master",
+			Build:    "3",
+		},
+		Environment: "production",
+	}
+}
+
+func newProtectedEnvironment() *v1.Environment {
+	return &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "production",
+		},
+		Spec: v1.EnvironmentSpec{
+			Kind:      v1.EnvironmentKindTypePermanent,
+			Namespace: "jx-production",
+			Protected: true,
+		},
+	}
+}
+
+func newCVEFact(image string, critical int) *v1.Fact {
+	return &v1.Fact{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cve-" + image,
+			Namespace: "jx",
+			Labels: map[string]string{
+				v1.FactLabelPipelineName: "myorg-myapp-master-3",
+			},
+		},
+		Spec: v1.FactSpec{
+			Name:     image,
+			FactType: v1.FactTypeCVE,
+			Measurements: []v1.Measurement{
+				{Name: cve.SeverityHigh, MeasurementType: v1.MeasurementCount, MeasurementValue: 4},
+				{Name: cve.SeverityCritical, MeasurementType: v1.MeasurementCount, MeasurementValue: critical},
+			},
+			SubjectReference: v1.ResourceReference{
+				Kind: "PipelineActivity",
+				Name: "myorg-myapp-master-3",
+			},
+		},
+	}
+}
+
+func getPromoteStep(t *testing.T, jxClient versioned.Interface) *v1.PromoteActivityStep {
+	activity, err := jxClient.JenkinsV1().PipelineActivities("jx").Get("myorg-myapp-master-3", metav1.GetOptions{})
+	require.NoError(t, err)
+	for _, step := range activity.Spec.Steps {
+		if step.Promote != nil && step.Promote.Environment == "production" {
+			return step.Promote
+		}
+	}
+	require.Fail(t, "no promote step for the production environment")
+	return nil
+}
+
+func TestCheckVulnerabilitiesSkipsUnprotectedEnvironment(t *testing.T) {
+	jxClient := jxfake.NewSimpleClientset(newCVEFact("gcr.io/myorg/myapp:1.2.3", 2))
+	o := newVulnerabilityPromoteOptions(t, jxClient)
+	env := newProtectedEnvironment()
+	env.Spec.Protected = false
+
+	check, err := o.CheckVulnerabilities(env, newPromoteKey())
+	require.NoError(t, err)
+	assert.Nil(t, check)
+}
+
+func TestCheckVulnerabilitiesDeniedByFacts(t *testing.T) {
+	jxClient := jxfake.NewSimpleClientset(newCVEFact("gcr.io/myorg/myapp:1.2.3", 2), newCVEFact("gcr.io/myorg/reaper:0.1.0", 1))
+	o := newVulnerabilityPromoteOptions(t, jxClient)
+
+	check, err := o.CheckVulnerabilities(newProtectedEnvironment(), newPromoteKey())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--ignore-vulnerabilities")
+	assert.Equal(t, v1.VulnerabilityCheckDecisionDenied, check.Decision)
+	assert.Equal(t, promote.VulnerabilitySourceFacts, check.Source)
+	assert.Equal(t, int32(3), check.Vulnerabilities)
+	assert.Equal(t, []string{"gcr.io/myorg/myapp:1.2.3", "gcr.io/myorg/reaper:0.1.0"}, check.Images)
+
+	step := getPromoteStep(t, jxClient)
+	assert.Equal(t, v1.ActivityStatusTypeFailed, step.Status)
+	require.NotNil(t, step.VulnerabilityCheck)
+	assert.Equal(t, v1.VulnerabilityCheckDecisionDenied, step.VulnerabilityCheck.Decision)
+}
+
+func TestCheckVulnerabilitiesDeniedByFactsOfLongPipelineName(t *testing.T) {
+	promoteKey := newPromoteKey()
+	promoteKey.Pipeline = "myorg/" + strings.Repeat("myapp", 12) + "/master"
+	promoteKey.Name = naming.ToValidName(promoteKey.Pipeline + "-3")
+	fact := newCVEFact("gcr.io/myorg/myapp:1.2.3", 2)
+	fact.Labels[v1.FactLabelPipelineName] = naming.ToValidLabelValue(promoteKey.Name)
+	jxClient := jxfake.NewSimpleClientset(fact)
+	o := newVulnerabilityPromoteOptions(t, jxClient)
+
+	check, err := o.CheckVulnerabilities(newProtectedEnvironment(), promoteKey)
+	require.Error(t, err)
+	assert.Equal(t, v1.VulnerabilityCheckDecisionDenied, check.Decision)
+	assert.Equal(t, promote.VulnerabilitySourceFacts, check.Source)
+	assert.Equal(t, int32(2), check.Vulnerabilities)
+}
+
+func TestCheckVulnerabilitiesOverridden(t *testing.T) {
+	jxClient := jxfake.NewSimpleClientset(newCVEFact("gcr.io/myorg/myapp:1.2.3", 2))
+	o := newVulnerabilityPromoteOptions(t, jxClient)
+	o.IgnoreVulnerabilities = true
+
+	check, err := o.CheckVulnerabilities(newProtectedEnvironment(), newPromoteKey())
+	require.NoError(t, err)
+	assert.Equal(t, v1.VulnerabilityCheckDecisionOverridden, check.Decision)
+
+	step := getPromoteStep(t, jxClient)
+	assert.NotEqual(t, v1.ActivityStatusTypeFailed, step.Status)
+	require.NotNil(t, step.VulnerabilityCheck)
+	assert.Equal(t, v1.VulnerabilityCheckDecisionOverridden, step.VulnerabilityCheck.Decision)
+}
+
+func TestCheckVulnerabilitiesUsesProviderForChartImages(t *testing.T) {
+	jxClient := jxfake.NewSimpleClientset()
+	o := newVulnerabilityPromoteOptions(t, jxClient)
+	o.CVEProvider = &stubCVEProvider{
+		vulnerabilities: map[string][]cve.Vulnerability{
+			"gcr.io/myorg/myapp:1.2.3": {
+				{Vuln: "CVE-2020-1967", Severity: cve.SeverityHigh},
+			},
+			"docker.io/library/nginx:1.19": {
+				{Vuln: "CVE-2021-23017", Severity: cve.SeverityMedium},
+			},
+		},
+	}
+
+	check, err := o.CheckVulnerabilities(newProtectedEnvironment(), newPromoteKey())
+	require.NoError(t, err)
+	assert.Equal(t, v1.VulnerabilityCheckDecisionAllowed, check.Decision)
+	assert.Equal(t, int32(0), check.Vulnerabilities)
+	assert.Len(t, check.Images, 4)
+
+	o.CVEProvider.(*stubCVEProvider).vulnerabilities["gcr.io/myorg/reaper"] = []cve.Vulnerability{
+		{Vuln: "CVE-2021-36159", Severity: cve.SeverityCritical},
+	}
+	check, err = o.CheckVulnerabilities(newProtectedEnvironment(), newPromoteKey())
+	require.Error(t, err)
+	assert.Equal(t, v1.VulnerabilityCheckDecisionDenied, check.Decision)
+	assert.Equal(t, int32(1), check.Vulnerabilities)
+}
+
+func TestChartValuesImages(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("test_data", "vulnerabilities", "myapp", "values.yaml"))
+	require.NoError(t, err)
+
+	images, err := promote.ChartValuesImages(data)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"docker.io/library/nginx:1.19", "gcr.io/myorg/cleaner:1.10", "gcr.io/myorg/myapp:1.2.3", "gcr.io/myorg/reaper"}, images)
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step/pr"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step/report"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step/restore"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/step/scan"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step/scheduler"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step/syntax"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step/update"
@@ -87,6 +88,7 @@ func NewCmdStep(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.AddCommand(report.NewCmdStepReport(commonOpts))
 	cmd.AddCommand(step.NewCmdStepOverrideRequirements(commonOpts))
 	cmd.AddCommand(restore.NewCmdStepRestore(commonOpts))
+	cmd.AddCommand(scan.NewCmdStepScan(commonOpts))
 
 	return cmd
 }
@@ -7,6 +7,7 @@ import (
 	"os"
 	"path/filepath"
 	"reflect"
+	"strconv"
 	"strings"
 	"time"
 
@@ -625,6 +626,94 @@ func (o *StepCreateTaskOptions) createEffectiveProjectConfig(packsDir string, pr
 	return effectiveProjectConfig, nil
 }
 
+// whenContext returns what the when conditions of the pipeline's stages are evaluated against for this build, or nil
+// if no stages have when conditions. The changed files and labels of a pull request are only looked up if conditions
+// use them.
+func (o *StepCreateTaskOptions) whenContext(pipeline *syntax.ParsedPipeline, pipelineConfigEnv []corev1.EnvVar) (*syntax.WhenContext, error) {
+	whens := pipeline.Whens()
+	if len(whens) == 0 {
+		return nil, nil
+	}
+
+	env := make(map[string]string)
+	for _, e := range os.Environ() {
+		pair := strings.SplitN(e, "=", 2)
+		if len(pair) == 2 {
+			env[pair[0]] = pair[1]
+		}
+	}
+	for _, e := range append(pipelineConfigEnv, pipeline.GetEnv()...) {
+		env[e.Name] = e.Value
+	}
+	for k, v := range o.AdditionalEnvVars {
+		env[k] = v
+	}
+
+	answer := &syntax.WhenContext{
+		Branch:      o.Branch,
+		Env:         env,
+		PullRequest: o.PullRequestNumber != "",
+	}
+	if !answer.PullRequest {
+		return answer, nil
+	}
+
+	usesChanges := false
+	usesLabels := false
+	for _, w := range whens {
+		usesChanges = usesChanges || len(w.Changes) > 0
+		usesLabels = usesLabels || len(w.Labels) > 0
+	}
+
+	if usesChanges {
+		baseSHA := env["PULL_BASE_SHA"]
+		if baseSHA == "" {
+			return nil, errors.New("PULL_BASE_SHA is not set so the files changed by the pull request cannot be found")
+		}
+		// only the files changed by the pull request since it branched from the base are compared
+		output, err := o.Git().ListChangedFilesFromBranch(o.CloneDir, baseSHA+"...HEAD")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the files changed since %s in %s", baseSHA, o.CloneDir)
+		}
+		answer.ChangedFiles = changedFilesFromNameStatus(output)
+	}
+
+	if usesLabels {
+		prNumber, err := strconv.Atoi(o.PullRequestNumber)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid pull request number %s", o.PullRequestNumber)
+		}
+		provider, err := o.GitProviderForURL(o.GitInfo.URL, "user name to find the labels of the pull request")
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create the git provider for %s", o.GitInfo.URL)
+		}
+		pr, err := provider.GetPullRequest(o.GitInfo.Organisation, o.GitInfo, prNumber)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get pull request %d of %s", prNumber, o.GitInfo.URL)
+		}
+		for _, l := range pr.Labels {
+			if l != nil && l.Name != nil {
+				answer.Labels = append(answer.Labels, *l.Name)
+			}
+		}
+	}
+	return answer, nil
+}
+
+// changedFilesFromNameStatus returns the paths in the output of git diff --name-status, including both the old and new
+// paths of renamed and copied files
+func changedFilesFromNameStatus(output string) []string {
+	var answer []string
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Split(strings.TrimSpace(line), "\t")
+		if len(fields) < 2 {
+			continue
+		}
+		answer = append(answer, fields[1:]...)
+	}
+	return answer
+}
+
 // GenerateTektonCRDs creates the Pipeline, Task, PipelineResource, PipelineRun, and PipelineStructure CRDs that will be applied to actually kick off the pipeline
 func (o *StepCreateTaskOptions) generateTektonCRDs(effectiveProjectConfig *config.ProjectConfig, ns string, pipelineName string) (*tekton.CRDWrapper, error) {
 	if effectiveProjectConfig == nil {
@@ -636,6 +725,11 @@ func (o *StepCreateTaskOptions) generateTektonCRDs(effectiveProjectConfig *confi
 		return nil, errors.Wrapf(err, "unable to extract the requested pipeline")
 	}
 
+	when, err := o.whenContext(effectivePipeline, effectiveProjectConfig.PipelineConfig.Env)
+	if err != nil {
+		return nil, errors.Wrapf(err, "evaluating the when conditions of stages")
+	}
+
 	crdParams := syntax.CRDsFromPipelineParams{
 		PipelineIdentifier: pipelineName,
 		BuildIdentifier:    o.BuildNumber,
@@ -647,6 +741,7 @@ func (o *StepCreateTaskOptions) generateTektonCRDs(effectiveProjectConfig *confi
 		Labels:             o.labels,
 		DefaultImage:       "",
 		InterpretMode:      o.InterpretMode,
+		When:               when,
 	}
 
 	pipeline, tasks, structure, err := effectivePipeline.GenerateCRDs(crdParams)
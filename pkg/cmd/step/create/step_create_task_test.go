@@ -26,6 +26,7 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/tekton"
 	"github.com/jenkins-x/jx/v2/pkg/tekton/syntax"
 	"github.com/knative/pkg/kmp"
+	"github.com/petergtz/pegomock"
 	uuid "github.com/satori/go.uuid"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/rand"
@@ -39,6 +40,7 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/tekton/tekton_helpers_test"
 	"github.com/jenkins-x/jx/v2/pkg/tests"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	pipelineapi "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -644,3 +646,38 @@ func assertLoadPodTemplates(t *testing.T) map[string]*corev1.Pod {
 	}
 	return nil
 }
+
+func TestChangedFilesFromNameStatus(t *testing.T) {
+	output := "M\tservices/api/main.go\nA\tdocs/index.md\nR100\tservices/web/old.go\tservices/web/new.go\n"
+
+	files := changedFilesFromNameStatus(output)
+
+	assert.Equal(t, []string{"services/api/main.go", "docs/index.md", "services/web/old.go", "services/web/new.go"}, files)
+}
+
+func TestWhenContextChangedFilesSinceMergeBase(t *testing.T) {
+	pegomock.RegisterMockTestingT(t)
+	gitter := gits_test.NewMockGitter()
+	pegomock.When(gitter.ListChangedFilesFromBranch(pegomock.AnyString(), pegomock.AnyString())).ThenReturn("M\tservices/api/main.go\n", nil)
+
+	o := &StepCreateTaskOptions{
+		PullRequestNumber: "1",
+		CloneDir:          "/workspace/source",
+		AdditionalEnvVars: map[string]string{"PULL_BASE_SHA": "abc123"},
+		StepOptions: step.StepOptions{
+			CommonOptions: &opts.CommonOptions{},
+		},
+	}
+	o.SetGit(gitter)
+	pipeline := &syntax.ParsedPipeline{
+		Stages: []syntax.Stage{
+			{Name: "API", When: &syntax.When{Changes: []string{"services/api/**"}}},
+		},
+	}
+
+	ctx, err := o.whenContext(pipeline, nil)
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"services/api/main.go"}, ctx.ChangedFiles)
+	gitter.VerifyWasCalledOnce().ListChangedFilesFromBranch("/workspace/source", "abc123...HEAD")
+}
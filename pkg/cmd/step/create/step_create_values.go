@@ -6,6 +6,7 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/jenkins-x/jx/v2/pkg/kube/cluster"
 	v1 "k8s.io/api/core/v1"
@@ -110,7 +111,7 @@ func NewCmdStepCreateValues(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVarP(&options.Name, "name", "", "values", "the kind of the file to create (and, by default, the schema name)")
 	cmd.Flags().StringVarP(&options.BasePath, "secret-base-path", "", "", fmt.Sprintf("the secret path used to store secrets in vault / file system. Typically a unique name per cluster+team. If none is specified we will default it to the cluster name from the %s file in the current or a parent directory.", config.RequirementsConfigFileName))
 	cmd.Flags().StringVarP(&options.ValuesFile, "out", "", "", "the path to the file to create, overrides --dir and --name")
-	cmd.Flags().StringVarP(&options.SecretsScheme, optionSecretsScheme, "", "", fmt.Sprintf("the scheme to store/reference any secrets in, valid options are vault and local. If none are specified we will default it from the %s file in the current or a parent directory.", config.RequirementsConfigFileName))
+	cmd.Flags().StringVarP(&options.SecretsScheme, optionSecretsScheme, "", "", fmt.Sprintf("the scheme to store/reference any secrets in, valid options are %s. If none are specified we will default it from the %s file in the current or a parent directory.", strings.Join(config.SecretStorageTypeValues, ", "), config.RequirementsConfigFileName))
 	return cmd
 }
 
@@ -155,8 +156,8 @@ func (o *StepCreateValuesOptions) Run() error {
 		}
 
 	}
-	if !(o.SecretsScheme == "vault" || o.SecretsScheme == "local") {
-		err = util.InvalidArgf(optionSecretsScheme, "Use one of vault or local")
+	if util.StringArrayIndex(config.SecretStorageTypeValues, o.SecretsScheme) < 0 {
+		err = util.InvalidArgf(optionSecretsScheme, "Use one of %s", strings.Join(config.SecretStorageTypeValues, ", "))
 		if err != nil {
 			return err
 		}
@@ -342,12 +343,12 @@ func getValidRegistryPushURL(url string) string {
 }
 
 func (o *StepCreateValuesOptions) createLocalSecretFilesSecret(requirements *config.RequirementsConfig) error {
-	if o.SecretsScheme == "local" && (os.Getenv("OVERRIDE_IN_CLUSTER_CHECK") == "true" || !cluster.IsInCluster()) {
+	if (o.SecretsScheme == "local" || o.SecretsScheme == "sops") && (os.Getenv("OVERRIDE_IN_CLUSTER_CHECK") == "true" || !cluster.IsInCluster()) {
 		kubeClient, ns, err := o.KubeClientAndDevNamespace()
 		if err != nil {
 			return err
 		}
-		secretFiles, err := getLocalSecretFilesAsMap(requirements)
+		secretFiles, err := getLocalSecretFilesAsMap(requirements, o.SecretsScheme)
 		if err != nil {
 			return errors.Wrap(err, "there was a problem obtaining the local secret files")
 		}
@@ -375,8 +376,16 @@ func (o *StepCreateValuesOptions) createLocalSecretFilesSecret(requirements *con
 	return nil
 }
 
-func getLocalSecretFilesAsMap(requirements *config.RequirementsConfig) (map[string][]byte, error) {
-	dir, err := util.LocalFileSystemSecretsDir()
+// getLocalSecretFilesAsMap returns the secret files of the cluster stored on the local file system, which are SOPS
+// encrypted if the secrets scheme is sops
+func getLocalSecretFilesAsMap(requirements *config.RequirementsConfig, secretsScheme string) (map[string][]byte, error) {
+	var dir string
+	var err error
+	if secretsScheme == "sops" {
+		dir, err = util.SopsSecretsDir()
+	} else {
+		dir, err = util.LocalFileSystemSecretsDir()
+	}
 	if err != nil {
 		return nil, errors.Wrap(err, "there was a problem obtaining the local file system for secrets")
 	}
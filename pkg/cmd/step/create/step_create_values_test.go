@@ -282,3 +282,31 @@ func TestCreateValuesFileWithLocalSecrets(t *testing.T) {
 		assert.Equal(r, "hmacToken: abc\n", string(secret.Data["prow.yaml"]))
 	})
 }
+
+func TestGetLocalSecretFilesAsMapWithSopsSecrets(t *testing.T) {
+	dir, err := ioutil.TempDir("", "test-sops-secrets-")
+	assert.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	oldDir, exists := os.LookupEnv("JX_SOPS_SECRETS_DIR")
+	err = os.Setenv("JX_SOPS_SECRETS_DIR", dir)
+	assert.NoError(t, err)
+	defer func() {
+		if exists {
+			os.Setenv("JX_SOPS_SECRETS_DIR", oldDir)
+		} else {
+			os.Unsetenv("JX_SOPS_SECRETS_DIR")
+		}
+	}()
+
+	err = os.MkdirAll(filepath.Join(dir, "mycluster"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+	err = ioutil.WriteFile(filepath.Join(dir, "mycluster", "adminUser.yaml"), []byte("password: ENC[AES256_GCM,data:abc]\n"), util.DefaultWritePermissions)
+	assert.NoError(t, err)
+
+	requirements := config.NewRequirementsConfig()
+	requirements.Cluster.ClusterName = "mycluster"
+	secretFiles, err := getLocalSecretFilesAsMap(requirements, "sops")
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]byte{"adminUser.yaml": []byte("password: ENC[AES256_GCM,data:abc]\n")}, secretFiles)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/jenkins-x/jx/v2/pkg/cluster/factory"
+	"github.com/jenkins-x/jx/v2/pkg/cluster/kind"
 
 	"github.com/jenkins-x/jx/v2/pkg/cloud"
 	"github.com/jenkins-x/jx/v2/pkg/cloud/gke"
@@ -26,6 +27,14 @@ import (
 	"github.com/spf13/cobra"
 )
 
+// activeClusterStatuses the status of the clusters of each provider which can be garbage collected
+var activeClusterStatuses = map[string]string{
+	cloud.EKS:  "ACTIVE",
+	cloud.AKS:  "Succeeded",
+	cloud.KIND: kind.StatusRunning,
+	cloud.K3D:  kind.StatusRunning,
+}
+
 // StepE2EGCOptions contains the command line flags
 type StepE2EGCOptions struct {
 	step.StepOptions
@@ -85,7 +94,9 @@ func (o *StepE2EGCOptions) Run() error {
 		case cloud.AWS:
 			fallthrough
 		case cloud.EKS:
-			return o.eksGarbageCollection()
+			return o.clusterGarbageCollection(cloud.EKS)
+		case cloud.AKS, cloud.KIND, cloud.K3D:
+			return o.clusterGarbageCollection(strings.ToLower(pr))
 		default:
 			return fmt.Errorf("provider %s doesn't have an E2E GC implementation defined", pr)
 		}
@@ -93,32 +104,33 @@ func (o *StepE2EGCOptions) Run() error {
 	return nil
 }
 
-func (o *StepE2EGCOptions) eksGarbageCollection() error {
-	eksClient, err := factory.NewClientForProvider(cloud.EKS)
+// clusterGarbageCollection removes the stale clusters of a provider using its cluster.Client
+func (o *StepE2EGCOptions) clusterGarbageCollection(provider string) error {
+	client, err := factory.NewClientForProvider(provider)
 	if err != nil {
-		return errors.Wrap(err, "could not obtain an EKS cluster client to ")
+		return errors.Wrapf(err, "could not obtain a %s cluster client", provider)
 	}
-	eksClusters, err := eksClient.List()
+	clusters, err := client.List()
 	if err != nil {
-		return errors.Wrap(err, "there was a problem obtaining every eksClient in the current account")
+		return errors.Wrapf(err, "there was a problem obtaining every %s cluster in the current account", provider)
 	}
 
-	for _, eksCluster := range eksClusters {
-		if eksCluster.Status == "ACTIVE" {
-			if !o.ShouldDeleteMarkedEKSCluster(eksCluster) {
-				if !o.ShouldDeleteOlderThanDurationEKS(eksCluster) {
-					if o.ShouldDeleteDueToNewerRunEKS(eksCluster, eksClusters) {
-						err = o.deleteEksCluster(eksCluster, eksClient)
+	for _, c := range clusters {
+		if c.Status == activeClusterStatuses[provider] {
+			if !o.ShouldDeleteMarkedEKSCluster(c) {
+				if !o.ShouldDeleteOlderThanDurationEKS(c) {
+					if o.ShouldDeleteDueToNewerRunEKS(c, clusters) {
+						err = o.deleteCluster(c, client)
 					}
 				} else {
-					err = o.deleteEksCluster(eksCluster, eksClient)
+					err = o.deleteCluster(c, client)
 				}
 			} else {
-				err = o.deleteEksCluster(eksCluster, eksClient)
+				err = o.deleteCluster(c, client)
 			}
 		}
 		if err != nil {
-			log.Logger().Errorf("error deleting cluster %s: %s", eksCluster.Name, err.Error())
+			log.Logger().Errorf("error deleting cluster %s: %s", c.Name, err.Error())
 		}
 	}
 	return nil
@@ -314,10 +326,10 @@ func (o *StepE2EGCOptions) ShouldDeleteDueToNewerRun(cluster *gke.Cluster, clust
 	return false
 }
 
-func (o *StepE2EGCOptions) deleteEksCluster(cluster *cluster.Cluster, client cluster.Client) error {
+func (o *StepE2EGCOptions) deleteCluster(cluster *cluster.Cluster, client cluster.Client) error {
 	err := client.Delete(cluster)
 	if err != nil {
-		return errors.Wrapf(err, "error deleting EKS cluster %s", cluster.Name)
+		return errors.Wrapf(err, "error deleting cluster %s", cluster.Name)
 	}
 	return nil
 }
@@ -31,6 +31,7 @@ func NewCmdStepRestore(commonOpts *opts.CommonOptions) *cobra.Command {
 		},
 	}
 	cmd.AddCommand(NewCmdStepRestoreFromBackup(commonOpts))
+	cmd.AddCommand(NewCmdStepRestoreFromGit(commonOpts))
 	return cmd
 }
 
@@ -0,0 +1,433 @@
+package restore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/ghodss/yaml"
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts/step"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/pmezard/go-difflib/difflib"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// FromGitOptions contains the command line options
+type FromGitOptions struct {
+	*StepRestoreOptions
+
+	URL             string
+	Dir             string
+	Commit          string
+	Date            string
+	Namespace       string
+	SourceNamespace string
+	Kinds           []string
+	DryRun          bool
+}
+
+// backupKind describes how the resources of one kind stored in the backup repository are restored
+type backupKind struct {
+	dir    string
+	new    func() metav1.Object
+	get    func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error)
+	create func(jxClient versioned.Interface, ns string, obj metav1.Object) error
+	update func(jxClient versioned.Interface, ns string, obj metav1.Object) error
+}
+
+var (
+	restoreFromGitLong = templates.LongDesc(`
+		Restores the Jenkins X resources written to the backup git repository by 'jx controller backup'.
+
+		The resources can be restored as they were at a given commit or date and re-applied into a different namespace.
+		Use --dry-run to see the differences with the resources in the cluster without changing anything.
+
+`)
+
+	restoreFromGitExample = templates.Examples(`
+		# shows what would be restored from the latest commit of the backup repository
+		jx step restore from-git --url https://github.com/myorg/organisation-myorg-backup.git --dry-run
+
+		# restores the resources as they were on a given date
+		jx step restore from-git --url https://github.com/myorg/organisation-myorg-backup.git --date 2020-03-21
+
+		# restores the environments of the jx namespace at a given commit into the jx-staging-restore namespace
+		jx step restore from-git --dir ~/.jx/backup/organisation-myorg-backup --commit 4d2a8e1 --kind environments --source-namespace jx -n jx-staging-restore
+	`)
+
+	// backupKinds the kinds of resource written to the backup repository keyed by their directory name
+	backupKinds = []backupKind{
+		{
+			dir: "environments",
+			new: func() metav1.Object { return &v1.Environment{} },
+			get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+				return jxClient.JenkinsV1().Environments(ns).Get(name, metav1.GetOptions{})
+			},
+			create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Environments(ns).Create(obj.(*v1.Environment))
+				return err
+			},
+			update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Environments(ns).Update(obj.(*v1.Environment))
+				return err
+			},
+		},
+		{
+			dir: "teams",
+			new: func() metav1.Object { return &v1.Team{} },
+			get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+				return jxClient.JenkinsV1().Teams(ns).Get(name, metav1.GetOptions{})
+			},
+			create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Teams(ns).Create(obj.(*v1.Team))
+				return err
+			},
+			update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Teams(ns).Update(obj.(*v1.Team))
+				return err
+			},
+		},
+		{
+			dir: "users",
+			new: func() metav1.Object { return &v1.User{} },
+			get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+				return jxClient.JenkinsV1().Users(ns).Get(name, metav1.GetOptions{})
+			},
+			create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Users(ns).Create(obj.(*v1.User))
+				return err
+			},
+			update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Users(ns).Update(obj.(*v1.User))
+				return err
+			},
+		},
+		{
+			dir: "sourcerepositories",
+			new: func() metav1.Object { return &v1.SourceRepository{} },
+			get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+				return jxClient.JenkinsV1().SourceRepositories(ns).Get(name, metav1.GetOptions{})
+			},
+			create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().SourceRepositories(ns).Create(obj.(*v1.SourceRepository))
+				return err
+			},
+			update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().SourceRepositories(ns).Update(obj.(*v1.SourceRepository))
+				return err
+			},
+		},
+		{
+			dir: "apps",
+			new: func() metav1.Object { return &v1.App{} },
+			get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+				return jxClient.JenkinsV1().Apps(ns).Get(name, metav1.GetOptions{})
+			},
+			create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Apps(ns).Create(obj.(*v1.App))
+				return err
+			},
+			update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Apps(ns).Update(obj.(*v1.App))
+				return err
+			},
+		},
+		{
+			dir: "schedulers",
+			new: func() metav1.Object { return &v1.Scheduler{} },
+			get: func(jxClient versioned.Interface, ns string, name string) (metav1.Object, error) {
+				return jxClient.JenkinsV1().Schedulers(ns).Get(name, metav1.GetOptions{})
+			},
+			create: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Schedulers(ns).Create(obj.(*v1.Scheduler))
+				return err
+			},
+			update: func(jxClient versioned.Interface, ns string, obj metav1.Object) error {
+				_, err := jxClient.JenkinsV1().Schedulers(ns).Update(obj.(*v1.Scheduler))
+				return err
+			},
+		},
+	}
+)
+
+// NewCmdStepRestoreFromGit creates the command
+func NewCmdStepRestoreFromGit(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &FromGitOptions{
+		StepRestoreOptions: &StepRestoreOptions{
+			StepOptions: step.StepOptions{
+				CommonOptions: commonOpts,
+			},
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "from-git [flags]",
+		Short:   "Restores the Jenkins X resources from the backup git repository at a commit or date",
+		Long:    restoreFromGitLong,
+		Example: restoreFromGitExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.URL, "url", "u", "", "The git URL of the backup repository")
+	cmd.Flags().StringVarP(&options.Dir, "dir", "", "", "The local clone of the backup repository to use instead of the git URL")
+	cmd.Flags().StringVarP(&options.Commit, "commit", "c", "", "The commit of the backup repository to restore. Defaults to the latest commit")
+	cmd.Flags().StringVarP(&options.Date, "date", "", "", "Restores the resources as they were at the given date using the format 2006-01-02 or RFC3339")
+	cmd.Flags().StringVarP(&options.Namespace, "namespace", "n", "", "The namespace to restore the resources into. Defaults to the current team namespace")
+	cmd.Flags().StringVarP(&options.SourceNamespace, "source-namespace", "", "", "The namespace of the backup repository to restore the resources from. Defaults to the namespace being restored into")
+	cmd.Flags().StringArrayVarP(&options.Kinds, "kind", "k", nil, "The kinds of resource to restore such as environments or sourcerepositories. Defaults to all of them")
+	cmd.Flags().BoolVarP(&options.DryRun, "dry-run", "", false, "Shows the differences with the resources in the cluster without applying them")
+	return cmd
+}
+
+// Run implements this command
+func (o *FromGitOptions) Run() error {
+	if o.URL == "" && o.Dir == "" {
+		return util.MissingOption("url")
+	}
+	if o.Commit != "" && o.Date != "" {
+		return errors.Errorf("only one of --commit and --date can be specified")
+	}
+	kinds, err := o.selectedKinds()
+	if err != nil {
+		return err
+	}
+
+	jxClient, devNs, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return errors.Wrap(err, "creating the jx client")
+	}
+	ns := o.Namespace
+	if ns == "" {
+		ns = devNs
+	}
+	sourceNs := o.SourceNamespace
+	if sourceNs == "" {
+		sourceNs = ns
+	}
+
+	dir, err := o.cloneBackupRepository()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+
+	for _, kind := range kinds {
+		err = o.restoreKind(jxClient, kind, filepath.Join(dir, kind.dir, sourceNs), ns)
+		if err != nil {
+			return errors.Wrapf(err, "restoring %s", kind.dir)
+		}
+	}
+	return nil
+}
+
+func (o *FromGitOptions) selectedKinds() ([]backupKind, error) {
+	if len(o.Kinds) == 0 {
+		return backupKinds, nil
+	}
+	answer := []backupKind{}
+	for _, name := range o.Kinds {
+		found := false
+		for _, kind := range backupKinds {
+			if kind.dir == strings.ToLower(name) {
+				answer = append(answer, kind)
+				found = true
+				break
+			}
+		}
+		if !found {
+			names := []string{}
+			for _, kind := range backupKinds {
+				names = append(names, kind.dir)
+			}
+			return nil, util.InvalidOption("kind", name, names)
+		}
+	}
+	return answer, nil
+}
+
+// cloneBackupRepository clones the backup repository into a temporary directory and checks out the commit to restore
+func (o *FromGitOptions) cloneBackupRepository() (string, error) {
+	source := o.URL
+	if source == "" {
+		source = o.Dir
+	}
+	dir, err := ioutil.TempDir("", "jx-restore-")
+	if err != nil {
+		return "", errors.Wrap(err, "creating a temporary directory")
+	}
+	err = o.Git().Clone(source, dir)
+	if err != nil {
+		os.RemoveAll(dir)
+		return "", errors.Wrapf(err, "cloning the backup repository %s", source)
+	}
+
+	revision := o.Commit
+	if o.Date != "" {
+		revision, err = o.revisionAtDate(dir)
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	if revision != "" {
+		err = o.Git().Checkout(dir, revision)
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", errors.Wrapf(err, "checking out %s of the backup repository", revision)
+		}
+		log.Logger().Infof("Restoring from commit %s of the backup repository", util.ColorInfo(revision))
+	}
+	return dir, nil
+}
+
+func (o *FromGitOptions) revisionAtDate(dir string) (string, error) {
+	t, err := time.Parse("2006-01-02", o.Date)
+	if err != nil {
+		t, err = time.Parse(time.RFC3339, o.Date)
+		if err != nil {
+			return "", util.InvalidOptionf("date", o.Date, "the date must use the format 2006-01-02 or RFC3339")
+		}
+	}
+	revision, err := o.Git().GetRevisionBeforeDateText(dir, t.Format(time.RFC3339))
+	if err != nil {
+		return "", errors.Wrapf(err, "finding the commit of the backup repository before %s", o.Date)
+	}
+	revision = strings.TrimSpace(revision)
+	if revision == "" {
+		return "", errors.Errorf("the backup repository has no commit before %s", o.Date)
+	}
+	return revision, nil
+}
+
+func (o *FromGitOptions) restoreKind(jxClient versioned.Interface, kind backupKind, dir string, ns string) error {
+	exists, err := util.DirExists(dir)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		log.Logger().Debugf("No %s found in the backup repository at %s", kind.dir, dir)
+		return nil
+	}
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return errors.Wrapf(err, "reading directory %s", dir)
+	}
+	names := []string{}
+	for _, f := range files {
+		if !f.IsDir() && strings.HasSuffix(f.Name(), ".yaml") {
+			names = append(names, f.Name())
+		}
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		fileName := filepath.Join(dir, name)
+		data, err := ioutil.ReadFile(fileName)
+		if err != nil {
+			return errors.Wrapf(err, "reading file %s", fileName)
+		}
+		obj := kind.new()
+		err = yaml.Unmarshal(data, obj)
+		if err != nil {
+			return errors.Wrapf(err, "unmarshalling file %s", fileName)
+		}
+		stripServerMetadata(obj)
+		obj.SetNamespace(ns)
+
+		err = o.restoreResource(jxClient, kind, obj, ns)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (o *FromGitOptions) restoreResource(jxClient versioned.Interface, kind backupKind, obj metav1.Object, ns string) error {
+	name := obj.GetName()
+	existing, err := kind.get(jxClient, ns, name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "getting %s %s in namespace %s", kind.dir, name, ns)
+	}
+	if err != nil {
+		if o.DryRun {
+			log.Logger().Infof("Would create %s %s in namespace %s", kind.dir, util.ColorInfo(name), ns)
+			return nil
+		}
+		err = kind.create(jxClient, ns, obj)
+		if err != nil {
+			return errors.Wrapf(err, "creating %s %s in namespace %s", kind.dir, name, ns)
+		}
+		log.Logger().Infof("Created %s %s in namespace %s", kind.dir, util.ColorInfo(name), ns)
+		return nil
+	}
+
+	resourceVersion := existing.GetResourceVersion()
+	stripServerMetadata(existing)
+	diff, err := resourceDiff(existing, obj)
+	if err != nil {
+		return errors.Wrapf(err, "comparing %s %s in namespace %s", kind.dir, name, ns)
+	}
+	if diff == "" {
+		log.Logger().Infof("%s %s in namespace %s is unchanged", kind.dir, util.ColorInfo(name), ns)
+		return nil
+	}
+	if o.DryRun {
+		log.Logger().Infof("Would update %s %s in namespace %s:\n%s", kind.dir, util.ColorInfo(name), ns, diff)
+		return nil
+	}
+	obj.SetResourceVersion(resourceVersion)
+	err = kind.update(jxClient, ns, obj)
+	if err != nil {
+		return errors.Wrapf(err, "updating %s %s in namespace %s", kind.dir, name, ns)
+	}
+	log.Logger().Infof("Updated %s %s in namespace %s", kind.dir, util.ColorInfo(name), ns)
+	return nil
+}
+
+// stripServerMetadata removes the metadata populated by the API server so a backed up resource can be re-applied
+func stripServerMetadata(obj metav1.Object) {
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetSelfLink("")
+	obj.SetGeneration(0)
+	obj.SetCreationTimestamp(metav1.Time{})
+}
+
+// resourceDiff returns the unified diff of the YAML of the two resources or an empty string if they are the same
+func resourceDiff(existing metav1.Object, restored metav1.Object) (string, error) {
+	existingYAML, err := yaml.Marshal(existing)
+	if err != nil {
+		return "", err
+	}
+	restoredYAML, err := yaml.Marshal(restored)
+	if err != nil {
+		return "", err
+	}
+	if string(existingYAML) == string(restoredYAML) {
+		return "", nil
+	}
+	return difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        difflib.SplitLines(string(existingYAML)),
+		B:        difflib.SplitLines(string(restoredYAML)),
+		FromFile: fmt.Sprintf("%s (cluster)", existing.GetName()),
+		ToFile:   fmt.Sprintf("%s (backup)", restored.GetName()),
+		Context:  3,
+	})
+}
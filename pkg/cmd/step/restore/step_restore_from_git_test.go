@@ -0,0 +1,191 @@
+// +build unit
+
+package restore
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	jxfake "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/clients/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts/step"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/gits/testhelpers"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const stagingBackup = `apiVersion: jenkins.io/v1
+kind: Environment
+metadata:
+  creationTimestamp: "2020-01-01T00:00:00Z"
+  name: staging
+  namespace: jx
+  resourceVersion: "1234"
+  uid: 5e6b2b5c-3c8e-4b8a-9d0a-2f1c1c1e1e1e
+spec:
+  label: %s
+  namespace: jx-staging
+`
+
+const repositoryBackup = `apiVersion: jenkins.io/v1
+kind: SourceRepository
+metadata:
+  name: myorg-myapp
+  namespace: jx
+spec:
+  org: myorg
+  repo: myapp
+`
+
+func createBackupRepository(t *testing.T) (string, string) {
+	fail := func(message string, _ ...int) {
+		t.Fatal(message)
+	}
+	dir, err := ioutil.TempDir("", "test-restore-from-git-")
+	require.NoError(t, err)
+
+	testhelpers.GitCmd(fail, dir, "init")
+	testhelpers.GitCmd(fail, dir, "config", "user.name", "test")
+	testhelpers.GitCmd(fail, dir, "config", "user.email", "test@example.com")
+
+	testhelpers.WriteFile(fail, dir, "environments/jx/staging.yaml", stagingYAML("Staging January"))
+	testhelpers.WriteFile(fail, dir, "sourcerepositories/jx/myorg-myapp.yaml", repositoryBackup)
+	testhelpers.Add(fail, dir)
+	commitWithDate(t, dir, "January backup", "2020-01-01T12:00:00Z")
+	first := testhelpers.HeadSha(fail, dir)
+
+	testhelpers.WriteFile(fail, dir, "environments/jx/staging.yaml", stagingYAML("Staging February"))
+	testhelpers.Add(fail, dir)
+	commitWithDate(t, dir, "February backup", "2020-02-01T12:00:00Z")
+	return dir, first
+}
+
+func stagingYAML(label string) string {
+	return fmt.Sprintf(stagingBackup, label)
+}
+
+func commitWithDate(t *testing.T, dir string, message string, date string) {
+	err := os.Setenv("GIT_COMMITTER_DATE", date)
+	require.NoError(t, err)
+	defer os.Unsetenv("GIT_COMMITTER_DATE")
+	testhelpers.GitCmd(func(message string, _ ...int) {
+		t.Fatal(message)
+	}, dir, "commit", "-m", message, "--no-gpg-sign", "--date", date)
+}
+
+func newFromGitOptions(dir string, jxClient versioned.Interface) *FromGitOptions {
+	commonOpts := opts.NewCommonOptionsWithFactory(fake.NewFakeFactory())
+	commonOpts.SetJxClient(jxClient)
+	commonOpts.SetDevNamespace("jx")
+	commonOpts.SetGit(gits.NewGitCLI())
+	return &FromGitOptions{
+		StepRestoreOptions: &StepRestoreOptions{
+			StepOptions: step.StepOptions{
+				CommonOptions: &commonOpts,
+			},
+		},
+		Dir: dir,
+	}
+}
+
+func TestRestoreFromGitAtDateIntoAnotherNamespace(t *testing.T) {
+	dir, _ := createBackupRepository(t)
+	defer os.RemoveAll(dir)
+
+	jxClient := jxfake.NewSimpleClientset()
+	o := newFromGitOptions(dir, jxClient)
+	o.Date = "2020-01-15"
+	o.Namespace = "jx-restore"
+	o.SourceNamespace = "jx"
+
+	err := o.Run()
+	require.NoError(t, err)
+
+	env, err := jxClient.JenkinsV1().Environments("jx-restore").Get("staging", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Staging January", env.Spec.Label)
+	assert.Equal(t, "jx-restore", env.Namespace)
+	assert.Equal(t, "", env.ResourceVersion)
+	assert.Equal(t, "", string(env.UID))
+
+	repo, err := jxClient.JenkinsV1().SourceRepositories("jx-restore").Get("myorg-myapp", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "myapp", repo.Spec.Repo)
+}
+
+func TestRestoreFromGitUpdatesExistingResources(t *testing.T) {
+	dir, first := createBackupRepository(t)
+	defer os.RemoveAll(dir)
+
+	existing := &v1.Environment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "staging",
+			Namespace: "jx",
+		},
+		Spec: v1.EnvironmentSpec{
+			Label:     "Staging Broken",
+			Namespace: "jx-staging",
+		},
+	}
+
+	jxClient := jxfake.NewSimpleClientset(existing)
+	o := newFromGitOptions(dir, jxClient)
+	o.Kinds = []string{"environments"}
+	o.DryRun = true
+
+	err := o.Run()
+	require.NoError(t, err)
+
+	env, err := jxClient.JenkinsV1().Environments("jx").Get("staging", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Staging Broken", env.Spec.Label, "a dry run should not change the environment")
+
+	o.DryRun = false
+	err = o.Run()
+	require.NoError(t, err)
+
+	env, err = jxClient.JenkinsV1().Environments("jx").Get("staging", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Staging February", env.Spec.Label)
+
+	_, err = jxClient.JenkinsV1().SourceRepositories("jx").Get("myorg-myapp", metav1.GetOptions{})
+	assert.Error(t, err, "only the environments should be restored")
+
+	o.Commit = first
+	err = o.Run()
+	require.NoError(t, err)
+
+	env, err = jxClient.JenkinsV1().Environments("jx").Get("staging", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "Staging January", env.Spec.Label)
+}
+
+func TestRestoreFromGitInvalidKind(t *testing.T) {
+	o := newFromGitOptions("dummy", jxfake.NewSimpleClientset())
+	o.Kinds = []string{"pipelineactivities"}
+
+	err := o.Run()
+	assert.Error(t, err)
+}
+
+func TestResourceDiff(t *testing.T) {
+	a := &v1.Environment{ObjectMeta: metav1.ObjectMeta{Name: "staging"}, Spec: v1.EnvironmentSpec{Label: "Staging"}}
+	b := a.DeepCopy()
+
+	diff, err := resourceDiff(a, b)
+	require.NoError(t, err)
+	assert.Equal(t, "", diff)
+
+	b.Spec.Label = "Staging Restored"
+	diff, err = resourceDiff(a, b)
+	require.NoError(t, err)
+	assert.Contains(t, diff, "-  label: Staging\n")
+	assert.Contains(t, diff, "+  label: Staging Restored\n")
+}
@@ -0,0 +1,40 @@
+package scan
+
+import (
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts/step"
+	"github.com/spf13/cobra"
+)
+
+// StepScanOptions contains the command line flags
+type StepScanOptions struct {
+	step.StepOptions
+}
+
+// NewCmdStepScan Creates a new Command object
+func NewCmdStepScan(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepScanOptions{
+		StepOptions: step.StepOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:   "scan",
+		Short: "scan [kind]",
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.AddCommand(NewCmdStepScanImage(commonOpts))
+	return cmd
+}
+
+// Run implements this command
+func (o *StepScanOptions) Run() error {
+	return o.Cmd.Help()
+}
@@ -0,0 +1,241 @@
+package scan
+
+import (
+	"fmt"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts/step"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/kube/naming"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	stepScanImageLong = templates.LongDesc(`
+		Scans an image for Common Vulnerabilities and Exposures (CVEs) using the scanner configured in the team settings.
+
+		The number of vulnerabilities of each severity is recorded as a Fact on the PipelineActivity of the build. The step fails if the image has any vulnerability at or above the severity threshold.
+`)
+
+	stepScanImageExample = templates.Examples(`
+		# Scan an image failing if it has any high or critical vulnerabilities
+		jx step scan image --image gcr.io/myorg/myapp:1.2.3
+
+		# Scan an image failing only if it has critical vulnerabilities
+		jx step scan image --image gcr.io/myorg/myapp:1.2.3 --severity critical
+`)
+)
+
+// StepScanImageOptions contains the command line flags
+type StepScanImageOptions struct {
+	step.StepOptions
+
+	Image    string
+	Severity string
+	Pipeline string
+	Build    string
+
+	// CVEProvider the provider used to scan the image instead of the one configured in the team settings
+	CVEProvider cve.CVEProvider
+}
+
+// NewCmdStepScanImage Creates a new Command object
+func NewCmdStepScanImage(commonOpts *opts.CommonOptions) *cobra.Command {
+	options := &StepScanImageOptions{
+		StepOptions: step.StepOptions{
+			CommonOptions: commonOpts,
+		},
+	}
+
+	cmd := &cobra.Command{
+		Use:     "image",
+		Short:   "Scans an image for vulnerabilities failing above a severity threshold",
+		Long:    stepScanImageLong,
+		Example: stepScanImageExample,
+		Run: func(cmd *cobra.Command, args []string) {
+			options.Cmd = cmd
+			options.Args = args
+			err := options.Run()
+			helper.CheckErr(err)
+		},
+	}
+	cmd.Flags().StringVarP(&options.Image, "image", "i", "", "The image to scan")
+	cmd.Flags().StringVarP(&options.Severity, "severity", "s", "", fmt.Sprintf("The lowest severity of vulnerabilities which fail the step. One of: %s. Defaults to the team settings or %s", util.ColorInfo(cve.Severities), cve.SeverityHigh))
+	cmd.Flags().StringVarP(&options.Pipeline, "pipeline", "p", "", "The pipeline name to record the results against. Defaults to the current pipeline")
+	cmd.Flags().StringVarP(&options.Build, "build", "b", "", "The build number to record the results against. Defaults to the current build")
+	return cmd
+}
+
+// Run implements this command
+func (o *StepScanImageOptions) Run() error {
+	if o.Image == "" {
+		return util.MissingOption("image")
+	}
+	jxClient, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return errors.Wrap(err, "cannot create the JX client")
+	}
+
+	settings, err := o.CVEScannerSettings()
+	if err != nil {
+		return err
+	}
+	threshold := settings.Severity
+	if o.Severity != "" {
+		threshold = o.Severity
+	}
+	threshold, err = cve.ParseSeverity(threshold)
+	if err != nil {
+		return err
+	}
+
+	provider := o.CVEProvider
+	if provider == nil {
+		provider, err = o.CreateCVEProvider(settings)
+		if err != nil {
+			return errors.Wrapf(err, "creating the %s CVE provider", settings.Kind)
+		}
+	}
+
+	log.Logger().Infof("Scanning image %s for vulnerabilities", util.ColorInfo(o.Image))
+	vulnerabilities, err := provider.GetImageVulnerabilities(cve.NewImageQuery(o.Image))
+	if err != nil {
+		return errors.Wrapf(err, "scanning image %s", o.Image)
+	}
+
+	failures := 0
+	for _, v := range vulnerabilities {
+		if cve.IsAtOrAbove(v.Severity, threshold) {
+			failures++
+		}
+	}
+	if len(vulnerabilities) > 0 {
+		table := o.CreateTable()
+		table.AddRow("Image", util.ColorInfo("Severity"), "Vulnerability", "URL", "Package", "Fix")
+		cve.AddVulnerabilitiesTableRows(&table, o.Image, vulnerabilities)
+		table.Render()
+	}
+
+	err = o.recordFact(jxClient, ns, vulnerabilities, threshold, failures == 0)
+	if err != nil {
+		return err
+	}
+
+	if failures > 0 {
+		return errors.Errorf("image %s has %d vulnerabilities at or above severity %s", o.Image, failures, threshold)
+	}
+	log.Logger().Infof("Image %s has no vulnerabilities at or above severity %s", util.ColorInfo(o.Image), util.ColorInfo(threshold))
+	return nil
+}
+
+// recordFact creates or updates the Fact recording the scan results against the PipelineActivity of the build
+func (o *StepScanImageOptions) recordFact(jxClient versioned.Interface, ns string, vulnerabilities []cve.Vulnerability, threshold string, passed bool) error {
+	gitInfo, _ := o.FindGitInfo("")
+	appName := ""
+	if gitInfo != nil {
+		appName = gitInfo.Name
+	}
+	pipeline, build := o.GetPipelineName(gitInfo, o.Pipeline, o.Build, appName)
+	if pipeline == "" || build == "" {
+		log.Logger().Warnf("Could not find the current pipeline so not recording the vulnerabilities of image %s", o.Image)
+		return nil
+	}
+
+	apisClient, err := o.ApiExtensionsClient()
+	if err != nil {
+		return errors.Wrap(err, "creating the API extensions client")
+	}
+	err = kube.RegisterFactCRD(apisClient)
+	if err != nil {
+		return errors.Wrap(err, "registering the Fact CRD")
+	}
+
+	key := &kube.PipelineActivityKey{
+		Name:     naming.ToValidName(pipeline + "-" + build),
+		Pipeline: pipeline,
+		Build:    build,
+	}
+	activity, _, err := key.GetOrCreate(jxClient, ns)
+	if err != nil {
+		return errors.Wrapf(err, "getting the PipelineActivity of pipeline %s build %s", pipeline, build)
+	}
+
+	fact := &v1.Fact{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: naming.ToValidName("cve-" + o.Image + "-" + activity.Name),
+			Labels: map[string]string{
+				v1.FactLabelSubjectKind:  "PipelineActivity",
+				v1.FactLabelPipelineName: naming.ToValidLabelValue(activity.Name),
+				v1.FactLabelOrg:          naming.ToValidLabelValue(activity.Spec.GitOwner),
+				v1.FactLabelRepo:         naming.ToValidLabelValue(activity.Spec.GitRepository),
+				v1.FactLabelBranch:       naming.ToValidLabelValue(activity.Spec.GitBranch),
+				v1.FactLabelBuildNumber:  naming.ToValidLabelValue(activity.Spec.Build),
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				{
+					APIVersion: v1.SchemeGroupVersion.String(),
+					Kind:       "PipelineActivity",
+					Name:       activity.Name,
+					UID:        activity.UID,
+				},
+			},
+		},
+		Spec: v1.FactSpec{
+			Name:     o.Image,
+			FactType: v1.FactTypeCVE,
+			Statements: []v1.Statement{
+				{
+					Name:             v1.CVEStatementSeverityThreshold,
+					StatementType:    threshold,
+					MeasurementValue: passed,
+				},
+			},
+			SubjectReference: v1.ResourceReference{
+				APIVersion: v1.SchemeGroupVersion.String(),
+				Kind:       "PipelineActivity",
+				Name:       activity.Name,
+				UID:        activity.UID,
+			},
+		},
+	}
+	counts := cve.CountBySeverity(vulnerabilities)
+	for _, severity := range cve.Severities {
+		fact.Spec.Measurements = append(fact.Spec.Measurements, v1.Measurement{
+			Name:             severity,
+			MeasurementType:  v1.MeasurementCount,
+			MeasurementValue: counts[severity],
+		})
+	}
+
+	facts := jxClient.JenkinsV1().Facts(ns)
+	existing, err := facts.Get(fact.Name, metav1.GetOptions{})
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "getting Fact %s", fact.Name)
+		}
+		_, err = facts.Create(fact)
+		if err != nil {
+			return errors.Wrapf(err, "creating Fact %s", fact.Name)
+		}
+	} else {
+		existing.Labels = fact.Labels
+		existing.OwnerReferences = fact.OwnerReferences
+		existing.Spec = fact.Spec
+		_, err = facts.Update(existing)
+		if err != nil {
+			return errors.Wrapf(err, "updating Fact %s", fact.Name)
+		}
+	}
+	log.Logger().Infof("Recorded the vulnerabilities of image %s in Fact %s", util.ColorInfo(o.Image), util.ColorInfo(fact.Name))
+	return nil
+}
@@ -0,0 +1,138 @@
+// +build unit
+
+package scan
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	jxfake "github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/clients/fake"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
+	"github.com/jenkins-x/jx/v2/pkg/cmd/opts/step"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/jenkins-x/jx/v2/pkg/table"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	apifake "k8s.io/apiextensions-apiserver/pkg/client/clientset/clientset/fake"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/kubernetes"
+)
+
+type stubCVEProvider struct {
+	vulnerabilities []cve.Vulnerability
+	query           cve.CVEQuery
+}
+
+func (p *stubCVEProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query cve.CVEQuery) error {
+	return nil
+}
+
+func (p *stubCVEProvider) GetImageVulnerabilities(query cve.CVEQuery) ([]cve.Vulnerability, error) {
+	p.query = query
+	return p.vulnerabilities, nil
+}
+
+func newStepScanImageOptions(jxClient versioned.Interface, settings *v1.CVEScannerSettings, provider cve.CVEProvider) *StepScanImageOptions {
+	commonOpts := opts.NewCommonOptionsWithFactory(fake.NewFakeFactory())
+	commonOpts.SetJxClient(jxClient)
+	commonOpts.SetDevNamespace("jx")
+	commonOpts.Out = os.Stdout
+	commonOpts.SetAPIExtensionsClient(apifake.NewSimpleClientset())
+	commonOpts.ModifyDevEnvironmentFn = func(callback func(env *v1.Environment) error) error {
+		env := &v1.Environment{}
+		env.Spec.TeamSettings.CVEScanner = settings
+		return callback(env)
+	}
+	return &StepScanImageOptions{
+		StepOptions: step.StepOptions{
+			CommonOptions: &commonOpts,
+		},
+		Image:       "gcr.io/myorg/myapp:1.2.3",
+		Pipeline:    "myorg/myapp/master",
+		Build:       "3",
+		CVEProvider: provider,
+	}
+}
+
+func TestStepScanImageRecordsFact(t *testing.T) {
+	jxClient := jxfake.NewSimpleClientset()
+	provider := &stubCVEProvider{
+		vulnerabilities: []cve.Vulnerability{
+			{Vuln: "CVE-2020-1967", Package: "openssl-1.1.1d", Severity: cve.SeverityHigh},
+			{Vuln: "CVE-2018-25032", Package: "zlib-1.2.11", Severity: cve.SeverityMedium},
+			{Vuln: "CVE-2021-3711", Package: "libssl-1.1.1k", Severity: cve.SeverityMedium},
+		},
+	}
+	o := newStepScanImageOptions(jxClient, &v1.CVEScannerSettings{Kind: cve.ProviderKindTrivy, Severity: cve.SeverityCritical}, provider)
+
+	err := o.Run()
+	require.NoError(t, err, "no vulnerability is above the critical threshold of the team settings")
+	assert.Equal(t, "gcr.io/myorg/myapp", provider.query.ImageName)
+	assert.Equal(t, "1.2.3", provider.query.Vesion)
+
+	facts, err := jxClient.JenkinsV1().Facts("jx").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, facts.Items, 1)
+	fact := facts.Items[0]
+	assert.Equal(t, v1.FactTypeCVE, fact.Spec.FactType)
+	assert.Equal(t, "gcr.io/myorg/myapp:1.2.3", fact.Spec.Name)
+	assert.Equal(t, "PipelineActivity", fact.Spec.SubjectReference.Kind)
+	assert.Equal(t, "myorg-myapp-master-3", fact.Spec.SubjectReference.Name)
+	assert.Equal(t, "myorg-myapp-master-3", fact.Labels[v1.FactLabelPipelineName])
+	assert.Equal(t, "3", fact.Labels[v1.FactLabelBuildNumber])
+
+	counts := map[string]int{}
+	for _, m := range fact.Spec.Measurements {
+		assert.Equal(t, v1.MeasurementCount, m.MeasurementType)
+		counts[m.Name] = m.MeasurementValue
+	}
+	assert.Equal(t, map[string]int{"Unknown": 0, "Negligible": 0, "Low": 0, "Medium": 2, "High": 1, "Critical": 0}, counts)
+	require.Len(t, fact.Spec.Statements, 1)
+	assert.Equal(t, v1.CVEStatementSeverityThreshold, fact.Spec.Statements[0].Name)
+	assert.Equal(t, cve.SeverityCritical, fact.Spec.Statements[0].StatementType)
+	assert.True(t, fact.Spec.Statements[0].MeasurementValue)
+
+	// rescanning with a lower threshold fails and updates the fact
+	o.Severity = "high"
+	err = o.Run()
+	assert.Error(t, err)
+
+	facts, err = jxClient.JenkinsV1().Facts("jx").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, facts.Items, 1)
+	assert.Equal(t, cve.SeverityHigh, facts.Items[0].Spec.Statements[0].StatementType)
+	assert.False(t, facts.Items[0].Spec.Statements[0].MeasurementValue)
+}
+
+func TestStepScanImageInvalidSeverity(t *testing.T) {
+	o := newStepScanImageOptions(jxfake.NewSimpleClientset(), nil, &stubCVEProvider{})
+	o.Severity = "severe"
+
+	err := o.Run()
+	assert.Error(t, err)
+}
+
+func TestStepScanImageRecordsFactWithValidLabels(t *testing.T) {
+	jxClient := jxfake.NewSimpleClientset()
+	o := newStepScanImageOptions(jxClient, nil, &stubCVEProvider{})
+	o.Pipeline = "My_Org/" + strings.Repeat("my-app", 12) + "/master"
+
+	err := o.Run()
+	require.NoError(t, err)
+
+	facts, err := jxClient.JenkinsV1().Facts("jx").List(metav1.ListOptions{})
+	require.NoError(t, err)
+	require.Len(t, facts.Items, 1)
+	labels := facts.Items[0].Labels
+	for _, label := range []string{v1.FactLabelPipelineName, v1.FactLabelOrg, v1.FactLabelRepo, v1.FactLabelBranch, v1.FactLabelBuildNumber} {
+		assert.NotEmpty(t, labels[label], label)
+		assert.Empty(t, validation.IsValidLabelValue(labels[label]), label)
+	}
+	assert.Equal(t, "My-Org", labels[v1.FactLabelOrg])
+	assert.Equal(t, "master", labels[v1.FactLabelBranch])
+}
@@ -669,6 +669,9 @@ func (o *StepChangelogOptions) addCommit(spec *v1.ReleaseSpec, commit *object.Co
 		Branch:    branch,
 		Committer: &committerDetails,
 	}
+	if !commit.Committer.When.IsZero() {
+		commitSummary.Timestamp = &metav1.Time{Time: commit.Committer.When}
+	}
 
 	err = o.addIssuesAndPullRequests(spec, &commitSummary, commit)
 	if err != nil {
@@ -691,7 +694,7 @@ func (o *StepChangelogOptions) addIssuesAndPullRequests(spec *v1.ReleaseSpec, co
 		o.State.LoggedIssueKind = true
 		log.Logger().Infof("Finding issues in commit messages using %s format", issueKind)
 	}
-	if issueKind == issues.Jira {
+	if issueKind == issues.Jira || issueKind == issues.Linear {
 		regex = JIRAIssueRegex
 	}
 	message := fullCommitMessageText(rawCommit)
@@ -2,6 +2,7 @@ package step
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts/step"
@@ -30,6 +31,7 @@ type StepNextBuildNumberOptions struct {
 	Owner      string
 	Repository string
 	Branch     string
+	Issuer     string
 }
 
 var (
@@ -64,6 +66,8 @@ func NewCmdStepNextBuildNumber(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVarP(&options.Owner, optionOwner, "o", "", "The Git repository owner")
 	cmd.Flags().StringVarP(&options.Repository, optionRepo, "r", "", "The Git repository name")
 	cmd.Flags().StringVarP(&options.Branch, optionBranch, "", "master", "The Git branch")
+	cmd.Flags().StringVarP(&options.Issuer, "issuer", "", buildnum.IssuerActivity,
+		fmt.Sprintf("The kind of build number issuer to use. One of: %s", strings.Join(buildnum.BuildNumberIssuers, ", ")))
 	return cmd
 }
 
@@ -79,7 +83,14 @@ func (o *StepNextBuildNumberOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	buildNumGen := buildnum.NewCRDBuildNumGen(jxClient, ns)
+	kubeClient, err := o.KubeClient()
+	if err != nil {
+		return err
+	}
+	buildNumGen, err := buildnum.NewBuildNumberIssuer(o.Issuer, kubeClient, jxClient, ns)
+	if err != nil {
+		return err
+	}
 
 	pID := kube.NewPipelineID(o.Owner, o.Repository, o.Branch)
 
@@ -224,8 +224,7 @@ func (o *StepStashOptions) Run() error {
 		log.Logger().Infof("stashed: %s", util.ColorInfo(u))
 	}
 
-	// TODO this pipeline name construction needs moving to a shared lib, and other things refactoring to use it
-	pipeline := fmt.Sprintf("%s-%s-%s-%s", projectOrg, projectRepoName, projectBranchName, buildNo)
+	pipeline := stashPipelineName(projectOrg, projectRepoName, projectBranchName, buildNo)
 
 	if pipeline != "" && buildNo != "" {
 		name := naming.ToValidName(pipeline)
@@ -256,6 +255,12 @@ func (o *StepStashOptions) Run() error {
 	return nil
 }
 
+// stashPipelineName returns the name of the pipeline that the attachments of stashed files are recorded against
+// TODO this pipeline name construction needs moving to a shared lib, and other things refactoring to use it
+func stashPipelineName(projectOrg string, projectRepoName string, projectBranchName string, buildNo string) string {
+	return fmt.Sprintf("%s-%s-%s-%s", projectOrg, projectRepoName, projectBranchName, buildNo)
+}
+
 func (o *StepStashOptions) determineProjectBranchName(projectBranchName string, gitURL string) (string, error) {
 	if projectBranchName != "" {
 		return projectBranchName, nil
@@ -5,6 +5,8 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
 	"strings"
 	"time"
@@ -13,24 +15,32 @@ import (
 
 	"github.com/jenkins-x/jx/v2/pkg/cmd/helper"
 
+	jenkinsv1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/builds"
 	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/opts"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/templates"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/kube/naming"
 	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/tekton/syntax"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/pkg/errors"
 	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 // StepUnstashOptions contains the command line flags
 type StepUnstashOptions struct {
 	step.StepOptions
 
-	URL     string
-	OutDir  string
-	Timeout time.Duration
+	URL           string
+	OutDir        string
+	Timeout       time.Duration
+	Stash         string
+	ProjectGitURL string
+	ProjectBranch string
 }
 
 var (
@@ -44,6 +54,9 @@ var (
 
 		# unstash the file to the from GCS to the console
 		jx step unstash -u gs://mybucket/foo/bar/output.log
+
+		# unstash the archive of a stash made by an earlier stage of the current pipeline
+		jx step unstash --stash binaries -o /workspace/.jx-stash/binaries.tar.gz
 `)
 )
 
@@ -70,6 +83,9 @@ func NewCmdStepUnstash(commonOpts *opts.CommonOptions) *cobra.Command {
 	cmd.Flags().StringVarP(&options.URL, "url", "u", "", "The fully qualified URL to the file to unstash including the storage host, path and file name")
 	cmd.Flags().StringVarP(&options.OutDir, "output", "o", "", "The output file or directory")
 	cmd.Flags().DurationVarP(&options.Timeout, "timeout", "t", time.Second*30, "The timeout period before we should fail unstashing the entry")
+	cmd.Flags().StringVarP(&options.Stash, "stash", "", "", "The name of a stash made by an earlier stage of the current pipeline to unstash instead of a URL")
+	cmd.Flags().StringVarP(&options.ProjectGitURL, "project-git-url", "", "", "The project git URL of the pipeline which made the stash. Defaults to the $SOURCE_URL environment variable")
+	cmd.Flags().StringVarP(&options.ProjectBranch, "project-branch", "", "", "The project git branch of the pipeline which made the stash. Defaults to the $BRANCH_NAME environment variable")
 	return cmd
 }
 
@@ -79,7 +95,72 @@ func (o *StepUnstashOptions) Run() error {
 	if err != nil {
 		return err
 	}
-	return Unstash(o.URL, o.OutDir, o.Timeout, authSvc)
+	u := o.URL
+	if u == "" && o.Stash != "" {
+		u, err = o.findStashURL()
+		if err != nil {
+			return err
+		}
+	}
+	return Unstash(u, o.OutDir, o.Timeout, authSvc)
+}
+
+// findStashURL finds the URL of the archive of the stash from the attachments of the current pipeline activity
+func (o *StepUnstashOptions) findStashURL() (string, error) {
+	gitURL := o.ProjectGitURL
+	if gitURL == "" {
+		gitURL = os.Getenv(envVarSourceURL)
+	}
+	if gitURL == "" {
+		return "", util.MissingOption("project-git-url")
+	}
+	gitInfo, err := gits.ParseGitURL(gitURL)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to parse the git URL %s", gitURL)
+	}
+	branch := o.ProjectBranch
+	if branch == "" {
+		branch = os.Getenv(util.EnvVarBranchName)
+	}
+	if branch == "" {
+		return "", util.MissingOption("project-branch")
+	}
+	buildNo := builds.GetBuildNumber()
+	if buildNo == "" {
+		return "", fmt.Errorf("could not find the build number of the current pipeline")
+	}
+
+	client, ns, err := o.JXClientAndDevNamespace()
+	if err != nil {
+		return "", errors.Wrap(err, "cannot create the JX client")
+	}
+	name := naming.ToValidName(stashPipelineName(gitInfo.Organisation, gitInfo.Name, branch, buildNo))
+	activity, err := client.JenkinsV1().PipelineActivities(ns).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to find the PipelineActivity %s", name)
+	}
+	u := FindStashURL(activity, o.Stash)
+	if u == "" {
+		return "", fmt.Errorf("no stash %s has been made by PipelineActivity %s", o.Stash, name)
+	}
+	return u, nil
+}
+
+// FindStashURL returns the URL of the archive of the named stash attached to the activity or an empty string if
+// there is no such stash
+func FindStashURL(activity *jenkinsv1.PipelineActivity, stash string) string {
+	archive := syntax.StashArchiveName(stash)
+	for _, a := range activity.Spec.Attachments {
+		if a.Name != syntax.StashClassifier {
+			continue
+		}
+		for _, u := range a.URLs {
+			if path.Base(u) == archive {
+				return u
+			}
+		}
+	}
+	return ""
 }
 
 func Unstash(u string, outDir string, timeout time.Duration, authSvc auth.ConfigService) error {
@@ -9,6 +9,7 @@ import (
 	"path"
 	"testing"
 
+	jenkinsv1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/cmd/step"
 	"github.com/jenkins-x/jx/v2/pkg/kube"
@@ -119,3 +120,30 @@ func TestCreateBucketHTTPFn(t *testing.T) {
 		})
 	}
 }
+
+func TestFindStashURL(t *testing.T) {
+	t.Parallel()
+
+	activity := &jenkinsv1.PipelineActivity{
+		Spec: jenkinsv1.PipelineActivitySpec{
+			Attachments: []jenkinsv1.Attachment{
+				{
+					Name: "tests",
+					URLs: []string{"gs://mybucket/jenkins-x/tests/myorg/myrepo/master/3/binaries.tar.gz"},
+				},
+				{
+					Name: "stash",
+					URLs: []string{"gs://mybucket/jenkins-x/stash/myorg/myrepo/master/3/reports.tar.gz"},
+				},
+				{
+					Name: "stash",
+					URLs: []string{"gs://mybucket/jenkins-x/stash/myorg/myrepo/master/3/binaries.tar.gz"},
+				},
+			},
+		},
+	}
+
+	assert.Equal(t, "gs://mybucket/jenkins-x/stash/myorg/myrepo/master/3/binaries.tar.gz", step.FindStashURL(activity, "binaries"))
+	assert.Equal(t, "gs://mybucket/jenkins-x/stash/myorg/myrepo/master/3/reports.tar.gz", step.FindStashURL(activity, "Reports"))
+	assert.Equal(t, "", step.FindStashURL(activity, "coverage"))
+}
@@ -474,8 +474,9 @@ func (o *StepVerifyPreInstallOptions) VerifyInstallConfig(kubeClient kubernetes.
 	_, err := kube.DefaultModifyConfigMap(kubeClient, ns, kube.ConfigMapNameJXInstallConfig,
 		func(configMap *corev1.ConfigMap) error {
 			secretsLocation := string(secrets.FileSystemLocationKind)
-			if requirements.SecretStorage == config.SecretStorageTypeVault {
-				secretsLocation = string(secrets.VaultLocationKind)
+			switch requirements.SecretStorage {
+			case config.SecretStorageTypeVault, config.SecretStorageTypeKubernetes, config.SecretStorageTypeSops:
+				secretsLocation = string(secrets.ToSecretsLocation(string(requirements.SecretStorage)))
 			}
 			modifyMapIfNotBlank(configMap.Data, kube.KubeProvider, requirements.Cluster.Provider)
 			modifyMapIfNotBlank(configMap.Data, kube.ProjectID, requirements.Cluster.ProjectID)
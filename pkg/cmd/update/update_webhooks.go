@@ -226,6 +226,10 @@ func (o *UpdateWebhooksOptions) updateRepoHook(git gits.GitProvider, owner strin
 				log.Logger().Infof("Found matching hook for url %s", util.ColorInfo(webHook.URL))
 				webHookArgs.ID = webHook.ID
 				webHookArgs.ExistingURL = o.PreviousHookUrl
+				if webHookArgs.ExistingURL == "" {
+					// providers which don't expose numeric webhook IDs locate the hook by its URL
+					webHookArgs.ExistingURL = webHook.URL
+				}
 				if !o.DryRun {
 					if err := git.UpdateWebHook(webHookArgs); err != nil {
 						return errors.Wrapf(err, "updating the webhook %q on repository '%s/%s'",
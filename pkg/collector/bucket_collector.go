@@ -3,7 +3,9 @@ package collector
 import (
 	"bytes"
 	"io/ioutil"
+	"net/url"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
@@ -74,3 +76,42 @@ func (c *BucketCollector) CollectData(data []byte, outputName string) (string, e
 	}
 	return url, nil
 }
+
+// ListArtifacts lists the artifacts in the bucket whose paths start with the given prefix
+func (c *BucketCollector) ListArtifacts(prefix string) ([]Artifact, error) {
+	objects, err := c.provider.ListObjects(c.bucketURL, prefix)
+	if err != nil {
+		return nil, err
+	}
+	// the keys include any path of the bucket URL whereas the artifact paths are relative to it
+	bucketPath := ""
+	u, err := url.Parse(c.bucketURL)
+	if err == nil {
+		bucketPath = strings.Trim(u.Path, "/")
+	}
+	answer := []Artifact{}
+	for _, obj := range objects {
+		artifactPath := obj.Key
+		if bucketPath != "" {
+			artifactPath = strings.TrimPrefix(strings.TrimPrefix(artifactPath, bucketPath), "/")
+		}
+		answer = append(answer, Artifact{
+			Path:    artifactPath,
+			URL:     obj.URL,
+			Size:    obj.Size,
+			ModTime: obj.ModTime,
+		})
+	}
+	return answer, nil
+}
+
+// DeleteArtifacts deletes the artifacts with the given paths from the bucket
+func (c *BucketCollector) DeleteArtifacts(paths []string) error {
+	for _, p := range paths {
+		err := c.provider.DeleteObject(buckets.ObjectURL(c.bucketURL, p))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -10,7 +10,9 @@ import (
 	"path"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/jenkins-x/jx/v2/pkg/cloud/buckets"
 	buckets_test "github.com/jenkins-x/jx/v2/pkg/cloud/buckets/mocks"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/petergtz/pegomock"
@@ -64,3 +66,39 @@ func TestBucketCollector_CollectFiles(t *testing.T) {
 	assert.Len(t, urls, 1)
 	assert.Equal(t, fmt.Sprintf("%s/%s", bucketURL, "example/example.txt"), urls[0], "There needs to be a URL pointing to the only file uploaded")
 }
+
+func TestBucketCollector_ListAndDeleteArtifacts(t *testing.T) {
+	pegomock.RegisterMockTestingT(t)
+	bucketURL := "gs://bucketName/storage"
+	modTime := time.Now()
+
+	mp := buckets_test.NewMockProvider()
+	pegomock.When(mp.ListObjects(bucketURL, "jenkins-x/")).ThenReturn([]buckets.ObjectAttributes{
+		{
+			Key:     "storage/jenkins-x/logs/myorg/myapp/master/1.log",
+			URL:     "gs://bucketName/storage/jenkins-x/logs/myorg/myapp/master/1.log",
+			Size:    10,
+			ModTime: modTime,
+		},
+	}, nil)
+
+	collector := BucketCollector{
+		bucketURL: bucketURL,
+		provider:  mp,
+	}
+
+	artifacts, err := collector.ListArtifacts("jenkins-x/")
+	assert.NoError(t, err)
+	assert.Equal(t, []Artifact{
+		{
+			Path:    "jenkins-x/logs/myorg/myapp/master/1.log",
+			URL:     "gs://bucketName/storage/jenkins-x/logs/myorg/myapp/master/1.log",
+			Size:    10,
+			ModTime: modTime,
+		},
+	}, artifacts)
+
+	err = collector.DeleteArtifacts([]string{artifacts[0].Path})
+	assert.NoError(t, err)
+	mp.VerifyWasCalledOnce().DeleteObject("gs://bucketName/storage/jenkins-x/logs/myorg/myapp/master/1.log")
+}
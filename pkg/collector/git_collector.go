@@ -161,7 +161,14 @@ func (c *GitCollector) CollectData(data []byte, outputPath string) (string, erro
 	return u, err
 }
 
-func (c *GitCollector) generateURL(storageOrg string, storageRepoName string, rPath string) (url string) {
+func (c *GitCollector) generateURL(storageOrg string, storageRepoName string, rPath string) string {
+	url := c.artifactURL(storageOrg, storageRepoName, rPath)
+	log.Logger().Infof("Publishing %s", util.ColorInfo(url))
+	return url
+}
+
+// artifactURL returns the URL to access the file at the given path of the storage branch
+func (c *GitCollector) artifactURL(storageOrg string, storageRepoName string, rPath string) (url string) {
 	if !c.gitInfo.IsGitHub() && c.gitKind == gits.KindGitHub {
 		url = fmt.Sprintf("https://%s/raw/%s/%s/%s/%s", c.gitInfo.Host, storageOrg, storageRepoName, c.gitBranch, rPath)
 	} else {
@@ -179,10 +186,131 @@ func (c *GitCollector) generateURL(storageOrg string, storageRepoName string, rP
 			url = fmt.Sprintf("https://raw.githubusercontent.com/%s/%s/%s/%s", storageOrg, storageRepoName, c.gitBranch, rPath)
 		}
 	}
-	log.Logger().Infof("Publishing %s", util.ColorInfo(url))
 	return url
 }
 
+// ListArtifacts lists the files committed to the storage branch whose paths start with the given prefix
+func (c *GitCollector) ListArtifacts(prefix string) ([]Artifact, error) {
+	answer := []Artifact{}
+	dir, exists, err := c.cloneBranchToTempDir()
+	if err != nil {
+		return answer, err
+	}
+	defer os.RemoveAll(dir)
+	if !exists {
+		return answer, nil
+	}
+
+	logPath := "."
+	idx := strings.LastIndex(prefix, "/")
+	if idx > 0 {
+		logPath = prefix[:idx]
+	}
+	commitTimes, err := c.gitter.GetLatestCommitTimes(dir, logPath)
+	if err != nil {
+		return answer, err
+	}
+
+	err = filepath.Walk(dir, func(name string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to remove dir %s from %s", dir, name)
+		}
+		rPath := filepath.ToSlash(rel)
+		if !strings.HasPrefix(rPath, prefix) {
+			return nil
+		}
+		answer = append(answer, Artifact{
+			Path:    rPath,
+			URL:     c.artifactURL(c.gitInfo.Organisation, c.gitInfo.Name, rPath),
+			Size:    info.Size(),
+			ModTime: commitTimes[rPath],
+		})
+		return nil
+	})
+	if err != nil {
+		return answer, errors.Wrapf(err, "failed to list the files of branch %s of %s", c.gitBranch, c.gitInfo.URL)
+	}
+	return answer, nil
+}
+
+// DeleteArtifacts removes the files with the given paths from the storage branch with a single commit
+func (c *GitCollector) DeleteArtifacts(paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	gitClient := c.gitter
+	dir, exists, err := c.cloneBranchToTempDir()
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if !exists {
+		return nil
+	}
+
+	for _, p := range paths {
+		err = os.Remove(filepath.Join(dir, filepath.FromSlash(p)))
+		if err != nil && !os.IsNotExist(err) {
+			return errors.Wrapf(err, "failed to remove file %s", p)
+		}
+	}
+	err = gitClient.Add(dir, "-A")
+	if err != nil {
+		return err
+	}
+	changes, err := gitClient.HasChanges(dir)
+	if err != nil {
+		return err
+	}
+	if !changes {
+		return nil
+	}
+	err = gitClient.CommitDir(dir, fmt.Sprintf("Removing %d files", len(paths)))
+	if err != nil {
+		return err
+	}
+	return gitClient.Push(dir, "origin", false, "HEAD:"+c.gitBranch)
+}
+
+// cloneBranchToTempDir clones only the storage branch with its history to a temp dir returning false if the branch
+// does not exist yet
+func (c *GitCollector) cloneBranchToTempDir() (string, bool, error) {
+	dir, err := ioutil.TempDir("", "jenkins-x-collect")
+	if err != nil {
+		return dir, false, err
+	}
+	log.Logger().Debugf("cloning %s branch %s", c.gitInfo.URL, c.gitBranch)
+	err = c.gitter.Init(dir)
+	if err != nil {
+		return dir, false, errors.Wrapf(err, "failed to init a new git repository in directory %s", dir)
+	}
+	err = c.gitter.AddRemote(dir, "origin", c.gitInfo.URL)
+	if err != nil {
+		return dir, false, errors.Wrapf(err, "failed to add remote origin with url %s in directory %s", c.gitInfo.URL, dir)
+	}
+	refspec := fmt.Sprintf("+refs/heads/%s:refs/remotes/origin/%s", c.gitBranch, c.gitBranch)
+	err = c.gitter.FetchBranch(dir, "origin", refspec)
+	if err != nil {
+		log.Logger().Warnf("failed to fetch %s branch %s so assuming it does not exist yet: %s", c.gitInfo.URL, c.gitBranch, err.Error())
+		return dir, false, nil
+	}
+	err = c.gitter.Checkout(dir, c.gitBranch)
+	if err != nil {
+		return dir, false, errors.Wrapf(err, "failed to checkout branch %s of %s", c.gitBranch, c.gitInfo.URL)
+	}
+	return dir, true, nil
+}
+
 // cloneGitHubPagesBranchToTempDir clones the github pages branch to a temp dir
 func cloneGitHubPagesBranchToTempDir(sourceURL string, gitClient gits.Gitter, branchName string) (string, error) {
 	// First clone the git repo
@@ -1,5 +1,19 @@
 package collector
 
+import "time"
+
+// Artifact a file previously stored by a collector
+type Artifact struct {
+	// Path the path of the artifact relative to the root of the storage
+	Path string
+	// URL the URL to access the artifact
+	URL string
+	// Size the size of the artifact in bytes
+	Size int64
+	// ModTime the time the artifact was last stored, which is zero if it is unknown
+	ModTime time.Time
+}
+
 // Collector an interface to collect data for storage in git or cloud storage etc
 type Collector interface {
 
@@ -10,4 +24,10 @@ type Collector interface {
 	// CollectData collects the data storing it at the given output path and returning the URL
 	// to access it
 	CollectData(data []byte, outputPath string) (string, error)
+
+	// ListArtifacts lists the artifacts in the storage whose paths start with the given prefix
+	ListArtifacts(prefix string) ([]Artifact, error)
+
+	// DeleteArtifacts removes the artifacts with the given paths from the storage
+	DeleteArtifacts(paths []string) error
 }
@@ -135,10 +135,15 @@ const (
 	// SecretStorageTypeLocal specifies that we use the local file system in
 	// `~/.jx/localSecrets` to store secrets
 	SecretStorageTypeLocal SecretStorageType = "local"
+	// SecretStorageTypeKubernetes specifies that we use Kubernetes Secrets in the
+	// development namespace to store secrets
+	SecretStorageTypeKubernetes SecretStorageType = "kubernetes"
+	// SecretStorageTypeSops specifies that we use SOPS encrypted files to store secrets
+	SecretStorageTypeSops SecretStorageType = "sops"
 )
 
 // SecretStorageTypeValues the string values for the secret storage
-var SecretStorageTypeValues = []string{"local", "vault"}
+var SecretStorageTypeValues = []string{"kubernetes", "local", "sops", "vault"}
 
 // WebhookType is the type of a webhook strategy
 type WebhookType string
@@ -308,6 +313,9 @@ type AzureConfig struct {
 	// RegistrySubscription the registry subscription for defaulting the container registry.
 	// Not used if you specify a Registry explicitly
 	RegistrySubscription string `json:"registrySubscription,omitempty"`
+	// StorageAccountName the storage account used for the Azure Blob Storage containers.
+	// Defaults to the AZURE_STORAGE_ACCOUNT environment variable
+	StorageAccountName string `json:"storageAccountName,omitempty"`
 }
 
 // GKEConfig contains GKE specific requirements
@@ -115,24 +115,9 @@ func (a AnchoreProvider) GetImageVulnerabilityTable(jxClient versioned.Interface
 		// if we have an image name then lets try and match image id(s)
 		if query.ImageName != "" {
 
-			var images []Image
-			subPath := fmt.Sprintf(GetImages)
-
-			err = a.AnchoreGet(subPath, &images)
+			imageIDs, err = a.findImageIDs(query)
 			if err != nil {
-				return fmt.Errorf("error getting images %v", err)
-			}
-
-			for _, image := range images {
-				for _, d := range image.ImageDetails {
-					if d.Repo == query.ImageName {
-						// if user has provided a version and it doesn't match lets skip this image
-						if query.Vesion != "" && query.Vesion != d.Tag {
-							continue
-						}
-						imageIDs = append(imageIDs, d.ImageId)
-					}
-				}
+				return err
 			}
 			if len(imageIDs) > 0 {
 				err = a.getCVEsFromImageList(table, &vList, imageIDs)
@@ -186,19 +171,60 @@ func (a AnchoreProvider) addVulnerabilitiesTableRows(table *table.Table, vList *
 	}
 	// TODO sort vList on severity and version?
 
-	for _, v := range vList.Vulnerabilities {
-		var sev string
-		switch v.Severity {
-		case "High":
-			sev = util.ColorError(v.Severity)
-		case "Medium":
-			sev = util.ColorWarning(v.Severity)
-		case "Low":
-			sev = util.ColorStatus(v.Severity)
+	AddVulnerabilitiesTableRows(table, image[0].ImageDetails[0].Fulltag, vList.Vulnerabilities)
+	return nil
+}
+
+// GetImageVulnerabilities returns the OS vulnerabilities of the image with the query's image ID, or of the analysed
+// images matching the query's image name and version
+func (a AnchoreProvider) GetImageVulnerabilities(query CVEQuery) ([]Vulnerability, error) {
+	imageIDs := []string{query.ImageID}
+	if query.ImageID == "" {
+		var err error
+		imageIDs, err = a.findImageIDs(query)
+		if err != nil {
+			return nil, err
+		}
+		if len(imageIDs) == 0 {
+			return nil, fmt.Errorf("no matching images found for ImageName %s and Vesion %s", query.ImageName, query.Vesion)
 		}
-		table.AddRow(image[0].ImageDetails[0].Fulltag, sev, v.Vuln, v.URL, v.Package, v.Fix)
 	}
-	return nil
+
+	var answer []Vulnerability
+	for _, imageID := range imageIDs {
+		var vList VulnerabilityList
+		subPath := fmt.Sprintf(getVulnerabilitiesByImageID, imageID, vulnerabilityType)
+
+		err := a.AnchoreGet(subPath, &vList)
+		if err != nil {
+			return nil, fmt.Errorf("error getting vulnerabilities for image %s: %v", imageID, err)
+		}
+		answer = append(answer, vList.Vulnerabilities...)
+	}
+	return answer, nil
+}
+
+// findImageIDs returns the IDs of the analysed images matching the query's image name and optional version
+func (a AnchoreProvider) findImageIDs(query CVEQuery) ([]string, error) {
+	var images []Image
+	err := a.AnchoreGet(GetImages, &images)
+	if err != nil {
+		return nil, fmt.Errorf("error getting images %v", err)
+	}
+
+	var imageIDs []string
+	for _, image := range images {
+		for _, d := range image.ImageDetails {
+			if d.Repo == query.ImageName {
+				// if user has provided a version and it doesn't match lets skip this image
+				if query.Vesion != "" && query.Vesion != d.Tag {
+					continue
+				}
+				imageIDs = append(imageIDs, d.ImageId)
+			}
+		}
+	}
+	return imageIDs, nil
 }
 
 func (a AnchoreProvider) getCVEsFromImageList(table *table.Table, vList *VulnerabilityList, ids []string) error {
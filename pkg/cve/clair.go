@@ -0,0 +1,215 @@
+package cve
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/google/go-containerregistry/pkg/authn"
+	"github.com/google/go-containerregistry/pkg/name"
+	"github.com/google/go-containerregistry/pkg/v1/remote"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/v2/pkg/table"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+const (
+	clairIndexReportPath         = "/indexer/api/v1/index_report"
+	clairVulnerabilityReportPath = "/matcher/api/v1/vulnerability_report/%s"
+	clairIndexErrorState         = "IndexError"
+)
+
+// ClairManifest the manifest of an image submitted to the Clair indexer
+type ClairManifest struct {
+	Hash   string       `json:"hash"`
+	Layers []ClairLayer `json:"layers"`
+}
+
+// ClairLayer a layer of an image which the Clair indexer fetches from the URI using the headers
+type ClairLayer struct {
+	Hash    string              `json:"hash"`
+	URI     string              `json:"uri"`
+	Headers map[string][]string `json:"headers,omitempty"`
+}
+
+type clairIndexReport struct {
+	ManifestHash string `json:"manifest_hash"`
+	State        string `json:"state"`
+	Success      bool   `json:"success"`
+	Err          string `json:"err"`
+}
+
+type clairVulnerabilityReport struct {
+	ManifestHash    string                        `json:"manifest_hash"`
+	Vulnerabilities map[string]clairVulnerability `json:"vulnerabilities"`
+}
+
+type clairVulnerability struct {
+	Name               string       `json:"name"`
+	Links              string       `json:"links"`
+	NormalizedSeverity string       `json:"normalized_severity"`
+	FixedInVersion     string       `json:"fixed_in_version"`
+	Package            clairPackage `json:"package"`
+}
+
+type clairPackage struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// ClairProvider implements CVEProvider interface for Clair v4 by indexing the manifest of an image and then getting
+// its vulnerability report from the matcher
+type ClairProvider struct {
+	Client   *http.Client
+	Username string
+	Password string
+	BaseURL  string
+	// ManifestResolver resolves the manifest of an image, defaulting to reading it from its registry
+	ManifestResolver func(image string) (*ClairManifest, error)
+}
+
+// NewClairProvider creates a provider using the Clair server, authenticating as the user if it has a password
+func NewClairProvider(server *auth.AuthServer, user *auth.UserAuth) (CVEProvider, error) {
+	provider := ClairProvider{
+		BaseURL:          strings.TrimSuffix(server.URL, "/"),
+		Client:           util.GetClient(),
+		ManifestResolver: ResolveRegistryManifest,
+	}
+	if user != nil && user.Password != "" {
+		provider.Username = user.Username
+		provider.Password = user.Password
+	}
+	return &provider, nil
+}
+
+// GetImageVulnerabilityTable adds the vulnerabilities of the queried image, or of the images running in the queried
+// environment, to the table
+func (c *ClairProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error {
+	return addQueryImagesTableRows(client, table, query, c.scan)
+}
+
+// GetImageVulnerabilities returns the vulnerabilities of the image with the query's image name and version
+func (c *ClairProvider) GetImageVulnerabilities(query CVEQuery) ([]Vulnerability, error) {
+	image, err := query.ImageReference()
+	if err != nil {
+		return nil, err
+	}
+	return c.scan(image)
+}
+
+func (c *ClairProvider) scan(image string) ([]Vulnerability, error) {
+	manifest, err := c.ManifestResolver(image)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving the manifest of image %s", image)
+	}
+
+	indexReport := clairIndexReport{}
+	err = c.do(http.MethodPost, clairIndexReportPath, manifest, &indexReport)
+	if err != nil {
+		return nil, errors.Wrapf(err, "indexing image %s", image)
+	}
+	if indexReport.State == clairIndexErrorState {
+		return nil, errors.Errorf("failed to index image %s: %s", image, indexReport.Err)
+	}
+
+	report := clairVulnerabilityReport{}
+	err = c.do(http.MethodGet, fmt.Sprintf(clairVulnerabilityReportPath, manifest.Hash), nil, &report)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting the vulnerability report of image %s", image)
+	}
+
+	ids := []string{}
+	for id := range report.Vulnerabilities {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	var answer []Vulnerability
+	for _, id := range ids {
+		v := report.Vulnerabilities[id]
+		pkg := v.Package.Name
+		if v.Package.Version != "" {
+			pkg += "-" + v.Package.Version
+		}
+		url := ""
+		// links are separated by spaces
+		links := strings.Fields(v.Links)
+		if len(links) > 0 {
+			url = links[0]
+		}
+		answer = append(answer, Vulnerability{
+			Vuln:     v.Name,
+			Package:  pkg,
+			Fix:      v.FixedInVersion,
+			Severity: NormalizeSeverity(v.NormalizedSeverity),
+			URL:      url,
+		})
+	}
+	return answer, nil
+}
+
+func (c *ClairProvider) do(method string, subPath string, body interface{}, result interface{}) error {
+	var headers map[string]string
+	if c.Password != "" {
+		headers = map[string]string{
+			"Authorization": "Basic " + util.BasicAuth(c.Username, c.Password),
+		}
+	}
+	return util.DoJSON(c.Client, method, c.BaseURL+subPath, headers, body, result)
+}
+
+// ResolveRegistryManifest reads the manifest of the image from its registry using the docker credentials of the
+// current user, which are passed on to Clair so it can fetch the layers of private images
+func ResolveRegistryManifest(image string) (*ClairManifest, error) {
+	ref, err := name.ParseReference(image, name.WeakValidation)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing image reference %s", image)
+	}
+	repository := ref.Context()
+	authenticator, err := authn.DefaultKeychain.Resolve(repository.Registry)
+	if err != nil {
+		return nil, errors.Wrapf(err, "resolving the credentials of registry %s", repository.RegistryStr())
+	}
+	img, err := remote.Image(ref, remote.WithAuth(authenticator))
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading image %s", image)
+	}
+	digest, err := img.Digest()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting the digest of image %s", image)
+	}
+	layers, err := img.Layers()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting the layers of image %s", image)
+	}
+
+	var headers map[string][]string
+	authorization, err := authenticator.Authorization()
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting the authorization for registry %s", repository.RegistryStr())
+	}
+	if authorization != "" {
+		headers = map[string][]string{
+			"Authorization": {authorization},
+		}
+	}
+
+	manifest := &ClairManifest{
+		Hash: digest.String(),
+	}
+	for _, layer := range layers {
+		layerDigest, err := layer.Digest()
+		if err != nil {
+			return nil, errors.Wrapf(err, "getting the digest of a layer of image %s", image)
+		}
+		manifest.Layers = append(manifest.Layers, ClairLayer{
+			Hash:    layerDigest.String(),
+			URI:     fmt.Sprintf("%s://%s/v2/%s/blobs/%s", repository.Registry.Scheme(), repository.RegistryStr(), repository.RepositoryStr(), layerDigest.String()),
+			Headers: headers,
+		})
+	}
+	return manifest, nil
+}
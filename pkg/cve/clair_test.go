@@ -0,0 +1,97 @@
+// +build unit
+
+package cve_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const clairManifestHash = "sha256:9a2b6a5dd4d2d1e3a0b1d1a4b8c5e3f6a7b8c9d0e1f2a3b4c5d6e7f8a9b0c1d2"
+
+func newClairProvider(t *testing.T, handler http.Handler) (*cve.ClairProvider, func()) {
+	server := httptest.NewServer(handler)
+	p, err := cve.NewClairProvider(&auth.AuthServer{URL: server.URL + "/"}, &auth.UserAuth{Username: "admin", Password: "secret"})
+	require.NoError(t, err)
+	provider := p.(*cve.ClairProvider)
+	provider.ManifestResolver = func(image string) (*cve.ClairManifest, error) {
+		assert.Equal(t, "debian:buster", image)
+		return &cve.ClairManifest{
+			Hash: clairManifestHash,
+			Layers: []cve.ClairLayer{
+				{
+					Hash: "sha256:1a2b3c",
+					URI:  "https://index.docker.io/v2/library/debian/blobs/sha256:1a2b3c",
+				},
+			},
+		}, nil
+	}
+	return provider, server.Close
+}
+
+func TestClairGetImageVulnerabilities(t *testing.T) {
+	report, err := ioutil.ReadFile(filepath.Join("test_data", "clair", "vulnerability_report.json"))
+	require.NoError(t, err)
+
+	indexed := false
+	mux := http.NewServeMux()
+	mux.HandleFunc("/indexer/api/v1/index_report", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		user, password, ok := r.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "admin", user)
+		assert.Equal(t, "secret", password)
+
+		manifest := cve.ClairManifest{}
+		err := json.NewDecoder(r.Body).Decode(&manifest)
+		assert.NoError(t, err)
+		assert.Equal(t, clairManifestHash, manifest.Hash)
+		assert.Len(t, manifest.Layers, 1)
+		indexed = true
+
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"manifest_hash": "` + clairManifestHash + `", "state": "IndexFinished", "success": true}`))
+	})
+	mux.HandleFunc("/matcher/api/v1/vulnerability_report/"+clairManifestHash, func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.True(t, indexed, "the manifest should be indexed before getting its vulnerability report")
+		w.Write(report)
+	})
+	provider, closer := newClairProvider(t, mux)
+	defer closer()
+
+	vulnerabilities, err := provider.GetImageVulnerabilities(cve.NewImageQuery("debian:buster"))
+	require.NoError(t, err)
+	require.Len(t, vulnerabilities, 2)
+	assert.Equal(t, cve.Vulnerability{
+		Vuln:     "CVE-2020-1967",
+		Package:  "openssl-1.1.1d-0+deb10u2",
+		Fix:      "1.1.1d-0+deb10u3",
+		Severity: cve.SeverityHigh,
+		URL:      "https://security-tracker.debian.org/tracker/CVE-2020-1967",
+	}, vulnerabilities[0])
+	assert.Equal(t, cve.SeverityNegligible, vulnerabilities[1].Severity)
+	assert.Equal(t, "", vulnerabilities[1].URL)
+}
+
+func TestClairGetImageVulnerabilitiesIndexError(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/indexer/api/v1/index_report", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"manifest_hash": "` + clairManifestHash + `", "state": "IndexError", "success": false, "err": "failed to fetch layers"}`))
+	})
+	provider, closer := newClairProvider(t, mux)
+	defer closer()
+
+	_, err := provider.GetImageVulnerabilities(cve.NewImageQuery("debian:buster"))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to fetch layers")
+}
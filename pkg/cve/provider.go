@@ -1,15 +1,32 @@
 package cve
 
 import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
 	"github.com/jenkins-x/jx/v2/pkg/table"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	meta_v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
 const (
 	AnnotationCVEImageId = "jenkins-x.io/cve-image-id"
+
+	// ProviderKindAnchore the kind of the Anchore Engine provider
+	ProviderKindAnchore = "anchore"
+	// ProviderKindTrivy the kind of the Trivy provider running in server mode
+	ProviderKindTrivy = "trivy"
+	// ProviderKindClair the kind of the Clair v4 provider
+	ProviderKindClair = "clair"
 )
 
+// ProviderKinds the kinds of CVE providers
+var ProviderKinds = []string{ProviderKindAnchore, ProviderKindTrivy, ProviderKindClair}
+
 type CVEQuery struct {
 	ImageName       string
 	ImageID         string
@@ -19,4 +36,90 @@ type CVEQuery struct {
 }
 type CVEProvider interface {
 	GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error
+
+	// GetImageVulnerabilities returns the vulnerabilities of the image identified by the query's image ID or its
+	// image name and version
+	GetImageVulnerabilities(query CVEQuery) ([]Vulnerability, error)
+}
+
+// NewCVEProvider creates the CVE provider of the given kind for the server
+func NewCVEProvider(kind string, server *auth.AuthServer, user *auth.UserAuth) (CVEProvider, error) {
+	switch kind {
+	case ProviderKindAnchore, "":
+		return NewAnchoreProvider(server, user)
+	case ProviderKindTrivy:
+		return NewTrivyProvider(server, user)
+	case ProviderKindClair:
+		return NewClairProvider(server, user)
+	default:
+		return nil, util.InvalidArg(kind, ProviderKinds)
+	}
+}
+
+// NewImageQuery returns a query for the image reference, splitting any tag into the version
+func NewImageQuery(image string) CVEQuery {
+	query := CVEQuery{
+		ImageName: image,
+	}
+	if strings.Contains(image, "@") {
+		return query
+	}
+	i := strings.LastIndex(image, ":")
+	if i > strings.LastIndex(image, "/") {
+		query.ImageName = image[:i]
+		query.Vesion = image[i+1:]
+	}
+	return query
+}
+
+// ImageReference returns the image reference of the query made from its image name and version
+func (q *CVEQuery) ImageReference() (string, error) {
+	if q.ImageName == "" {
+		return "", errors.New("no image name specified")
+	}
+	if q.Vesion == "" {
+		return q.ImageName, nil
+	}
+	return q.ImageName + ":" + q.Vesion, nil
+}
+
+// AddVulnerabilitiesTableRows adds a row for each vulnerability of the image to the table
+func AddVulnerabilitiesTableRows(table *table.Table, image string, vulnerabilities []Vulnerability) {
+	for _, v := range vulnerabilities {
+		table.AddRow(image, colorSeverity(v.Severity), v.Vuln, v.URL, v.Package, v.Fix)
+	}
+}
+
+// addQueryImagesTableRows adds the vulnerabilities of the queried image, or the images of the pods running in the
+// queried environment, to the table using the scan function to find the vulnerabilities of each image
+func addQueryImagesTableRows(client kubernetes.Interface, table *table.Table, query CVEQuery, scan func(image string) ([]Vulnerability, error)) error {
+	var images []string
+	if query.Environment != "" {
+		podList, err := client.CoreV1().Pods(query.TargetNamespace).List(meta_v1.ListOptions{})
+		if err != nil {
+			return err
+		}
+		for _, p := range podList.Items {
+			for _, c := range p.Spec.Containers {
+				if util.StringArrayIndex(images, c.Image) < 0 {
+					images = append(images, c.Image)
+				}
+			}
+		}
+	} else {
+		image, err := query.ImageReference()
+		if err != nil {
+			return fmt.Errorf("choose an image name and an optional version or an environment to find vulnerabilities")
+		}
+		images = append(images, image)
+	}
+
+	for _, image := range images {
+		vulnerabilities, err := scan(image)
+		if err != nil {
+			return errors.Wrapf(err, "getting vulnerabilities for image %s", image)
+		}
+		AddVulnerabilitiesTableRows(table, image, vulnerabilities)
+	}
+	return nil
 }
@@ -0,0 +1,78 @@
+package cve
+
+import (
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/util"
+)
+
+const (
+	// SeverityUnknown the severity of a vulnerability which has not been rated
+	SeverityUnknown = "Unknown"
+	// SeverityNegligible the severity of a vulnerability which is not a security problem in practice
+	SeverityNegligible = "Negligible"
+	// SeverityLow the low severity
+	SeverityLow = "Low"
+	// SeverityMedium the medium severity
+	SeverityMedium = "Medium"
+	// SeverityHigh the high severity
+	SeverityHigh = "High"
+	// SeverityCritical the critical severity
+	SeverityCritical = "Critical"
+)
+
+// Severities the vulnerability severities from the least to the most severe
+var Severities = []string{SeverityUnknown, SeverityNegligible, SeverityLow, SeverityMedium, SeverityHigh, SeverityCritical}
+
+// SeverityRank returns the position of the severity in Severities ignoring case, so that more severe vulnerabilities
+// have a higher rank. Severities which are not recognised rank as unknown
+func SeverityRank(severity string) int {
+	for i, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return i
+		}
+	}
+	return 0
+}
+
+// NormalizeSeverity returns the severity in the case used by Severities, or unknown if it is not recognised
+func NormalizeSeverity(severity string) string {
+	return Severities[SeverityRank(severity)]
+}
+
+// ParseSeverity validates the severity returning it in the case used by Severities
+func ParseSeverity(severity string) (string, error) {
+	for _, s := range Severities {
+		if strings.EqualFold(s, severity) {
+			return s, nil
+		}
+	}
+	return "", util.InvalidArg(severity, Severities)
+}
+
+// IsAtOrAbove returns true if the severity is the same as or more severe than the threshold
+func IsAtOrAbove(severity string, threshold string) bool {
+	return SeverityRank(severity) >= SeverityRank(threshold)
+}
+
+// CountBySeverity returns the number of vulnerabilities of each severity
+func CountBySeverity(vulnerabilities []Vulnerability) map[string]int {
+	counts := map[string]int{}
+	for _, v := range vulnerabilities {
+		counts[NormalizeSeverity(v.Severity)]++
+	}
+	return counts
+}
+
+func colorSeverity(severity string) string {
+	switch SeverityRank(severity) {
+	case SeverityRank(SeverityCritical), SeverityRank(SeverityHigh):
+		return util.ColorError(severity)
+	case SeverityRank(SeverityMedium):
+		return util.ColorWarning(severity)
+	case SeverityRank(SeverityLow):
+		return util.ColorStatus(severity)
+	default:
+		return severity
+	}
+}
@@ -0,0 +1,50 @@
+// +build unit
+
+package cve_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAtOrAbove(t *testing.T) {
+	assert.True(t, cve.IsAtOrAbove("CRITICAL", cve.SeverityHigh))
+	assert.True(t, cve.IsAtOrAbove("high", cve.SeverityHigh))
+	assert.False(t, cve.IsAtOrAbove(cve.SeverityMedium, cve.SeverityHigh))
+	assert.False(t, cve.IsAtOrAbove("whatever", cve.SeverityNegligible))
+	assert.True(t, cve.IsAtOrAbove("whatever", cve.SeverityUnknown))
+}
+
+func TestParseSeverity(t *testing.T) {
+	severity, err := cve.ParseSeverity("critical")
+	assert.NoError(t, err)
+	assert.Equal(t, cve.SeverityCritical, severity)
+
+	_, err = cve.ParseSeverity("severe")
+	assert.Error(t, err)
+}
+
+func TestNewImageQuery(t *testing.T) {
+	testCases := []struct {
+		image   string
+		name    string
+		version string
+	}{
+		{"jenkinsxio/nexus:0.0.5", "jenkinsxio/nexus", "0.0.5"},
+		{"jenkinsxio/nexus", "jenkinsxio/nexus", ""},
+		{"localhost:5000/myapp", "localhost:5000/myapp", ""},
+		{"localhost:5000/myapp:1.0.0", "localhost:5000/myapp", "1.0.0"},
+		{"gcr.io/myorg/myapp@sha256:1a2b3c", "gcr.io/myorg/myapp@sha256:1a2b3c", ""},
+	}
+	for _, tc := range testCases {
+		query := cve.NewImageQuery(tc.image)
+		assert.Equal(t, tc.name, query.ImageName, tc.image)
+		assert.Equal(t, tc.version, query.Vesion, tc.image)
+
+		image, err := query.ImageReference()
+		assert.NoError(t, err)
+		assert.Equal(t, tc.image, image)
+	}
+}
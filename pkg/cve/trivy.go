@@ -0,0 +1,128 @@
+package cve
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/client/clientset/versioned"
+	"github.com/jenkins-x/jx/v2/pkg/table"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	"k8s.io/client-go/kubernetes"
+)
+
+// TrivyProvider implements CVEProvider interface using the trivy CLI as a client of a Trivy server
+type TrivyProvider struct {
+	Runner    util.Commander
+	ServerURL string
+	Token     string
+}
+
+type trivyReport struct {
+	Results []trivyResult
+}
+
+type trivyResult struct {
+	Target          string
+	Vulnerabilities []trivyVulnerability
+}
+
+type trivyVulnerability struct {
+	VulnerabilityID  string
+	PkgName          string
+	InstalledVersion string
+	FixedVersion     string
+	Severity         string
+	PrimaryURL       string
+	References       []string
+}
+
+// NewTrivyProvider creates a provider scanning images with the Trivy server, using the API token of the user if any
+func NewTrivyProvider(server *auth.AuthServer, user *auth.UserAuth) (CVEProvider, error) {
+	provider := TrivyProvider{
+		Runner: &util.Command{
+			Name: "trivy",
+		},
+		ServerURL: server.URL,
+	}
+	if user != nil {
+		provider.Token = user.ApiToken
+	}
+	return &provider, nil
+}
+
+// GetImageVulnerabilityTable adds the vulnerabilities of the queried image, or of the images running in the queried
+// environment, to the table
+func (t *TrivyProvider) GetImageVulnerabilityTable(jxClient versioned.Interface, client kubernetes.Interface, table *table.Table, query CVEQuery) error {
+	return addQueryImagesTableRows(client, table, query, t.scan)
+}
+
+// GetImageVulnerabilities returns the vulnerabilities of the image with the query's image name and version
+func (t *TrivyProvider) GetImageVulnerabilities(query CVEQuery) ([]Vulnerability, error) {
+	image, err := query.ImageReference()
+	if err != nil {
+		return nil, err
+	}
+	return t.scan(image)
+}
+
+func (t *TrivyProvider) scan(image string) ([]Vulnerability, error) {
+	file, err := ioutil.TempFile("", "trivy-report-")
+	if err != nil {
+		return nil, errors.Wrap(err, "creating the trivy report file")
+	}
+	fileName := file.Name()
+	file.Close()
+	defer os.Remove(fileName)
+
+	// the token is passed in the environment so that it does not show up in the process list or the command logs
+	if t.Token != "" {
+		t.Runner.SetEnvVariable("TRIVY_TOKEN", t.Token)
+	}
+	t.Runner.SetArgs([]string{"image", "--server", t.ServerURL, "--format", "json", "--output", fileName, "--quiet", image})
+	_, err = t.Runner.RunWithoutRetry()
+	if err != nil {
+		return nil, errors.Wrapf(err, "scanning image %s with the trivy server %s", image, t.ServerURL)
+	}
+
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, errors.Wrapf(err, "reading the trivy report %s", fileName)
+	}
+	return parseTrivyReport(data)
+}
+
+// parseTrivyReport parses the JSON report of trivy, which older versions write as an array of results
+func parseTrivyReport(data []byte) ([]Vulnerability, error) {
+	report := trivyReport{}
+	var err error
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &report.Results)
+	} else {
+		err = json.Unmarshal(data, &report)
+	}
+	if err != nil {
+		return nil, errors.Wrap(err, "unmarshalling the trivy report")
+	}
+
+	var answer []Vulnerability
+	for _, result := range report.Results {
+		for _, v := range result.Vulnerabilities {
+			url := v.PrimaryURL
+			if url == "" && len(v.References) > 0 {
+				url = v.References[0]
+			}
+			answer = append(answer, Vulnerability{
+				Vuln:     v.VulnerabilityID,
+				Package:  v.PkgName + "-" + v.InstalledVersion,
+				Fix:      v.FixedVersion,
+				Severity: NormalizeSeverity(v.Severity),
+				URL:      url,
+			})
+		}
+	}
+	return answer, nil
+}
@@ -0,0 +1,84 @@
+// +build unit
+
+package cve_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/cve"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTrivy writes the report to the output file passed to trivy instead of running it
+type fakeTrivy struct {
+	*util.Command
+	report string
+}
+
+func (f *fakeTrivy) RunWithoutRetry() (string, error) {
+	args := f.CurrentArgs()
+	for i, arg := range args {
+		if arg == "--output" && i+1 < len(args) {
+			return "", ioutil.WriteFile(args[i+1], []byte(f.report), util.DefaultWritePermissions)
+		}
+	}
+	return f.report, nil
+}
+
+func newTrivyProvider(t *testing.T, report string) (*cve.TrivyProvider, *fakeTrivy) {
+	p, err := cve.NewTrivyProvider(&auth.AuthServer{URL: "http://trivy:4954"}, &auth.UserAuth{ApiToken: "secret"})
+	require.NoError(t, err)
+	provider := p.(*cve.TrivyProvider)
+	runner := &fakeTrivy{Command: &util.Command{Name: "trivy"}, report: report}
+	provider.Runner = runner
+	return provider, runner
+}
+
+func TestTrivyGetImageVulnerabilities(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("test_data", "trivy", "report.json"))
+	require.NoError(t, err)
+	provider, runner := newTrivyProvider(t, string(data))
+
+	vulnerabilities, err := provider.GetImageVulnerabilities(cve.NewImageQuery("jenkinsxio/nexus:0.0.5"))
+	require.NoError(t, err)
+
+	args := runner.CurrentArgs()
+	assert.Equal(t, []string{"image", "--server", "http://trivy:4954", "--format", "json"}, args[:5])
+	assert.Equal(t, []string{"--quiet", "jenkinsxio/nexus:0.0.5"}, args[7:])
+	assert.Equal(t, "secret", runner.CurrentEnv()["TRIVY_TOKEN"])
+
+	require.Len(t, vulnerabilities, 3)
+	assert.Equal(t, cve.Vulnerability{
+		Vuln:     "CVE-2021-36159",
+		Package:  "apk-tools-2.10.6-r0",
+		Fix:      "2.10.7-r0",
+		Severity: cve.SeverityCritical,
+		URL:      "https://avd.aquasec.com/nvd/cve-2021-36159",
+	}, vulnerabilities[0])
+	assert.Equal(t, cve.SeverityMedium, vulnerabilities[1].Severity)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2021-3711", vulnerabilities[1].URL)
+	assert.Equal(t, "lodash-4.17.15", vulnerabilities[2].Package)
+	assert.Equal(t, cve.SeverityHigh, vulnerabilities[2].Severity)
+}
+
+func TestTrivyGetImageVulnerabilitiesLegacyReport(t *testing.T) {
+	provider, _ := newTrivyProvider(t, `[{"Target": "alpine:3.10", "Vulnerabilities": [{"VulnerabilityID": "CVE-2019-14697", "PkgName": "musl", "InstalledVersion": "1.1.22-r2", "Severity": "HIGH"}]}]`)
+
+	vulnerabilities, err := provider.GetImageVulnerabilities(cve.NewImageQuery("alpine:3.10"))
+	require.NoError(t, err)
+	require.Len(t, vulnerabilities, 1)
+	assert.Equal(t, "CVE-2019-14697", vulnerabilities[0].Vuln)
+	assert.Equal(t, cve.SeverityHigh, vulnerabilities[0].Severity)
+}
+
+func TestTrivyGetImageVulnerabilitiesRequiresImageName(t *testing.T) {
+	provider, _ := newTrivyProvider(t, "{}")
+
+	_, err := provider.GetImageVulnerabilities(cve.CVEQuery{ImageID: "07b67913cd8c"})
+	assert.Error(t, err)
+}
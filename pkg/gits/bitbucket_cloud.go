@@ -1,9 +1,17 @@
 package gits
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
 	"os"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -11,7 +19,6 @@ import (
 
 	"github.com/pkg/errors"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 
 	"github.com/jenkins-x/jx/v2/pkg/auth"
@@ -24,6 +31,8 @@ type BitbucketCloudProvider struct {
 	Client   *bitbucket.APIClient
 	Username string
 	Context  context.Context
+	// BaseURL is the URL of the Bitbucket API used for requests the generated client does not support
+	BaseURL string
 
 	Server auth.AuthServer
 	User   auth.UserAuth
@@ -56,6 +65,7 @@ func NewBitbucketCloudProvider(server *auth.AuthServer, user *auth.UserAuth, git
 
 	cfg := bitbucket.NewConfiguration()
 	provider.Client = bitbucket.NewAPIClient(cfg)
+	provider.BaseURL = cfg.BasePath
 
 	return &provider, nil
 }
@@ -495,8 +505,6 @@ func (b *BitbucketCloudProvider) GetPullRequestCommits(owner string, repository
 		return answer, fmt.Errorf("No commitValues for %s/%s/%d", owner, repo, number)
 	}
 
-	rawEmailMatcher, _ := regexp.Compile("[^<]*<([^>]+)>")
-
 	for _, data := range commitValues {
 		if data == nil {
 			continue
@@ -524,31 +532,35 @@ func (b *BitbucketCloudProvider) GetPullRequestCommits(owner string, repository
 			return answer, err
 		}
 
-		// update the login and email
-		login := ""
-		email := ""
-		if commit.Author != nil {
-			// commit.Author is the actual Bitbucket user
-			if commit.Author.User != nil {
-				login = commit.Author.User.Username
-			}
-			// Author.MessageLines contains the Git commit author in the form: User <email@example.com>
-			email = rawEmailMatcher.ReplaceAllString(commit.Author.Raw, "$1")
-		}
+		answer = append(answer, bitbucketCommitToGitCommit(&commit))
+	}
+	return answer, nil
+}
 
-		summary := &GitCommit{
-			Message: commit.Message,
-			URL:     "", // Commit model no longer provides links.
-			SHA:     commit.Hash,
-			Author: &GitUser{
-				Login: login,
-				Email: email,
-			},
+var rawEmailMatcher = regexp.MustCompile("[^<]*<([^>]+)>")
+
+func bitbucketCommitToGitCommit(commit *bitbucket.Commit) *GitCommit {
+	// update the login and email
+	login := ""
+	email := ""
+	if commit.Author != nil {
+		// commit.Author is the actual Bitbucket user
+		if commit.Author.User != nil {
+			login = commit.Author.User.Username
 		}
+		// Author.MessageLines contains the Git commit author in the form: User <email@example.com>
+		email = rawEmailMatcher.ReplaceAllString(commit.Author.Raw, "$1")
+	}
 
-		answer = append(answer, summary)
+	return &GitCommit{
+		Message: commit.Message,
+		URL:     "", // Commit model no longer provides links.
+		SHA:     commit.Hash,
+		Author: &GitUser{
+			Login: login,
+			Email: email,
+		},
 	}
-	return answer, nil
 }
 
 func (b *BitbucketCloudProvider) PullRequestLastCommitStatus(pr *GitPullRequest) (string, error) {
@@ -714,19 +726,22 @@ func (b *BitbucketCloudProvider) MergePullRequest(pr *GitPullRequest, message st
 	return nil
 }
 
+// bitbucketCloudWebHookEvents are the events we register webhooks for
+var bitbucketCloudWebHookEvents = []string{
+	"repo:push",
+	"pullrequest:created",
+	"pullrequest:updated",
+	"pullrequest:fulfilled",
+	"pullrequest:rejected",
+}
+
 func (b *BitbucketCloudProvider) CreateWebHook(data *GitWebHookArguments) error {
 
 	options := map[string]interface{}{
 		"body": map[string]interface{}{
-			"url":    data.URL,
-			"active": true,
-			"events": []string{
-				"repo:push",
-				"pullrequest:created",
-				"pullrequest:updated",
-				"pullrequest:fulfilled",
-				"pullrequest:rejected",
-			},
+			"url":         data.URL,
+			"active":      true,
+			"events":      bitbucketCloudWebHookEvents,
 			"description": "Jenkins X Web Hook",
 		},
 	}
@@ -747,12 +762,112 @@ func (b *BitbucketCloudProvider) CreateWebHook(data *GitWebHookArguments) error
 // ListWebHooks lists the webhooks
 func (b *BitbucketCloudProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
 	webHooks := []*GitWebHookArguments{}
-	return webHooks, fmt.Errorf("not implemented!")
+	hooks, err := b.listWebHookSubscriptions(owner, repo)
+	if err != nil {
+		return webHooks, err
+	}
+	for _, hook := range hooks {
+		// webhooks on bitbucket.org are identified by UUID so we can only match them by URL
+		webHooks = append(webHooks, &GitWebHookArguments{
+			Owner: owner,
+			Repo: &GitRepository{
+				Organisation: owner,
+				Name:         repo,
+			},
+			URL: hook.Url,
+		})
+	}
+	return webHooks, nil
 }
 
-// UpdateWebHook updates the webhook
+// UpdateWebHook updates the webhook registered for the ExistingURL, or the URL if no ExistingURL is specified
 func (b *BitbucketCloudProvider) UpdateWebHook(data *GitWebHookArguments) error {
-	return fmt.Errorf("not implemented!")
+	owner := data.Repo.Organisation
+	if owner == "" {
+		owner = b.Username
+	}
+	repo := data.Repo.Name
+	if data.URL == "" {
+		return fmt.Errorf("Missing property URL")
+	}
+	uuid, err := b.findWebHookUUID(owner, repo, data)
+	if err != nil {
+		return err
+	}
+	if uuid == "" {
+		log.Logger().Warn("No webhooks found to update")
+		return nil
+	}
+	log.Logger().Infof("Updating Bitbucket webhook for %s/%s for url %s", util.ColorInfo(owner), util.ColorInfo(repo), util.ColorInfo(data.URL))
+	// the generated client does not send a body when updating a webhook
+	body := map[string]interface{}{
+		"url":         data.URL,
+		"active":      true,
+		"events":      bitbucketCloudWebHookEvents,
+		"description": "Jenkins X Web Hook",
+	}
+	_, err = b.apiRequest(http.MethodPut, util.UrlJoin("repositories", owner, repo, "hooks", url.PathEscape(uuid)), nil, body)
+	if err != nil {
+		return errors.Wrapf(err, "failed to update webhook %s on %s/%s", uuid, owner, repo)
+	}
+	return nil
+}
+
+// DeleteWebHook deletes the webhook registered for the ExistingURL, or the URL if no ExistingURL is specified
+func (b *BitbucketCloudProvider) DeleteWebHook(data *GitWebHookArguments) error {
+	owner := data.Repo.Organisation
+	if owner == "" {
+		owner = b.Username
+	}
+	repo := data.Repo.Name
+	uuid, err := b.findWebHookUUID(owner, repo, data)
+	if err != nil {
+		return err
+	}
+	if uuid == "" {
+		log.Logger().Warn("No webhooks found to delete")
+		return nil
+	}
+	_, err = b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugHooksUidDelete(b.Context, owner, repo, uuid)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete webhook %s on %s/%s", uuid, owner, repo)
+	}
+	return nil
+}
+
+// findWebHookUUID returns the UUID of the webhook described by the data, or an empty string if there is no such webhook
+func (b *BitbucketCloudProvider) findWebHookUUID(owner string, repo string, data *GitWebHookArguments) (string, error) {
+	hookURL := data.ExistingURL
+	if hookURL == "" {
+		hookURL = data.URL
+	}
+	if hookURL == "" {
+		return "", nil
+	}
+	hooks, err := b.listWebHookSubscriptions(owner, repo)
+	if err != nil {
+		return "", err
+	}
+	for _, hook := range hooks {
+		if hook.Url == hookURL {
+			return hook.Uuid, nil
+		}
+	}
+	return "", nil
+}
+
+func (b *BitbucketCloudProvider) listWebHookSubscriptions(owner string, repo string) ([]bitbucket.WebhookSubscription, error) {
+	if owner == "" {
+		owner = b.Username
+	}
+	if repo == "" {
+		return nil, fmt.Errorf("Missing property Repo")
+	}
+	results, _, err := b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugHooksGet(b.Context, owner, repo)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list webhooks on repository %s/%s", owner, repo)
+	}
+	return results.Values, nil
 }
 
 func BitbucketIssueToGitIssue(bIssue bitbucket.Issue) *GitIssue {
@@ -1033,19 +1148,44 @@ func (b *BitbucketCloudProvider) AddCollaborator(user string, organisation strin
 	return nil
 }
 
-func (b *BitbucketCloudProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+// ListInvitations returns no invitations as collaborators cannot be added automatically
+func (b *BitbucketCloudProvider) ListInvitations() ([]*GitRepositoryInvitation, error) {
+	return []*GitRepositoryInvitation{}, nil
 }
 
-func (b *BitbucketCloudProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.")
-	return &github.Response{}, nil
+// AcceptInvitation does nothing as collaborators cannot be added automatically
+func (b *BitbucketCloudProvider) AcceptInvitation(ID int64) error {
+	return nil
 }
 
 func (b *BitbucketCloudProvider) GetContent(org string, name string, path string, ref string) (*GitFileContent, error) {
-	log.Logger().Warn("Getting content not supported on bitbucket")
-	return nil, nil
+	if ref == "" {
+		repo, _, err := b.Client.RepositoriesApi.RepositoriesUsernameRepoSlugGet(b.Context, org, name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the main branch of %s/%s", org, name)
+		}
+		if repo.Mainbranch == nil || repo.Mainbranch.Name == "" {
+			return nil, fmt.Errorf("repository %s/%s has no main branch", org, name)
+		}
+		ref = repo.Mainbranch.Name
+	}
+	path = strings.TrimPrefix(path, "/")
+	srcPath := util.UrlJoin("repositories", org, name, "src", url.PathEscape(ref), path)
+	data, err := b.apiRequest(http.MethodGet, srcPath, nil, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get content of %s in %s/%s at ref %s", path, org, name, ref)
+	}
+	return &GitFileContent{
+		Type:        "file",
+		Encoding:    "base64",
+		Size:        len(data),
+		Name:        filepath.Base(path),
+		Path:        path,
+		Content:     base64.StdEncoding.EncodeToString(data),
+		Url:         util.UrlJoin(b.BaseURL, srcPath),
+		HtmlUrl:     util.UrlJoin(b.Server.URL, org, name, "src", ref, path),
+		DownloadUrl: util.UrlJoin(b.Server.URL, org, name, "raw", ref, path),
+	}, nil
 }
 
 // ShouldForkForPullReques treturns true if we should create a personal fork of this repository
@@ -1064,10 +1204,92 @@ func BitBucketCloudAccessTokenURL(url string, username string) string {
 	return util.UrlJoin(url, "/account/user", username, "/app-passwords/new")
 }
 
-// ListCommits lists the commits for the specified repo and owner
+// ListCommits lists the commits for the specified repo and owner. Bitbucket can only filter the commits by path so the
+// author, since and until options are applied on the client
 func (b *BitbucketCloudProvider) ListCommits(owner, repo string, opt *ListCommitsArguments) ([]*GitCommit, error) {
-	log.Logger().Warn("Listing commits not supported on bitbucket cloud")
-	return nil, nil
+	commitsPath := util.UrlJoin("repositories", owner, repo, "commits")
+	if opt.SHA != "" {
+		commitsPath = util.UrlJoin(commitsPath, url.PathEscape(opt.SHA))
+	}
+	fetch := func(page int, perPage int) ([]listedCommit, error) {
+		query := url.Values{}
+		if opt.Path != "" {
+			query.Set("path", opt.Path)
+		}
+		if page > 0 {
+			query.Set("page", strconv.Itoa(page))
+		}
+		if perPage > 0 {
+			query.Set("pagelen", strconv.Itoa(perPage))
+		}
+		data, err := b.apiRequest(http.MethodGet, commitsPath, query, nil)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to list commits for repository %s/%s", owner, repo)
+		}
+		results := struct {
+			Values []bitbucket.Commit `json:"values"`
+		}{}
+		err = json.Unmarshal(data, &results)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to unmarshal commits for repository %s/%s", owner, repo)
+		}
+		listed := []listedCommit{}
+		for i := range results.Values {
+			listed = append(listed, listedCommit{
+				commit: bitbucketCommitToGitCommit(&results.Values[i]),
+				date:   results.Values[i].Date,
+			})
+		}
+		return listed, nil
+	}
+	if opt.Author != "" || !opt.Since.IsZero() || !opt.Until.IsZero() {
+		return opt.filterCommits(fetch, nil)
+	}
+	listed, err := fetch(opt.Page, opt.PerPage)
+	if err != nil {
+		return nil, err
+	}
+	return listedCommits(listed), nil
+}
+
+// apiRequest performs a request for the Bitbucket API resources which are not supported by the generated client,
+// encoding the body as JSON if there is one
+func (b *BitbucketCloudProvider) apiRequest(method string, path string, query url.Values, body interface{}) ([]byte, error) {
+	u := util.UrlJoin(b.BaseURL, path)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal the body of %s %s", method, u)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if basicAuth, ok := b.Context.Value(bitbucket.ContextBasicAuth).(bitbucket.BasicAuth); ok {
+		req.SetBasicAuth(basicAuth.UserName, basicAuth.Password)
+	}
+	resp, err := util.GetClient().Do(req.WithContext(b.Context))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode >= 300 {
+		return nil, errors.Errorf("%s %s returned status %s: %s", method, u, resp.Status, string(data))
+	}
+	return data, nil
 }
 
 // AddLabelsToIssue adds labels to issues or pullrequests
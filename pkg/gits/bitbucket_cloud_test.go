@@ -3,6 +3,7 @@
 package gits_test
 
 import (
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -93,8 +94,19 @@ var bitbucketRouter = util.Router{
 	"/repositories/test-user/test-repo/pullrequests/1/merge": util.MethodMap{
 		"POST": "pullrequests.test-repo.merged.json",
 	},
+	"/repositories/test-user/test-repo/commits": util.MethodMap{
+		"GET": "repos.test-repo.commits.json",
+	},
+	"/repositories/test-user/test-repo/src/master/README.md": util.MethodMap{
+		"GET": "README.md",
+	},
 	"/repositories/test-user/test-repo/hooks": util.MethodMap{
 		"POST": "webhooks.example.json",
+		"GET":  "webhooks.json",
+	},
+	"/repositories/test-user/test-repo/hooks/{81c9cddc-38ef-4ea2-bae7-4bf581f82c6c}": util.MethodMap{
+		"DELETE": "webhooks.example.json",
+		"PUT":    "webhooks.example.json",
 	},
 	"/repositories/test-user/test-repo/issues": util.MethodMap{
 		"POST": "issues.test-repo.issue-1.json",
@@ -160,6 +172,7 @@ func (suite *BitbucketCloudProviderTestSuite) SetupSuite() {
 		suite.Require().NotNil(bp)
 		suite.Require().True(ok)
 		bp.Client = clientSingleton
+		bp.BaseURL = suite.server.URL
 
 		suite.providers[profile.username] = *bp
 	}
@@ -343,6 +356,29 @@ func (suite *BitbucketCloudProviderTestSuite) TestPullRequestCommits() {
 	suite.Require().Equal(commits[0].Author.Email, "test-user@gmail.com")
 }
 
+func (suite *BitbucketCloudProviderTestSuite) TestListCommits() {
+	commits, err := suite.provider.ListCommits("test-user", "test-repo", &gits.ListCommitsArguments{})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal("bbc7b863a56144647a806646b73e3b43749decad", commits[0].SHA)
+	suite.Require().Equal("test-user", commits[0].Author.Login)
+	suite.Require().Equal("test-user@gmail.com", commits[0].Author.Email)
+	suite.Require().Equal("other-user@gmail.com", commits[1].Author.Email)
+}
+
+func (suite *BitbucketCloudProviderTestSuite) TestGetContent() {
+	content, err := suite.provider.GetContent("test-user", "test-repo", "README.md", "")
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(content)
+	suite.Require().Equal("README.md", content.Name)
+	suite.Require().Equal("base64", content.Encoding)
+	data, err := base64.StdEncoding.DecodeString(content.Content)
+	suite.Require().Nil(err)
+	suite.Require().Contains(string(data), "This is a test repository.")
+}
+
 func (suite *BitbucketCloudProviderTestSuite) TestPullRequestLastCommitStatus() {
 
 	pr := &gits.GitPullRequest{
@@ -405,6 +441,36 @@ func (suite *BitbucketCloudProviderTestSuite) TestCreateWebHook() {
 	suite.Require().Nil(err)
 }
 
+func (suite *BitbucketCloudProviderTestSuite) TestListWebHooks() {
+	webHooks, err := suite.provider.ListWebHooks("test-user", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(webHooks, 1)
+	suite.Require().Equal("https://example.com/bitbucket-webhook/", webHooks[0].URL)
+	suite.Require().Equal("test-repo", webHooks[0].Repo.Name)
+}
+
+func (suite *BitbucketCloudProviderTestSuite) TestUpdateWebHook() {
+	data := &gits.GitWebHookArguments{
+		Repo:        &gits.GitRepository{Name: "test-repo", Organisation: "test-user"},
+		URL:         "https://my-jenkins.example.com/bitbucket-webhook/",
+		ExistingURL: "https://example.com/bitbucket-webhook/",
+	}
+	err := suite.provider.UpdateWebHook(data)
+
+	suite.Require().Nil(err)
+}
+
+func (suite *BitbucketCloudProviderTestSuite) TestDeleteWebHook() {
+	data := &gits.GitWebHookArguments{
+		Repo: &gits.GitRepository{Name: "test-repo", Organisation: "test-user"},
+		URL:  "https://example.com/bitbucket-webhook/",
+	}
+	err := suite.provider.DeleteWebHook(data)
+
+	suite.Require().Nil(err)
+}
+
 func (suite *BitbucketCloudProviderTestSuite) TestSearchIssues() {
 	issues, err := suite.provider.SearchIssues("test-user", "test-repo", "")
 
@@ -493,15 +559,13 @@ func (suite *BitbucketCloudProviderTestSuite) TestAddCollaborator() {
 }
 
 func (suite *BitbucketCloudProviderTestSuite) TestListInvitations() {
-	invites, res, err := suite.provider.ListInvitations()
+	invites, err := suite.provider.ListInvitations()
 	suite.Require().NotNil(invites)
-	suite.Require().NotNil(res)
 	suite.Require().Nil(err)
 }
 
 func (suite *BitbucketCloudProviderTestSuite) TestAcceptInvitations() {
-	res, err := suite.provider.AcceptInvitation(1)
-	suite.Require().NotNil(res)
+	err := suite.provider.AcceptInvitation(1)
 	suite.Require().Nil(err)
 }
 
@@ -2,10 +2,13 @@ package gits
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -13,7 +16,6 @@ import (
 	"github.com/blang/semver"
 	"github.com/pkg/errors"
 
-	"github.com/google/go-github/github"
 	"github.com/mitchellh/mapstructure"
 
 	bitbucket "github.com/gfleury/go-bitbucket-v1"
@@ -37,10 +39,33 @@ type BitbucketServerProvider struct {
 	Client   *bitbucket.APIClient
 	Username string
 	Context  context.Context
+	// BaseURL is the URL of the REST API used for requests the generated client does not support
+	BaseURL string
 
 	Server auth.AuthServer
 	User   auth.UserAuth
 	Git    Gitter
+
+	// IssueTracker resolves issues from the Jira server linked to the Bitbucket Server, if any
+	IssueTracker JiraIssueTracker
+	// JiraProject is the key of the Jira project of the issues. Defaults to the Bitbucket project key
+	JiraProject string
+}
+
+// JiraIssueTracker looks up the Jira issues of a Bitbucket Server which is linked to Jira.
+// It is implemented by the Jira issue provider of the issues package
+type JiraIssueTracker interface {
+	GetIssue(key string) (*GitIssue, error)
+	SearchIssues(query string) ([]*GitIssue, error)
+	SearchIssuesClosedSince(t time.Time) ([]*GitIssue, error)
+	IssueURL(key string) string
+}
+
+// BitbucketServerJiraServer is a Jira server linked to a Bitbucket Server through its Jira integration
+type BitbucketServerJiraServer struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	URL  string `json:"url"`
 }
 
 type projectsPage struct {
@@ -121,6 +146,7 @@ func NewBitbucketServerProvider(server *auth.AuthServer, user *auth.UserAuth, gi
 
 	cfg := bitbucket.NewConfiguration(server.URL + "/rest")
 	provider.Client = bitbucket.NewAPIClient(apiKeyAuthContext, cfg)
+	provider.BaseURL = cfg.BasePath
 
 	return &provider, nil
 }
@@ -1053,16 +1079,60 @@ func (b *BitbucketServerProvider) UpdateWebHook(data *GitWebHookArguments) error
 	return nil
 }
 
-func (b *BitbucketServerProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
+// DeleteWebHook deletes a webhook from a git repository.  It is best to pass in the webhook ID.
+func (b *BitbucketServerProvider) DeleteWebHook(data *GitWebHookArguments) error {
+	projectKey, repo, err := b.parseWebHookURL(data)
+	if err != nil {
+		return err
+	}
 
-	gitIssues := []*GitIssue{}
+	dataID := data.ID
+	hookURL := data.ExistingURL
+	if hookURL == "" {
+		hookURL = data.URL
+	}
+	if dataID == 0 && hookURL != "" {
+		hooks, err := b.ListWebHooks(projectKey, repo)
+		if err != nil {
+			return errors.Wrapf(err, "error querying webhooks on %s/%s", projectKey, repo)
+		}
+		for _, hook := range hooks {
+			if hookURL == hook.URL {
+				dataID = hook.ID
+			}
+		}
+	}
+	if dataID == 0 {
+		log.Logger().Warn("No webhooks found to delete")
+		return nil
+	}
+	id := int32(dataID)
+	if int64(id) != dataID {
+		return errors.Errorf("Failed to delete webhook with ID = %d due to int32 conversion failure", dataID)
+	}
 
-	log.Logger().Warn("Searching issues on bitbucket server is not supported at this moment")
+	log.Logger().Infof("Deleting Bitbucket server webhook %d for %s/%s", id, util.ColorInfo(projectKey), util.ColorInfo(repo))
+	_, err = b.Client.DefaultApi.DeleteWebhook(projectKey, repo, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete webhook on %s/%s", projectKey, repo)
+	}
+	return nil
+}
 
-	return gitIssues, nil
+// SearchIssues searches the issues of the Jira server linked to the Bitbucket Server
+func (b *BitbucketServerProvider) SearchIssues(org string, name string, query string) ([]*GitIssue, error) {
+	if b.IssueTracker == nil {
+		log.Logger().Warn("Searching issues on bitbucket server requires a linked Jira server")
+		return []*GitIssue{}, nil
+	}
+	return b.IssueTracker.SearchIssues(query)
 }
 
+// SearchIssuesClosedSince searches the issues of the linked Jira server which were closed since the given time
 func (b *BitbucketServerProvider) SearchIssuesClosedSince(org string, name string, t time.Time) ([]*GitIssue, error) {
+	if b.IssueTracker != nil {
+		return b.IssueTracker.SearchIssuesClosedSince(t)
+	}
 	issues, err := b.SearchIssues(org, name, "")
 	if err != nil {
 		return issues, err
@@ -1070,13 +1140,53 @@ func (b *BitbucketServerProvider) SearchIssuesClosedSince(org string, name strin
 	return FilterIssuesClosedSince(issues, t), nil
 }
 
+// GetIssue returns the issue of the linked Jira server with the given number in the Jira project of the repository
 func (b *BitbucketServerProvider) GetIssue(org string, name string, number int) (*GitIssue, error) {
+	if b.IssueTracker == nil {
+		log.Logger().Warn("Finding an issue on bitbucket server requires a linked Jira server")
+		return &GitIssue{}, nil
+	}
+	return b.IssueTracker.GetIssue(b.jiraIssueKey(org, number))
+}
 
-	log.Logger().Warn("Finding an issue on bitbucket server is not supported at this moment")
-	return &GitIssue{}, nil
+func (b *BitbucketServerProvider) jiraIssueKey(org string, number int) string {
+	project := b.JiraProject
+	if project == "" {
+		project = strings.ToUpper(org)
+	}
+	return fmt.Sprintf("%s-%d", project, number)
+}
+
+// LinkedJiraServers returns the Jira servers linked to the Bitbucket Server through its Jira integration
+func (b *BitbucketServerProvider) LinkedJiraServers() ([]BitbucketServerJiraServer, error) {
+	// the generated client has no resources for the Jira integration plugin
+	u := util.UrlJoin(b.BaseURL, "jira-integration/1.0/servers")
+	req, err := http.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Authorization", "Bearer "+b.User.ApiToken)
+	req.Header.Set("Accept", "application/json")
+	resp, err := util.GetClient().Do(req.WithContext(b.Context))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the Jira servers linked to bitbucket server")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return nil, errors.Errorf("failed to list the Jira servers linked to bitbucket server: GET %s returned status %s", u, resp.Status)
+	}
+	servers := []BitbucketServerJiraServer{}
+	err = json.NewDecoder(resp.Body).Decode(&servers)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse the Jira servers linked to bitbucket server")
+	}
+	return servers, nil
 }
 
 func (b *BitbucketServerProvider) IssueURL(org string, name string, number int, isPull bool) string {
+	if b.IssueTracker != nil && !isPull {
+		return b.IssueTracker.IssueURL(b.jiraIssueKey(org, number))
+	}
 	serverPrefix := b.Server.URL
 	if strings.Index(serverPrefix, "://") < 0 {
 		serverPrefix = "https://" + serverPrefix
@@ -1207,26 +1317,58 @@ func (b *BitbucketServerProvider) GetRelease(org string, name string, tag string
 	return nil, nil
 }
 
+// AddCollaborator grants the user write permission on the repository
 func (b *BitbucketServerProvider) AddCollaborator(user string, organisation string, repo string) error {
+	log.Logger().Infof("Automatically adding the pipeline user: %v as a collaborator.", user)
 	options := make(map[string]interface{})
 	options["name"] = user
 	options["permission"] = "REPO_WRITE"
 	_, err := b.Client.DefaultApi.SetPermissionForUser(organisation, repo, options)
-	return err
+	if err != nil {
+		return errors.Wrapf(err, "failed to grant %s write permission on %s/%s", user, organisation, repo)
+	}
+	return nil
 }
 
-func (b *BitbucketServerProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+// ListInvitations lists pending invites. Bitbucket Server grants repository permissions immediately so there are
+// never any pending invitations
+func (b *BitbucketServerProvider) ListInvitations() ([]*GitRepositoryInvitation, error) {
+	return []*GitRepositoryInvitation{}, nil
 }
 
-func (b *BitbucketServerProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for bitbucket.")
-	return &github.Response{}, nil
+// AcceptInvitation accepts an invitation. Bitbucket Server grants repository permissions immediately so there is
+// nothing to accept
+func (b *BitbucketServerProvider) AcceptInvitation(ID int64) error {
+	return nil
 }
 
 func (b *BitbucketServerProvider) GetContent(org string, name string, path string, ref string) (*GitFileContent, error) {
-	return nil, fmt.Errorf("Getting content not supported on bitbucket")
+	options := make(map[string]interface{})
+	if ref != "" {
+		options["at"] = ref
+	}
+	path = strings.TrimPrefix(path, "/")
+	apiResponse, err := b.Client.DefaultApi.GetContent_11(strings.ToUpper(org), name, path, options)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get content of %s in %s/%s at ref %s", path, org, name, ref)
+	}
+	htmlURL := util.UrlJoin(b.Server.URL, "projects", strings.ToUpper(org), "repos", name, "browse", path)
+	rawURL := util.UrlJoin(b.Server.URL, "projects", strings.ToUpper(org), "repos", name, "raw", path)
+	if ref != "" {
+		htmlURL += "?at=" + url.QueryEscape(ref)
+		rawURL += "?at=" + url.QueryEscape(ref)
+	}
+	return &GitFileContent{
+		Type:        "file",
+		Encoding:    "base64",
+		Size:        len(apiResponse.Payload),
+		Name:        filepath.Base(path),
+		Path:        path,
+		Content:     base64.StdEncoding.EncodeToString(apiResponse.Payload),
+		Url:         rawURL,
+		HtmlUrl:     htmlURL,
+		DownloadUrl: rawURL,
+	}, nil
 }
 
 // ShouldForkForPullReques treturns true if we should create a personal fork of this repository
@@ -1245,39 +1387,62 @@ func BitBucketServerAccessTokenURL(url string) string {
 	return util.UrlJoin(url, "/plugins/servlet/access-tokens/manage")
 }
 
-// ListCommits lists the commits for the specified repo and owner
+// ListCommits lists the commits for the specified repo and owner. Bitbucket Server can only filter the commits by path
+// so the author, since and until options are applied on the client
 func (b *BitbucketServerProvider) ListCommits(owner, repoName string, opt *ListCommitsArguments) ([]*GitCommit, error) {
-	options := make(map[string]interface{})
-	options["limit"] = pageLimit
-	options["start"] = 0
-	options["until"] = opt.SHA
-	var commitsPage commitsPage
-	commits := []*GitCommit{}
-
 	repo, err := b.GetRepository(owner, repoName)
 	if err != nil {
 		return nil, err
 	}
-	apiResponse, err := b.Client.DefaultApi.GetCommits(strings.ToUpper(owner), repo.Name, options)
-	if err != nil {
-		return nil, err
+	fetch := func(page int, perPage int) ([]listedCommit, error) {
+		options := make(map[string]interface{})
+		options["limit"] = pageLimit
+		options["start"] = 0
+		if perPage > 0 {
+			options["limit"] = perPage
+			if page > 1 {
+				options["start"] = (page - 1) * perPage
+			}
+		}
+		if opt.SHA != "" {
+			options["until"] = opt.SHA
+		}
+		if opt.Path != "" {
+			options["path"] = opt.Path
+		}
+		apiResponse, err := b.Client.DefaultApi.GetCommits(strings.ToUpper(owner), repo.Name, options)
+		if err != nil {
+			return nil, err
+		}
+		var commitsPage commitsPage
+		err = mapstructure.Decode(apiResponse.Values, &commitsPage)
+		if err != nil {
+			return nil, err
+		}
+		listed := []listedCommit{}
+		for i := range commitsPage.Values {
+			commit := &commitsPage.Values[i]
+			listed = append(listed, listedCommit{
+				commit: convertBitBucketCommitToGitCommit(commit, repo),
+				date:   time.Unix(0, commit.CommitterTimestamp*int64(time.Millisecond)),
+			})
+		}
+		return listed, nil
 	}
-
-	err = mapstructure.Decode(apiResponse.Values, &commitsPage)
+	if opt.Author != "" || !opt.Since.IsZero() || !opt.Until.IsZero() {
+		return opt.filterCommits(fetch, nil)
+	}
+	listed, err := fetch(opt.Page, opt.PerPage)
 	if err != nil {
 		return nil, err
 	}
-
-	for _, commit := range commitsPage.Values {
-		commits = append(commits, convertBitBucketCommitToGitCommit(&commit, repo))
-	}
-
-	return commits, nil
+	return listedCommits(listed), nil
 }
 
-// AddLabelsToIssue adds labels to issues or pullrequests
+// AddLabelsToIssue only logs a warning as Bitbucket Server has no issue or pull request labels, so that pull requests
+// can still be created with labels
 func (b *BitbucketServerProvider) AddLabelsToIssue(owner, repo string, number int, labels []string) error {
-	log.Logger().Warnf("Adding labels not supported on bitbucket server yet for repo %s/%s issue %d labels %v", owner, repo, number, labels)
+	log.Logger().Warnf("Adding labels not supported on bitbucket server for repo %s/%s issue %d labels %v", owner, repo, number, labels)
 	return nil
 }
 
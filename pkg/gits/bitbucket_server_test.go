@@ -4,9 +4,11 @@ package gits_test
 
 import (
 	"context"
+	"encoding/base64"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	bitbucket "github.com/gfleury/go-bitbucket-v1"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
@@ -71,12 +73,16 @@ var bitbucketServerRouter = util.Router{
 	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/pull-requests/1/comments": util.MethodMap{
 		"POST": "pr-comment.json",
 	},
+	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/raw/README.md": util.MethodMap{
+		"GET": "README.md",
+	},
 	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/webhooks": util.MethodMap{
 		"POST": "webhook.json",
 		"GET":  "webhooks.json",
 	},
 	"/rest/api/1.0/projects/TEST-ORG/repos/test-repo/webhooks/123": util.MethodMap{
-		"PUT": "webhook.json",
+		"PUT":    "webhook.json",
+		"DELETE": "webhook.json",
 	},
 	"/rest/api/1.0/users/test-user": util.MethodMap{
 		"GET": "user.json",
@@ -90,6 +96,34 @@ var bitbucketServerRouter = util.Router{
 	"/rest/api/1.0/application-properties": util.MethodMap{
 		"GET": "app-props.json",
 	},
+	"/rest/jira-integration/1.0/servers": util.MethodMap{
+		"GET": "jira-servers.json",
+	},
+}
+
+type fakeJiraIssueTracker struct {
+	issues map[string]*gits.GitIssue
+}
+
+func (f *fakeJiraIssueTracker) GetIssue(key string) (*gits.GitIssue, error) {
+	return f.issues[key], nil
+}
+
+func (f *fakeJiraIssueTracker) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	answer := []*gits.GitIssue{}
+	for _, issue := range f.issues {
+		answer = append(answer, issue)
+	}
+	return answer, nil
+}
+
+func (f *fakeJiraIssueTracker) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	issues, err := f.SearchIssues("")
+	return gits.FilterIssuesClosedSince(issues, t), err
+}
+
+func (f *fakeJiraIssueTracker) IssueURL(key string) string {
+	return "https://jira.example.com/browse/" + key
 }
 
 func (suite *BitbucketServerProviderTestSuite) SetupSuite() {
@@ -129,6 +163,12 @@ func (suite *BitbucketServerProviderTestSuite) SetupSuite() {
 
 	apiKeyAuthContext := context.WithValue(ctx, bitbucket.ContextAccessToken, ua.ApiToken)
 	suite.provider.Client = bitbucket.NewAPIClient(apiKeyAuthContext, cfg)
+	suite.provider.BaseURL = cfg.BasePath
+}
+
+func (suite *BitbucketServerProviderTestSuite) TearDownTest() {
+	suite.provider.IssueTracker = nil
+	suite.provider.JiraProject = ""
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestGetRepository() {
@@ -279,6 +319,18 @@ func (suite *BitbucketServerProviderTestSuite) TestGetPullRequest() {
 	suite.Require().Equal(pr.Title, "Test Pull Request")
 }
 
+func (suite *BitbucketServerProviderTestSuite) TestGetContent() {
+	content, err := suite.provider.GetContent("test-org", "test-repo", "README.md", "master")
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(content)
+	suite.Require().Equal("README.md", content.Name)
+	data, err := base64.StdEncoding.DecodeString(content.Content)
+	suite.Require().Nil(err)
+	suite.Require().Contains(string(data), "This is a test repository.")
+	suite.Require().Equal("http://auth.example.com/projects/TEST-ORG/repos/test-repo/browse/README.md?at=master", content.HtmlUrl)
+}
+
 func (suite *BitbucketServerProviderTestSuite) TestPullRequestCommits() {
 	commits, err := suite.provider.GetPullRequestCommits("TEST-ORG", &gits.GitRepository{
 		URL:     "https://auth.example.com/projects/TEST-ORG/repos/test-repo",
@@ -394,6 +446,17 @@ func (suite *BitbucketServerProviderTestSuite) TestUpdateWebHook() {
 	suite.Require().Nil(err)
 }
 
+func (suite *BitbucketServerProviderTestSuite) TestDeleteWebHook() {
+
+	data := &gits.GitWebHookArguments{
+		Repo:        &gits.GitRepository{URL: "https://auth.example.com/projects/TEST-ORG/repos/test-repo"},
+		ExistingURL: "http://jenkins.example.com/bitbucket-scmsource-hook/notify",
+	}
+	err := suite.provider.DeleteWebHook(data)
+
+	suite.Require().Nil(err)
+}
+
 func (suite *BitbucketServerProviderTestSuite) TestUserInfo() {
 
 	userInfo := suite.provider.UserInfo("test-user")
@@ -406,21 +469,82 @@ func (suite *BitbucketServerProviderTestSuite) TestUserInfo() {
 	}, *userInfo)
 }
 
+func (suite *BitbucketServerProviderTestSuite) TestGetIssueWithoutJira() {
+	issue, err := suite.provider.GetIssue("test-org", "test-repo", 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("", issue.Key)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestGetIssueFromJira() {
+	suite.provider.IssueTracker = &fakeJiraIssueTracker{
+		issues: map[string]*gits.GitIssue{
+			"TEST-ORG-1": {Key: "TEST-ORG-1", Title: "Test issue"},
+		},
+	}
+
+	issue, err := suite.provider.GetIssue("test-org", "test-repo", 1)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("Test issue", issue.Title)
+	suite.Require().Equal("https://jira.example.com/browse/TEST-ORG-1", suite.provider.IssueURL("test-org", "test-repo", 1, false))
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestGetIssueFromJiraProject() {
+	suite.provider.JiraProject = "PROJ"
+	suite.provider.IssueTracker = &fakeJiraIssueTracker{
+		issues: map[string]*gits.GitIssue{
+			"PROJ-7": {Key: "PROJ-7", Title: "Jira project issue"},
+		},
+	}
+
+	issue, err := suite.provider.GetIssue("test-org", "test-repo", 7)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("Jira project issue", issue.Title)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestSearchIssuesFromJira() {
+	suite.provider.IssueTracker = &fakeJiraIssueTracker{
+		issues: map[string]*gits.GitIssue{
+			"TEST-ORG-1": {Key: "TEST-ORG-1", Title: "Test issue"},
+		},
+	}
+
+	issues, err := suite.provider.SearchIssues("test-org", "test-repo", "")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(issues, 1)
+	suite.Require().Equal("TEST-ORG-1", issues[0].Key)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestLinkedJiraServers() {
+	servers, err := suite.provider.LinkedJiraServers()
+
+	suite.Require().Nil(err)
+	suite.Require().Len(servers, 1)
+	suite.Require().Equal("https://jira.example.com", servers[0].URL)
+}
+
+func (suite *BitbucketServerProviderTestSuite) TestAddLabelsToIssue() {
+	err := suite.provider.AddLabelsToIssue("test-org", "test-repo", 1, []string{"updatebot"})
+
+	suite.Require().Nil(err)
+}
+
 func (suite *BitbucketServerProviderTestSuite) TestAddCollaborator() {
 	err := suite.provider.AddCollaborator("derek", orgname, "repo")
 	suite.Require().Nil(err)
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestListInvitations() {
-	invites, res, err := suite.provider.ListInvitations()
+	invites, err := suite.provider.ListInvitations()
 	suite.Require().NotNil(invites)
-	suite.Require().NotNil(res)
 	suite.Require().Nil(err)
 }
 
 func (suite *BitbucketServerProviderTestSuite) TestAcceptInvitations() {
-	res, err := suite.provider.AcceptInvitation(1)
-	suite.Require().NotNil(res)
+	err := suite.provider.AcceptInvitation(1)
 	suite.Require().Nil(err)
 }
 
@@ -1,19 +1,27 @@
 package gits
 
 import (
+	"bytes"
 	"context"
+	"encoding/base64"
 	"fmt"
+	"net/http"
 	"net/url"
 	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 	"time"
 
 	gerrit "github.com/andygrunwald/go-gerrit"
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/pkg/errors"
 )
 
+var commitSHARegex = regexp.MustCompile("^[0-9a-f]{40}$")
+
 type GerritProvider struct {
 	Client   *gerrit.Client
 	Username string
@@ -191,6 +199,11 @@ func (p *GerritProvider) UpdateWebHook(data *GitWebHookArguments) error {
 	return nil
 }
 
+// DeleteWebHook is not supported for this git provider
+func (p *GerritProvider) DeleteWebHook(data *GitWebHookArguments) error {
+	return fmt.Errorf("Deleting webhooks not supported on gerrit")
+}
+
 // ListWebHooks lists all webhooks for the specified repo.
 func (p *GerritProvider) ListWebHooks(org, repo string) ([]*GitWebHookArguments, error) {
 	return nil, nil
@@ -315,18 +328,52 @@ func (p *GerritProvider) AddCollaborator(user string, organisation string, repo
 	return nil
 }
 
-func (p *GerritProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gerrit.")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+// ListInvitations returns no invitations as collaborators cannot be added automatically
+func (p *GerritProvider) ListInvitations() ([]*GitRepositoryInvitation, error) {
+	return []*GitRepositoryInvitation{}, nil
 }
 
-func (p *GerritProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gerrit.")
-	return &github.Response{}, nil
+// AcceptInvitation does nothing as collaborators cannot be added automatically
+func (p *GerritProvider) AcceptInvitation(ID int64) error {
+	return nil
 }
 
+// GetContent returns the content of the file at path in the given ref, defaulting to the HEAD of the project
 func (p *GerritProvider) GetContent(org string, name string, path string, ref string) (*GitFileContent, error) {
-	return nil, fmt.Errorf("Getting content not supported on gerrit")
+	project := buildEncodedProjectName(org, name)
+	path = strings.TrimPrefix(path, "/")
+	if ref == "" {
+		ref = "HEAD"
+	}
+	var u string
+	if commitSHARegex.MatchString(ref) {
+		u = fmt.Sprintf("projects/%s/commits/%s/files/%s/content", project, ref, url.PathEscape(path))
+	} else {
+		u = fmt.Sprintf("projects/%s/branches/%s/files/%s/content", project, url.PathEscape(ref), url.PathEscape(path))
+	}
+	req, err := p.Client.NewRequest(http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	// the content is returned base64 encoded so we read the raw body rather than letting the client decode it as JSON
+	content := &bytes.Buffer{}
+	_, err = p.Client.Do(req, content)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get content of %s in %s at ref %s", path, project, ref)
+	}
+	data, err := base64.StdEncoding.DecodeString(content.String())
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode content of %s in %s at ref %s", path, project, ref)
+	}
+	return &GitFileContent{
+		Type:     "file",
+		Encoding: "base64",
+		Size:     len(data),
+		Name:     filepath.Base(path),
+		Path:     path,
+		Content:  content.String(),
+		Url:      util.UrlJoin(p.Server.URL, u),
+	}, nil
 }
 
 // ShouldForkForPullReques treturns true if we should create a personal fork of this repository
@@ -335,8 +382,96 @@ func (p *GerritProvider) ShouldForkForPullRequest(originalOwner string, repoName
 	return originalOwner != username
 }
 
+// ListCommits lists the commits of the changes merged into the given branch, defaulting to the HEAD branch of the
+// project, using a single change query so that the path, author and date filters are applied by gerrit
 func (p *GerritProvider) ListCommits(owner, repo string, opt *ListCommitsArguments) ([]*GitCommit, error) {
-	return nil, fmt.Errorf("Listing commits not supported on gerrit")
+	project := repo
+	if owner != "" {
+		project = owner + "/" + repo
+	}
+	branch := opt.SHA
+	if commitSHARegex.MatchString(branch) {
+		return nil, fmt.Errorf("Listing commits from a commit SHA not supported on gerrit")
+	}
+	if branch == "" {
+		head, _, err := p.Client.Projects.GetHEAD(project)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to find the HEAD branch of %s", project)
+		}
+		branch = head
+	}
+	branch = strings.TrimPrefix(branch, "refs/heads/")
+
+	query := []string{
+		"project:" + gerritQueryValue(project),
+		"branch:" + gerritQueryValue(branch),
+		"status:merged",
+	}
+	if opt.Path != "" {
+		path := strings.Trim(opt.Path, "/")
+		query = append(query, fmt.Sprintf("(path:%s OR dir:%s)", gerritQueryValue(path), gerritQueryValue(path)))
+	}
+	if opt.Author != "" {
+		query = append(query, "author:"+gerritQueryValue(opt.Author))
+	}
+	if !opt.Since.IsZero() {
+		query = append(query, "mergedafter:"+gerritQueryValue(opt.Since.UTC().Format(gerritTimeFormat)))
+	}
+	if !opt.Until.IsZero() {
+		query = append(query, "mergedbefore:"+gerritQueryValue(opt.Until.UTC().Format(gerritTimeFormat)))
+	}
+
+	perPage := opt.PerPage
+	if perPage <= 0 {
+		perPage = pageSize
+	}
+	queryOpt := &gerrit.QueryChangeOptions{
+		ChangeOptions: gerrit.ChangeOptions{
+			AdditionalFields: []string{"CURRENT_REVISION", "CURRENT_COMMIT"},
+		},
+	}
+	queryOpt.Query = []string{strings.Join(query, " ")}
+	queryOpt.Limit = perPage
+	if opt.Page > 1 {
+		queryOpt.Skip = (opt.Page - 1) * perPage
+	}
+	changes, _, err := p.Client.Changes.QueryChanges(queryOpt)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to query the changes merged into branch %s of %s", branch, project)
+	}
+
+	commits := []*GitCommit{}
+	if changes == nil {
+		return commits, nil
+	}
+	for _, change := range *changes {
+		revision, ok := change.Revisions[change.CurrentRevision]
+		if !ok {
+			continue
+		}
+		commits = append(commits, &GitCommit{
+			SHA:     change.CurrentRevision,
+			Message: revision.Commit.Message,
+			Branch:  branch,
+			Author: &GitUser{
+				Name:  revision.Commit.Author.Name,
+				Email: revision.Commit.Author.Email,
+			},
+			Committer: &GitUser{
+				Name:  revision.Commit.Committer.Name,
+				Email: revision.Commit.Committer.Email,
+			},
+		})
+	}
+	return commits, nil
+}
+
+// gerritTimeFormat the format of the timestamps in gerrit change queries
+const gerritTimeFormat = "2006-01-02 15:04:05"
+
+// gerritQueryValue quotes a value of a gerrit change query operator
+func gerritQueryValue(value string) string {
+	return `"` + strings.Replace(value, `"`, `\"`, -1) + `"`
 }
 
 // AddLabelsToIssue adds labels to issues or pullrequests
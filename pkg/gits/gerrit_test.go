@@ -3,11 +3,14 @@
 package gits_test
 
 import (
+	"encoding/base64"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
@@ -21,6 +24,8 @@ type GerritProviderTestSuite struct {
 	mux      *http.ServeMux
 	server   *httptest.Server
 	provider *gits.GerritProvider
+
+	changesQuery url.Values
 }
 
 var gerritRouter = util.Router{
@@ -30,6 +35,12 @@ var gerritRouter = util.Router{
 	"/a/projects/test-org%2Ftest-user/": util.MethodMap{
 		"PUT": "create-project.json",
 	},
+	"/a/projects/test-repo/HEAD": util.MethodMap{
+		"GET": "head.json",
+	},
+	"/a/projects/test-repo/branches/master/files/README.md/content": util.MethodMap{
+		"GET": "readme-content.txt",
+	},
 }
 
 func (suite *GerritProviderTestSuite) SetupSuite() {
@@ -40,6 +51,11 @@ func (suite *GerritProviderTestSuite) SetupSuite() {
 	for path, methodMap := range gerritRouter {
 		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/gerrit", methodMap))
 	}
+	changes := util.GetMockAPIResponseFromFile("test_data/gerrit", util.MethodMap{"GET": "changes-merged.json"})
+	suite.mux.HandleFunc("/a/changes/", func(w http.ResponseWriter, r *http.Request) {
+		suite.changesQuery = r.URL.Query()
+		changes(w, r)
+	})
 
 	as := auth.AuthServer{
 		URL:         suite.server.URL,
@@ -95,6 +111,56 @@ func (suite *GerritProviderTestSuite) TestCreateRepository() {
 	suite.Require().Equal(fmt.Sprintf("%s:test-org/test-repo", suite.server.URL), repo.SSHURL)
 }
 
+func (suite *GerritProviderTestSuite) TestListCommits() {
+	commits, err := suite.provider.ListCommits("", "test-repo", &gits.ListCommitsArguments{})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal("67ebf73496383c6777035e374d2d664009e2aa5c", commits[0].SHA)
+	suite.Require().Equal("test-user@example.com", commits[0].Author.Email)
+	suite.Require().Equal("184ebe53805e102605d11f6b143486d15c23a09c", commits[1].SHA)
+	suite.Require().Equal(`project:"test-repo" branch:"master" status:merged`, suite.changesQuery.Get("q"))
+	suite.Require().Equal([]string{"CURRENT_REVISION", "CURRENT_COMMIT"}, suite.changesQuery["o"])
+}
+
+func (suite *GerritProviderTestSuite) TestListCommitsWithFilters() {
+	_, err := suite.provider.ListCommits("", "test-repo", &gits.ListCommitsArguments{
+		SHA:     "release",
+		Path:    "charts/",
+		Author:  "test-user@example.com",
+		Since:   time.Date(2019, 1, 14, 11, 0, 0, 0, time.UTC),
+		Until:   time.Date(2019, 1, 15, 0, 0, 0, 0, time.UTC),
+		Page:    2,
+		PerPage: 1,
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(`project:"test-repo" branch:"release" status:merged (path:"charts" OR dir:"charts") `+
+		`author:"test-user@example.com" mergedafter:"2019-01-14 11:00:00" mergedbefore:"2019-01-15 00:00:00"`,
+		suite.changesQuery.Get("q"))
+	suite.Require().Equal("1", suite.changesQuery.Get("n"))
+	suite.Require().Equal("1", suite.changesQuery.Get("S"))
+}
+
+func (suite *GerritProviderTestSuite) TestListCommitsFromSHA() {
+	_, err := suite.provider.ListCommits("", "test-repo", &gits.ListCommitsArguments{
+		SHA: "67ebf73496383c6777035e374d2d664009e2aa5c",
+	})
+
+	suite.Require().Error(err)
+}
+
+func (suite *GerritProviderTestSuite) TestGetContent() {
+	content, err := suite.provider.GetContent("", "test-repo", "README.md", "master")
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(content)
+	suite.Require().Equal("README.md", content.Name)
+	data, err := base64.StdEncoding.DecodeString(content.Content)
+	suite.Require().Nil(err)
+	suite.Require().Contains(string(data), "This is a test repository.")
+}
+
 func TestGerritProviderTestSuite(t *testing.T) {
 	if testing.Short() {
 		t.Skip("skipping GerritProviderTestSuite in short mode")
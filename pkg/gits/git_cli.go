@@ -1128,6 +1128,37 @@ func (g *GitCLI) GetLatestCommitSha(dir string) (string, error) {
 	return g.gitCmdWithOutput(dir, "rev-parse", "HEAD")
 }
 
+// GetLatestCommitTimes returns the time of the latest commit changing each of the files under the given path
+// keyed by the path of the file relative to the root of the repository
+func (g *GitCLI) GetLatestCommitTimes(dir string, path string) (map[string]time.Time, error) {
+	out, err := g.gitCmdWithOutput(dir, "log", "--format=%x1e%ct", "--name-only", "--", path)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the commit log of %s in %s", path, dir)
+	}
+	answer := map[string]time.Time{}
+	for _, commit := range strings.Split(out, "\x1e") {
+		lines := strings.Split(strings.TrimSpace(commit), "\n")
+		if len(lines) == 0 || lines[0] == "" {
+			continue
+		}
+		seconds, err := strconv.ParseInt(strings.TrimSpace(lines[0]), 10, 64)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the commit time %s", lines[0])
+		}
+		commitTime := time.Unix(seconds, 0)
+		for _, name := range lines[1:] {
+			name = strings.TrimSpace(name)
+			if name == "" {
+				continue
+			}
+			if _, ok := answer[name]; !ok {
+				answer[name] = commitTime
+			}
+		}
+	}
+	return answer, nil
+}
+
 // GetFirstCommitSha returns the sha of the first commit
 func (g *GitCLI) GetFirstCommitSha(dir string) (string, error) {
 	return g.gitCmdWithOutput(dir, "rev-list", "--max-parents=0", "HEAD")
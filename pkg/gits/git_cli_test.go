@@ -274,6 +274,43 @@ var _ = Describe("Git CLI", func() {
 			})
 		})
 
+		Describe("#GetLatestCommitTimes", func() {
+			var origCommitterDate string
+
+			BeforeEach(func() {
+				origCommitterDate = os.Getenv("GIT_COMMITTER_DATE")
+				_ = os.Setenv("GIT_COMMITTER_DATE", "@1500000000 +0000")
+				testhelpers.WriteFile(Fail, repoDir, "a.txt", "foo")
+				testhelpers.WriteFile(Fail, repoDir, "logs/b.txt", "foo")
+				testhelpers.Add(Fail, repoDir)
+				testhelpers.Commit(Fail, repoDir, "first commit")
+
+				_ = os.Setenv("GIT_COMMITTER_DATE", "@1600000000 +0000")
+				testhelpers.WriteFile(Fail, repoDir, "logs/b.txt", "bar")
+				testhelpers.Add(Fail, repoDir)
+				testhelpers.Commit(Fail, repoDir, "second commit")
+			})
+
+			AfterEach(func() {
+				_ = os.Setenv("GIT_COMMITTER_DATE", origCommitterDate)
+			})
+
+			Specify("the time of the latest commit of each file is returned", func() {
+				times, err := git.GetLatestCommitTimes(repoDir, ".")
+				Expect(err).Should(BeNil())
+				Expect(times).Should(HaveLen(2))
+				Expect(times["a.txt"].Unix()).Should(Equal(int64(1500000000)))
+				Expect(times["logs/b.txt"].Unix()).Should(Equal(int64(1600000000)))
+			})
+
+			Specify("only the files under the path are returned", func() {
+				times, err := git.GetLatestCommitTimes(repoDir, "logs")
+				Expect(err).Should(BeNil())
+				Expect(times).Should(HaveLen(1))
+				Expect(times).Should(HaveKey("logs/b.txt"))
+			})
+		})
+
 		Describe("#GetCommitPointedToByTag", func() {
 			Context("when there is no commit", func() {
 				Specify("an error is returned", func() {
@@ -508,6 +508,11 @@ func (g *GitFake) GetLatestCommitSha(dir string) (string, error) {
 	return g.Commits[len-1].SHA, nil
 }
 
+// GetLatestCommitTimes returns the time of the latest commit changing each of the files under the given path
+func (g *GitFake) GetLatestCommitTimes(dir string, path string) (map[string]time.Time, error) {
+	return map[string]time.Time{}, nil
+}
+
 // GetFirstCommitSha returns the last commit message
 func (g *GitFake) GetFirstCommitSha(dir string) (string, error) {
 	len := len(g.Commits)
@@ -452,6 +452,11 @@ func (g *GitLocal) GetLatestCommitSha(dir string) (string, error) {
 	return g.GitCLI.GetLatestCommitSha(dir)
 }
 
+// GetLatestCommitTimes returns the time of the latest commit changing each of the files under the given path
+func (g *GitLocal) GetLatestCommitTimes(dir string, path string) (map[string]time.Time, error) {
+	return g.GitCLI.GetLatestCommitTimes(dir, path)
+}
+
 // GetFirstCommitSha gets the first commit sha
 func (g *GitLocal) GetFirstCommitSha(dir string) (string, error) {
 	return g.GitCLI.GetFirstCommitSha(dir)
@@ -11,7 +11,6 @@ import (
 	errors2 "github.com/pkg/errors"
 
 	"code.gitea.io/sdk/gitea"
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
@@ -225,28 +224,26 @@ func (p *GiteaProvider) CreateWebHook(data *GitWebHookArguments) error {
 	if repo == "" {
 		return fmt.Errorf("Missing property URL")
 	}
-	hooks, err := p.Client.ListRepoHooks(owner, repo, gitea.ListHooksOptions{})
+	hooks, err := p.listRepoHooks(owner, repo)
 	if err != nil {
 		return err
 	}
 	for _, hook := range hooks {
 		s := hook.Config["url"]
 		if s == webhookUrl {
+			if data.Secret != "" {
+				// Gitea never returns the secret of a hook so lets make sure the existing hook uses the current one
+				log.Logger().Infof("Updating the secret of the existing Gitea webhook for %s", webhookUrl)
+				return p.editRepoHook(owner, repo, hook.ID, data)
+			}
 			log.Logger().Warnf("Already has a webhook registered for %s", webhookUrl)
 			return nil
 		}
 	}
-	config := map[string]string{
-		"url":          webhookUrl,
-		"content_type": "json",
-	}
-	if data.Secret != "" {
-		config["secret"] = data.Secret
-	}
 	hook := gitea.CreateHookOption{
 		Type:   "gitea",
-		Config: config,
-		Events: []string{"create", "push", "pull_request"},
+		Config: giteaHookConfig(data),
+		Events: giteaHookEvents,
 		Active: true,
 	}
 	log.Logger().Infof("Creating Gitea webhook for %s/%s for url %s", util.ColorInfo(owner), util.ColorInfo(repo), util.ColorInfo(webhookUrl))
@@ -257,13 +254,158 @@ func (p *GiteaProvider) CreateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+// ListWebHooks lists the webhooks on the given repository
 func (p *GiteaProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArguments, error) {
 	webHooks := []*GitWebHookArguments{}
-	return webHooks, fmt.Errorf("not implemented!")
+	if owner == "" {
+		owner = p.Username
+	}
+	if repo == "" {
+		return webHooks, fmt.Errorf("Missing property Repo")
+	}
+	hooks, err := p.listRepoHooks(owner, repo)
+	if err != nil {
+		return webHooks, errors2.Wrapf(err, "failed to list webhooks on repository %s/%s", owner, repo)
+	}
+	for _, hook := range hooks {
+		webHooks = append(webHooks, &GitWebHookArguments{
+			ID:    hook.ID,
+			Owner: owner,
+			Repo: &GitRepository{
+				Organisation: owner,
+				Name:         repo,
+			},
+			URL: hook.Config["url"],
+		})
+	}
+	return webHooks, nil
 }
 
+// UpdateWebHook updates the webhook with the given ID or, if no ID is specified, the webhook registered
+// for the ExistingURL. The secret of the webhook is replaced if one is specified
 func (p *GiteaProvider) UpdateWebHook(data *GitWebHookArguments) error {
-	return fmt.Errorf("not implemented!")
+	owner := data.Owner
+	if owner == "" {
+		owner = p.Username
+	}
+	repo := data.Repo.Name
+	if repo == "" {
+		return fmt.Errorf("Missing property Repo")
+	}
+	if data.URL == "" {
+		return fmt.Errorf("Missing property URL")
+	}
+	id, err := p.findRepoHookID(owner, repo, data)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		log.Logger().Warn("No webhooks found to update")
+		return nil
+	}
+	log.Logger().Infof("Updating Gitea webhook for %s/%s for url %s", util.ColorInfo(owner), util.ColorInfo(repo), util.ColorInfo(data.URL))
+	return p.editRepoHook(owner, repo, id, data)
+}
+
+// DeleteWebHook deletes the webhook with the given ID or, if no ID is specified, the webhook registered
+// for the ExistingURL or URL
+func (p *GiteaProvider) DeleteWebHook(data *GitWebHookArguments) error {
+	owner := data.Owner
+	if owner == "" {
+		owner = p.Username
+	}
+	repo := data.Repo.Name
+	if repo == "" {
+		return fmt.Errorf("Missing property Repo")
+	}
+	id, err := p.findRepoHookID(owner, repo, data)
+	if err != nil {
+		return err
+	}
+	if id == 0 {
+		log.Logger().Warn("No webhooks found to delete")
+		return nil
+	}
+	log.Logger().Infof("Deleting Gitea webhook %d for %s/%s", id, util.ColorInfo(owner), util.ColorInfo(repo))
+	err = p.Client.DeleteRepoHook(owner, repo, id)
+	if err != nil {
+		return errors2.Wrapf(err, "failed to delete webhook %d on repository %s/%s", id, owner, repo)
+	}
+	return nil
+}
+
+// giteaHookEvents are the events we register webhooks for
+var giteaHookEvents = []string{"create", "push", "pull_request"}
+
+func giteaHookConfig(data *GitWebHookArguments) map[string]string {
+	config := map[string]string{
+		"url":          data.URL,
+		"content_type": "json",
+	}
+	if data.Secret != "" {
+		config["secret"] = data.Secret
+	}
+	return config
+}
+
+func (p *GiteaProvider) editRepoHook(owner string, repo string, id int64, data *GitWebHookArguments) error {
+	active := true
+	opt := gitea.EditHookOption{
+		Config: giteaHookConfig(data),
+		Events: giteaHookEvents,
+		Active: &active,
+	}
+	err := p.Client.EditRepoHook(owner, repo, id, opt)
+	if err != nil {
+		return errors2.Wrapf(err, "failed to update webhook %d on repository %s/%s", id, owner, repo)
+	}
+	return nil
+}
+
+// findRepoHookID returns the ID of the webhook described by the data, or 0 if there is no such webhook
+func (p *GiteaProvider) findRepoHookID(owner string, repo string, data *GitWebHookArguments) (int64, error) {
+	if data.ID != 0 {
+		return data.ID, nil
+	}
+	hookURL := data.ExistingURL
+	if hookURL == "" {
+		hookURL = data.URL
+	}
+	if hookURL == "" {
+		return 0, nil
+	}
+	hooks, err := p.listRepoHooks(owner, repo)
+	if err != nil {
+		return 0, errors2.Wrapf(err, "failed to list webhooks on repository %s/%s", owner, repo)
+	}
+	for _, hook := range hooks {
+		if hook.Config["url"] == hookURL {
+			return hook.ID, nil
+		}
+	}
+	return 0, nil
+}
+
+func (p *GiteaProvider) listRepoHooks(owner string, repo string) ([]*gitea.Hook, error) {
+	opt := gitea.ListHooksOptions{
+		ListOptions: gitea.ListOptions{
+			Page:     1,
+			PageSize: pageSize,
+		},
+	}
+	answer := []*gitea.Hook{}
+	for {
+		hooks, err := p.Client.ListRepoHooks(owner, repo, opt)
+		if err != nil {
+			return answer, err
+		}
+		answer = append(answer, hooks...)
+		if len(hooks) < pageSize || len(hooks) == 0 {
+			break
+		}
+		opt.Page++
+	}
+	return answer, nil
 }
 
 func (p *GiteaProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
@@ -581,7 +723,7 @@ func (p *GiteaProvider) ListCommitStatus(org string, repo string, sha string) ([
 	}
 	for _, result := range results {
 		status := &GitRepoStatus{
-			ID:          string(result.ID),
+			ID:          strconv.FormatInt(result.ID, 10),
 			Context:     result.Context,
 			URL:         result.URL,
 			TargetURL:   result.TargetURL,
@@ -771,23 +913,50 @@ func (p *GiteaProvider) UserInfo(username string) *GitUser {
 	}
 }
 
+// AddCollaborator adds the user as a collaborator with write permission to the repository
 func (p *GiteaProvider) AddCollaborator(user string, organisation string, repo string) error {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Gitea. Please add user: %v as a collaborator to this project.", user)
+	log.Logger().Infof("Automatically adding the pipeline user: %v as a collaborator.", user)
+	permission := "write"
+	err := p.Client.AddCollaborator(organisation, repo, user, gitea.AddCollaboratorOption{
+		Permission: &permission,
+	})
+	if err != nil {
+		return errors2.Wrapf(err, "failed to add %s as a collaborator to %s/%s", user, organisation, repo)
+	}
 	return nil
 }
 
-func (p *GiteaProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Gitea.")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+// ListInvitations lists pending invites. Gitea adds collaborators immediately so there are never any pending invitations
+func (p *GiteaProvider) ListInvitations() ([]*GitRepositoryInvitation, error) {
+	return []*GitRepositoryInvitation{}, nil
 }
 
-func (p *GiteaProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for Gitea.")
-	return &github.Response{}, nil
+// AcceptInvitation accepts an invitation. Gitea adds collaborators immediately so there is nothing to accept
+func (p *GiteaProvider) AcceptInvitation(ID int64) error {
+	return nil
 }
 
 func (p *GiteaProvider) GetContent(org string, name string, path string, ref string) (*GitFileContent, error) {
-	return nil, fmt.Errorf("Getting content not supported on gitea")
+	contents, err := p.Client.GetContents(org, name, ref, strings.TrimPrefix(path, "/"))
+	if err != nil {
+		return nil, errors2.Wrapf(err, "failed to get content of %s in %s/%s at ref %s", path, org, name, ref)
+	}
+	if contents.Type != "file" {
+		return nil, fmt.Errorf("Directory Content not yet supported")
+	}
+	return &GitFileContent{
+		Type:        contents.Type,
+		Encoding:    util.DereferenceString(contents.Encoding),
+		Size:        int(contents.Size),
+		Name:        contents.Name,
+		Path:        contents.Path,
+		Content:     util.DereferenceString(contents.Content),
+		Sha:         contents.SHA,
+		Url:         util.DereferenceString(contents.URL),
+		GitUrl:      util.DereferenceString(contents.GitURL),
+		HtmlUrl:     util.DereferenceString(contents.HTMLURL),
+		DownloadUrl: util.DereferenceString(contents.DownloadURL),
+	}, nil
 }
 
 // ShouldForkForPullReques treturns true if we should create a personal fork of this repository
@@ -796,8 +965,127 @@ func (p *GiteaProvider) ShouldForkForPullRequest(originalOwner string, repoName
 	return originalOwner != username
 }
 
+// ListCommits lists the commits for the specified repo and owner. Gitea cannot filter the commits it lists so the path,
+// author, since and until options are applied on the client
 func (p *GiteaProvider) ListCommits(owner, repo string, opt *ListCommitsArguments) ([]*GitCommit, error) {
-	return nil, fmt.Errorf("Listing commits not supported on gitea")
+	parents := map[string][]string{}
+	fetch := func(page int, perPage int) ([]listedCommit, error) {
+		giteaOpt := gitea.ListCommitOptions{
+			ListOptions: gitea.ListOptions{
+				Page:     page,
+				PageSize: perPage,
+			},
+			SHA: opt.SHA,
+		}
+		giteaCommits, err := p.Client.ListRepoCommits(owner, repo, giteaOpt)
+		if err != nil {
+			return nil, errors2.Wrapf(err, "failed to list commits for repository %s/%s", owner, repo)
+		}
+		listed := []listedCommit{}
+		for _, commit := range giteaCommits {
+			if commit == nil || commit.CommitMeta == nil {
+				continue
+			}
+			c := listedCommit{
+				commit: &GitCommit{
+					SHA: commit.SHA,
+					URL: commit.HTMLURL,
+				},
+			}
+			if commit.RepoCommit != nil {
+				c.commit.Message = commit.RepoCommit.Message
+				if commit.RepoCommit.Author != nil {
+					c.commit.Author = &GitUser{
+						Name:  commit.RepoCommit.Author.Name,
+						Email: commit.RepoCommit.Author.Email,
+					}
+				}
+				if commit.RepoCommit.Committer != nil {
+					c.commit.Committer = &GitUser{
+						Name:  commit.RepoCommit.Committer.Name,
+						Email: commit.RepoCommit.Committer.Email,
+					}
+					date, err := time.Parse(time.RFC3339, commit.RepoCommit.Committer.Date)
+					if err == nil {
+						c.date = date
+					}
+				}
+			}
+			// prefer the Gitea user over the raw git identity as it includes the login and avatar
+			if commit.Author != nil {
+				c.commit.Author = toGiteaUser(commit.Author)
+			}
+			for _, parent := range commit.Parents {
+				if parent != nil {
+					parents[commit.SHA] = append(parents[commit.SHA], parent.SHA)
+				}
+			}
+			listed = append(listed, c)
+		}
+		return listed, nil
+	}
+	if opt.Path == "" && opt.Author == "" && opt.Since.IsZero() && opt.Until.IsZero() {
+		listed, err := fetch(opt.Page, opt.PerPage)
+		if err != nil {
+			return nil, err
+		}
+		return listedCommits(listed), nil
+	}
+
+	var changesPath func(commit *GitCommit) (bool, error)
+	if opt.Path != "" {
+		pathSHAs := map[string]string{}
+		pathSHA := func(ref string) (string, error) {
+			sha, ok := pathSHAs[ref]
+			if !ok {
+				var err error
+				sha, err = p.pathSHA(owner, repo, ref, opt.Path)
+				if err != nil {
+					return "", err
+				}
+				pathSHAs[ref] = sha
+			}
+			return sha, nil
+		}
+		changesPath = func(commit *GitCommit) (bool, error) {
+			sha, err := pathSHA(commit.SHA)
+			if err != nil {
+				return false, err
+			}
+			parentSHA := ""
+			if len(parents[commit.SHA]) > 0 {
+				parentSHA, err = pathSHA(parents[commit.SHA][0])
+				if err != nil {
+					return false, err
+				}
+			}
+			return sha != parentSHA, nil
+		}
+	}
+	return opt.filterCommits(fetch, changesPath)
+}
+
+// pathSHA returns the SHA of the blob or tree at the given path of the given commit or an empty string if there is
+// nothing at this path
+func (p *GiteaProvider) pathSHA(owner, repo, ref, path string) (string, error) {
+	sha := ref
+	for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+		tree, err := p.Client.GetTrees(owner, repo, sha, false)
+		if err != nil {
+			return "", errors2.Wrapf(err, "failed to get the tree %s of repository %s/%s", sha, owner, repo)
+		}
+		sha = ""
+		for _, entry := range tree.Entries {
+			if entry.Path == name {
+				sha = entry.SHA
+				break
+			}
+		}
+		if sha == "" {
+			return "", nil
+		}
+	}
+	return sha, nil
 }
 
 // AddLabelsToIssue adds labels to issues or pullrequests
@@ -0,0 +1,295 @@
+// +build unit
+
+package gits_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"code.gitea.io/sdk/gitea"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/stretchr/testify/suite"
+)
+
+type GiteaProviderTestSuite struct {
+	suite.Suite
+	mux           *http.ServeMux
+	server        *httptest.Server
+	provider      *gits.GiteaProvider
+	editedHooks   []gitea.EditHookOption
+	deletedHooks  int
+	collaborators []gitea.AddCollaboratorOption
+}
+
+var giteaRouter = util.Router{
+	"/api/v1/repos/test-user/test-repo/hooks": util.MethodMap{
+		"GET":  "hooks.json",
+		"POST": "hook.json",
+	},
+}
+
+func (suite *GiteaProviderTestSuite) SetupSuite() {
+	suite.mux = http.NewServeMux()
+
+	for path, methodMap := range giteaRouter {
+		suite.mux.HandleFunc(path, util.GetMockAPIResponseFromFile("test_data/gitea", methodMap))
+	}
+	suite.mux.HandleFunc("/api/v1/repos/test-user/test-repo/hooks/7", suite.handleHook)
+	suite.mux.HandleFunc("/api/v1/repos/test-user/test-repo/collaborators/pipeline-user", suite.handleCollaborator)
+	suite.mux.HandleFunc("/api/v1/repos/test-user/test-repo/commits", suite.handleCommits)
+	suite.mux.HandleFunc("/api/v1/repos/test-user/test-repo/git/trees/", suite.handleTree)
+
+	suite.server = httptest.NewServer(suite.mux)
+	suite.Require().NotNil(suite.server)
+
+	as := auth.AuthServer{
+		URL:         suite.server.URL,
+		Name:        "Test Gitea Server",
+		Kind:        "gitea",
+		CurrentUser: "test-user",
+	}
+	ua := auth.UserAuth{
+		Username: "test-user",
+		ApiToken: "0123456789abdef",
+	}
+
+	gp, err := gits.NewGiteaProvider(&as, &ua, gits.NewGitCLI())
+	suite.Require().Nil(err)
+
+	var ok bool
+	suite.provider, ok = gp.(*gits.GiteaProvider)
+	suite.Require().True(ok)
+	suite.Require().NotNil(suite.provider)
+}
+
+func (suite *GiteaProviderTestSuite) SetupTest() {
+	suite.editedHooks = nil
+	suite.deletedHooks = 0
+	suite.collaborators = nil
+}
+
+func (suite *GiteaProviderTestSuite) handleHook(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPatch:
+		opt := gitea.EditHookOption{}
+		err := json.NewDecoder(r.Body).Decode(&opt)
+		suite.Require().Nil(err)
+		suite.editedHooks = append(suite.editedHooks, opt)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		suite.deletedHooks++
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func (suite *GiteaProviderTestSuite) handleCollaborator(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	opt := gitea.AddCollaboratorOption{}
+	err := json.NewDecoder(r.Body).Decode(&opt)
+	suite.Require().Nil(err)
+	suite.collaborators = append(suite.collaborators, opt)
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (suite *GiteaProviderTestSuite) handleCommits(w http.ResponseWriter, r *http.Request) {
+	commit := func(sha string, author string, date string, parents ...string) *gitea.Commit {
+		c := &gitea.Commit{
+			CommitMeta: &gitea.CommitMeta{SHA: sha},
+			RepoCommit: &gitea.RepoCommit{
+				Message: "commit " + sha,
+				Author: &gitea.CommitUser{
+					Identity: gitea.Identity{Name: author, Email: author + "@example.com"},
+					Date:     date,
+				},
+				Committer: &gitea.CommitUser{
+					Identity: gitea.Identity{Name: author, Email: author + "@example.com"},
+					Date:     date,
+				},
+			},
+		}
+		for _, parent := range parents {
+			c.Parents = append(c.Parents, &gitea.CommitMeta{SHA: parent})
+		}
+		return c
+	}
+	err := json.NewEncoder(w).Encode([]*gitea.Commit{
+		commit("c3", "alice", "2020-03-03T10:00:00Z", "c2"),
+		commit("c2", "bob", "2020-03-02T10:00:00Z", "c1"),
+		commit("c1", "alice", "2020-03-01T10:00:00Z"),
+	})
+	suite.Require().Nil(err)
+}
+
+func (suite *GiteaProviderTestSuite) handleTree(w http.ResponseWriter, r *http.Request) {
+	trees := map[string][]gitea.GitEntry{
+		"c3": {{Path: "charts", Type: "tree", SHA: "t3"}},
+		"t3": {{Path: "app", Type: "tree", SHA: "a2"}},
+		"c2": {{Path: "charts", Type: "tree", SHA: "t2"}, {Path: "README.md", Type: "blob", SHA: "r1"}},
+		"t2": {{Path: "app", Type: "tree", SHA: "a2"}},
+		"c1": {{Path: "charts", Type: "tree", SHA: "t1"}},
+		"t1": {{Path: "app", Type: "tree", SHA: "a1"}},
+	}
+	sha := strings.TrimPrefix(r.URL.Path, "/api/v1/repos/test-user/test-repo/git/trees/")
+	entries, ok := trees[sha]
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	err := json.NewEncoder(w).Encode(gitea.GitTreeResponse{SHA: sha, Entries: entries})
+	suite.Require().Nil(err)
+}
+
+func (suite *GiteaProviderTestSuite) TestListCommits() {
+	commits, err := suite.provider.ListCommits("test-user", "test-repo", &gits.ListCommitsArguments{})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 3)
+	suite.Require().Equal("c3", commits[0].SHA)
+	suite.Require().Equal("alice@example.com", commits[0].Author.Email)
+}
+
+func (suite *GiteaProviderTestSuite) TestListCommitsByPath() {
+	commits, err := suite.provider.ListCommits("test-user", "test-repo", &gits.ListCommitsArguments{
+		Path: "charts/app",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal("c2", commits[0].SHA)
+	suite.Require().Equal("c1", commits[1].SHA)
+}
+
+func (suite *GiteaProviderTestSuite) TestListCommitsByAuthorAndDate() {
+	commits, err := suite.provider.ListCommits("test-user", "test-repo", &gits.ListCommitsArguments{
+		Author: "alice@example.com",
+		Since:  time.Date(2020, 3, 2, 0, 0, 0, 0, time.UTC),
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 1)
+	suite.Require().Equal("c3", commits[0].SHA)
+
+	commits, err = suite.provider.ListCommits("test-user", "test-repo", &gits.ListCommitsArguments{
+		Until: time.Date(2020, 3, 2, 12, 0, 0, 0, time.UTC),
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal("c2", commits[0].SHA)
+	suite.Require().Equal("c1", commits[1].SHA)
+}
+
+func (suite *GiteaProviderTestSuite) TestAddCollaborator() {
+	err := suite.provider.AddCollaborator("pipeline-user", "test-user", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(suite.collaborators, 1)
+	suite.Require().Equal("write", *suite.collaborators[0].Permission)
+}
+
+func (suite *GiteaProviderTestSuite) TestListInvitations() {
+	invites, err := suite.provider.ListInvitations()
+
+	suite.Require().Nil(err)
+	suite.Require().Empty(invites)
+}
+
+func (suite *GiteaProviderTestSuite) TestListWebHooks() {
+	webHooks, err := suite.provider.ListWebHooks("test-user", "test-repo")
+
+	suite.Require().Nil(err)
+	suite.Require().Len(webHooks, 1)
+	suite.Require().Equal(int64(7), webHooks[0].ID)
+	suite.Require().Equal("http://hook.jx.example.com/hook", webHooks[0].URL)
+	suite.Require().Equal("test-repo", webHooks[0].Repo.Name)
+}
+
+func (suite *GiteaProviderTestSuite) TestUpdateWebHook() {
+	data := &gits.GitWebHookArguments{
+		Owner:       "test-user",
+		Repo:        &gits.GitRepository{Name: "test-repo"},
+		URL:         "http://hook.jx.example.com/new",
+		ExistingURL: "http://hook.jx.example.com/hook",
+		Secret:      "newSecret",
+	}
+	err := suite.provider.UpdateWebHook(data)
+
+	suite.Require().Nil(err)
+	suite.Require().Len(suite.editedHooks, 1)
+	suite.Require().Equal("http://hook.jx.example.com/new", suite.editedHooks[0].Config["url"])
+	suite.Require().Equal("newSecret", suite.editedHooks[0].Config["secret"])
+}
+
+func (suite *GiteaProviderTestSuite) TestCreateWebHookRotatesSecret() {
+	data := &gits.GitWebHookArguments{
+		Owner:  "test-user",
+		Repo:   &gits.GitRepository{Name: "test-repo"},
+		URL:    "http://hook.jx.example.com/hook",
+		Secret: "rotatedSecret",
+	}
+	err := suite.provider.CreateWebHook(data)
+
+	suite.Require().Nil(err)
+	suite.Require().Len(suite.editedHooks, 1)
+	suite.Require().Equal("rotatedSecret", suite.editedHooks[0].Config["secret"])
+}
+
+func (suite *GiteaProviderTestSuite) TestCreateWebHook() {
+	data := &gits.GitWebHookArguments{
+		Owner:  "test-user",
+		Repo:   &gits.GitRepository{Name: "test-repo"},
+		URL:    "http://hook.jx.example.com/other",
+		Secret: "someSecret",
+	}
+	err := suite.provider.CreateWebHook(data)
+
+	suite.Require().Nil(err)
+	suite.Require().Empty(suite.editedHooks)
+}
+
+func (suite *GiteaProviderTestSuite) TestDeleteWebHook() {
+	data := &gits.GitWebHookArguments{
+		Owner: "test-user",
+		Repo:  &gits.GitRepository{Name: "test-repo"},
+		URL:   "http://hook.jx.example.com/hook",
+	}
+	err := suite.provider.DeleteWebHook(data)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(1, suite.deletedHooks)
+}
+
+func (suite *GiteaProviderTestSuite) TestDeleteWebHookNotFound() {
+	data := &gits.GitWebHookArguments{
+		Owner: "test-user",
+		Repo:  &gits.GitRepository{Name: "test-repo"},
+		URL:   "http://hook.jx.example.com/unknown",
+	}
+	err := suite.provider.DeleteWebHook(data)
+
+	suite.Require().Nil(err)
+	suite.Require().Equal(0, suite.deletedHooks)
+}
+
+func TestGiteaProviderTestSuite(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping GiteaProviderTestSuite in short mode")
+	} else {
+		suite.Run(t, new(GiteaProviderTestSuite))
+	}
+}
+
+func (suite *GiteaProviderTestSuite) TearDownSuite() {
+	suite.server.Close()
+}
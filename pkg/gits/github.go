@@ -589,6 +589,45 @@ func (p *GitHubProvider) UpdateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+// DeleteWebHook deletes the webhook with the given ID or, if no ID is specified, the webhook registered
+// for the ExistingURL or URL
+func (p *GitHubProvider) DeleteWebHook(data *GitWebHookArguments) error {
+	owner := data.Owner
+	if owner == "" {
+		owner = p.Username
+	}
+	repo := data.Repo.Name
+	if repo == "" {
+		return fmt.Errorf("Missing property Repo")
+	}
+	id := data.ID
+	if id == 0 {
+		hookURL := data.ExistingURL
+		if hookURL == "" {
+			hookURL = data.URL
+		}
+		hooks, err := p.ListWebHooks(owner, repo)
+		if err != nil {
+			return err
+		}
+		for _, hook := range hooks {
+			if hookURL != "" && hook.URL == hookURL {
+				id = hook.ID
+			}
+		}
+	}
+	if id == 0 {
+		log.Logger().Warn("No webhooks found to delete")
+		return nil
+	}
+	log.Logger().Infof("Deleting GitHub webhook %d for %s/%s", id, util.ColorInfo(owner), util.ColorInfo(repo))
+	_, err := p.Client.Repositories.DeleteHook(p.Context, owner, repo, id)
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete webhook on %s/%s with ID %v", owner, repo, id)
+	}
+	return nil
+}
+
 func (p *GitHubProvider) CreatePullRequest(data *GitPullRequestArguments) (*GitPullRequest, error) {
 	owner := data.GitRepository.Organisation
 	repo := data.GitRepository.Name
@@ -1399,13 +1438,62 @@ func (p *GitHubProvider) AddCollaborator(user string, organisation string, repo
 	return nil
 }
 
-func (p *GitHubProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	return p.Client.Users.ListInvitations(p.Context, &github.ListOptions{})
+// ListInvitations lists the pending repository invitations of the current user
+func (p *GitHubProvider) ListInvitations() ([]*GitRepositoryInvitation, error) {
+	answer := []*GitRepositoryInvitation{}
+	opt := &github.ListOptions{
+		PerPage: pageSize,
+	}
+	for {
+		invites, resp, err := p.Client.Users.ListInvitations(p.Context, opt)
+		if err != nil {
+			return answer, err
+		}
+		for _, invite := range invites {
+			answer = append(answer, toGitHubInvitation(invite))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opt.Page = resp.NextPage
+	}
+	return answer, nil
 }
 
-func (p *GitHubProvider) AcceptInvitation(ID int64) (*github.Response, error) {
+// AcceptInvitation accepts the repository invitation with the given ID
+func (p *GitHubProvider) AcceptInvitation(ID int64) error {
+	_, err := p.Client.Users.AcceptInvitation(p.Context, ID)
+	if err != nil {
+		return err
+	}
 	log.Logger().Infof("Automatically accepted invitation: %v for the pipeline user.", ID)
-	return p.Client.Users.AcceptInvitation(p.Context, ID)
+	return nil
+}
+
+func toGitHubInvitation(invite *github.RepositoryInvitation) *GitRepositoryInvitation {
+	answer := &GitRepositoryInvitation{
+		ID:          invite.GetID(),
+		Permissions: invite.GetPermissions(),
+		URL:         invite.GetHTMLURL(),
+	}
+	if invite.Repo != nil {
+		answer.Repo = toGitHubRepo(invite.Repo.GetName(), invite.Repo.GetOwner().GetLogin(), invite.Repo)
+	}
+	if invite.Invitee != nil {
+		answer.Invitee = &GitUser{
+			Login: invite.Invitee.GetLogin(),
+			Name:  invite.Invitee.GetName(),
+			URL:   invite.Invitee.GetHTMLURL(),
+		}
+	}
+	if invite.Inviter != nil {
+		answer.Inviter = &GitUser{
+			Login: invite.Inviter.GetLogin(),
+			Name:  invite.Inviter.GetName(),
+			URL:   invite.Inviter.GetHTMLURL(),
+		}
+	}
+	return answer
 }
 
 // ShouldForkForPullRequest returns true if we should create a personal fork of this repository
@@ -1441,8 +1529,11 @@ func asText(text *string) string {
 }
 func (p *GitHubProvider) ListCommits(owner, repo string, opt *ListCommitsArguments) ([]*GitCommit, error) {
 	githubOpt := &github.CommitsListOptions{
-		SHA:  opt.SHA,
-		Path: opt.Path,
+		SHA:    opt.SHA,
+		Path:   opt.Path,
+		Author: opt.Author,
+		Since:  opt.Since,
+		Until:  opt.Until,
 		ListOptions: github.ListOptions{
 			Page:    opt.Page,
 			PerPage: opt.PerPage,
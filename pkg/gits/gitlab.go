@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -11,7 +12,6 @@ import (
 
 	errors2 "github.com/pkg/errors"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
@@ -617,6 +617,42 @@ func (g *GitlabProvider) UpdateWebHook(data *GitWebHookArguments) error {
 	return err
 }
 
+// DeleteWebHook deletes the webhook with the given ID or, if no ID is specified, the webhook registered
+// for the ExistingURL or URL
+func (g *GitlabProvider) DeleteWebHook(data *GitWebHookArguments) error {
+	owner := owner(data.Owner, g.Username)
+	pid, err := g.projectId(owner, g.Username, data.Repo.Name)
+	if err != nil {
+		return err
+	}
+	id := int(data.ID)
+	if id == 0 {
+		hookURLs := []string{data.ExistingURL}
+		if data.ExistingURL == "" && data.URL != "" {
+			// CreateWebHook registers the URL with the owner and repository appended
+			hookURLs = []string{data.URL, util.UrlJoin(data.URL, owner, data.Repo.Name)}
+		}
+		hooks, err := g.ListWebHooks(owner, data.Repo.Name)
+		if err != nil {
+			return err
+		}
+		for _, hook := range hooks {
+			if hook.URL != "" && util.StringArrayIndex(hookURLs, hook.URL) >= 0 {
+				id = int(hook.ID)
+			}
+		}
+	}
+	if id == 0 {
+		log.Logger().Warn("No webhooks found to delete")
+		return nil
+	}
+	_, err = g.Client.Projects.DeleteProjectHook(pid, id)
+	if err != nil {
+		return errors2.Wrapf(err, "failed to delete webhook %d on %s/%s", id, owner, data.Repo.Name)
+	}
+	return nil
+}
+
 func (g *GitlabProvider) SearchIssues(org, repo, query string) ([]*GitIssue, error) {
 	opt := &gitlab.ListProjectIssuesOptions{Search: &query}
 	return g.searchIssuesWithOptions(org, repo, opt)
@@ -853,25 +889,54 @@ func (g *GitlabProvider) AddCollaborator(user string, organisation string, repo
 		UserID:      userId,
 		AccessLevel: &accessLevel,
 	}
-	_, _, err = g.Client.ProjectMembers.AddProjectMember(pid, opts)
+	_, resp, err := g.Client.ProjectMembers.AddProjectMember(pid, opts)
+	if err != nil && resp != nil && resp.StatusCode == http.StatusConflict {
+		// the user is already a member of the project
+		log.Logger().Debugf("%s is already a member of %s/%s", user, organisation, repo)
+		return nil
+	}
 	return err
 }
 
-// ListInvitations lists pending invites
-func (g *GitlabProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gitlab.")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+// ListInvitations lists pending invites. GitLab adds project members immediately so there are never any pending invitations
+func (g *GitlabProvider) ListInvitations() ([]*GitRepositoryInvitation, error) {
+	return []*GitRepositoryInvitation{}, nil
 }
 
-// AcceptInvitation accepts an invitation
-func (g *GitlabProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for gitlab.")
-	return &github.Response{}, nil
+// AcceptInvitation accepts an invitation. GitLab adds project members immediately so there is nothing to accept
+func (g *GitlabProvider) AcceptInvitation(ID int64) error {
+	return nil
 }
 
 // GetContent returns the content of a file
 func (g *GitlabProvider) GetContent(org string, name string, path string, ref string) (*GitFileContent, error) {
-	return nil, fmt.Errorf("Getting content not supported on gitlab")
+	pid, err := g.projectId(org, g.Username, name)
+	if err != nil {
+		return nil, err
+	}
+	if ref == "" {
+		project, _, err := g.Client.Projects.GetProject(pid, nil)
+		if err != nil {
+			return nil, errors2.Wrapf(err, "failed to find the default branch of %s/%s", org, name)
+		}
+		ref = project.DefaultBranch
+	}
+	path = strings.TrimPrefix(path, "/")
+	file, _, err := g.Client.RepositoryFiles.GetFile(pid, path, &gitlab.GetFileOptions{Ref: &ref})
+	if err != nil {
+		return nil, errors2.Wrapf(err, "failed to get content of %s in %s/%s at ref %s", path, org, name, ref)
+	}
+	return &GitFileContent{
+		Type:     "file",
+		Encoding: file.Encoding,
+		Size:     file.Size,
+		Name:     file.FileName,
+		Path:     file.FilePath,
+		Content:  file.Content,
+		Sha:      file.BlobID,
+		Url:      util.UrlJoin(g.Server.URL, org, name, "-", "raw", ref, path),
+		HtmlUrl:  util.UrlJoin(g.Server.URL, org, name, "-", "blob", ref, path),
+	}, nil
 }
 
 // ShouldForkForPullReques treturns true if we should create a personal fork of this repository
@@ -887,9 +952,70 @@ func GitlabAccessTokenURL(url string) string {
 	return util.UrlJoin(url, "/profile/personal_access_tokens")
 }
 
-// ListCommits lists the commits for the specified repo and owner
+// ListCommits lists the commits for the specified repo and owner. GitLab cannot list the commits of an author so they
+// are filtered on the client
 func (g *GitlabProvider) ListCommits(owner, repo string, opt *ListCommitsArguments) ([]*GitCommit, error) {
-	return nil, fmt.Errorf("Listing commits not supported on gitlab")
+	pid, err := g.projectId(owner, g.Username, repo)
+	if err != nil {
+		return nil, err
+	}
+	gitlabOpt := &gitlab.ListCommitsOptions{}
+	if opt.SHA != "" {
+		gitlabOpt.RefName = &opt.SHA
+	}
+	if opt.Path != "" {
+		gitlabOpt.Path = &opt.Path
+	}
+	if !opt.Since.IsZero() {
+		gitlabOpt.Since = &opt.Since
+	}
+	if !opt.Until.IsZero() {
+		gitlabOpt.Until = &opt.Until
+	}
+	fetch := func(page int, perPage int) ([]listedCommit, error) {
+		gitlabOpt.ListOptions = gitlab.ListOptions{
+			Page:    page,
+			PerPage: perPage,
+		}
+		gitlabCommits, _, err := g.Client.Commits.ListCommits(pid, gitlabOpt)
+		if err != nil {
+			return nil, errors2.Wrapf(err, "failed to list commits for repository %s/%s", owner, repo)
+		}
+		listed := []listedCommit{}
+		for _, commit := range gitlabCommits {
+			if commit == nil {
+				continue
+			}
+			c := listedCommit{
+				commit: &GitCommit{
+					SHA:     commit.ID,
+					Message: commit.Message,
+					URL:     util.UrlJoin(g.Server.URL, owner, repo, "-", "commit", commit.ID),
+					Author: &GitUser{
+						Name:  commit.AuthorName,
+						Email: commit.AuthorEmail,
+					},
+					Committer: &GitUser{
+						Name:  commit.CommitterName,
+						Email: commit.CommitterEmail,
+					},
+				},
+			}
+			if commit.CommittedDate != nil {
+				c.date = *commit.CommittedDate
+			}
+			listed = append(listed, c)
+		}
+		return listed, nil
+	}
+	if opt.Author != "" {
+		return opt.filterCommits(fetch, nil)
+	}
+	listed, err := fetch(opt.Page, opt.PerPage)
+	if err != nil {
+		return nil, err
+	}
+	return listedCommits(listed), nil
 }
 
 // AddLabelsToIssue adds labels to issues or pullrequests
@@ -111,6 +111,12 @@ func configureGitlabMock(suite *GitlabProviderSuite, mux *http.ServeMux) {
 		fmt.Sprintf("/api/v4/projects/%s/members", gitlabProjectID): util.MethodMap{
 			"POST": "add-project-member.json",
 		},
+		"/api/v4/projects/5860291/repository/commits": util.MethodMap{
+			"GET": "commits.json",
+		},
+		"/api/v4/projects/5860291/repository/files/README.md": util.MethodMap{
+			"GET": "file.json",
+		},
 		fmt.Sprintf("/api/v4/users"): util.MethodMap{
 			"GET": "list-users.json",
 		},
@@ -171,21 +177,78 @@ func (suite *GitlabProviderSuite) TestGetRepository() {
 	suite.Require().Equal(gitlabProjectName, repo.Name)
 }
 
+func (suite *GitlabProviderSuite) TestListCommits() {
+	commits, err := suite.provider.ListCommits(gitlabUserName, "userproject", &gits.ListCommitsArguments{})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 2)
+	suite.Require().Equal("ed899a2f4b50b4370feeea94676502b42383c746", commits[0].SHA)
+	suite.Require().Equal("testperson@example.com", commits[0].Author.Email)
+	suite.Require().Equal("Raymond Smith", commits[1].Committer.Name)
+}
+
+func (suite *GitlabProviderSuite) TestListCommitsByAuthor() {
+	commits, err := suite.provider.ListCommits(gitlabUserName, "userproject", &gits.ListCommitsArguments{
+		Author: "raymond@example.com",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Len(commits, 1)
+	suite.Require().Equal("6104942438c14ec7bd21c6cd5bd995272b3faff6", commits[0].SHA)
+}
+
+func (suite *GitlabProviderSuite) TestGetContent() {
+	content, err := suite.provider.GetContent(gitlabUserName, "userproject", "README.md", "master")
+
+	suite.Require().Nil(err)
+	suite.Require().NotNil(content)
+	suite.Require().Equal("README.md", content.Name)
+	suite.Require().Equal("base64", content.Encoding)
+	suite.Require().Equal("79f7bbd25901e8334750839545a9bd021f0e4c83", content.Sha)
+}
+
+func (suite *GitlabProviderSuite) TestDeleteWebHookByURL() {
+	deleted := ""
+	suite.mux.HandleFunc("/api/v4/projects/5860291/hooks", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[{"id": 1, "url": "https://example.com/hook"}, {"id": 2, "url": "https://hook.example.com/testperson/userproject"}]`))
+	})
+	suite.mux.HandleFunc("/api/v4/projects/5860291/hooks/", func(w http.ResponseWriter, r *http.Request) {
+		suite.Require().Equal(http.MethodDelete, r.Method)
+		deleted = r.URL.Path
+	})
+
+	err := suite.provider.DeleteWebHook(&gits.GitWebHookArguments{
+		Repo: &gits.GitRepository{Name: "userproject"},
+		URL:  "https://hook.example.com",
+	})
+
+	suite.Require().Nil(err)
+	suite.Require().Equal("/api/v4/projects/5860291/hooks/2", deleted)
+}
+
 func (suite *GitlabProviderSuite) TestAddCollaborator() {
 	err := suite.provider.AddCollaborator(gitlabSecondUserName, gitlabOrgName, gitlabProjectName)
 	suite.Require().Nil(err)
 }
 
+func (suite *GitlabProviderSuite) TestAddCollaboratorAlreadyMember() {
+	suite.mux.HandleFunc("/api/v4/projects/5860291/members", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusConflict)
+		w.Write([]byte(`{"message": "Member already exists"}`))
+	})
+
+	err := suite.provider.AddCollaborator(gitlabSecondUserName, gitlabUserName, "userproject")
+	suite.Require().Nil(err)
+}
+
 func (suite *GitlabProviderSuite) TestListInvitations() {
-	invites, res, err := suite.provider.ListInvitations()
+	invites, err := suite.provider.ListInvitations()
 	suite.Require().NotNil(invites)
-	suite.Require().NotNil(res)
 	suite.Require().Nil(err)
 }
 
 func (suite *GitlabProviderSuite) TestAcceptInvitations() {
-	res, err := suite.provider.AcceptInvitation(1)
-	suite.Require().NotNil(res)
+	err := suite.provider.AcceptInvitation(1)
 	suite.Require().Nil(err)
 }
 
@@ -10,9 +10,11 @@ import (
 	"path/filepath"
 	"sort"
 	"strings"
+	"time"
 
 	"gopkg.in/src-d/go-git.v4/config"
 
+	"github.com/cenkalti/backoff"
 	uuid "github.com/satori/go.uuid"
 
 	jxconfig "github.com/jenkins-x/jx/v2/pkg/config"
@@ -789,6 +791,40 @@ func FilterOpenPullRequests(provider GitProvider, owner string, repo string, fil
 	return answer, nil
 }
 
+// AddCollaboratorAndAcceptInvitations grants the collaborator access to the repository using the provider of the
+// repository owner. If a provider for the collaborator is given then the collaborator's pending invitations are
+// accepted, retrying for a while in case the provider APIs are flaky
+func AddCollaboratorAndAcceptInvitations(provider GitProvider, collaboratorProvider GitProvider, collaborator string, organisation string, repo string) error {
+	err := provider.AddCollaborator(collaborator, organisation, repo)
+	if err != nil {
+		return errors.Wrapf(err, "adding %s as a collaborator to %s/%s", collaborator, organisation, repo)
+	}
+	if collaboratorProvider == nil {
+		return nil
+	}
+	f := func() error {
+		invites, err := collaboratorProvider.ListInvitations()
+		if err != nil {
+			return err
+		}
+		for _, invite := range invites {
+			err = collaboratorProvider.AcceptInvitation(invite.ID)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	exponentialBackOff := backoff.NewExponentialBackOff()
+	exponentialBackOff.MaxElapsedTime = 20 * time.Second
+	exponentialBackOff.Reset()
+	err = backoff.Retry(f, exponentialBackOff)
+	if err != nil {
+		return errors.Wrapf(err, "accepting the invitations of %s", collaborator)
+	}
+	return nil
+}
+
 //IsUnadvertisedObjectError returns true if the reason for the error is that the request was for an object that is unadvertised (i.e. doesn't exist)
 func IsUnadvertisedObjectError(err error) bool {
 	return strings.Contains(err.Error(), "Server does not allow request for unadvertised object")
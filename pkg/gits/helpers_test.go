@@ -17,6 +17,8 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/tests"
 
 	"github.com/jenkins-x/jx/v2/pkg/gits"
+	mocks "github.com/jenkins-x/jx/v2/pkg/gits/mocks"
+	"github.com/petergtz/pegomock"
 	"github.com/pkg/errors"
 	uuid "github.com/satori/go.uuid"
 	"github.com/stretchr/testify/assert"
@@ -2258,3 +2260,26 @@ func TestIsDefaultBootConfigURL(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, withRightURLNoDotGit)
 }
+
+func TestAddCollaboratorAndAcceptInvitations(t *testing.T) {
+	pegomock.RegisterMockTestingT(t)
+	provider := mocks.NewMockGitProvider()
+	pipelineUserProvider := mocks.NewMockGitProvider()
+	pegomock.When(pipelineUserProvider.ListInvitations()).ThenReturn([]*gits.GitRepositoryInvitation{{ID: 1}, {ID: 2}}, nil)
+
+	err := gits.AddCollaboratorAndAcceptInvitations(provider, pipelineUserProvider, "pipeline-user", "test-org", "test-repo")
+	assert.NoError(t, err)
+
+	provider.VerifyWasCalledOnce().AddCollaborator("pipeline-user", "test-org", "test-repo")
+	pipelineUserProvider.VerifyWasCalledOnce().AcceptInvitation(int64(1))
+	pipelineUserProvider.VerifyWasCalledOnce().AcceptInvitation(int64(2))
+}
+
+func TestAddCollaboratorWithoutCollaboratorProvider(t *testing.T) {
+	pegomock.RegisterMockTestingT(t)
+	provider := mocks.NewMockGitProvider()
+	pegomock.When(provider.AddCollaborator("pipeline-user", "test-org", "test-repo")).ThenReturn(errors.New("forbidden"))
+
+	err := gits.AddCollaboratorAndAcceptInvitations(provider, nil, "pipeline-user", "test-org", "test-repo")
+	assert.Error(t, err)
+}
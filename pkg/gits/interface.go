@@ -6,7 +6,6 @@ import (
 	"os"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	gitcfg "gopkg.in/src-d/go-git.v4/config"
 )
@@ -72,6 +71,8 @@ type GitProvider interface {
 
 	UpdateWebHook(data *GitWebHookArguments) error
 
+	DeleteWebHook(data *GitWebHookArguments) error
+
 	IsGitHub() bool
 
 	IsGitea() bool
@@ -154,11 +155,16 @@ type GitProvider interface {
 	// Returns user info, if possible
 	UserInfo(username string) *GitUser
 
-	AddCollaborator(string, string, string) error
-	// TODO Refactor to remove bespoke types when we implement another provider
-	ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error)
-	// TODO Refactor to remove bespoke types when we implement another provider
-	AcceptInvitation(int64) (*github.Response, error)
+	// AddCollaborator grants the user write access to the repository of the given organisation. It succeeds if the
+	// user is already a collaborator
+	AddCollaborator(user string, organisation string, repo string) error
+
+	// ListInvitations lists the pending repository invitations of the current user. Providers which grant
+	// access to collaborators immediately return an empty list
+	ListInvitations() ([]*GitRepositoryInvitation, error)
+
+	// AcceptInvitation accepts the pending repository invitation with the given ID on behalf of the current user
+	AcceptInvitation(ID int64) error
 
 	// ShouldForkForPullRequest returns true if we should create a personal fork of this repository
 	// before creating a pull request
@@ -281,6 +287,7 @@ type Gitter interface {
 	FilterTags(dir string, filter string) ([]string, error)
 	CreateTag(dir string, tag string, msg string) error
 	GetLatestCommitSha(dir string) (string, error)
+	GetLatestCommitTimes(dir string, path string) (map[string]time.Time, error)
 	GetFirstCommitSha(dir string) (string, error)
 	GetCommits(dir string, start string, end string) ([]GitCommit, error)
 	RevParse(dir string, rev string) (string, error)
@@ -8,7 +8,6 @@ import (
 	"reflect"
 	"time"
 
-	github "github.com/google/go-github/github"
 	auth "github.com/jenkins-x/jx/v2/pkg/auth"
 	gits "github.com/jenkins-x/jx/v2/pkg/gits"
 	pegomock "github.com/petergtz/pegomock"
@@ -29,23 +28,19 @@ func NewMockGitProvider(options ...pegomock.Option) *MockGitProvider {
 func (mock *MockGitProvider) SetFailHandler(fh pegomock.FailHandler) { mock.fail = fh }
 func (mock *MockGitProvider) FailHandler() pegomock.FailHandler      { return mock.fail }
 
-func (mock *MockGitProvider) AcceptInvitation(_param0 int64) (*github.Response, error) {
+func (mock *MockGitProvider) AcceptInvitation(_param0 int64) error {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
 	}
 	params := []pegomock.Param{_param0}
-	result := pegomock.GetGenericMockFrom(mock).Invoke("AcceptInvitation", params, []reflect.Type{reflect.TypeOf((**github.Response)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
-	var ret0 *github.Response
-	var ret1 error
+	result := pegomock.GetGenericMockFrom(mock).Invoke("AcceptInvitation", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
 	if len(result) != 0 {
 		if result[0] != nil {
-			ret0 = result[0].(*github.Response)
-		}
-		if result[1] != nil {
-			ret1 = result[1].(error)
+			ret0 = result[0].(error)
 		}
 	}
-	return ret0, ret1
+	return ret0
 }
 
 func (mock *MockGitProvider) AddCollaborator(_param0 string, _param1 string, _param2 string) error {
@@ -244,6 +239,21 @@ func (mock *MockGitProvider) DeleteRepository(_param0 string, _param1 string) er
 	return ret0
 }
 
+func (mock *MockGitProvider) DeleteWebHook(_param0 *gits.GitWebHookArguments) error {
+	if mock == nil {
+		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
+	}
+	params := []pegomock.Param{_param0}
+	result := pegomock.GetGenericMockFrom(mock).Invoke("DeleteWebHook", params, []reflect.Type{reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 error
+	if len(result) != 0 {
+		if result[0] != nil {
+			ret0 = result[0].(error)
+		}
+	}
+	return ret0
+}
+
 func (mock *MockGitProvider) ForkRepository(_param0 string, _param1 string, _param2 string) (*gits.GitRepository, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
@@ -641,27 +651,23 @@ func (mock *MockGitProvider) ListCommits(_param0 string, _param1 string, _param2
 	return ret0, ret1
 }
 
-func (mock *MockGitProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
+func (mock *MockGitProvider) ListInvitations() ([]*gits.GitRepositoryInvitation, error) {
 	if mock == nil {
 		panic("mock must not be nil. Use myMock := NewMockGitProvider().")
 	}
 	params := []pegomock.Param{}
-	result := pegomock.GetGenericMockFrom(mock).Invoke("ListInvitations", params, []reflect.Type{reflect.TypeOf((*[]*github.RepositoryInvitation)(nil)).Elem(), reflect.TypeOf((**github.Response)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
-	var ret0 []*github.RepositoryInvitation
-	var ret1 *github.Response
-	var ret2 error
+	result := pegomock.GetGenericMockFrom(mock).Invoke("ListInvitations", params, []reflect.Type{reflect.TypeOf((*[]*gits.GitRepositoryInvitation)(nil)).Elem(), reflect.TypeOf((*error)(nil)).Elem()})
+	var ret0 []*gits.GitRepositoryInvitation
+	var ret1 error
 	if len(result) != 0 {
 		if result[0] != nil {
-			ret0 = result[0].([]*github.RepositoryInvitation)
+			ret0 = result[0].([]*gits.GitRepositoryInvitation)
 		}
 		if result[1] != nil {
-			ret1 = result[1].(*github.Response)
-		}
-		if result[2] != nil {
-			ret2 = result[2].(error)
+			ret1 = result[1].(error)
 		}
 	}
-	return ret0, ret1, ret2
+	return ret0, ret1
 }
 
 func (mock *MockGitProvider) ListOpenPullRequests(_param0 string, _param1 string) ([]*gits.GitPullRequest, error) {
@@ -1500,6 +1506,33 @@ func (c *MockGitProvider_DeleteRepository_OngoingVerification) GetAllCapturedArg
 	return
 }
 
+func (verifier *VerifierMockGitProvider) DeleteWebHook(_param0 *gits.GitWebHookArguments) *MockGitProvider_DeleteWebHook_OngoingVerification {
+	params := []pegomock.Param{_param0}
+	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "DeleteWebHook", params, verifier.timeout)
+	return &MockGitProvider_DeleteWebHook_OngoingVerification{mock: verifier.mock, methodInvocations: methodInvocations}
+}
+
+type MockGitProvider_DeleteWebHook_OngoingVerification struct {
+	mock              *MockGitProvider
+	methodInvocations []pegomock.MethodInvocation
+}
+
+func (c *MockGitProvider_DeleteWebHook_OngoingVerification) GetCapturedArguments() *gits.GitWebHookArguments {
+	_param0 := c.GetAllCapturedArguments()
+	return _param0[len(_param0)-1]
+}
+
+func (c *MockGitProvider_DeleteWebHook_OngoingVerification) GetAllCapturedArguments() (_param0 []*gits.GitWebHookArguments) {
+	params := pegomock.GetGenericMockFrom(c.mock).GetInvocationParams(c.methodInvocations)
+	if len(params) > 0 {
+		_param0 = make([]*gits.GitWebHookArguments, len(c.methodInvocations))
+		for u, param := range params[0] {
+			_param0[u] = param.(*gits.GitWebHookArguments)
+		}
+	}
+	return
+}
+
 func (verifier *VerifierMockGitProvider) ForkRepository(_param0 string, _param1 string, _param2 string) *MockGitProvider_ForkRepository_OngoingVerification {
 	params := []pegomock.Param{_param0, _param1, _param2}
 	methodInvocations := pegomock.GetGenericMockFrom(verifier.mock).Verify(verifier.inOrderContext, verifier.invocationCountMatcher, "ForkRepository", params, verifier.timeout)
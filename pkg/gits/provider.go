@@ -96,6 +96,101 @@ type ListCommitsArguments struct {
 	PerPage int
 }
 
+// listedCommit a commit listed by a git provider along with its commit date so that it can be filtered on the client
+type listedCommit struct {
+	commit *GitCommit
+	date   time.Time
+}
+
+// listedCommits returns the commits of the given listed commits
+func listedCommits(listed []listedCommit) []*GitCommit {
+	commits := []*GitCommit{}
+	for _, c := range listed {
+		commits = append(commits, c.commit)
+	}
+	return commits
+}
+
+// matchesAuthor returns true if no author is specified or if the login, email or name of the given user matches it
+func (a *ListCommitsArguments) matchesAuthor(user *GitUser) bool {
+	if a.Author == "" {
+		return true
+	}
+	if user == nil {
+		return false
+	}
+	for _, value := range []string{user.Login, user.Email, user.Name} {
+		if value != "" && strings.EqualFold(value, a.Author) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesDate returns true if the given commit date is within the since and until dates if they are specified
+func (a *ListCommitsArguments) matchesDate(date time.Time) bool {
+	if !a.Since.IsZero() && date.Before(a.Since) {
+		return false
+	}
+	if !a.Until.IsZero() && date.After(a.Until) {
+		return false
+	}
+	return true
+}
+
+// filterCommits lists the commits matching the author, since and until options on the client for the git providers
+// whose API cannot filter by them. The fetch function returns the given page of the commits filtered by the API and
+// the optional changesPath function tells if a commit changes the path option. The pages of the unfiltered list are
+// fetched until the requested page of the filtered list is complete, stopping early once a whole page of commits is
+// older than the since option
+func (a *ListCommitsArguments) filterCommits(fetch func(page int, perPage int) ([]listedCommit, error),
+	changesPath func(commit *GitCommit) (bool, error)) ([]*GitCommit, error) {
+	perPage := a.PerPage
+	if perPage <= 0 {
+		perPage = pageSize
+	}
+	skip := 0
+	if a.Page > 1 {
+		skip = (a.Page - 1) * perPage
+	}
+	commits := []*GitCommit{}
+	for page := 1; ; page++ {
+		listed, err := fetch(page, perPage)
+		if err != nil {
+			return nil, err
+		}
+		recent := false
+		for _, c := range listed {
+			if a.Since.IsZero() || !c.date.Before(a.Since) {
+				recent = true
+			}
+			if !a.matchesDate(c.date) || !a.matchesAuthor(c.commit.Author) {
+				continue
+			}
+			if changesPath != nil {
+				changed, err := changesPath(c.commit)
+				if err != nil {
+					return nil, err
+				}
+				if !changed {
+					continue
+				}
+			}
+			if skip > 0 {
+				skip--
+				continue
+			}
+			commits = append(commits, c.commit)
+			if len(commits) == perPage {
+				return commits, nil
+			}
+		}
+		if len(listed) < perPage || !recent {
+			return commits, nil
+		}
+	}
+}
+
 type GitIssue struct {
 	URL           string
 	Owner         string
@@ -125,6 +220,16 @@ type GitUser struct {
 	AvatarURL string
 }
 
+// GitRepositoryInvitation is a pending invitation for a user to collaborate on a repository
+type GitRepositoryInvitation struct {
+	ID          int64
+	Repo        *GitRepository
+	Invitee     *GitUser
+	Inviter     *GitUser
+	Permissions string
+	URL         string
+}
+
 type GitRelease struct {
 	ID            int64
 	Name          string
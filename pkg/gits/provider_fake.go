@@ -10,7 +10,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/google/go-github/github"
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
@@ -505,8 +504,28 @@ func (p *FakeProvider) ListWebHooks(owner string, repo string) ([]*GitWebHookArg
 	return p.WebHooks, nil
 }
 
+// DeleteWebHook removes the webhook with the same ID or URL
+func (p *FakeProvider) DeleteWebHook(data *GitWebHookArguments) error {
+	webHooks := []*GitWebHookArguments{}
+	for _, hook := range p.WebHooks {
+		if (data.ID != 0 && hook.ID == data.ID) || (data.ID == 0 && hook.URL == data.URL) {
+			continue
+		}
+		webHooks = append(webHooks, hook)
+	}
+	p.WebHooks = webHooks
+	return nil
+}
+
+// UpdateWebHook replaces the webhook with the same ID or ExistingURL
 func (p *FakeProvider) UpdateWebHook(data *GitWebHookArguments) error {
-	return fmt.Errorf("not implemented!")
+	for i, hook := range p.WebHooks {
+		if (data.ID != 0 && hook.ID == data.ID) || (data.ID == 0 && data.ExistingURL != "" && hook.URL == data.ExistingURL) {
+			p.WebHooks[i] = data
+			return nil
+		}
+	}
+	return fmt.Errorf("no webhook found to update")
 }
 
 func (f *FakeProvider) IsGitHub() bool {
@@ -778,14 +797,14 @@ func (f *FakeProvider) AddCollaborator(user string, organisation string, repo st
 	return nil
 }
 
-func (f *FakeProvider) ListInvitations() ([]*github.RepositoryInvitation, *github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for git fake.")
-	return []*github.RepositoryInvitation{}, &github.Response{}, nil
+// ListInvitations returns no invitations as collaborators cannot be added automatically
+func (f *FakeProvider) ListInvitations() ([]*GitRepositoryInvitation, error) {
+	return []*GitRepositoryInvitation{}, nil
 }
 
-func (f *FakeProvider) AcceptInvitation(ID int64) (*github.Response, error) {
-	log.Logger().Infof("Automatically adding the pipeline user as a collaborator is currently not implemented for git fake.")
-	return &github.Response{}, nil
+// AcceptInvitation does nothing as collaborators cannot be added automatically
+func (f *FakeProvider) AcceptInvitation(ID int64) error {
+	return nil
 }
 
 // GetContent gets the content
@@ -26,6 +26,8 @@ const (
 	VaultLocationKind SecretsLocationKind = "vault"
 	// KubeLocationKind indicates that secrets location is in Kubernetes
 	KubeLocationKind SecretsLocationKind = "kube"
+	// SopsLocationKind indicates that secrets location is SOPS encrypted files
+	SopsLocationKind SecretsLocationKind = "sops"
 	// AutoLocationKind indicates that secrets location needs to be dynamically determine
 	AutoLocationKind SecretsLocationKind = "auto"
 )
@@ -61,9 +63,9 @@ func (s *secretLocation) Location() SecretsLocationKind {
 	if err != nil {
 		return s.location
 	}
-	value, ok := configMap[SecretsLocationKey]
-	if ok && value == string(VaultLocationKind) {
-		return VaultLocationKind
+	switch location := ToSecretsLocation(configMap[SecretsLocationKey]); location {
+	case VaultLocationKind, KubeLocationKind, SopsLocationKind:
+		return location
 	}
 	return s.location
 }
@@ -100,8 +102,10 @@ func ToSecretsLocation(location string) SecretsLocationKind {
 		return FileSystemLocationKind
 	case "vault":
 		return VaultLocationKind
-	case "kube":
+	case "kube", "kubernetes":
 		return KubeLocationKind
+	case "sops":
+		return SopsLocationKind
 	default:
 		return AutoLocationKind
 	}
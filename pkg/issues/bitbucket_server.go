@@ -0,0 +1,58 @@
+package issues
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/pkg/errors"
+)
+
+// CreateBitbucketServerIssueProvider creates a Jira issue provider for the Jira server which is linked to the
+// Bitbucket Server through its Jira integration and registers it as the issue tracker of the git provider.
+// The Jira credentials are looked up in the given auth config, falling back to the Bitbucket Server credentials
+// as both servers commonly share the same user directory
+func CreateBitbucketServerIssueProvider(gitProvider *gits.BitbucketServerProvider, authConfig *auth.AuthConfig, owner string, batchMode bool, git gits.Gitter) (IssueProvider, error) {
+	servers, err := gitProvider.LinkedJiraServers()
+	if err != nil {
+		return nil, err
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no Jira server is linked to bitbucket server %s", gitProvider.ServerURL())
+	}
+	jiraServer := servers[0]
+	if len(servers) > 1 {
+		log.Logger().Warnf("Bitbucket server %s is linked to %d Jira servers, using %s", gitProvider.ServerURL(), len(servers), jiraServer.URL)
+	}
+
+	server := &auth.AuthServer{
+		URL:  jiraServer.URL,
+		Name: jiraServer.Name,
+		Kind: Jira,
+	}
+	var userAuth *auth.UserAuth
+	if authConfig != nil {
+		if configured := authConfig.GetServer(jiraServer.URL); configured != nil {
+			server = configured
+			userAuth = configured.CurrentAuth()
+		}
+	}
+	if userAuth == nil || userAuth.IsInvalid() {
+		gitUserAuth := gitProvider.UserAuth()
+		userAuth = &gitUserAuth
+	}
+
+	project := gitProvider.JiraProject
+	if project == "" {
+		project = strings.ToUpper(owner)
+	}
+	provider, err := CreateJiraIssueProvider(server, userAuth, project, batchMode, git)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating the Jira issue provider for %s", jiraServer.URL)
+	}
+	gitProvider.IssueTracker = provider
+	gitProvider.JiraProject = project
+	return provider, nil
+}
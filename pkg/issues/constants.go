@@ -5,6 +5,9 @@ const (
 	Jira     = "jira"
 	Trello   = "trello"
 	Git      = "git"
+	GitHub   = "github"
+	GitLab   = "gitlab"
+	Linear   = "linear"
 )
 
 var (
@@ -13,5 +16,5 @@ var (
 )
 
 var (
-	IssueTrackerKinds = []string{Bugzilla, Jira, Trello}
+	IssueTrackerKinds = []string{Bugzilla, Jira, Trello, GitHub, GitLab, Linear}
 )
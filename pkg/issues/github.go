@@ -0,0 +1,214 @@
+package issues
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/go-github/github"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	gitHubServerURL = "https://github.com"
+	issuesPageSize  = 100
+)
+
+// GitHubIssueService is an issue tracker for the issues of a GitHub repository. The repository is configured as the
+// project of the issue tracker so does not have to be on the same server as the source code.
+type GitHubIssueService struct {
+	Client     *github.Client
+	Context    context.Context
+	Server     *auth.AuthServer
+	Owner      string
+	Repository string
+}
+
+// CreateGitHubIssueProvider creates an issue provider for the GitHub repository given as the project, in the form
+// owner/repository. The server URL defaults to https://github.com, any other URL is treated as GitHub Enterprise.
+func CreateGitHubIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool) (IssueProvider, error) {
+	owner, repository, err := splitProject(project)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid GitHub issue tracker project")
+	}
+	gitServer := *server
+	if gitServer.URL == "" {
+		gitServer.URL = gitHubServerURL
+	}
+	var gitProvider gits.GitProvider
+	if userAuth == nil || userAuth.ApiToken == "" {
+		if batchMode {
+			log.Logger().Warnf("No API token found for GitHub server %s so using anonymous access", gitServer.URL)
+		}
+		gitProvider, err = gits.NewAnonymousGitHubProvider(&gitServer, nil)
+	} else {
+		gitProvider, err = gits.NewGitHubProvider(&gitServer, userAuth, nil)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating the GitHub client for %s", gitServer.URL)
+	}
+	gitHubProvider := gitProvider.(*gits.GitHubProvider)
+	return &GitHubIssueService{
+		Client:     gitHubProvider.Client,
+		Context:    gitHubProvider.Context,
+		Server:     &gitServer,
+		Owner:      owner,
+		Repository: repository,
+	}, nil
+}
+
+// GetIssue returns the issue with the given number
+func (i *GitHubIssueService) GetIssue(key string) (*gits.GitIssue, error) {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return nil, err
+	}
+	issue, _, err := i.Client.Issues.Get(i.Context, i.Owner, i.Repository, n)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting issue %d of %s/%s", n, i.Owner, i.Repository)
+	}
+	return i.toGitIssue(issue), nil
+}
+
+// SearchIssues searches the open issues of the repository
+func (i *GitHubIssueService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	q := fmt.Sprintf("repo:%s/%s is:issue is:open", i.Owner, i.Repository)
+	if query != "" {
+		q += " " + query
+	}
+	return i.search(q)
+}
+
+// SearchIssuesClosedSince searches the issues of the repository closed since the given time
+func (i *GitHubIssueService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	q := fmt.Sprintf("repo:%s/%s is:issue is:closed closed:>=%s", i.Owner, i.Repository, t.UTC().Format(time.RFC3339))
+	issues, err := i.search(q)
+	if err != nil {
+		return issues, err
+	}
+	return gits.FilterIssuesClosedSince(issues, t), nil
+}
+
+func (i *GitHubIssueService) search(q string) ([]*gits.GitIssue, error) {
+	answer := []*gits.GitIssue{}
+	opts := &github.SearchOptions{
+		ListOptions: github.ListOptions{PerPage: issuesPageSize},
+	}
+	for {
+		result, resp, err := i.Client.Search.Issues(i.Context, q, opts)
+		if err != nil {
+			return answer, errors.Wrapf(err, "searching issues with %q", q)
+		}
+		for k := range result.Issues {
+			answer = append(answer, i.toGitIssue(&result.Issues[k]))
+		}
+		if resp.NextPage == 0 {
+			return answer, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CreateIssue creates a new issue in the repository
+func (i *GitHubIssueService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	labels := []string{}
+	for _, l := range issue.Labels {
+		if l.Name != "" {
+			labels = append(labels, l.Name)
+		}
+	}
+	request := &github.IssueRequest{
+		Title:  &issue.Title,
+		Labels: &labels,
+	}
+	if issue.Body != "" {
+		request.Body = &issue.Body
+	}
+	created, _, err := i.Client.Issues.Create(i.Context, i.Owner, i.Repository, request)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating issue in %s/%s", i.Owner, i.Repository)
+	}
+	return i.toGitIssue(created), nil
+}
+
+// CreateIssueComment comments on the issue with the given number
+func (i *GitHubIssueService) CreateIssueComment(key string, comment string) error {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return err
+	}
+	_, _, err = i.Client.Issues.CreateComment(i.Context, i.Owner, i.Repository, n, &github.IssueComment{Body: &comment})
+	if err != nil {
+		return errors.Wrapf(err, "commenting on issue %d of %s/%s", n, i.Owner, i.Repository)
+	}
+	return nil
+}
+
+// IssueURL returns the URL of the issue with the given number
+func (i *GitHubIssueService) IssueURL(key string) string {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return ""
+	}
+	return util.UrlJoin(i.HomeURL(), strconv.Itoa(n))
+}
+
+// HomeURL returns the URL of the issues of the repository
+func (i *GitHubIssueService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, i.Owner, i.Repository, "issues")
+}
+
+func (i *GitHubIssueService) toGitIssue(issue *github.Issue) *gits.GitIssue {
+	number := issue.GetNumber()
+	state := issue.GetState()
+	answer := &gits.GitIssue{
+		URL:           issue.GetHTMLURL(),
+		Owner:         i.Owner,
+		Repo:          i.Repository,
+		Number:        &number,
+		Key:           strconv.Itoa(number),
+		Title:         issue.GetTitle(),
+		Body:          issue.GetBody(),
+		State:         &state,
+		CreatedAt:     issue.CreatedAt,
+		UpdatedAt:     issue.UpdatedAt,
+		ClosedAt:      issue.ClosedAt,
+		IsPullRequest: issue.IsPullRequest(),
+		User:          gitHubUserToGitUser(issue.User),
+		ClosedBy:      gitHubUserToGitUser(issue.ClosedBy),
+	}
+	if answer.URL == "" {
+		answer.URL = i.IssueURL(answer.Key)
+	}
+	for _, l := range issue.Labels {
+		answer.Labels = append(answer.Labels, gits.GitLabel{
+			URL:   l.GetURL(),
+			Name:  l.GetName(),
+			Color: l.GetColor(),
+		})
+	}
+	for _, a := range issue.Assignees {
+		if a != nil {
+			answer.Assignees = append(answer.Assignees, *gitHubUserToGitUser(a))
+		}
+	}
+	return answer
+}
+
+func gitHubUserToGitUser(user *github.User) *gits.GitUser {
+	if user == nil {
+		return nil
+	}
+	return &gits.GitUser{
+		URL:       user.GetHTMLURL(),
+		Login:     user.GetLogin(),
+		Name:      user.GetName(),
+		Email:     user.GetEmail(),
+		AvatarURL: user.GetAvatarURL(),
+	}
+}
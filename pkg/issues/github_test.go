@@ -0,0 +1,85 @@
+// +build unit
+
+package issues_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/issues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitHubIssueProvider(t *testing.T) {
+	var created map[string]interface{}
+	var comment map[string]interface{}
+	var searchQuery string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v3/repos/acme/tracker/issues/12", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, _ = w.Write([]byte(`{"number": 12, "html_url": "https://github.acme.com/acme/tracker/issues/12", "title": "Broken build",
+			"state": "open", "labels": [{"name": "bug"}], "user": {"login": "jstrachan"}}`))
+	})
+	mux.HandleFunc("/api/v3/search/issues", func(w http.ResponseWriter, r *http.Request) {
+		searchQuery = r.URL.Query().Get("q")
+		_, _ = w.Write([]byte(`{"items": [{"number": 3, "title": "Old bug", "state": "closed", "closed_at": "2020-03-02T10:00:00Z"}]}`))
+	})
+	mux.HandleFunc("/api/v3/repos/acme/tracker/issues", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{"number": 13, "title": "New issue", "state": "open"}`))
+	})
+	mux.HandleFunc("/api/v3/repos/acme/tracker/issues/13/comments", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, http.MethodPost, r.Method)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&comment))
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte(`{}`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.GitHub, &auth.AuthServer{URL: server.URL, Kind: issues.GitHub},
+		&auth.UserAuth{Username: "jenkins-x-bot", ApiToken: "secret"}, "acme/tracker", true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, issues.Git, issues.GetIssueProvider(provider))
+
+	issue, err := provider.GetIssue("12")
+	require.NoError(t, err)
+	assert.Equal(t, "12", issue.Key)
+	assert.Equal(t, "Broken build", issue.Title)
+	assert.Equal(t, "https://github.acme.com/acme/tracker/issues/12", issue.URL)
+	assert.Equal(t, "jstrachan", issue.User.Login)
+	require.Len(t, issue.Labels, 1)
+	assert.Equal(t, "bug", issue.Labels[0].Name)
+
+	closed, err := provider.SearchIssuesClosedSince(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, closed, 1)
+	assert.Equal(t, "Old bug", closed[0].Title)
+	assert.Equal(t, "repo:acme/tracker is:issue is:closed closed:>=2020-03-01T00:00:00Z", searchQuery)
+
+	newIssue, err := provider.CreateIssue(&gits.GitIssue{Title: "New issue", Body: "Details", Labels: gits.ToGitLabels([]string{"bug"})})
+	require.NoError(t, err)
+	assert.Equal(t, "13", newIssue.Key)
+	assert.Equal(t, server.URL+"/acme/tracker/issues/13", newIssue.URL)
+	assert.Equal(t, "New issue", created["title"])
+	assert.Equal(t, "Details", created["body"])
+	assert.Equal(t, []interface{}{"bug"}, created["labels"])
+
+	require.NoError(t, provider.CreateIssueComment("13", "Fixed in 1.2.3"))
+	assert.Equal(t, "Fixed in 1.2.3", comment["body"])
+
+	assert.Equal(t, server.URL+"/acme/tracker/issues", provider.HomeURL())
+}
+
+func TestGitHubIssueProviderRequiresRepository(t *testing.T) {
+	_, err := issues.CreateIssueProvider(issues.GitHub, &auth.AuthServer{Kind: issues.GitHub}, &auth.UserAuth{}, "acme", true, nil)
+	assert.Error(t, err)
+}
@@ -0,0 +1,200 @@
+package issues
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	gitlab "github.com/xanzy/go-gitlab"
+)
+
+const gitLabServerURL = "https://gitlab.com"
+
+// GitLabIssueService is an issue tracker for the issues of a GitLab project. The project is configured as the project
+// of the issue tracker so does not have to be on the same server as the source code.
+type GitLabIssueService struct {
+	Client *gitlab.Client
+	Server *auth.AuthServer
+	// Project is the full path of the project, including any subgroups
+	Project string
+}
+
+// CreateGitLabIssueProvider creates an issue provider for the GitLab project given as the project, such as
+// group/subgroup/project. The server URL defaults to https://gitlab.com.
+func CreateGitLabIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool) (IssueProvider, error) {
+	if _, _, err := splitProject(project); err != nil {
+		return nil, errors.Wrap(err, "invalid GitLab issue tracker project")
+	}
+	gitServer := *server
+	if gitServer.URL == "" {
+		gitServer.URL = gitLabServerURL
+	}
+	if userAuth == nil || userAuth.ApiToken == "" {
+		if batchMode {
+			log.Logger().Warnf("No API token found for GitLab server %s so using anonymous access", gitServer.URL)
+		}
+		userAuth = &auth.UserAuth{}
+	}
+	gitProvider, err := gits.NewGitlabProvider(&gitServer, userAuth, nil)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating the GitLab client for %s", gitServer.URL)
+	}
+	return &GitLabIssueService{
+		Client:  gitProvider.(*gits.GitlabProvider).Client,
+		Server:  &gitServer,
+		Project: project,
+	}, nil
+}
+
+// GetIssue returns the issue with the given number
+func (i *GitLabIssueService) GetIssue(key string) (*gits.GitIssue, error) {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return nil, err
+	}
+	issue, _, err := i.Client.Issues.GetIssue(i.Project, n)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting issue %d of %s", n, i.Project)
+	}
+	return i.toGitIssue(issue), nil
+}
+
+// SearchIssues searches the open issues of the project
+func (i *GitLabIssueService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	opts := &gitlab.ListProjectIssuesOptions{
+		State: gitlab.String("opened"),
+	}
+	if query != "" {
+		opts.Search = &query
+	}
+	return i.list(opts)
+}
+
+// SearchIssuesClosedSince searches the issues of the project closed since the given time
+func (i *GitLabIssueService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	since := t.UTC()
+	opts := &gitlab.ListProjectIssuesOptions{
+		State:        gitlab.String("closed"),
+		UpdatedAfter: &since,
+	}
+	issues, err := i.list(opts)
+	if err != nil {
+		return issues, err
+	}
+	return gits.FilterIssuesClosedSince(issues, t), nil
+}
+
+func (i *GitLabIssueService) list(opts *gitlab.ListProjectIssuesOptions) ([]*gits.GitIssue, error) {
+	answer := []*gits.GitIssue{}
+	opts.PerPage = issuesPageSize
+	for {
+		issues, resp, err := i.Client.Issues.ListProjectIssues(i.Project, opts)
+		if err != nil {
+			return answer, errors.Wrapf(err, "listing issues of %s", i.Project)
+		}
+		for _, issue := range issues {
+			answer = append(answer, i.toGitIssue(issue))
+		}
+		if resp.NextPage == 0 {
+			return answer, nil
+		}
+		opts.Page = resp.NextPage
+	}
+}
+
+// CreateIssue creates a new issue in the project
+func (i *GitLabIssueService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	labels := gitlab.Labels{}
+	for _, l := range issue.Labels {
+		if l.Name != "" {
+			labels = append(labels, l.Name)
+		}
+	}
+	opts := &gitlab.CreateIssueOptions{
+		Title: &issue.Title,
+	}
+	if issue.Body != "" {
+		opts.Description = &issue.Body
+	}
+	if len(labels) > 0 {
+		opts.Labels = &labels
+	}
+	created, _, err := i.Client.Issues.CreateIssue(i.Project, opts)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating issue in %s", i.Project)
+	}
+	return i.toGitIssue(created), nil
+}
+
+// CreateIssueComment adds a note to the issue with the given number
+func (i *GitLabIssueService) CreateIssueComment(key string, comment string) error {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return err
+	}
+	_, _, err = i.Client.Notes.CreateIssueNote(i.Project, n, &gitlab.CreateIssueNoteOptions{Body: &comment})
+	if err != nil {
+		return errors.Wrapf(err, "commenting on issue %d of %s", n, i.Project)
+	}
+	return nil
+}
+
+// IssueURL returns the URL of the issue with the given number
+func (i *GitLabIssueService) IssueURL(key string) string {
+	n, err := issueKeyToNumber(key)
+	if err != nil {
+		return ""
+	}
+	return util.UrlJoin(i.HomeURL(), strconv.Itoa(n))
+}
+
+// HomeURL returns the URL of the issues of the project
+func (i *GitLabIssueService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, i.Project, "-", "issues")
+}
+
+func (i *GitLabIssueService) toGitIssue(issue *gitlab.Issue) *gits.GitIssue {
+	number := issue.IID
+	state := issue.State
+	owner, repo, _ := splitProject(i.Project)
+	answer := &gits.GitIssue{
+		URL:       issue.WebURL,
+		Owner:     owner,
+		Repo:      repo,
+		Number:    &number,
+		Key:       strconv.Itoa(number),
+		Title:     issue.Title,
+		Body:      issue.Description,
+		State:     &state,
+		Labels:    gits.ToGitLabels(issue.Labels),
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.ClosedAt,
+	}
+	if answer.URL == "" {
+		answer.URL = i.IssueURL(answer.Key)
+	}
+	if issue.Author != nil {
+		answer.User = &gits.GitUser{
+			URL:       issue.Author.WebURL,
+			Login:     issue.Author.Username,
+			Name:      issue.Author.Name,
+			AvatarURL: issue.Author.AvatarURL,
+		}
+	}
+	for _, a := range issue.Assignees {
+		if a != nil {
+			answer.Assignees = append(answer.Assignees, gits.GitUser{
+				URL:       a.WebURL,
+				Login:     a.Username,
+				Name:      a.Name,
+				AvatarURL: a.AvatarURL,
+			})
+		}
+	}
+	return answer
+}
@@ -0,0 +1,76 @@
+// +build unit
+
+package issues_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/issues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGitLabIssueProvider(t *testing.T) {
+	var created map[string]interface{}
+	var note map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("PRIVATE-TOKEN"))
+		switch r.URL.EscapedPath() {
+		case "/api/v4/projects/acme%2Fplatform%2Ftracker/issues/7":
+			_, _ = w.Write([]byte(`{"iid": 7, "web_url": "https://gitlab.acme.com/acme/platform/tracker/-/issues/7",
+				"title": "Flaky test", "description": "It fails", "state": "opened", "labels": ["test"], "author": {"username": "rawlingsj"}}`))
+		case "/api/v4/projects/acme%2Fplatform%2Ftracker/issues":
+			if r.Method == http.MethodPost {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+				w.WriteHeader(http.StatusCreated)
+				_, _ = w.Write([]byte(`{"iid": 8, "title": "New issue", "state": "opened"}`))
+				return
+			}
+			assert.Equal(t, "opened", r.URL.Query().Get("state"))
+			assert.Equal(t, "flaky", r.URL.Query().Get("search"))
+			_, _ = w.Write([]byte(`[{"iid": 7, "title": "Flaky test", "state": "opened"}]`))
+		case "/api/v4/projects/acme%2Fplatform%2Ftracker/issues/8/notes":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&note))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.GitLab, &auth.AuthServer{URL: server.URL, Kind: issues.GitLab},
+		&auth.UserAuth{Username: "jenkins-x-bot", ApiToken: "secret"}, "acme/platform/tracker", true, nil)
+	require.NoError(t, err)
+
+	issue, err := provider.GetIssue("7")
+	require.NoError(t, err)
+	assert.Equal(t, "7", issue.Key)
+	assert.Equal(t, "Flaky test", issue.Title)
+	assert.Equal(t, "It fails", issue.Body)
+	assert.Equal(t, "acme/platform", issue.Owner)
+	assert.Equal(t, "tracker", issue.Repo)
+	assert.Equal(t, "rawlingsj", issue.User.Login)
+
+	found, err := provider.SearchIssues("flaky")
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, server.URL+"/acme/platform/tracker/-/issues/7", found[0].URL)
+
+	newIssue, err := provider.CreateIssue(&gits.GitIssue{Title: "New issue", Labels: gits.ToGitLabels([]string{"bug", "ui"})})
+	require.NoError(t, err)
+	assert.Equal(t, "8", newIssue.Key)
+	assert.Equal(t, "New issue", created["title"])
+	assert.Equal(t, "bug,ui", created["labels"])
+
+	require.NoError(t, provider.CreateIssueComment("8", "Thanks"))
+	assert.Equal(t, "Thanks", note["body"])
+
+	_, err = provider.GetIssue("99")
+	assert.Error(t, err)
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
@@ -12,6 +13,7 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/gits"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
 )
 
 type JiraService struct {
@@ -82,8 +84,16 @@ func (i *JiraService) SearchIssues(query string) ([]*gits.GitIssue, error) {
 }
 
 func (i *JiraService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
-	log.Logger().Warn("TODO SearchIssuesClosedSince() not yet implemented for JIRA")
-	return nil, nil
+	jql := "project = " + i.Project + " AND resolved >= \"" + t.Format("2006/01/02 15:04") + "\""
+	answer := []*gits.GitIssue{}
+	issues, _, err := i.JiraClient.Issue.Search(jql, nil)
+	if err != nil {
+		return answer, err
+	}
+	for _, issue := range issues {
+		answer = append(answer, i.jiraToGitIssue(&issue))
+	}
+	return answer, nil
 }
 
 func (i *JiraService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
@@ -195,3 +205,78 @@ func (i *JiraService) ServerName() string {
 func (i *JiraService) HomeURL() string {
 	return util.UrlJoin(i.Server.URL, "browse", i.Project)
 }
+
+// TransitionIssue performs the transition of the issue whose name, or the name of the status it leads to, is the
+// given status
+func (i *JiraService) TransitionIssue(key string, status string) error {
+	issue, _, err := i.JiraClient.Issue.Get(key, nil)
+	if err != nil {
+		return errors.Wrapf(err, "getting issue %s", key)
+	}
+	if issue.Fields != nil && issue.Fields.Status != nil && strings.EqualFold(issue.Fields.Status.Name, status) {
+		return nil
+	}
+	transitions, _, err := i.JiraClient.Issue.GetTransitions(key)
+	if err != nil {
+		return errors.Wrapf(err, "getting the transitions of issue %s", key)
+	}
+	var names []string
+	for _, t := range transitions {
+		if strings.EqualFold(t.Name, status) || strings.EqualFold(t.To.Name, status) {
+			_, err = i.JiraClient.Issue.DoTransition(key, t.ID)
+			if err != nil {
+				return errors.Wrapf(err, "transitioning issue %s to %s", key, status)
+			}
+			return nil
+		}
+		names = append(names, t.Name)
+	}
+	return fmt.Errorf("no transition to %s is available for issue %s, the available transitions are: %s", status, key, strings.Join(names, ", "))
+}
+
+// SetFixVersion adds the version to the fix versions of the issue, creating the version in the project of the issue
+// if it does not exist
+func (i *JiraService) SetFixVersion(key string, version string) error {
+	projectKey := i.Project
+	if idx := strings.LastIndex(key, "-"); idx > 0 {
+		projectKey = key[:idx]
+	}
+	project, _, err := i.JiraClient.Project.Get(projectKey)
+	if err != nil {
+		return errors.Wrapf(err, "getting project %s", projectKey)
+	}
+	found := false
+	for _, v := range project.Versions {
+		if v.Name == version {
+			found = true
+			break
+		}
+	}
+	if !found {
+		projectID, err := strconv.Atoi(project.ID)
+		if err != nil {
+			return errors.Wrapf(err, "parsing the ID %s of project %s", project.ID, projectKey)
+		}
+		_, _, err = i.JiraClient.Version.Create(&jira.Version{
+			Name:      version,
+			ProjectID: projectID,
+		})
+		if err != nil {
+			return errors.Wrapf(err, "creating version %s in project %s", version, projectKey)
+		}
+	}
+	update := map[string]interface{}{
+		"update": map[string]interface{}{
+			"fixVersions": []interface{}{
+				map[string]interface{}{
+					"add": map[string]interface{}{"name": version},
+				},
+			},
+		},
+	}
+	_, err = i.JiraClient.Issue.UpdateIssue(key, update)
+	if err != nil {
+		return errors.Wrapf(err, "adding fix version %s to issue %s", version, key)
+	}
+	return nil
+}
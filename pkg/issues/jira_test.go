@@ -0,0 +1,79 @@
+// +build unit
+
+package issues_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/issues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJiraTransitionIssueAndSetFixVersion(t *testing.T) {
+	var transition map[string]interface{}
+	var version map[string]interface{}
+	var update map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rest/api/2/issue/ENG-1":
+			if r.Method == http.MethodPut {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&update))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			_, _ = w.Write([]byte(`{"key": "ENG-1", "fields": {"status": {"name": "In Review"}}}`))
+		case "/rest/api/2/issue/ENG-2":
+			_, _ = w.Write([]byte(`{"key": "ENG-2", "fields": {"status": {"name": "Deployed to Staging"}}}`))
+		case "/rest/api/2/issue/ENG-1/transitions":
+			if r.Method == http.MethodPost {
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&transition))
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+			_, _ = w.Write([]byte(`{"transitions": [{"id": "11", "name": "Reopen", "to": {"name": "Open"}},
+				{"id": "21", "name": "Deploy", "to": {"name": "Deployed to Staging"}}]}`))
+		case "/rest/api/2/project/ENG":
+			_, _ = w.Write([]byte(`{"id": "10000", "key": "ENG", "versions": [{"id": "1", "name": "1.0.0"}]}`))
+		case "/rest/api/2/version":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&version))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = w.Write([]byte(`{"id": "2", "name": "1.1.0", "projectId": 10000}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.Jira, &auth.AuthServer{URL: server.URL, Kind: issues.Jira},
+		&auth.UserAuth{Username: "jenkins-x-bot", ApiToken: "secret"}, "ENG", true, nil)
+	require.NoError(t, err)
+	transitioner, ok := provider.(issues.IssueTransitioner)
+	require.True(t, ok, "the Jira issue provider should support transitions")
+
+	require.NoError(t, transitioner.TransitionIssue("ENG-1", "deployed to staging"))
+	assert.Equal(t, map[string]interface{}{"id": "21"}, transition["transition"])
+
+	transition = nil
+	require.NoError(t, transitioner.TransitionIssue("ENG-2", "Deployed to Staging"))
+	assert.Nil(t, transition, "an issue which already has the status should not be transitioned")
+
+	err = transitioner.TransitionIssue("ENG-1", "Done")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Reopen, Deploy")
+
+	require.NoError(t, transitioner.SetFixVersion("ENG-1", "1.1.0"))
+	assert.Equal(t, "1.1.0", version["name"])
+	assert.Equal(t, float64(10000), version["projectId"])
+	fixVersions := update["update"].(map[string]interface{})["fixVersions"]
+	assert.Equal(t, []interface{}{map[string]interface{}{"add": map[string]interface{}{"name": "1.1.0"}}}, fixVersions)
+
+	version = nil
+	require.NoError(t, transitioner.SetFixVersion("ENG-1", "1.0.0"))
+	assert.Nil(t, version, "an existing version should not be created again")
+}
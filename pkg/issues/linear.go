@@ -0,0 +1,317 @@
+package issues
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const (
+	linearHost   = "linear.app"
+	linearAPIURL = "https://api.linear.app/graphql"
+
+	linearIssueFields = `id identifier title description url createdAt updatedAt completedAt canceledAt
+state { type }
+labels { nodes { name color } }
+creator { name displayName email avatarUrl url }
+assignee { name displayName email avatarUrl url }`
+)
+
+// LinearService is an issue tracker for the issues of a Linear team, using the Linear GraphQL API. The server URL is
+// the URL of the workspace, such as https://linear.app/acme, and the project is the key of the team, such as ENG.
+type LinearService struct {
+	Client   *http.Client
+	Server   *auth.AuthServer
+	UserAuth *auth.UserAuth
+	Team     string
+	APIURL   string
+}
+
+type linearRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables,omitempty"`
+}
+
+type linearResponse struct {
+	Data   interface{}   `json:"data"`
+	Errors []linearError `json:"errors"`
+}
+
+type linearError struct {
+	Message string `json:"message"`
+}
+
+type linearIssue struct {
+	ID          string     `json:"id"`
+	Identifier  string     `json:"identifier"`
+	Title       string     `json:"title"`
+	Description string     `json:"description"`
+	URL         string     `json:"url"`
+	CreatedAt   *time.Time `json:"createdAt"`
+	UpdatedAt   *time.Time `json:"updatedAt"`
+	CompletedAt *time.Time `json:"completedAt"`
+	CanceledAt  *time.Time `json:"canceledAt"`
+	State       *struct {
+		Type string `json:"type"`
+	} `json:"state"`
+	Labels struct {
+		Nodes []struct {
+			Name  string `json:"name"`
+			Color string `json:"color"`
+		} `json:"nodes"`
+	} `json:"labels"`
+	Creator  *linearUser `json:"creator"`
+	Assignee *linearUser `json:"assignee"`
+}
+
+type linearUser struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"displayName"`
+	Email       string `json:"email"`
+	AvatarURL   string `json:"avatarUrl"`
+	URL         string `json:"url"`
+}
+
+type linearIssueConnection struct {
+	Nodes    []linearIssue `json:"nodes"`
+	PageInfo struct {
+		HasNextPage bool   `json:"hasNextPage"`
+		EndCursor   string `json:"endCursor"`
+	} `json:"pageInfo"`
+}
+
+// CreateLinearIssueProvider creates an issue provider for the Linear team whose key is the project
+func CreateLinearIssueProvider(server *auth.AuthServer, userAuth *auth.UserAuth, project string) (IssueProvider, error) {
+	if server.URL == "" {
+		return nil, fmt.Errorf("No base URL for server!")
+	}
+	if project == "" {
+		return nil, fmt.Errorf("no Linear team key is configured as the project of the issue tracker")
+	}
+	if userAuth == nil || userAuth.ApiToken == "" {
+		return nil, fmt.Errorf("no API key found for Linear workspace %s", server.URL)
+	}
+	apiURL := linearAPIURL
+	u, err := url.Parse(server.URL)
+	if err != nil {
+		return nil, errors.Wrapf(err, "parsing Linear workspace URL %s", server.URL)
+	}
+	if u.Hostname() != linearHost {
+		apiURL = util.UrlJoin(u.Scheme+"://"+u.Host, "graphql")
+	}
+	return &LinearService{
+		Client:   util.GetClient(),
+		Server:   server,
+		UserAuth: userAuth,
+		Team:     strings.ToUpper(project),
+		APIURL:   apiURL,
+	}, nil
+}
+
+// GetIssue returns the issue with the given identifier, such as ENG-123
+func (i *LinearService) GetIssue(key string) (*gits.GitIssue, error) {
+	var data struct {
+		Issue *linearIssue `json:"issue"`
+	}
+	err := i.query("query($id: String!) { issue(id: $id) { "+linearIssueFields+" } }",
+		map[string]interface{}{"id": key}, &data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "getting Linear issue %s", key)
+	}
+	if data.Issue == nil {
+		return nil, fmt.Errorf("no Linear issue %s found", key)
+	}
+	return i.toGitIssue(data.Issue), nil
+}
+
+// SearchIssues searches the issues of the team which are not completed or canceled
+func (i *LinearService) SearchIssues(query string) ([]*gits.GitIssue, error) {
+	filter := i.teamFilter()
+	filter["state"] = map[string]interface{}{"type": map[string]interface{}{"nin": []string{"completed", "canceled"}}}
+	if query != "" {
+		filter["title"] = map[string]interface{}{"containsIgnoreCase": query}
+	}
+	return i.searchIssues(filter)
+}
+
+// SearchIssuesClosedSince searches the issues of the team completed since the given time
+func (i *LinearService) SearchIssuesClosedSince(t time.Time) ([]*gits.GitIssue, error) {
+	filter := i.teamFilter()
+	filter["completedAt"] = map[string]interface{}{"gte": t.UTC().Format(time.RFC3339)}
+	return i.searchIssues(filter)
+}
+
+func (i *LinearService) searchIssues(filter map[string]interface{}) ([]*gits.GitIssue, error) {
+	answer := []*gits.GitIssue{}
+	variables := map[string]interface{}{"filter": filter}
+	for {
+		var data struct {
+			Issues linearIssueConnection `json:"issues"`
+		}
+		err := i.query("query($filter: IssueFilter, $after: String) { issues(filter: $filter, first: "+strconv.Itoa(issuesPageSize)+
+			", after: $after) { nodes { "+linearIssueFields+" } pageInfo { hasNextPage endCursor } } }", variables, &data)
+		if err != nil {
+			return answer, errors.Wrapf(err, "searching the issues of Linear team %s", i.Team)
+		}
+		for k := range data.Issues.Nodes {
+			answer = append(answer, i.toGitIssue(&data.Issues.Nodes[k]))
+		}
+		if !data.Issues.PageInfo.HasNextPage || data.Issues.PageInfo.EndCursor == "" {
+			return answer, nil
+		}
+		variables["after"] = data.Issues.PageInfo.EndCursor
+	}
+}
+
+// CreateIssue creates a new issue in the team
+func (i *LinearService) CreateIssue(issue *gits.GitIssue) (*gits.GitIssue, error) {
+	var teams struct {
+		Teams struct {
+			Nodes []struct {
+				ID string `json:"id"`
+			} `json:"nodes"`
+		} `json:"teams"`
+	}
+	err := i.query("query($filter: TeamFilter) { teams(filter: $filter) { nodes { id } } }",
+		map[string]interface{}{"filter": map[string]interface{}{"key": map[string]interface{}{"eq": i.Team}}}, &teams)
+	if err != nil {
+		return nil, errors.Wrapf(err, "finding Linear team %s", i.Team)
+	}
+	if len(teams.Teams.Nodes) == 0 {
+		return nil, fmt.Errorf("no Linear team %s found", i.Team)
+	}
+
+	var data struct {
+		IssueCreate struct {
+			Success bool         `json:"success"`
+			Issue   *linearIssue `json:"issue"`
+		} `json:"issueCreate"`
+	}
+	input := map[string]interface{}{
+		"teamId":      teams.Teams.Nodes[0].ID,
+		"title":       issue.Title,
+		"description": issue.Body,
+	}
+	err = i.query("mutation($input: IssueCreateInput!) { issueCreate(input: $input) { success issue { "+linearIssueFields+" } } }",
+		map[string]interface{}{"input": input}, &data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "creating issue in Linear team %s", i.Team)
+	}
+	if !data.IssueCreate.Success || data.IssueCreate.Issue == nil {
+		return nil, fmt.Errorf("Linear did not create the issue in team %s", i.Team)
+	}
+	return i.toGitIssue(data.IssueCreate.Issue), nil
+}
+
+// CreateIssueComment comments on the issue with the given identifier
+func (i *LinearService) CreateIssueComment(key string, comment string) error {
+	var data struct {
+		CommentCreate struct {
+			Success bool `json:"success"`
+		} `json:"commentCreate"`
+	}
+	input := map[string]interface{}{
+		"issueId": key,
+		"body":    comment,
+	}
+	err := i.query("mutation($input: CommentCreateInput!) { commentCreate(input: $input) { success } }",
+		map[string]interface{}{"input": input}, &data)
+	if err != nil {
+		return errors.Wrapf(err, "commenting on Linear issue %s", key)
+	}
+	if !data.CommentCreate.Success {
+		return fmt.Errorf("Linear did not create the comment on issue %s", key)
+	}
+	return nil
+}
+
+// IssueURL returns the URL of the issue with the given identifier
+func (i *LinearService) IssueURL(key string) string {
+	return util.UrlJoin(i.Server.URL, "issue", key)
+}
+
+// HomeURL returns the URL of the team
+func (i *LinearService) HomeURL() string {
+	return util.UrlJoin(i.Server.URL, "team", i.Team)
+}
+
+func (i *LinearService) teamFilter() map[string]interface{} {
+	return map[string]interface{}{
+		"team": map[string]interface{}{"key": map[string]interface{}{"eq": i.Team}},
+	}
+}
+
+// query runs the GraphQL query or mutation, unmarshalling its data into the given value
+func (i *LinearService) query(query string, variables map[string]interface{}, data interface{}) error {
+	headers := map[string]string{
+		"Authorization": i.UserAuth.ApiToken,
+	}
+	resp := &linearResponse{Data: data}
+	err := util.DoJSON(i.Client, http.MethodPost, i.APIURL, headers, &linearRequest{Query: query, Variables: variables}, resp)
+	if err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		var messages []string
+		for _, e := range resp.Errors {
+			messages = append(messages, e.Message)
+		}
+		return fmt.Errorf("%s", strings.Join(messages, ", "))
+	}
+	return nil
+}
+
+func (i *LinearService) toGitIssue(issue *linearIssue) *gits.GitIssue {
+	answer := &gits.GitIssue{
+		URL:       issue.URL,
+		Key:       issue.Identifier,
+		Title:     issue.Title,
+		Body:      issue.Description,
+		CreatedAt: issue.CreatedAt,
+		UpdatedAt: issue.UpdatedAt,
+		ClosedAt:  issue.CompletedAt,
+		User:      linearUserToGitUser(issue.Creator),
+	}
+	if answer.URL == "" {
+		answer.URL = i.IssueURL(issue.Identifier)
+	}
+	if answer.ClosedAt == nil {
+		answer.ClosedAt = issue.CanceledAt
+	}
+	state := IssueOpen
+	if issue.State != nil && (issue.State.Type == "completed" || issue.State.Type == "canceled") {
+		state = IssueClosed
+	}
+	answer.State = &state
+	for _, l := range issue.Labels.Nodes {
+		answer.Labels = append(answer.Labels, gits.GitLabel{
+			Name:  l.Name,
+			Color: l.Color,
+		})
+	}
+	if assignee := linearUserToGitUser(issue.Assignee); assignee != nil {
+		answer.Assignees = []gits.GitUser{*assignee}
+	}
+	return answer
+}
+
+func linearUserToGitUser(user *linearUser) *gits.GitUser {
+	if user == nil {
+		return nil
+	}
+	return &gits.GitUser{
+		URL:       user.URL,
+		Login:     user.DisplayName,
+		Name:      user.Name,
+		Email:     user.Email,
+		AvatarURL: user.AvatarURL,
+	}
+}
@@ -0,0 +1,123 @@
+// +build unit
+
+package issues_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/issues"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type graphQLRequest struct {
+	Query     string                 `json:"query"`
+	Variables map[string]interface{} `json:"variables"`
+}
+
+func TestLinearIssueProvider(t *testing.T) {
+	var requests []graphQLRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/graphql", r.URL.Path)
+		require.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "lin_api_secret", r.Header.Get("Authorization"))
+		req := graphQLRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		requests = append(requests, req)
+		switch {
+		case strings.Contains(req.Query, "issue(id: $id)"):
+			if req.Variables["id"] != "ENG-42" {
+				_, _ = w.Write([]byte(`{"data": {"issue": null}, "errors": [{"message": "Entity not found"}]}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"issue": {"id": "uuid-42", "identifier": "ENG-42", "title": "Speed up builds",
+				"url": "https://linear.app/acme/issue/ENG-42/speed-up-builds", "state": {"type": "started"},
+				"labels": {"nodes": [{"name": "ci"}]}, "assignee": {"name": "James", "displayName": "james"}}}}`))
+		case strings.Contains(req.Query, "issues(filter: $filter"):
+			_, _ = w.Write([]byte(`{"data": {"issues": {"nodes": [{"identifier": "ENG-40", "title": "Done", "state": {"type": "completed"},
+				"completedAt": "2020-03-02T10:00:00Z"}]}}}`))
+		case strings.Contains(req.Query, "teams(filter: $filter)"):
+			_, _ = w.Write([]byte(`{"data": {"teams": {"nodes": [{"id": "team-uuid"}]}}}`))
+		case strings.Contains(req.Query, "issueCreate"):
+			_, _ = w.Write([]byte(`{"data": {"issueCreate": {"success": true, "issue": {"identifier": "ENG-43", "title": "New issue", "state": {"type": "unstarted"}}}}}`))
+		case strings.Contains(req.Query, "commentCreate"):
+			_, _ = w.Write([]byte(`{"data": {"commentCreate": {"success": true}}}`))
+		default:
+			t.Errorf("unexpected query %s", req.Query)
+		}
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.Linear, &auth.AuthServer{URL: server.URL + "/acme", Kind: issues.Linear},
+		&auth.UserAuth{ApiToken: "lin_api_secret"}, "eng", true, nil)
+	require.NoError(t, err)
+	assert.Equal(t, issues.Linear, issues.GetIssueProvider(provider))
+
+	issue, err := provider.GetIssue("ENG-42")
+	require.NoError(t, err)
+	assert.Equal(t, "ENG-42", issue.Key)
+	assert.Equal(t, "Speed up builds", issue.Title)
+	assert.Equal(t, "https://linear.app/acme/issue/ENG-42/speed-up-builds", issue.URL)
+	assert.Equal(t, issues.IssueOpen, *issue.State)
+	require.Len(t, issue.Assignees, 1)
+	assert.Equal(t, "james", issue.Assignees[0].Login)
+
+	_, err = provider.GetIssue("ENG-1")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Entity not found")
+
+	closed, err := provider.SearchIssuesClosedSince(time.Date(2020, 3, 1, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Len(t, closed, 1)
+	assert.Equal(t, issues.IssueClosed, *closed[0].State)
+	filter := requests[len(requests)-1].Variables["filter"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"key": map[string]interface{}{"eq": "ENG"}}, filter["team"])
+	assert.Equal(t, map[string]interface{}{"gte": "2020-03-01T00:00:00Z"}, filter["completedAt"])
+
+	newIssue, err := provider.CreateIssue(&gits.GitIssue{Title: "New issue", Body: "Details"})
+	require.NoError(t, err)
+	assert.Equal(t, "ENG-43", newIssue.Key)
+	assert.Equal(t, server.URL+"/acme/issue/ENG-43", newIssue.URL)
+	input := requests[len(requests)-1].Variables["input"].(map[string]interface{})
+	assert.Equal(t, "team-uuid", input["teamId"])
+	assert.Equal(t, "Details", input["description"])
+
+	require.NoError(t, provider.CreateIssueComment("ENG-43", "Released"))
+	assert.Equal(t, server.URL+"/acme/team/ENG", provider.HomeURL())
+}
+
+func TestLinearIssueProviderFollowsPages(t *testing.T) {
+	var afters []interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := graphQLRequest{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		require.Contains(t, req.Query, "pageInfo { hasNextPage endCursor }")
+		afters = append(afters, req.Variables["after"])
+		if req.Variables["after"] == nil {
+			_, _ = w.Write([]byte(`{"data": {"issues": {"nodes": [{"identifier": "ENG-1", "title": "First"}],
+				"pageInfo": {"hasNextPage": true, "endCursor": "cursor-1"}}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"issues": {"nodes": [{"identifier": "ENG-2", "title": "Second"}],
+			"pageInfo": {"hasNextPage": false, "endCursor": "cursor-2"}}}}`))
+	}))
+	defer server.Close()
+
+	provider, err := issues.CreateIssueProvider(issues.Linear, &auth.AuthServer{URL: server.URL + "/acme", Kind: issues.Linear},
+		&auth.UserAuth{ApiToken: "lin_api_secret"}, "eng", true, nil)
+	require.NoError(t, err)
+
+	found, err := provider.SearchIssues("")
+	require.NoError(t, err)
+	require.Len(t, found, 2)
+	assert.Equal(t, "ENG-1", found[0].Key)
+	assert.Equal(t, "ENG-2", found[1].Key)
+	assert.Equal(t, []interface{}{nil, "cursor-1"}, afters)
+}
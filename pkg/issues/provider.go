@@ -2,10 +2,12 @@ package issues
 
 import (
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/jenkins-x/jx/v2/pkg/auth"
 	"github.com/jenkins-x/jx/v2/pkg/gits"
+	"github.com/jenkins-x/jx/v2/pkg/util"
 )
 
 type IssueProvider interface {
@@ -31,10 +33,25 @@ type IssueProvider interface {
 	HomeURL() string
 }
 
+// IssueTransitioner is implemented by issue providers which can move issues through a workflow, such as Jira
+type IssueTransitioner interface {
+	// TransitionIssue moves the given issue to the status of the given name, doing nothing if it already has it
+	TransitionIssue(key string, status string) error
+
+	// SetFixVersion adds the version, creating it if need be, to the fix versions of the given issue
+	SetFixVersion(key string, version string) error
+}
+
 func CreateIssueProvider(kind string, server *auth.AuthServer, userAuth *auth.UserAuth, project string, batchMode bool, git gits.Gitter) (IssueProvider, error) {
 	switch kind {
 	case Jira:
 		return CreateJiraIssueProvider(server, userAuth, project, batchMode, git)
+	case GitHub:
+		return CreateGitHubIssueProvider(server, userAuth, project, batchMode)
+	case GitLab:
+		return CreateGitLabIssueProvider(server, userAuth, project, batchMode)
+	case Linear:
+		return CreateLinearIssueProvider(server, userAuth, project)
 	default:
 		return nil, fmt.Errorf("Unsupported issue provider kind: %s", kind)
 	}
@@ -45,6 +62,18 @@ func ProviderAccessTokenURL(kind string, url string) string {
 	case Jira:
 		// TODO handle on premise servers too by detecting the URL is at atlassian.com
 		return "https://id.atlassian.com/manage/api-tokens"
+	case GitHub:
+		if url == "" {
+			url = gitHubServerURL
+		}
+		return util.UrlJoin(url, "settings/tokens")
+	case GitLab:
+		if url == "" {
+			url = gitLabServerURL
+		}
+		return util.UrlJoin(url, "profile/personal_access_tokens")
+	case Linear:
+		return util.UrlJoin(url, "settings/api")
 	default:
 		return ""
 	}
@@ -52,9 +81,20 @@ func ProviderAccessTokenURL(kind string, url string) string {
 
 // GetIssueProvider returns the kind of issue provider
 func GetIssueProvider(tracker IssueProvider) string {
-	_, ok := tracker.(*JiraService)
-	if ok {
+	switch tracker.(type) {
+	case *JiraService:
 		return Jira
+	case *LinearService:
+		return Linear
 	}
 	return Git
 }
+
+// splitProject splits a project of the form owner/repository at its last slash
+func splitProject(project string) (string, string, error) {
+	idx := strings.LastIndex(project, "/")
+	if idx <= 0 || idx == len(project)-1 {
+		return "", "", fmt.Errorf("project %s should be of the form owner/repository", project)
+	}
+	return project[:idx], project[idx+1:], nil
+}
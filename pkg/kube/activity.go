@@ -62,6 +62,7 @@ type PromoteStepActivityKey struct {
 
 type PromotePullRequestFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromotePullRequestStep) error
 type PromoteUpdateFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep, *v1.PromoteUpdateStep) error
+type PromoteFn func(*v1.PipelineActivity, *v1.PipelineActivityStep, *v1.PromoteActivityStep) error
 
 type PipelineDetails struct {
 	GitOwner      string
@@ -691,6 +692,33 @@ func (k *PromoteStepActivityKey) GetOrCreatePromoteUpdate(jxClient versioned.Int
 	return a, s, p, p.Update, created, err
 }
 
+// OnPromote updates the Promote step of the activity, such as when checking the release before promoting it
+func (k *PromoteStepActivityKey) OnPromote(jxClient versioned.Interface, ns string, fn PromoteFn) error {
+	if !k.IsValid() {
+		return nil
+	}
+	activities := jxClient.JenkinsV1().PipelineActivities(ns)
+	if activities == nil {
+		log.Logger().Warn("Warning: no PipelineActivities client available!")
+		return nil
+	}
+	a, s, ps, added, err := k.GetOrCreatePromote(jxClient, ns)
+	if err != nil {
+		return err
+	}
+	p1 := asYaml(a)
+	err = fn(a, s, ps)
+	if err != nil {
+		return err
+	}
+	p2 := asYaml(a)
+
+	if added || p1 == "" || p1 != p2 {
+		_, err = activities.PatchUpdate(a)
+	}
+	return err
+}
+
 //OnPromotePullRequest updates activities on a Promote PR
 func (k *PromoteStepActivityKey) OnPromotePullRequest(kubeClient kubernetes.Interface, jxClient versioned.Interface, ns string, fn PromotePullRequestFn) error {
 	if !k.IsValid() {
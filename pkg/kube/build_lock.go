@@ -1,8 +1,10 @@
 package kube
 
 import (
+	"encoding/json"
 	"fmt"
 	"os"
+	"sort"
 	"strconv"
 	"time"
 
@@ -11,6 +13,8 @@ import (
 	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/watch"
 	"k8s.io/client-go/kubernetes"
 )
@@ -29,11 +33,40 @@ var buildLockPhaseRunning map[v1.PodPhase]bool = map[v1.PodPhase]bool{
 // DisableBuildLockEnvKey environment variable used to disable build lock in jx step helm apply
 const DisableBuildLockEnvKey = "JX_DISABLE_BUILD_LOCK"
 
+// the key of the lock data storing the queue of waiting builds
+const buildLockQueueKey = "queue"
+
+// the key of the lock data storing the build holding the lock
+// the other keys are kept for the previous versions of jx, which store the waiting build in them
+const buildLockHolderKey = "holder"
+
+// BuildLockWaiter is a build holding or waiting for the build lock of a namespace
+type BuildLockWaiter struct {
+	Owner      string    `json:"owner"`
+	Repository string    `json:"repository"`
+	Branch     string    `json:"branch"`
+	Build      string    `json:"build"`
+	Pod        string    `json:"pod,omitempty"`
+	UID        types.UID `json:"uid,omitempty"`
+	Timestamp  string    `json:"timestamp"`
+}
+
+// BuildLock is the build holding the lock of a namespace and the queue of builds waiting for it
+type BuildLock struct {
+	Name      string            `json:"name"`
+	Namespace string            `json:"namespace"`
+	Holder    BuildLockWaiter   `json:"holder"`
+	Expires   string            `json:"expires,omitempty"`
+	Queue     []BuildLockWaiter `json:"queue,omitempty"`
+}
+
 // AcquireBuildLock acquires a build lock, to avoid other builds to edit the
-// same namespace while a deployment is already running, other deployments
-// can negotiate which one should run after, by editing its data.
+// same namespace while a deployment is already running. Other deployments
+// wait in a queue stored in the lock, ordered by arrival, and the lock is
+// handed over to the head of the queue when released. An older build waiting
+// for the same branch is superseded by a newer one, which takes its place.
 // Returns a function to release the lock (to be called in a defer)
-// Returns an error if a newer build is already running, or if an error happened
+// Returns an error if a newer build is already running or waiting, or if an error happened
 func AcquireBuildLock(kubeClient kubernetes.Interface, devNamespace, namespace string) (func() error, error) {
 	// Only lock if running in Tekton
 	if ok, err := IsTektonEnabled(kubeClient, devNamespace); err != nil {
@@ -48,6 +81,11 @@ func AcquireBuildLock(kubeClient kubernetes.Interface, devNamespace, namespace s
 	if err != nil {
 		return nil, err
 	}
+	build := buildLockHolder(lock)
+	// returns a function that releases the lock
+	release := func() error {
+		return releaseBuildLock(kubeClient, devNamespace, lock.Name, build)
+	}
 	// this loop continuously tries to create the lock
 Create:
 	for {
@@ -59,7 +97,7 @@ Create:
 		}
 		log.Logger().Infof("creating the lock configmap %s", lock.Name)
 		// create the lock
-		new, err := kubeClient.CoreV1().ConfigMaps(devNamespace).Create(lock)
+		_, err := kubeClient.CoreV1().ConfigMaps(devNamespace).Create(lock)
 		if err != nil {
 			status, ok := err.(*errors.StatusError)
 			// an error while creating the lock
@@ -72,20 +110,7 @@ Create:
 		} else {
 			// the lock is created, can now perform the updates
 			log.Logger().Infof("lock configmap %s created", lock.Name)
-			// returns a function that releases the lock
-			return func() error {
-				log.Logger().Infof("cleaning the lock configmap %s", lock.Name)
-				err := kubeClient.CoreV1().ConfigMaps(devNamespace).Delete(lock.Name,
-					&metav1.DeleteOptions{
-						Preconditions: &metav1.Preconditions{
-							UID: &new.UID,
-						},
-					})
-				if err != nil {
-					log.Logger().Warnf("failed to cleanup the lock configmap %s: %s\n", lock.Name, err.Error())
-				}
-				return err
-			}, nil
+			return release, nil
 		}
 		// create these variables outside, to be able to edit them before the next loop
 		var old *v1.ConfigMap
@@ -107,45 +132,50 @@ Create:
 					return nil, err
 				}
 			}
+			// the lock was handed over to this build
+			if isBuildLockHolder(old, build) {
+				log.Logger().Infof("lock configmap %s handed over", lock.Name)
+				return release, nil
+			}
 			// get the locking pod
 			var remove bool
 			remove, pod, err = getLockingPod(kubeClient, namespace, old, pod)
 			if err != nil {
 				return nil, err
-				// the lock should simply be removed
+				// the lock should be handed over to the next build
 			} else if remove {
-				log.Logger().Infof("cleaning the old lock configmap %s", lock.Name)
-				err := kubeClient.CoreV1().ConfigMaps(devNamespace).Delete(lock.Name,
-					&metav1.DeleteOptions{
-						Preconditions: &metav1.Preconditions{
-							UID: &old.UID,
-						},
-					})
-				// removed, now try to create it
-				if err == nil {
-					continue Create
+				log.Logger().Infof("handing over the old lock configmap %s", lock.Name)
+				old, err = handOverBuildLock(kubeClient, old)
+				if err != nil {
+					status, ok := err.(*errors.StatusError)
+					// already deleted, try to create it
+					if ok && status.Status().Reason == metav1.StatusReasonNotFound {
+						continue Create
+						// the lock changed, read it again
+					} else if ok && status.Status().Reason == metav1.StatusReasonConflict {
+						log.Logger().Infof("lock configmap %s changed", lock.Name)
+						old = nil
+						continue Read
+					}
+					// an error while handing over the lock
+					log.Logger().Warnf("failed to hand over the old lock configmap %s: %s\n", lock.Name, err.Error())
+					return nil, err
 				}
-				status, ok := err.(*errors.StatusError)
-				// already deleted, try to create it
-				if ok && status.Status().Reason == metav1.StatusReasonNotFound {
+				// removed as nobody was waiting, now try to create it
+				if old == nil {
 					continue Create
-					// the lock changed, read it again
-				} else if ok && status.Status().Reason == metav1.StatusReasonConflict {
-					log.Logger().Infof("lock configmap %s changed", lock.Name)
-					old = nil
-					continue Read
-					// an error while removing the pod
-				} else {
-					log.Logger().Warnf("failed to cleanup the old lock configmap %s: %s\n", lock.Name, err.Error())
-					return nil, err
 				}
+				continue Read
 			}
-			// compare the builds
-			if data, err := compareBuildLocks(old.Data, lock.Data); err != nil {
+			// join the queue
+			if queue, err := enqueueBuildLock(old, build); err != nil {
 				return nil, err
-				// should update the build to wait
-			} else if data != nil {
-				old.Data = data
+				// should update the queue to wait
+			} else if queue != nil {
+				err = setBuildLockQueue(old, queue)
+				if err != nil {
+					return nil, err
+				}
 				old, err = kubeClient.CoreV1().ConfigMaps(devNamespace).Update(old)
 				if err != nil {
 					status, ok := err.(*errors.StatusError)
@@ -163,9 +193,10 @@ Create:
 					log.Logger().Warnf("failed to update the lock configmap %s: %s\n", lock.Name, err.Error())
 					return nil, err
 				}
+				log.Logger().Infof("waiting in position %d of the lock configmap %s", buildLockPosition(queue, build), lock.Name)
 			}
 			// watch the lock for updates
-			if old, err = watchBuildLock(kubeClient, old, pod, lock.Data); err != nil {
+			if old, err = watchBuildLock(kubeClient, old, pod); err != nil {
 				return nil, err
 				// lock configmap was updated, read it again
 			} else if old != nil {
@@ -178,6 +209,137 @@ Create:
 	}
 }
 
+// releaseBuildLock hands the lock over to the next waiting build if the given build still holds it
+func releaseBuildLock(kubeClient kubernetes.Interface, devNamespace, name string, build BuildLockWaiter) error {
+	for {
+		lock, err := kubeClient.CoreV1().ConfigMaps(devNamespace).Get(name, metav1.GetOptions{})
+		if err != nil {
+			status, ok := err.(*errors.StatusError)
+			if ok && status.Status().Reason == metav1.StatusReasonNotFound {
+				log.Logger().Warnf("the lock configmap %s was already removed", name)
+				return nil
+			}
+			log.Logger().Warnf("failed to get the lock configmap %s: %s\n", name, err.Error())
+			return err
+		}
+		if !isBuildLockHolder(lock, build) {
+			log.Logger().Warnf("the lock configmap %s is not held by build %s anymore", name, build.Build)
+			return nil
+		}
+		log.Logger().Infof("cleaning the lock configmap %s", name)
+		_, err = handOverBuildLock(kubeClient, lock)
+		if err != nil {
+			status, ok := err.(*errors.StatusError)
+			// the lock changed, another build is joining the queue
+			if ok && status.Status().Reason == metav1.StatusReasonConflict {
+				continue
+			}
+			if ok && status.Status().Reason == metav1.StatusReasonNotFound {
+				return nil
+			}
+			log.Logger().Warnf("failed to cleanup the lock configmap %s: %s\n", name, err.Error())
+		}
+		return err
+	}
+}
+
+// handOverBuildLock gives the lock to the first build of its queue, or removes the lock if no build is waiting.
+// Returns the updated lock, or nil if it was removed
+func handOverBuildLock(kubeClient kubernetes.Interface, lock *v1.ConfigMap) (*v1.ConfigMap, error) {
+	queue, err := buildLockQueue(lock)
+	if err != nil {
+		return nil, err
+	}
+	if len(queue) == 0 {
+		err := kubeClient.CoreV1().ConfigMaps(lock.Namespace).Delete(lock.Name,
+			&metav1.DeleteOptions{
+				Preconditions: &metav1.Preconditions{
+					UID: &lock.UID,
+				},
+			})
+		return nil, err
+	}
+	next := lock.DeepCopy()
+	err = setBuildLockHolder(next, queue[0])
+	if err != nil {
+		return nil, err
+	}
+	err = setBuildLockQueue(next, queue[1:])
+	if err != nil {
+		return nil, err
+	}
+	log.Logger().Infof("handing the lock configmap %s over to %s/%s/%s #%s", lock.Name, queue[0].Owner,
+		queue[0].Repository, queue[0].Branch, queue[0].Build)
+	return kubeClient.CoreV1().ConfigMaps(lock.Namespace).Update(next)
+}
+
+// GetBuildLocks returns the build locks of the namespaces with their holder and queue
+func GetBuildLocks(kubeClient kubernetes.Interface, devNamespace string) ([]BuildLock, error) {
+	list, err := kubeClient.CoreV1().ConfigMaps(devNamespace).List(metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(buildLockLabels).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	answer := []BuildLock{}
+	for i := range list.Items {
+		lock := &list.Items[i]
+		queue, err := buildLockQueue(lock)
+		if err != nil {
+			return nil, err
+		}
+		answer = append(answer, BuildLock{
+			Name:      lock.Name,
+			Namespace: lock.Labels["namespace"],
+			Holder:    buildLockHolder(lock),
+			Expires:   lock.Annotations["expires"],
+			Queue:     queue,
+		})
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		return answer[i].Namespace < answer[j].Namespace
+	})
+	return answer, nil
+}
+
+// BreakBuildLock breaks the build lock of a namespace, handing it over to the next waiting build.
+// Returns the lock after it was broken, or nil if no build was waiting and the lock was removed
+func BreakBuildLock(kubeClient kubernetes.Interface, devNamespace, namespace string) (*BuildLock, error) {
+	for {
+		lock, err := kubeClient.CoreV1().ConfigMaps(devNamespace).Get(buildLockName(namespace), metav1.GetOptions{})
+		if err != nil {
+			return nil, err
+		}
+		next, err := handOverBuildLock(kubeClient, lock)
+		if err != nil {
+			status, ok := err.(*errors.StatusError)
+			if ok && status.Status().Reason == metav1.StatusReasonConflict {
+				continue
+			}
+			return nil, err
+		}
+		if next == nil {
+			return nil, nil
+		}
+		queue, err := buildLockQueue(next)
+		if err != nil {
+			return nil, err
+		}
+		return &BuildLock{
+			Name:      next.Name,
+			Namespace: namespace,
+			Holder:    buildLockHolder(next),
+			Expires:   next.Annotations["expires"],
+			Queue:     queue,
+		}, nil
+	}
+}
+
+// buildLockName returns the name of the lock configmap of a namespace
+func buildLockName(namespace string) string {
+	return fmt.Sprintf("jx-lock-%s", namespace)
+}
+
 // makeBuildLock make the lock configmap of the current build
 func makeBuildLock(kubeClient kubernetes.Interface, devNamespace, namespace string) (*v1.ConfigMap, error) {
 	// Get infos from the headers
@@ -206,7 +368,7 @@ func makeBuildLock(kubeClient kubernetes.Interface, devNamespace, namespace stri
 	// Create the lock object
 	lock := &v1.ConfigMap{
 		ObjectMeta: metav1.ObjectMeta{
-			Name:      fmt.Sprintf("jx-lock-%s", namespace),
+			Name:      buildLockName(namespace),
 			Namespace: devNamespace,
 			Labels: map[string]string{
 				"namespace":  namespace,
@@ -266,6 +428,10 @@ func makeBuildLock(kubeClient kubernetes.Interface, devNamespace, namespace stri
 		}}
 		lock.Data["pod"] = pod.Name
 	}
+	err := storeBuildLockHolder(lock, buildLockHolder(lock))
+	if err != nil {
+		return nil, err
+	}
 	return lock, nil
 }
 
@@ -336,8 +502,8 @@ func getLockingPod(kubeClient kubernetes.Interface, namespace string, lock *v1.C
 
 // watchBuildLock watches a lock configmap and its locking pod to detect any change
 // Returns nil if the lock was deleted, or is expected to be deleted
-// Returns the new lock configmap if another build is waiting
-func watchBuildLock(kubeClient kubernetes.Interface, lock *v1.ConfigMap, pod *v1.Pod, build map[string]string) (*v1.ConfigMap, error) {
+// Returns the new lock configmap if it was updated, or has expired
+func watchBuildLock(kubeClient kubernetes.Interface, lock *v1.ConfigMap, pod *v1.Pod) (*v1.ConfigMap, error) {
 	log.Logger().Infof("waiting for updates on the lock configmap %s", lock.Name)
 	// watch a timer for expiration
 	var expChan <-chan time.Time
@@ -354,9 +520,9 @@ func watchBuildLock(kubeClient kubernetes.Interface, lock *v1.ConfigMap, pod *v1
 		}
 		log.Logger().Infof("waiting for the lock configmap %s for %s. "+
 			"if you are sure that the local build %s/%s #%s has finished, "+
-			"you can clean the lock with\n\t`kubectl delete configmap -n %s %s`",
+			"you can break the lock with\n\t`jx delete lock %s`",
 			lock.Name, remaining.Round(time.Second), lock.Labels["repository"],
-			lock.Labels["branch"], lock.Labels["build"], lock.Namespace, lock.Name)
+			lock.Labels["branch"], lock.Labels["build"], lock.Labels["namespace"])
 		timer := time.NewTimer(remaining)
 		defer timer.Stop()
 		expChan = timer.C
@@ -389,15 +555,9 @@ func watchBuildLock(kubeClient kubernetes.Interface, lock *v1.ConfigMap, pod *v1
 		// an event about the lock
 		case event := <-lockChan:
 			switch event.Type {
-			// the lock has changed
+			// the lock has changed, read it again
 			case watch.Added, watch.Modified:
-				lock = event.Object.(*v1.ConfigMap)
-				// if the waiting build has changed, read again
-				if next, err := compareBuildLocks(lock.Data, build); err != nil {
-					return nil, err
-				} else if next != nil {
-					return lock, nil
-				}
+				return event.Object.(*v1.ConfigMap), nil
 			// the lock is deleted, try to create it
 			case watch.Deleted:
 				return nil, nil
@@ -433,70 +593,220 @@ func watchBuildLock(kubeClient kubernetes.Interface, lock *v1.ConfigMap, pod *v1
 	}
 }
 
-// compareBuildLocks compares two builds
-// If next is nil, the build is already waiting
-// if next is not nil, the build should wait by updating the lock with these data
-func compareBuildLocks(old, new map[string]string) (map[string]string, error) {
-	sameRepo := true
-	for _, k := range [3]string{"owner", "repository", "branch"} {
-		if old[k] != new[k] {
-			sameRepo = false
+// buildLockHolder returns the build holding a lock
+// The previous versions of jx store the build waiting for the lock in its data, so the holder of a lock that
+// they created is read from its labels and owner
+func buildLockHolder(lock *v1.ConfigMap) BuildLockWaiter {
+	holder := BuildLockWaiter{}
+	if data := lock.Data[buildLockHolderKey]; data != "" {
+		err := json.Unmarshal([]byte(data), &holder)
+		if err == nil {
+			return holder
 		}
+		log.Logger().Warnf("cannot parse the holder of the lock %s: %s\n", lock.Name, err.Error())
 	}
-	// both are deplying the same repo and branch, compare build number
-	if sameRepo {
-		// same build and pod, we're already waiting
-		if old["build"] == new["build"] && old["pod"] == new["pod"] && old["expires"] == new["expires"] {
-			return nil, nil
+	holder = BuildLockWaiter{
+		Owner:      lock.Labels["owner"],
+		Repository: lock.Labels["repository"],
+		Branch:     lock.Labels["branch"],
+		Build:      lock.Labels["build"],
+		Timestamp:  lock.CreationTimestamp.UTC().Format(time.RFC3339),
+	}
+	if lock.CreationTimestamp.IsZero() {
+		holder.Timestamp = lock.Data["timestamp"]
+	}
+	if len(lock.OwnerReferences) == 1 {
+		holder.Pod = lock.OwnerReferences[0].Name
+		holder.UID = lock.OwnerReferences[0].UID
+	}
+	return holder
+}
+
+// storeBuildLockHolder stores the build holding a lock in its data
+func storeBuildLockHolder(lock *v1.ConfigMap, build BuildLockWaiter) error {
+	data, err := json.Marshal(build)
+	if err != nil {
+		return err
+	}
+	if lock.Data == nil {
+		lock.Data = map[string]string{}
+	}
+	lock.Data[buildLockHolderKey] = string(data)
+	return nil
+}
+
+// isBuildLockHolder returns true if the lock is held by the given build
+func isBuildLockHolder(lock *v1.ConfigMap, build BuildLockWaiter) bool {
+	for k, v := range buildLockLabels {
+		if lock.Labels[k] != v {
+			return false
 		}
-		// parse the builds
-		if oldBuild, err := strconv.Atoi(old["build"]); err != nil {
-			log.Logger().Warnf("cannot parse the lock's build number %s: %s\n", old["build"], err.Error())
-			return nil, err
-		} else if newBuild, err := strconv.Atoi(new["build"]); err != nil {
-			log.Logger().Warnf("cannot parse the lock's build number %s: %s\n", new["build"], err.Error())
-			return nil, err
-			// older build, give up
-		} else if oldBuild >= newBuild {
-			log.Logger().Warnf("newer build %d is waiting already", oldBuild)
-			return nil, fmt.Errorf("newer build %d is waiting already", oldBuild)
+	}
+	holder := buildLockHolder(lock)
+	return sameBuildLockBranch(holder, build) && holder.Build == build.Build && holder.Pod == build.Pod
+}
+
+// setBuildLockHolder makes the given build the holder of a lock
+func setBuildLockHolder(lock *v1.ConfigMap, build BuildLockWaiter) error {
+	values := map[string]string{
+		"owner":      build.Owner,
+		"repository": build.Repository,
+		"branch":     build.Branch,
+		"build":      build.Build,
+	}
+	for k, v := range values {
+		lock.Labels[k] = v
+		lock.Data[k] = v
+	}
+	lock.Data["timestamp"] = build.Timestamp
+	if lock.Annotations == nil {
+		lock.Annotations = map[string]string{}
+	}
+	if build.Pod != "" {
+		lock.OwnerReferences = []metav1.OwnerReference{{
+			APIVersion: "v1",
+			Kind:       "Pod",
+			Name:       build.Pod,
+			UID:        build.UID,
+		}}
+		lock.Data["pod"] = build.Pod
+		delete(lock.Annotations, "expires")
+		delete(lock.Data, "expires")
+	} else {
+		// no pod to follow, set an expiration date
+		expires := time.Now().UTC().Add(buildLockExpires).Format(time.RFC3339)
+		lock.OwnerReferences = nil
+		delete(lock.Data, "pod")
+		lock.Annotations["expires"] = expires
+		lock.Data["expires"] = expires
+	}
+	return storeBuildLockHolder(lock, build)
+}
+
+// buildLockQueue returns the builds waiting for a lock
+func buildLockQueue(lock *v1.ConfigMap) ([]BuildLockWaiter, error) {
+	queue := []BuildLockWaiter{}
+	data := lock.Data[buildLockQueueKey]
+	if data == "" {
+		return queue, nil
+	}
+	err := json.Unmarshal([]byte(data), &queue)
+	if err != nil {
+		log.Logger().Warnf("cannot parse the queue of the lock %s: %s\n", lock.Name, err.Error())
+		return nil, err
+	}
+	return queue, nil
+}
+
+// setBuildLockQueue stores the builds waiting for a lock
+func setBuildLockQueue(lock *v1.ConfigMap, queue []BuildLockWaiter) error {
+	if len(queue) == 0 {
+		delete(lock.Data, buildLockQueueKey)
+		return nil
+	}
+	data, err := json.Marshal(queue)
+	if err != nil {
+		return err
+	}
+	if lock.Data == nil {
+		lock.Data = map[string]string{}
+	}
+	lock.Data[buildLockQueueKey] = string(data)
+	return nil
+}
+
+// buildLockPosition returns the 1 based position of a build in a queue, or 0 if it is not waiting
+func buildLockPosition(queue []BuildLockWaiter, build BuildLockWaiter) int {
+	for i, w := range queue {
+		if sameBuildLockBranch(w, build) && w.Build == build.Build {
+			return i + 1
 		}
-		// parse the timestamps in order to keep th newest one
-		if oldTime, err := time.Parse(time.RFC3339, old["timestamp"]); err != nil {
-			log.Logger().Warnf("cannot parse the lock's timestamp %s: %s\n", old["timestamp"], err.Error())
-			return nil, err
-		} else if newTime, err := time.Parse(time.RFC3339, new["timestamp"]); err != nil {
-			log.Logger().Warnf("cannot parse the lock's timestamp %s: %s\n", new["timestamp"], err.Error())
+	}
+	return 0
+}
+
+// sameBuildLockBranch returns true if both builds are deploying the same repository and branch
+func sameBuildLockBranch(a, b BuildLockWaiter) bool {
+	return a.Owner == b.Owner && a.Repository == b.Repository && a.Branch == b.Branch
+}
+
+// compareBuildNumbers compares the build numbers of two builds of the same branch
+func compareBuildNumbers(a, b BuildLockWaiter) (int, error) {
+	aBuild, err := strconv.Atoi(a.Build)
+	if err != nil {
+		log.Logger().Warnf("cannot parse the lock's build number %s: %s\n", a.Build, err.Error())
+		return 0, err
+	}
+	bBuild, err := strconv.Atoi(b.Build)
+	if err != nil {
+		log.Logger().Warnf("cannot parse the lock's build number %s: %s\n", b.Build, err.Error())
+		return 0, err
+	}
+	return aBuild - bBuild, nil
+}
+
+// enqueueBuildLock computes the queue of a lock once the build joined it
+// The queue is kept in the order of arrival, a build joining the end of the queue or taking the place of
+// the older build of the same branch it supersedes
+// If the queue is nil, the build is already waiting
+// If the queue is not nil, the build should wait by updating the lock with this queue
+// Returns an error if a newer build of the same branch is already running or waiting
+func enqueueBuildLock(lock *v1.ConfigMap, build BuildLockWaiter) ([]BuildLockWaiter, error) {
+	holder := buildLockHolder(lock)
+	if sameBuildLockBranch(holder, build) {
+		cmp, err := compareBuildNumbers(holder, build)
+		if err != nil {
 			return nil, err
-			// keep increasing the timestamp, for consistency reasons
-		} else if oldTime.After(newTime) {
-			next := map[string]string{}
-			for k, v := range new {
-				next[k] = v
-			}
-			next["timestamp"] = old["timestamp"]
-			return next, nil
-			// timestamp already right
-		} else {
-			return new, nil
 		}
-		// both are deploying different repos, keep the newest one
-		// it is a corner case for consistency
-		// but should not happen on a standard cluster
-	} else {
-		// parse the timestamps
-		if oldTime, err := time.Parse(time.RFC3339, old["timestamp"]); err != nil {
-			log.Logger().Warnf("cannot parse the lock's timestamp %s: %s\n", old["timestamp"], err.Error())
-			return nil, err
-		} else if newTime, err := time.Parse(time.RFC3339, new["timestamp"]); err != nil {
-			log.Logger().Warnf("cannot parse the lock's timestamp %s: %s\n", new["timestamp"], err.Error())
+		// same or newer build running, give up
+		if cmp >= 0 {
+			log.Logger().Warnf("newer build %s is running already", holder.Build)
+			return nil, fmt.Errorf("newer build %s is running already", holder.Build)
+		}
+	}
+	queue, err := buildLockQueue(lock)
+	if err != nil {
+		return nil, err
+	}
+	next := []BuildLockWaiter{}
+	found := false
+	changed := false
+	for _, w := range queue {
+		if !sameBuildLockBranch(w, build) {
+			next = append(next, w)
+			continue
+		}
+		// same build and pod, we're already waiting
+		if w.Build == build.Build && w.Pod == build.Pod && !found {
+			next = append(next, w)
+			found = true
+			continue
+		}
+		cmp, err := compareBuildNumbers(w, build)
+		if err != nil {
 			return nil, err
-			// newer deployment, wait
-		} else if newTime.After(oldTime) {
-			return new, nil
-			// older deployment, give up
-		} else {
-			return nil, fmt.Errorf("newer build %s is waiting already", oldTime)
 		}
+		// same or newer build waiting, give up
+		if cmp >= 0 {
+			log.Logger().Warnf("newer build %s is waiting already", w.Build)
+			return nil, fmt.Errorf("newer build %s is waiting already", w.Build)
+		}
+		// older build of the same branch, skip it and take its place in the queue
+		log.Logger().Infof("build %s is superseded by build %s", w.Build, build.Build)
+		if !found {
+			waiting := build
+			waiting.Timestamp = w.Timestamp
+			next = append(next, waiting)
+			found = true
+		}
+		changed = true
+	}
+	if !found {
+		next = append(next, build)
+		changed = true
+	}
+	if !changed {
+		return nil, nil
 	}
+	return next, nil
 }
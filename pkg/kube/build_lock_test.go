@@ -22,200 +22,118 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
-func Test_compareBuildLocks(t *testing.T) {
+func Test_enqueueBuildLock(t *testing.T) {
 	time1 := time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC).Format(time.RFC3339Nano)
 	time2 := time.Date(2000, 1, 1, 0, 0, 0, 200000000, time.UTC).Format(time.RFC3339Nano)
 	time3 := time.Date(2000, 1, 1, 0, 0, 0, 210000000, time.UTC).Format(time.RFC3339Nano)
+	time4 := time.Date(2000, 1, 1, 0, 0, 1, 0, time.UTC).Format(time.RFC3339Nano)
+	holder := BuildLockWaiter{"my-owner", "my-repository", "my-branch", "99", "build-pod-99", "", time1}
+	other := BuildLockWaiter{"other-owner", "other-repository", "my-branch", "7", "build-pod-7", "", time1}
+	build := BuildLockWaiter{"my-owner", "my-repository", "my-branch", "101", "build-pod-101", "", time4}
 	examples := []struct {
-		name string
-		old  map[string]string
-		new  map[string]string
-		ret  map[string]string
-		err  bool
+		name   string
+		holder BuildLockWaiter
+		queue  []BuildLockWaiter
+		build  BuildLockWaiter
+		ret    []BuildLockWaiter
+		err    bool
 	}{{
-		"same build",
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "build-pod-123",
-			"timestamp":  time2,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "build-pod-123",
-			"timestamp":  time2,
-		},
+		"join an empty queue",
+		other,
 		nil,
+		build,
+		[]BuildLockWaiter{build},
 		false,
 	}, {
-		"same build but different pod (for some reason)",
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "build-pod-123",
-			"timestamp":  time2,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "other-pod-123",
-			"timestamp":  time2,
-		},
+		"already waiting",
+		other,
+		[]BuildLockWaiter{build},
+		build,
+		nil,
+		false,
+	}, {
+		"lower build running",
+		holder,
+		nil,
+		build,
+		[]BuildLockWaiter{build},
+		false,
+	}, {
+		"higher build running",
+		BuildLockWaiter{"my-owner", "my-repository", "my-branch", "103", "build-pod-103", "", time1},
+		nil,
+		build,
 		nil,
 		true,
 	}, {
-		"lower build",
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "101",
-			"pod":        "build-pod-101",
-			"timestamp":  time2,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "99",
-			"pod":        "build-pod-99",
-			"timestamp":  time1,
-		},
+		"same build running in another pod",
+		BuildLockWaiter{"my-owner", "my-repository", "my-branch", "101", "other-pod-101", "", time1},
+		nil,
+		build,
 		nil,
 		true,
 	}, {
-		"higher build",
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "101",
-			"pod":        "build-pod-101",
-			"timestamp":  time1,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "103",
-			"pod":        "build-pod-103",
-			"timestamp":  time2,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "103",
-			"pod":        "build-pod-103",
-			"timestamp":  time2,
-		},
-		false,
+		"higher build waiting",
+		other,
+		[]BuildLockWaiter{{"my-owner", "my-repository", "my-branch", "103", "build-pod-103", "", time2}},
+		build,
+		nil,
+		true,
 	}, {
-		"higher build but lower timestamp",
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "101",
-			"pod":        "build-pod-101",
-			"timestamp":  time3,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "103",
-			"pod":        "build-pod-103",
-			"timestamp":  time2,
+		"lower build waiting is superseded and keeps its arrival",
+		other,
+		[]BuildLockWaiter{
+			{"other-owner", "a-repository", "master", "101", "a-pod-101", "", time1},
+			{"my-owner", "my-repository", "my-branch", "100", "build-pod-100", "", time2},
+			{"other-owner", "b-repository", "master", "101", "b-pod-101", "", time3},
 		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "103",
-			"pod":        "build-pod-103",
-			"timestamp":  time3,
+		build,
+		[]BuildLockWaiter{
+			{"other-owner", "a-repository", "master", "101", "a-pod-101", "", time1},
+			{"my-owner", "my-repository", "my-branch", "101", "build-pod-101", "", time2},
+			{"other-owner", "b-repository", "master", "101", "b-pod-101", "", time3},
 		},
 		false,
 	}, {
-		"other build, same timestamp",
-		map[string]string{
-			"owner":      "other-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "111",
-			"pod":        "build-pod-111",
-			"timestamp":  time2,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "other-pod-123",
-			"timestamp":  time2,
-		},
-		nil,
-		true,
-	}, {
-		"other build, lower timestamp",
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "other-repository",
-			"branch":     "my-branch",
-			"build":      "111",
-			"pod":        "build-pod-111",
-			"timestamp":  time2,
+		"builds of other repositories waiting first",
+		other,
+		[]BuildLockWaiter{
+			{"other-owner", "a-repository", "master", "1", "a-pod-1", "", time1},
+			{"other-owner", "b-repository", "master", "2", "b-pod-2", "", time3},
 		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "other-pod-123",
-			"timestamp":  time1,
+		build,
+		[]BuildLockWaiter{
+			{"other-owner", "a-repository", "master", "1", "a-pod-1", "", time1},
+			{"other-owner", "b-repository", "master", "2", "b-pod-2", "", time3},
+			build,
 		},
-		nil,
-		true,
+		false,
 	}, {
-		"other build, higher timestamp",
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "other-branch",
-			"build":      "111",
-			"pod":        "build-pod-111",
-			"timestamp":  time2,
-		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "other-pod-123",
-			"timestamp":  time3,
+		"higher build of another repository waiting first as it arrived first",
+		other,
+		[]BuildLockWaiter{
+			{"other-owner", "a-repository", "master", "1", "a-pod-1", "", time1},
+			{"other-owner", "b-repository", "master", "200", "b-pod-200", "", time3},
 		},
-		map[string]string{
-			"owner":      "my-owner",
-			"repository": "my-repository",
-			"branch":     "my-branch",
-			"build":      "123",
-			"pod":        "other-pod-123",
-			"timestamp":  time3,
+		build,
+		[]BuildLockWaiter{
+			{"other-owner", "a-repository", "master", "1", "a-pod-1", "", time1},
+			{"other-owner", "b-repository", "master", "200", "b-pod-200", "", time3},
+			build,
 		},
 		false,
 	}}
 	for _, example := range examples {
-		ret, err := compareBuildLocks(example.old, example.new)
+		lock := &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   "jx-lock-my-namespace",
+				Labels: map[string]string{},
+			},
+			Data: map[string]string{},
+		}
+		require.NoError(t, setBuildLockHolder(lock, example.holder), example.name)
+		require.NoError(t, setBuildLockQueue(lock, example.queue), example.name)
+		ret, err := enqueueBuildLock(lock, example.build)
 		assert.Equal(t, example.ret, ret, example.name)
 		if example.err {
 			assert.Error(t, err, example.name)
@@ -225,6 +143,53 @@ func Test_compareBuildLocks(t *testing.T) {
 	}
 }
 
+func Test_buildLockHolder(t *testing.T) {
+	// a lock created by a previous version of jx, where another build is waiting in its data
+	lock := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "jx-lock-my-namespace",
+			Labels: map[string]string{
+				"owner":             "my-owner",
+				"repository":        "my-repository",
+				"branch":            "my-branch",
+				"build":             "11",
+				"jenkins-x.io/kind": "build-lock",
+			},
+			OwnerReferences: []metav1.OwnerReference{{
+				APIVersion: "v1",
+				Kind:       "Pod",
+				Name:       "build-pod-11",
+				UID:        "uid-11",
+			}},
+		},
+		Data: map[string]string{
+			"owner":      "other-owner",
+			"repository": "other-repository",
+			"branch":     "other-branch",
+			"build":      "13",
+			"pod":        "other-pod-13",
+			"timestamp":  buildLock_Timestamp(0),
+		},
+	}
+	holder := buildLockHolder(lock)
+	assert.Equal(t, "my-owner", holder.Owner)
+	assert.Equal(t, "my-repository", holder.Repository)
+	assert.Equal(t, "my-branch", holder.Branch)
+	assert.Equal(t, "11", holder.Build)
+	assert.Equal(t, "build-pod-11", holder.Pod)
+	assert.Equal(t, types.UID("uid-11"), holder.UID)
+	assert.True(t, isBuildLockHolder(lock, holder))
+
+	// handing the lock over stores its holder apart from the data of the previous versions
+	next := BuildLockWaiter{"other-owner", "other-repository", "other-branch", "13", "other-pod-13", "uid-13", buildLock_Timestamp(0)}
+	require.NoError(t, setBuildLockHolder(lock, next))
+	assert.Equal(t, next, buildLockHolder(lock))
+	assert.NotEmpty(t, lock.Data[buildLockHolderKey])
+	lock.Data["build"] = "14"
+	lock.Data["pod"] = "other-pod-14"
+	assert.Equal(t, next, buildLockHolder(lock))
+}
+
 // buildLock_Client creates a fake client with a fake tekton deployment
 func buildLock_Client(t *testing.T) *fake.Clientset {
 	client := fake.NewSimpleClientset()
@@ -524,6 +489,40 @@ func buildLock_AssertLockFromPod(t *testing.T, client kubernetes.Interface, name
 	}
 }
 
+// buildLock_WaitQueue waits until the given number of builds are waiting for the lock
+func buildLock_WaitQueue(t *testing.T, client kubernetes.Interface, name string, count int) *v1.ConfigMap {
+	for i := 0; i < 500; i++ {
+		lock, err := client.CoreV1().ConfigMaps("jx").Get(name, metav1.GetOptions{})
+		require.NoError(t, err)
+		queue, err := buildLockQueue(lock)
+		require.NoError(t, err)
+		if len(queue) == count {
+			return lock
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	require.Fail(t, "timeout", "waiting for %d builds in the queue", count)
+	return nil
+}
+
+// buildLock_AssertWaiting checks if a build is waiting at the given position of the lock's queue
+func buildLock_AssertWaiting(t *testing.T, lock *v1.ConfigMap, position int, owner, repository, branch, build, pod string) {
+	queue, err := buildLockQueue(lock)
+	require.NoError(t, err)
+	require.True(t, len(queue) >= position, "the queue should have at least %d builds", position)
+	waiter := queue[position-1]
+	assert.Equal(t, owner, waiter.Owner)
+	assert.Equal(t, repository, waiter.Repository)
+	assert.Equal(t, branch, waiter.Branch)
+	assert.Equal(t, build, waiter.Build)
+	assert.Equal(t, pod, waiter.Pod)
+	ts, err := time.Parse(time.RFC3339Nano, waiter.Timestamp)
+	if assert.NoError(t, err) {
+		assert.True(t, ts.Before(time.Now().Add(time.Minute)))
+		assert.True(t, ts.After(time.Now().Add(time.Duration(-1)*time.Minute)))
+	}
+}
+
 func TestAcquireBuildLock(t *testing.T) {
 	// just acquire a lock when no lock exists
 	client := buildLock_Client(t)
@@ -646,17 +645,120 @@ func TestAcquireBuildLock_higherRuns(t *testing.T) {
 	callback()
 }
 
-func TestAcquireBuildLock_laterRuns(t *testing.T) {
-	// fails at acquiring the lock because a later build is running
+func TestAcquireBuildLock_waitOtherRepository(t *testing.T) {
+	// wait for a build of another repository, even if it started later
 	client := buildLock_Client(t)
+	counter := buildLock_CountWatch(client)
 	previous := buildLock_Pod(t, client, "other-owner", "other-repository", "other-branch", "42")
-	buildLock_LockFromPod(t, client, "my-namespace", previous, 42)
-
+	old := buildLock_LockFromPod(t, client, "my-namespace", previous, 42)
+	// should join the queue
 	pod := buildLock_Pod(t, client, "my-owner", "my-repository", "my-branch", "13")
-	clean, channel := buildLock_AcquireFromPod(t, client, "my-namespace", pod, true)
+	clean, channel := buildLock_AcquireFromPod(t, client, "my-namespace", pod, false)
 	defer clean()
+	// wait for AcquireBuildLock to be waiting
+	for {
+		count := 0
+		select {
+		case count = <-counter:
+		case callback := <-channel:
+			require.NotNil(t, callback, "timeout")
+			assert.Fail(t, "TestAcquireBuildLock returned")
+			callback()
+			return
+		}
+		if count == 2 {
+			break
+		}
+	}
+	// check the lock
+	lock, err := client.CoreV1().ConfigMaps("jx").Get(old.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, old.ObjectMeta, lock.ObjectMeta)
+	assert.Equal(t, "42", lock.Data["build"])
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "my-repository", "my-branch", "13", pod.Name)
+	// should acquire the lock
+	err = client.CoreV1().Pods("jx").Delete(previous.Name, &metav1.DeleteOptions{})
+	require.NoError(t, err)
 	callback := <-channel
+	require.NotNil(t, callback, "timeout")
+	buildLock_AssertLockFromPod(t, client, "my-namespace", pod)
+	callback()
+	buildLock_AssertNoLock(t, client, "my-namespace")
+}
+
+func TestAcquireBuildLock_queue(t *testing.T) {
+	// builds of different repositories acquire the lock in the order of their arrival, whatever their build numbers
+	client := buildLock_Client(t)
+	previous := buildLock_Pod(t, client, "other-owner", "other-repository", "other-branch", "42")
+	old := buildLock_LockFromPod(t, client, "my-namespace", previous, -42)
+	first := buildLock_Pod(t, client, "my-owner", "first-repository", "my-branch", "13")
+	clean, firstChannel := buildLock_AcquireFromPod(t, client, "my-namespace", first, false)
+	defer clean()
+	buildLock_WaitQueue(t, client, old.Name, 1)
+	second := buildLock_Pod(t, client, "my-owner", "second-repository", "my-branch", "7")
+	clean, secondChannel := buildLock_AcquireFromPod(t, client, "my-namespace", second, false)
+	defer clean()
+	lock := buildLock_WaitQueue(t, client, old.Name, 2)
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "first-repository", "my-branch", "13", first.Name)
+	buildLock_AssertWaiting(t, lock, 2, "my-owner", "second-repository", "my-branch", "7", second.Name)
+	// the first build to arrive should acquire the lock
+	err := client.CoreV1().Pods("jx").Delete(previous.Name, &metav1.DeleteOptions{})
+	require.NoError(t, err)
+	callback := <-firstChannel
+	require.NotNil(t, callback, "timeout")
+	buildLock_AssertLockFromPod(t, client, "my-namespace", first)
+	lock, err = client.CoreV1().ConfigMaps("jx").Get(old.Name, metav1.GetOptions{})
+	require.NoError(t, err)
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "second-repository", "my-branch", "7", second.Name)
+	// releasing the lock hands it over to the next build, even if its build number is lower
+	callback()
+	callback = <-secondChannel
+	require.NotNil(t, callback, "timeout")
+	buildLock_AssertLockFromPod(t, client, "my-namespace", second)
 	callback()
+	buildLock_AssertNoLock(t, client, "my-namespace")
+}
+
+func TestBreakBuildLock(t *testing.T) {
+	client := buildLock_Client(t)
+	previous := buildLock_Pod(t, client, "my-owner", "my-repository", "my-branch", "11")
+	old := buildLock_LockFromPod(t, client, "my-namespace", previous, -11)
+	pod := buildLock_Pod(t, client, "other-owner", "other-repository", "other-branch", "13")
+	err := setBuildLockQueue(old, []BuildLockWaiter{{
+		Owner:      "other-owner",
+		Repository: "other-repository",
+		Branch:     "other-branch",
+		Build:      "13",
+		Pod:        pod.Name,
+		UID:        pod.UID,
+		Timestamp:  buildLock_Timestamp(0),
+	}})
+	require.NoError(t, err)
+	_, err = client.CoreV1().ConfigMaps("jx").Update(old)
+	require.NoError(t, err)
+
+	locks, err := GetBuildLocks(client, "jx")
+	require.NoError(t, err)
+	require.Len(t, locks, 1)
+	assert.Equal(t, "my-namespace", locks[0].Namespace)
+	assert.Equal(t, "11", locks[0].Holder.Build)
+	assert.Equal(t, previous.Name, locks[0].Holder.Pod)
+	require.Len(t, locks[0].Queue, 1)
+	assert.Equal(t, "13", locks[0].Queue[0].Build)
+
+	// breaking the lock hands it over to the waiting build
+	lock, err := BreakBuildLock(client, "jx", "my-namespace")
+	require.NoError(t, err)
+	require.NotNil(t, lock)
+	assert.Equal(t, "13", lock.Holder.Build)
+	assert.Empty(t, lock.Queue)
+	buildLock_AssertLockFromPod(t, client, "my-namespace", pod)
+
+	// breaking the lock without waiting builds removes it
+	lock, err = BreakBuildLock(client, "jx", "my-namespace")
+	require.NoError(t, err)
+	assert.Nil(t, lock)
+	buildLock_AssertNoLock(t, client, "my-namespace")
 }
 
 func TestAcquireBuildLock_waitLowerPodDeleted(t *testing.T) {
@@ -688,13 +790,8 @@ func TestAcquireBuildLock_waitLowerPodDeleted(t *testing.T) {
 	lock, err := client.CoreV1().ConfigMaps("jx").Get(old.Name, metav1.GetOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, old.ObjectMeta, lock.ObjectMeta)
-	assert.Equal(t, "my-namespace", lock.Data["namespace"])
-	assert.Equal(t, "my-owner", lock.Data["owner"])
-	assert.Equal(t, "my-repository", lock.Data["repository"])
-	assert.Equal(t, "my-branch", lock.Data["branch"])
-	assert.Equal(t, "13", lock.Data["build"])
-	assert.Equal(t, pod.Name, lock.Data["pod"])
-	assert.Equal(t, old.Data["timestamp"], lock.Data["timestamp"])
+	assert.Equal(t, old.Data["build"], lock.Data["build"])
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "my-repository", "my-branch", "13", pod.Name)
 	// should acquire the lock
 	err = client.CoreV1().Pods("jx").Delete(previous.Name, &metav1.DeleteOptions{})
 	require.NoError(t, err)
@@ -734,13 +831,8 @@ func TestAcquireBuildLock_waitLowerLockDeleted(t *testing.T) {
 	lock, err := client.CoreV1().ConfigMaps("jx").Get(old.Name, metav1.GetOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, old.ObjectMeta, lock.ObjectMeta)
-	assert.Equal(t, "my-namespace", lock.Data["namespace"])
-	assert.Equal(t, "my-owner", lock.Data["owner"])
-	assert.Equal(t, "my-repository", lock.Data["repository"])
-	assert.Equal(t, "my-branch", lock.Data["branch"])
-	assert.Equal(t, "13", lock.Data["build"])
-	assert.Equal(t, pod.Name, lock.Data["pod"])
-	assert.Equal(t, old.Data["timestamp"], lock.Data["timestamp"])
+	assert.Equal(t, old.Data["build"], lock.Data["build"])
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "my-repository", "my-branch", "13", pod.Name)
 	// should acquire the lock
 	err = client.CoreV1().ConfigMaps("jx").Delete(old.Name, &metav1.DeleteOptions{})
 	require.NoError(t, err)
@@ -779,13 +871,8 @@ func TestAcquireBuildLock_waitEarlierFinished(t *testing.T) {
 	lock, err := client.CoreV1().ConfigMaps("jx").Get(old.Name, metav1.GetOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, old.ObjectMeta, lock.ObjectMeta)
-	assert.Equal(t, "my-namespace", lock.Data["namespace"])
-	assert.Equal(t, "my-owner", lock.Data["owner"])
-	assert.Equal(t, "my-repository", lock.Data["repository"])
-	assert.Equal(t, "my-branch", lock.Data["branch"])
-	assert.Equal(t, "13", lock.Data["build"])
-	assert.Equal(t, "", lock.Data["pod"])
-	assert.Equal(t, old.Data["timestamp"], lock.Data["timestamp"])
+	assert.Equal(t, old.Data["build"], lock.Data["build"])
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "my-repository", "my-branch", "13", "")
 	// should acquire the lock
 	previous.Status.Phase = v1.PodSucceeded
 	_, err = client.CoreV1().Pods("jx").Update(previous)
@@ -825,13 +912,8 @@ func TestAcquireBuildLock_waitLowerExpired(t *testing.T) {
 	lock, err := client.CoreV1().ConfigMaps("jx").Get(old.Name, metav1.GetOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, old.ObjectMeta, lock.ObjectMeta)
-	assert.Equal(t, "my-namespace", lock.Data["namespace"])
-	assert.Equal(t, "my-owner", lock.Data["owner"])
-	assert.Equal(t, "my-repository", lock.Data["repository"])
-	assert.Equal(t, "my-branch", lock.Data["branch"])
-	assert.Equal(t, "13", lock.Data["build"])
-	assert.Equal(t, pod.Name, lock.Data["pod"])
-	assert.Equal(t, old.Data["timestamp"], lock.Data["timestamp"])
+	assert.Equal(t, old.Data["build"], lock.Data["build"])
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "my-repository", "my-branch", "13", pod.Name)
 	// should acquire the lock after 2 seconds
 	callback := <-channel
 	require.NotNil(t, callback, "timeout")
@@ -841,7 +923,7 @@ func TestAcquireBuildLock_waitLowerExpired(t *testing.T) {
 }
 
 func TestAcquireBuildLock_waitButHigher(t *testing.T) {
-	// wait for a lower run to finish, but an higher run appears
+	// wait for a lower run to finish, but an higher run supersedes it
 	client := buildLock_Client(t)
 	counter := buildLock_CountWatch(client)
 	previous := buildLock_Pod(t, client, "my-owner", "my-repository", "my-branch", "11")
@@ -869,18 +951,14 @@ func TestAcquireBuildLock_waitButHigher(t *testing.T) {
 	lock, err := client.CoreV1().ConfigMaps("jx").Get(old.Name, metav1.GetOptions{})
 	require.NoError(t, err)
 	assert.Equal(t, old.ObjectMeta, lock.ObjectMeta)
-	assert.Equal(t, "my-namespace", lock.Data["namespace"])
-	assert.Equal(t, "my-owner", lock.Data["owner"])
-	assert.Equal(t, "my-repository", lock.Data["repository"])
-	assert.Equal(t, "my-branch", lock.Data["branch"])
-	assert.Equal(t, "13", lock.Data["build"])
-	ts, err := time.Parse(time.RFC3339Nano, lock.Data["timestamp"])
-	if assert.NoError(t, err) {
-		assert.True(t, ts.Before(time.Now().Add(time.Minute)))
-		assert.True(t, ts.After(time.Now().Add(time.Duration(-1)*time.Minute)))
-	}
-	// update the lock and expect failure
-	lock.Data["build"] = "21"
+	assert.Equal(t, old.Data["build"], lock.Data["build"])
+	buildLock_AssertWaiting(t, lock, 1, "my-owner", "my-repository", "my-branch", "13", pod.Name)
+	// a higher build supersedes this one in the queue, expect failure
+	queue, err := buildLockQueue(lock)
+	require.NoError(t, err)
+	queue[0].Build = "21"
+	queue[0].Pod = "pipeline-my-owner-my-repository-my-branch-21"
+	require.NoError(t, setBuildLockQueue(lock, queue))
 	_, err = client.CoreV1().ConfigMaps("jx").Update(lock)
 	require.NoError(t, err)
 	callback := <-channel
@@ -167,6 +167,9 @@ const (
 	// ValueKindAddon an addon auth secret/credentials
 	ValueKindAddon = "addon"
 
+	// ValueKindBuildNumber a ConfigMap storing the last build number of a pipeline
+	ValueKindBuildNumber = "build-number"
+
 	// ValueKindChat a chat auth secret/credentials
 	ValueKindChat = "chat"
 
@@ -286,6 +289,8 @@ const (
 	AnnotationGitReportState = "jenkins.io/git-report-state"
 	// AnnotationGitReportRunningStages used to annotate what stages were last reported to git as running
 	AnnotationGitReportRunningStages = "jenkins.io/git-report-running-stages"
+	// AnnotationChatNotifiedStatus used to annotate what status of a PipelineActivity was last notified to the team chat channel
+	AnnotationChatNotifiedStatus = "jenkins.io/chat-notified-status"
 
 	// AnnotationIsDefaultStorageClass used to indicate a storageclass is default
 	AnnotationIsDefaultStorageClass = "storageclass.kubernetes.io/is-default-class"
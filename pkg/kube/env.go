@@ -174,6 +174,10 @@ func CreateEnvironmentSurvey(batchMode bool, authConfigSvc auth.ConfigService, d
 			return nil, err
 		}
 	}
+	// protection is only removed by editing the Environment resource so that it is not lost when editing other settings
+	if config.Spec.Protected {
+		data.Spec.Protected = true
+	}
 	if config.Spec.Cluster != "" {
 		data.Spec.Cluster = config.Spec.Cluster
 	} else {
@@ -1,21 +1,56 @@
 package kube
 
 import (
+	"regexp"
 	"strings"
 
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/secreturl"
+	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
 
-// LogMasker replaces words in a log from a set of secrets
+// LogMaskRule masks the text matching a regular expression or, if the expression has capturing groups, only the
+// text matching the groups so that the name of a secret can remain visible
+type LogMaskRule struct {
+	Name    string
+	Pattern *regexp.Regexp
+}
+
+// DefaultLogMaskRules the rules masking well-known secret formats
+var DefaultLogMaskRules = []LogMaskRule{
+	{
+		Name:    "jwt",
+		Pattern: regexp.MustCompile(`\beyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`),
+	},
+	{
+		Name:    "aws-access-key-id",
+		Pattern: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b`),
+	},
+	{
+		Name:    "aws-secret-access-key",
+		Pattern: regexp.MustCompile(`(?i)aws_?secret_?access_?key["']?\s*[:=]\s*["']?([A-Za-z0-9/+=]{40})`),
+	},
+	{
+		Name:    "github-token",
+		Pattern: regexp.MustCompile(`\b(?:gh[pousr]_[A-Za-z0-9]{36,}|github_pat_[A-Za-z0-9_]{22,})\b`),
+	},
+}
+
+// LogMasker replaces words in a log from a set of secrets and the text matching a set of rules
 type LogMasker struct {
 	ReplaceWords map[string]string
+	Rules        []LogMaskRule
 }
 
-// NewLogMasker creates a new LogMasker loading secrets from the given namespace
+// NewLogMasker creates a new LogMasker loading secrets from the given namespace and masking the well-known secret
+// formats
 func NewLogMasker(kubeClient kubernetes.Interface, ns string) (*LogMasker, error) {
-	masker := &LogMasker{}
+	masker := &LogMasker{
+		Rules: append([]LogMaskRule{}, DefaultLogMaskRules...),
+	}
 	resourceList, err := kubeClient.CoreV1().Secrets(ns).List(metav1.ListOptions{})
 	if err != nil {
 		return masker, err
@@ -26,6 +61,30 @@ func NewLogMasker(kubeClient kubernetes.Interface, ns string) (*LogMasker, error
 	return masker, nil
 }
 
+// NewTeamLogMasker creates a new LogMasker loading secrets from the given namespace, masking the well-known secret
+// formats and applying the patterns and secrets of the log masking settings of the team. The secrets are read using
+// the secret URL client which can be nil if the settings have no secrets
+func NewTeamLogMasker(kubeClient kubernetes.Interface, ns string, settings *v1.LogMaskingSettings, secretURLClient secreturl.Client) (*LogMasker, error) {
+	masker, err := NewLogMasker(kubeClient, ns)
+	if err != nil {
+		return masker, errors.Wrapf(err, "loading the secrets in namespace %s", ns)
+	}
+	if settings == nil {
+		return masker, nil
+	}
+	err = masker.AddPatterns(settings.Patterns...)
+	if err != nil {
+		return masker, err
+	}
+	if len(settings.Secrets) > 0 {
+		if secretURLClient == nil {
+			return masker, errors.New("no secret URL client to read the secrets to mask")
+		}
+		err = masker.LoadSecretURLSecrets(secretURLClient, settings.Secrets...)
+	}
+	return masker, err
+}
+
 // NewLogMaskerFromMap creates a new LogMasker with all the string values in a tree of map
 func NewLogMaskerFromMap(m map[string]interface{}) *LogMasker {
 	masker := &LogMasker{
@@ -47,6 +106,37 @@ func (m *LogMasker) LoadSecrets(kubeClient kubernetes.Interface, ns string) erro
 	return nil
 }
 
+// LoadSecretURLSecrets loads the values of the named secrets read by the secret URL client, such as from Vault or the
+// local file system, into the log masker
+func (m *LogMasker) LoadSecretURLSecrets(client secreturl.Client, secretNames ...string) error {
+	if m.ReplaceWords == nil {
+		m.ReplaceWords = map[string]string{}
+	}
+	for _, name := range secretNames {
+		values, err := client.Read(name)
+		if err != nil {
+			return errors.Wrapf(err, "reading secret %s", name)
+		}
+		m.replaceMapValues(values)
+	}
+	return nil
+}
+
+// AddPatterns adds rules masking the text matching the regular expressions
+func (m *LogMasker) AddPatterns(patterns ...string) error {
+	for _, pattern := range patterns {
+		r, err := regexp.Compile(pattern)
+		if err != nil {
+			return errors.Wrapf(err, "parsing log mask pattern %s", pattern)
+		}
+		m.Rules = append(m.Rules, LogMaskRule{
+			Name:    pattern,
+			Pattern: r,
+		})
+	}
+	return nil
+}
+
 // LoadSecret loads the secret data into the log masker
 func (m *LogMasker) LoadSecret(secret *corev1.Secret) {
 	if m.ReplaceWords == nil {
@@ -71,6 +161,9 @@ func (m *LogMasker) MaskLog(text string) string {
 	for k, v := range m.ReplaceWords {
 		answer = strings.Replace(answer, k, v, -1)
 	}
+	for _, rule := range m.Rules {
+		answer = m.maskRule(answer, rule)
+	}
 	return answer
 }
 
@@ -89,7 +182,7 @@ func (m *LogMasker) replaceMapValues(values map[string]interface{}) {
 			continue
 		}
 		text, ok := value.(string)
-		if ok {
+		if ok && text != "" {
 			m.ReplaceWords[text] = m.replaceValue(text)
 		}
 	}
@@ -98,3 +191,36 @@ func (m *LogMasker) replaceMapValues(values map[string]interface{}) {
 func (m *LogMasker) replaceValue(value string) string {
 	return strings.Repeat("*", len(value))
 }
+
+// maskRule masks the text matching the rule, or only its capturing groups if it has any
+func (m *LogMasker) maskRule(text string, rule LogMaskRule) string {
+	if rule.Pattern == nil {
+		return text
+	}
+	matches := rule.Pattern.FindAllStringSubmatchIndex(text, -1)
+	if len(matches) == 0 {
+		return text
+	}
+	var buffer strings.Builder
+	last := 0
+	for _, match := range matches {
+		groups := [][]int{match[:2]}
+		if len(match) > 2 {
+			groups = nil
+			for i := 2; i+1 < len(match); i += 2 {
+				groups = append(groups, match[i:i+2])
+			}
+		}
+		for _, group := range groups {
+			start, end := group[0], group[1]
+			if start < last || end <= start {
+				continue
+			}
+			buffer.WriteString(text[last:start])
+			buffer.WriteString(m.replaceValue(text[start:end]))
+			last = end
+		}
+	}
+	buffer.WriteString(text[last:])
+	return buffer.String()
+}
@@ -10,6 +10,9 @@ import (
 	uuid "github.com/satori/go.uuid"
 )
 
+// the maximum length of a label value
+const maxLabelValueLength = 63
+
 // ToValidImageName converts the given string into a valid docker image name
 func ToValidImageName(name string) string {
 	return strings.ToLower(name)
@@ -134,6 +137,16 @@ func ToValidValue(name string) string {
 	return answer
 }
 
+// ToValidLabelValue converts a value such as a branch or pipeline name, which can contain slashes or be too long,
+// into a valid label value
+func ToValidLabelValue(value string) string {
+	answer := ToValidValue(strings.Replace(value, "/", "-", -1))
+	if len(answer) > maxLabelValueLength {
+		answer = answer[:maxLabelValueLength]
+	}
+	return strings.Trim(answer, "-.")
+}
+
 //EmailToK8sID converts the provided email address to a valid Kubernetes resource name, converting the @ to a .
 func EmailToK8sID(email string) string {
 	return ToValidNameWithDots(strings.Replace(email, "@", ".", -1))
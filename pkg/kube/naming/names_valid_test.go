@@ -3,10 +3,12 @@
 package naming_test
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/jenkins-x/jx/v2/pkg/kube/naming"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/util/validation"
 )
 
 func TestToValidName(t *testing.T) {
@@ -37,6 +39,23 @@ func TestToValidValue(t *testing.T) {
 	assertToValidValue(t, "", "")
 }
 
+func TestToValidLabelValue(t *testing.T) {
+	t.Parallel()
+	values := map[string]string{
+		"master":                "master",
+		"feature/cve-gate":      "feature-cve-gate",
+		"release/1.x/":          "release-1.x",
+		".hidden":               "hidden",
+		strings.Repeat("a", 70): strings.Repeat("a", 63),
+		"users/jdoe/fix_bug":    "users-jdoe-fix-bug",
+	}
+	for value, expected := range values {
+		actual := naming.ToValidLabelValue(value)
+		assert.Equal(t, expected, actual, value)
+		assert.Empty(t, validation.IsValidLabelValue(actual), value)
+	}
+}
+
 func TestToValidNameWithDots(t *testing.T) {
 	t.Parallel()
 	assertToValidNameWithDots(t, "foo-bar-0.1.0", "foo-bar-0.1.0")
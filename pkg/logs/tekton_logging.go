@@ -28,6 +28,7 @@ import (
 	"github.com/jenkins-x/jx/v2/pkg/kube"
 	"github.com/jenkins-x/jx/v2/pkg/log"
 	"github.com/jenkins-x/jx/v2/pkg/tekton"
+	"github.com/jenkins-x/jx/v2/pkg/tekton/syntax"
 	"github.com/jenkins-x/jx/v2/pkg/util"
 	"github.com/pkg/errors"
 	tektonapis "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
@@ -51,6 +52,10 @@ type TektonLogger struct {
 	errorsChannel     chan error
 	wg                *sync.WaitGroup
 	FailIfPodFails    bool
+	// Stage if not empty only the logs of the stage with this name are written
+	Stage string
+	// LogMasker if not nil masks the secrets in the logs of the pods and the logs read from long term storage
+	LogMasker *kube.LogMasker
 }
 
 // LogWriter is an interface that can be implemented to define different ways to stream / write logs
@@ -224,6 +229,7 @@ func getPipelineRunsForActivity(pa *v1.PipelineActivity, tektonClient tektonclie
 func (t TektonLogger) GetRunningBuildLogs(pa *v1.PipelineActivity, buildName string, noWaitForRuns bool) error {
 	loggedAllRunsForActivity := false
 	foundLogs := false
+	loggedStage := false
 
 	// Make sure we check again for the build pipeline if we just get the metapipeline initially, assuming the metapipeline succeeds
 	for !loggedAllRunsForActivity {
@@ -290,6 +296,10 @@ func (t TektonLogger) GetRunningBuildLogs(pa *v1.PipelineActivity, buildName str
 						strings.ToLower(params.Branch) == strings.ToLower(pa.Spec.GitBranch) && params.Build == pa.Spec.Build {
 						stagesSeen[stageName] = true
 						foundLogs = true
+						if t.Stage != "" && stageName != syntax.MangleToRfc1035Label(t.Stage, "") {
+							continue
+						}
+						loggedStage = true
 						err := t.getContainerLogsFromPod(pod, pa, buildName, stageName)
 						if err != nil {
 							return errors.Wrapf(err, "failed to obtain the logs for build %s and stage %s", buildName, stageName)
@@ -313,6 +323,9 @@ func (t TektonLogger) GetRunningBuildLogs(pa *v1.PipelineActivity, buildName str
 	if !foundLogs {
 		return errors.New("the build pods for this build have been garbage collected and the log was not found in the long term storage bucket")
 	}
+	if t.Stage != "" && !loggedStage {
+		return errors.Errorf("no stage %s was found in build %s", t.Stage, buildName)
+	}
 
 	return nil
 }
@@ -377,14 +390,14 @@ func (t *TektonLogger) fetchLogsToChannel(ns string, pod *corev1.Pod, container
 		return err
 	}
 	defer cleanFN()
-	err = writeStreamLines(reader, t.logsChannel)
+	err = t.writeStreamLines(reader)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
-func writeStreamLines(reader io.Reader, logCh chan<- LogLine) error {
+func (t *TektonLogger) writeStreamLines(reader io.Reader) error {
 	buffReader := bufio.NewReader(reader)
 	if buffReader == nil {
 		return errors.New("there was a problem obtaining a buffered reader")
@@ -397,10 +410,18 @@ func writeStreamLines(reader io.Reader, logCh chan<- LogLine) error {
 			}
 			return errors.Wrap(err, "failed to read stream")
 		}
-		logCh <- LogLine{Line: string(line), ShouldMask: true}
+		t.logsChannel <- t.maskLine(LogLine{Line: string(line), ShouldMask: true})
 	}
 }
 
+// maskLine masks the secrets in the line if there is a LogMasker
+func (t *TektonLogger) maskLine(line LogLine) LogLine {
+	if t.LogMasker != nil {
+		line.Line = t.LogMasker.MaskLog(line.Line)
+	}
+	return line
+}
+
 func hasStepFailed(pod *corev1.Pod, stepNumber int, kubeClient kubernetes.Interface, ns string) bool {
 	pod, err := kubeClient.CoreV1().Pods(ns).Get(pod.Name, metav1.GetOptions{})
 	if err != nil {
@@ -466,10 +487,10 @@ func (t *TektonLogger) StreamPipelinePersistentLogs(logsURL string, jxClient ver
 			return t.streamPipedLogs(scanner, logsURL)
 		}
 		return t.streamPipedLogs(scanner, logsURL)
-	case "s3":
+	case "s3", "azblob":
 		scanner, err := performProviderDownload(logsURL, jxClient, ns)
 		if err != nil {
-			return errors.Wrap(err, "there was a problem downloading logs from s3 bucket")
+			return errors.Wrapf(err, "there was a problem downloading logs from %s bucket", u.Scheme)
 		}
 		return t.streamPipedLogs(scanner, logsURL)
 	case "http", "https":
@@ -488,17 +509,17 @@ func (t *TektonLogger) StreamPipelinePersistentLogs(logsURL string, jxClient ver
 			Line: "The build pods for this build have been garbage collected and we couldn't find the any stored log file",
 		})
 	}
-	return t.writeBlockingLine(LogLine{
+	return t.writeBlockingLine(t.maskLine(LogLine{
 		Line: string(logBytes),
-	})
+	}))
 }
 
 func (t *TektonLogger) streamPipedLogs(scanner *bufio.Scanner, logsURL string) error {
 	for scanner.Scan() {
 		text := scanner.Text()
-		err := t.LogWriter.WriteLog(LogLine{
+		err := t.LogWriter.WriteLog(t.maskLine(LogLine{
 			Line: text,
-		}, t.logsChannel)
+		}), t.logsChannel)
 
 		if err != nil {
 			return errors.Wrapf(err, "there was a problem streaming the log file from the GKE bucket %s", logsURL)
@@ -584,7 +605,7 @@ func downloadLogFile(logsURL string, authSvc auth.ConfigService) ([]byte, error)
 func performProviderDownload(logsURL string, jxClient versioned.Interface, ns string) (*bufio.Scanner, error) {
 	provider, err := NewBucketProviderFromTeamSettingsConfiguration(jxClient, ns)
 	if err != nil {
-		return nil, errors.Wrap(err, "There was a problem obtaining a Bucket provider for bucket scheme gs://")
+		return nil, errors.Wrapf(err, "There was a problem obtaining a Bucket provider for bucket %s", logsURL)
 	}
 	return provider.DownloadFileFromBucket(logsURL)
 }
@@ -0,0 +1,56 @@
+package pipline_events
+
+import (
+	"net/http"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+)
+
+// DefaultCloudEventsSource is the source of CloudEvents if the team settings don't specify one
+const DefaultCloudEventsSource = "https://jenkins-x.io"
+
+// CloudEventsProvider sends events as CloudEvents 1.0 over HTTP in binary mode, so the attributes of the event are
+// sent as ce- headers and the resource as the JSON body
+type CloudEventsProvider struct {
+	Client   *http.Client
+	URL      string
+	Source   string
+	UserAuth *auth.UserAuth
+}
+
+// NewCloudEventsProvider creates a provider sending CloudEvents from the given source to the URL
+func NewCloudEventsProvider(u string, source string, userAuth *auth.UserAuth) PipelineEventsProvider {
+	if source == "" {
+		source = DefaultCloudEventsSource
+	}
+	return &CloudEventsProvider{
+		Client:   util.GetClient(),
+		URL:      u,
+		Source:   source,
+		UserAuth: userAuth,
+	}
+}
+
+// SendActivity sends the PipelineActivity as a CloudEvent
+func (p *CloudEventsProvider) SendActivity(a *v1.PipelineActivity) error {
+	return p.send(NewActivityEvent(a))
+}
+
+// SendRelease sends the Release as a CloudEvent
+func (p *CloudEventsProvider) SendRelease(r *v1.Release) error {
+	return p.send(NewReleaseEvent(r))
+}
+
+func (p *CloudEventsProvider) send(event *Event) error {
+	headers := map[string]string{
+		"ce-specversion": "1.0",
+		"ce-id":          event.ID,
+		"ce-source":      p.Source,
+		"ce-type":        event.Type,
+		"ce-subject":     event.Subject,
+		"ce-time":        event.Time.Format("2006-01-02T15:04:05.999999999Z07:00"),
+	}
+	return postJSON(p.Client, p.URL, "application/json", headers, p.UserAuth, event.Data)
+}
@@ -2,15 +2,12 @@ package pipline_events
 
 import (
 	"encoding/json"
-	"io/ioutil"
 	"net/http"
 
 	"github.com/pkg/errors"
 
 	"fmt"
 
-	"bytes"
-
 	"strings"
 
 	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
@@ -125,23 +122,12 @@ func (e ElasticsearchProvider) post(index, indexID string, body []byte, rs resul
 
 	url := fmt.Sprintf("%s/%s/event/%s", e.BaseURL, index, indexID)
 
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(body))
-	req.Header.Add("Authorization", "Basic "+e.BasicAuth)
-	req.Header.Set("Content-Type", "application/json")
-
-	resp, err := e.Client.Do(req)
-	if err != nil {
-		return fmt.Errorf("error POSTing to elasticsearch %v", err)
-	}
-
-	if resp.StatusCode != 200 && resp.StatusCode != 201 {
-		return fmt.Errorf("error response POSTing to elasticsearch: %s", resp.Status)
+	headers := map[string]string{
+		"Authorization": "Basic " + e.BasicAuth,
 	}
-
-	data, err := ioutil.ReadAll(resp.Body)
-	err = json.Unmarshal(data, &rs)
+	err := util.DoJSON(e.Client, http.MethodPost, url, headers, json.RawMessage(body), rs)
 	if err != nil {
-		return fmt.Errorf("error unmarshalling %v", err)
+		return errors.Wrap(err, "error POSTing to elasticsearch")
 	}
 	return nil
 }
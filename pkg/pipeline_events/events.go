@@ -0,0 +1,81 @@
+package pipline_events
+
+import (
+	"net/http"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// EventTypeActivity is the type of the events sent when a PipelineActivity changes
+	EventTypeActivity = "io.jenkins-x.pipelineactivity.changed"
+	// EventTypeRelease is the type of the events sent when a Release changes
+	EventTypeRelease = "io.jenkins-x.release.changed"
+)
+
+// Event is a change to a PipelineActivity or Release as sent to the event sinks
+type Event struct {
+	// ID is unique to the version of the resource so sinks can ignore events they have already received
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	Subject   string      `json:"subject"`
+	Namespace string      `json:"namespace,omitempty"`
+	Time      time.Time   `json:"time"`
+	Data      interface{} `json:"data"`
+}
+
+// NewActivityEvent creates the event for the current version of the PipelineActivity
+func NewActivityEvent(a *v1.PipelineActivity) *Event {
+	return newEvent(EventTypeActivity, &a.ObjectMeta, a)
+}
+
+// NewReleaseEvent creates the event for the current version of the Release
+func NewReleaseEvent(r *v1.Release) *Event {
+	return newEvent(EventTypeRelease, &r.ObjectMeta, r)
+}
+
+func newEvent(eventType string, meta *metav1.ObjectMeta, data interface{}) *Event {
+	id := string(meta.UID)
+	if id == "" {
+		id = meta.Namespace + "-" + meta.Name
+	}
+	if meta.ResourceVersion != "" {
+		id += "-" + meta.ResourceVersion
+	}
+	return &Event{
+		ID:        id,
+		Type:      eventType,
+		Subject:   meta.Name,
+		Namespace: meta.Namespace,
+		Time:      time.Now().UTC(),
+		Data:      data,
+	}
+}
+
+// postJSON posts the body with the given content type and headers, failing on any response other than a 2xx with an
+// *util.HTTPStatusError
+func postJSON(client *http.Client, u string, contentType string, headers map[string]string, userAuth *auth.UserAuth, body interface{}) error {
+	requestHeaders := map[string]string{
+		"Content-Type": contentType,
+	}
+	for k, v := range headers {
+		requestHeaders[k] = v
+	}
+	if userAuth != nil {
+		if userAuth.ApiToken != "" {
+			requestHeaders["Authorization"] = "Bearer " + userAuth.ApiToken
+		} else if userAuth.Username != "" && userAuth.Password != "" {
+			requestHeaders["Authorization"] = "Basic " + util.BasicAuth(userAuth.Username, userAuth.Password)
+		}
+	}
+	err := util.DoJSON(client, http.MethodPost, u, requestHeaders, body, nil)
+	if err != nil {
+		return errors.Wrapf(err, "posting to %s", u)
+	}
+	return nil
+}
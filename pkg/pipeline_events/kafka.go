@@ -0,0 +1,66 @@
+package pipline_events
+
+import (
+	"net/http"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+const kafkaContentType = "application/vnd.kafka.json.v2+json"
+
+// KafkaProvider produces events to a Kafka topic via the v2 API of a Kafka REST proxy, such as the Confluent REST
+// Proxy or the Redpanda HTTP proxy. Records are keyed by the name of the resource so the events of a resource stay in
+// order.
+type KafkaProvider struct {
+	Client   *http.Client
+	URL      string
+	Topic    string
+	UserAuth *auth.UserAuth
+}
+
+type kafkaRecords struct {
+	Records []kafkaRecord `json:"records"`
+}
+
+type kafkaRecord struct {
+	Key   string `json:"key,omitempty"`
+	Value *Event `json:"value"`
+}
+
+// NewKafkaProvider creates a provider producing events to the topic via the REST proxy at the URL
+func NewKafkaProvider(u string, topic string, userAuth *auth.UserAuth) (PipelineEventsProvider, error) {
+	if topic == "" {
+		return nil, errors.Errorf("no Kafka topic is configured for the REST proxy %s", u)
+	}
+	return &KafkaProvider{
+		Client:   util.GetClient(),
+		URL:      u,
+		Topic:    topic,
+		UserAuth: userAuth,
+	}, nil
+}
+
+// SendActivity produces the event for the PipelineActivity
+func (p *KafkaProvider) SendActivity(a *v1.PipelineActivity) error {
+	return p.send(NewActivityEvent(a))
+}
+
+// SendRelease produces the event for the Release
+func (p *KafkaProvider) SendRelease(r *v1.Release) error {
+	return p.send(NewReleaseEvent(r))
+}
+
+func (p *KafkaProvider) send(event *Event) error {
+	records := &kafkaRecords{
+		Records: []kafkaRecord{
+			{
+				Key:   event.Namespace + "/" + event.Subject,
+				Value: event,
+			},
+		},
+	}
+	return postJSON(p.Client, util.UrlJoin(p.URL, "topics", p.Topic), kafkaContentType, nil, p.UserAuth, records)
+}
@@ -1,8 +1,65 @@
 package pipline_events
 
-import v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+import (
+	"fmt"
 
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+)
+
+const (
+	// Elasticsearch indexes events in Elasticsearch
+	Elasticsearch = "elasticsearch"
+	// CloudEvents sends events as CloudEvents over HTTP
+	CloudEvents = "cloudevents"
+	// Webhook posts events as JSON to a webhook
+	Webhook = "webhook"
+	// Kafka produces events to a topic via a Kafka REST proxy
+	Kafka = "kafka"
+)
+
+// PipelineEventsProviderKinds are the kinds of pipeline events provider
+var PipelineEventsProviderKinds = []string{Elasticsearch, CloudEvents, Webhook, Kafka}
+
+// PipelineEventsProvider sends changes to PipelineActivities and Releases to a sink such as Elasticsearch
 type PipelineEventsProvider interface {
 	SendActivity(a *v1.PipelineActivity) error
 	SendRelease(a *v1.Release) error
 }
+
+// CreatePipelineEventsProvider creates the provider for the team settings, retrying events which fail and dead
+// lettering them to a webhook if one is configured
+func CreatePipelineEventsProvider(settings *v1.PipelineEventsSettings, userAuth *auth.UserAuth, deadLetterAuth *auth.UserAuth) (PipelineEventsProvider, error) {
+	if settings == nil || settings.URL == "" {
+		return nil, fmt.Errorf("no pipeline events URL is configured")
+	}
+	var provider PipelineEventsProvider
+	var err error
+	switch settings.Kind {
+	case Elasticsearch:
+		if userAuth == nil {
+			userAuth = &auth.UserAuth{}
+		}
+		provider, err = NewElasticsearchProvider(&auth.AuthServer{URL: settings.URL, Kind: settings.Kind}, userAuth)
+	case CloudEvents:
+		provider = NewCloudEventsProvider(settings.URL, settings.Source, userAuth)
+	case Webhook:
+		provider = NewWebhookProvider(settings.URL, userAuth)
+	case Kafka:
+		provider, err = NewKafkaProvider(settings.URL, settings.Topic, userAuth)
+	default:
+		return nil, fmt.Errorf("unsupported pipeline events provider kind %q, supported kinds are %v", settings.Kind, PipelineEventsProviderKinds)
+	}
+	if err != nil {
+		return nil, err
+	}
+	maxRetries := uint64(DefaultMaxRetries)
+	if settings.MaxRetries > 0 {
+		maxRetries = uint64(settings.MaxRetries)
+	}
+	var deadLetter DeadLetterSink
+	if settings.DeadLetterURL != "" {
+		deadLetter = NewWebhookProvider(settings.DeadLetterURL, deadLetterAuth)
+	}
+	return NewRetryingProvider(provider, maxRetries, deadLetter), nil
+}
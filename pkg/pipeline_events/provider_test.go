@@ -0,0 +1,219 @@
+// +build unit
+
+package pipline_events_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	pipelineevents "github.com/jenkins-x/jx/v2/pkg/pipeline_events"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type request struct {
+	path   string
+	header http.Header
+	body   map[string]interface{}
+}
+
+type fakeSink struct {
+	sync.Mutex
+	requests []request
+	failures int
+	status   int
+}
+
+func (f *fakeSink) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.Lock()
+	defer f.Unlock()
+	data, _ := ioutil.ReadAll(r.Body)
+	body := map[string]interface{}{}
+	_ = json.Unmarshal(data, &body)
+	f.requests = append(f.requests, request{path: r.URL.Path, header: r.Header, body: body})
+	if f.failures > 0 {
+		f.failures--
+		status := f.status
+		if status == 0 {
+			status = http.StatusServiceUnavailable
+		}
+		w.WriteHeader(status)
+		return
+	}
+	w.WriteHeader(http.StatusAccepted)
+}
+
+func activity() *v1.PipelineActivity {
+	return &v1.PipelineActivity{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "jx-demo-master-1",
+			Namespace:       "jx",
+			UID:             "1234",
+			ResourceVersion: "7",
+		},
+		Spec: v1.PipelineActivitySpec{
+			Pipeline: "jx/demo/master",
+			Status:   v1.ActivityStatusTypeSucceeded,
+		},
+	}
+}
+
+func TestCloudEventsProvider(t *testing.T) {
+	sink := &fakeSink{}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	provider, err := pipelineevents.CreatePipelineEventsProvider(&v1.PipelineEventsSettings{
+		Kind:   pipelineevents.CloudEvents,
+		URL:    server.URL,
+		Source: "https://jx.acme.com",
+	}, &auth.UserAuth{ApiToken: "secret"}, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SendActivity(activity()))
+	require.Len(t, sink.requests, 1)
+	r := sink.requests[0]
+	assert.Equal(t, "1.0", r.header.Get("ce-specversion"))
+	assert.Equal(t, "1234-7", r.header.Get("ce-id"))
+	assert.Equal(t, "https://jx.acme.com", r.header.Get("ce-source"))
+	assert.Equal(t, pipelineevents.EventTypeActivity, r.header.Get("ce-type"))
+	assert.Equal(t, "jx-demo-master-1", r.header.Get("ce-subject"))
+	assert.Equal(t, "Bearer secret", r.header.Get("Authorization"))
+	assert.Equal(t, "jx/demo/master", r.body["spec"].(map[string]interface{})["pipeline"])
+}
+
+func TestWebhookProvider(t *testing.T) {
+	sink := &fakeSink{}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	provider, err := pipelineevents.CreatePipelineEventsProvider(&v1.PipelineEventsSettings{
+		Kind: pipelineevents.Webhook,
+		URL:  server.URL + "/events",
+	}, &auth.UserAuth{Username: "jx", Password: "secret"}, nil)
+	require.NoError(t, err)
+
+	release := &v1.Release{
+		ObjectMeta: metav1.ObjectMeta{Name: "demo-1.0.1", Namespace: "jx-staging"},
+		Spec:       v1.ReleaseSpec{Version: "1.0.1"},
+	}
+	require.NoError(t, provider.SendRelease(release))
+	require.Len(t, sink.requests, 1)
+	r := sink.requests[0]
+	assert.Equal(t, "/events", r.path)
+	username, password, ok := (&http.Request{Header: r.header}).BasicAuth()
+	require.True(t, ok)
+	assert.Equal(t, "jx", username)
+	assert.Equal(t, "secret", password)
+	assert.Equal(t, pipelineevents.EventTypeRelease, r.body["type"])
+	assert.Equal(t, "demo-1.0.1", r.body["subject"])
+	assert.Equal(t, "jx-staging", r.body["namespace"])
+	assert.Equal(t, "1.0.1", r.body["data"].(map[string]interface{})["spec"].(map[string]interface{})["version"])
+}
+
+func TestKafkaProvider(t *testing.T) {
+	sink := &fakeSink{}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+
+	_, err := pipelineevents.CreatePipelineEventsProvider(&v1.PipelineEventsSettings{
+		Kind: pipelineevents.Kafka,
+		URL:  server.URL,
+	}, nil, nil)
+	assert.Error(t, err, "a topic should be required")
+
+	provider, err := pipelineevents.CreatePipelineEventsProvider(&v1.PipelineEventsSettings{
+		Kind:  pipelineevents.Kafka,
+		URL:   server.URL,
+		Topic: "pipelines",
+	}, nil, nil)
+	require.NoError(t, err)
+
+	require.NoError(t, provider.SendActivity(activity()))
+	require.Len(t, sink.requests, 1)
+	r := sink.requests[0]
+	assert.Equal(t, "/topics/pipelines", r.path)
+	assert.Equal(t, "application/vnd.kafka.json.v2+json", r.header.Get("Content-Type"))
+	records := r.body["records"].([]interface{})
+	require.Len(t, records, 1)
+	record := records[0].(map[string]interface{})
+	assert.Equal(t, "jx/jx-demo-master-1", record["key"])
+	assert.Equal(t, "1234-7", record["value"].(map[string]interface{})["id"])
+}
+
+func TestRetryAndDeadLetter(t *testing.T) {
+	sink := &fakeSink{failures: 2}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+	deadLetters := &fakeSink{}
+	deadLetterServer := httptest.NewServer(deadLetters)
+	defer deadLetterServer.Close()
+
+	provider := pipelineevents.NewRetryingProvider(pipelineevents.NewWebhookProvider(server.URL, nil), 2,
+		pipelineevents.NewWebhookProvider(deadLetterServer.URL, nil))
+	provider.InitialInterval = 0
+
+	require.NoError(t, provider.SendActivity(activity()))
+	assert.Len(t, sink.requests, 3, "the event should be sent after two retries")
+	assert.Len(t, deadLetters.requests, 0)
+
+	sink.failures = 3
+	require.NoError(t, provider.SendActivity(activity()))
+	assert.Len(t, sink.requests, 6)
+	require.Len(t, deadLetters.requests, 1, "the event should be dead lettered once the retries are exhausted")
+	deadLetter := deadLetters.requests[0].body
+	assert.Equal(t, "1234-7", deadLetter["event"].(map[string]interface{})["id"])
+	assert.Contains(t, deadLetter["error"], "status 503")
+
+	provider.DeadLetter = nil
+	sink.failures = 3
+	assert.Error(t, provider.SendActivity(activity()), "without a dead letter sink the error should be returned")
+}
+
+func TestRetryOnlyTransientFailures(t *testing.T) {
+	sink := &fakeSink{failures: 2, status: http.StatusTooManyRequests}
+	server := httptest.NewServer(sink)
+	defer server.Close()
+	deadLetters := &fakeSink{}
+	deadLetterServer := httptest.NewServer(deadLetters)
+	defer deadLetterServer.Close()
+
+	provider := pipelineevents.NewRetryingProvider(pipelineevents.NewWebhookProvider(server.URL, nil), 5,
+		pipelineevents.NewWebhookProvider(deadLetterServer.URL, nil))
+	provider.InitialInterval = 0
+
+	require.NoError(t, provider.SendActivity(activity()))
+	assert.Len(t, sink.requests, 3, "rate limited events should be retried")
+
+	sink.failures = 1
+	sink.status = http.StatusBadRequest
+	require.NoError(t, provider.SendActivity(activity()))
+	assert.Len(t, sink.requests, 4, "events rejected by the sink should not be retried")
+	require.Len(t, deadLetters.requests, 1)
+	assert.Contains(t, deadLetters.requests[0].body["error"], "status 400")
+}
+
+func TestIsRetryable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Close()
+	err := pipelineevents.NewWebhookProvider(server.URL, nil).SendActivity(activity())
+	require.Error(t, err)
+	assert.True(t, pipelineevents.IsRetryable(err), "network errors should be retried: %s", err)
+
+	assert.True(t, pipelineevents.IsRetryable(&util.HTTPStatusError{StatusCode: http.StatusBadGateway}))
+	assert.True(t, pipelineevents.IsRetryable(&util.HTTPStatusError{StatusCode: http.StatusTooManyRequests}))
+	assert.False(t, pipelineevents.IsRetryable(&util.HTTPStatusError{StatusCode: http.StatusUnauthorized}))
+}
+
+func TestUnsupportedProviderKind(t *testing.T) {
+	_, err := pipelineevents.CreatePipelineEventsProvider(&v1.PipelineEventsSettings{Kind: "carrier-pigeon", URL: "https://example.com"}, nil, nil)
+	assert.Error(t, err)
+}
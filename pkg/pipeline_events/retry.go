@@ -0,0 +1,94 @@
+package pipline_events
+
+import (
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/cenkalti/backoff"
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+// DefaultMaxRetries is how many times sending an event is retried if the team settings don't specify it
+const DefaultMaxRetries = 5
+
+// DeadLetterSink receives the events which could not be sent
+type DeadLetterSink interface {
+	DeadLetter(event *Event, cause error) error
+}
+
+// RetryingProvider retries sending events with an exponential backoff, passing those which still fail to the dead
+// letter sink, if there is one
+type RetryingProvider struct {
+	Provider        PipelineEventsProvider
+	MaxRetries      uint64
+	InitialInterval time.Duration
+	DeadLetter      DeadLetterSink
+}
+
+// NewRetryingProvider wraps the provider so sending is retried up to the given number of times
+func NewRetryingProvider(provider PipelineEventsProvider, maxRetries uint64, deadLetter DeadLetterSink) *RetryingProvider {
+	return &RetryingProvider{
+		Provider:        provider,
+		MaxRetries:      maxRetries,
+		InitialInterval: backoff.DefaultInitialInterval,
+		DeadLetter:      deadLetter,
+	}
+}
+
+// SendActivity sends the PipelineActivity, retrying on failure
+func (p *RetryingProvider) SendActivity(a *v1.PipelineActivity) error {
+	return p.send(func() *Event { return NewActivityEvent(a) }, func() error {
+		return p.Provider.SendActivity(a)
+	})
+}
+
+// SendRelease sends the Release, retrying on failure
+func (p *RetryingProvider) SendRelease(r *v1.Release) error {
+	return p.send(func() *Event { return NewReleaseEvent(r) }, func() error {
+		return p.Provider.SendRelease(r)
+	})
+}
+
+func (p *RetryingProvider) send(event func() *Event, f func() error) error {
+	bo := backoff.NewExponentialBackOff()
+	bo.InitialInterval = p.InitialInterval
+	// the number of retries limits how long we keep trying
+	bo.MaxElapsedTime = 0
+	bo.Reset()
+	err := backoff.Retry(func() error {
+		err := f()
+		if err != nil && !IsRetryable(err) {
+			return backoff.Permanent(err)
+		}
+		return err
+	}, backoff.WithMaxRetries(bo, p.MaxRetries))
+	if err == nil {
+		return nil
+	}
+	if p.DeadLetter == nil {
+		return err
+	}
+	e := event()
+	log.Logger().Warnf("Dead lettering event %s: %s", e.ID, err)
+	dlErr := p.DeadLetter.DeadLetter(e, err)
+	if dlErr != nil {
+		return errors.Wrapf(dlErr, "dead lettering event %s which failed with: %s", e.ID, err)
+	}
+	return nil
+}
+
+// IsRetryable returns true if sending an event failed with a server error, because the sink is rate limiting or
+// because of a network error, rather than because the sink rejected the event
+func IsRetryable(err error) bool {
+	switch cause := errors.Cause(err).(type) {
+	case *util.HTTPStatusError:
+		return cause.StatusCode >= http.StatusInternalServerError || cause.StatusCode == http.StatusTooManyRequests
+	case net.Error:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package pipline_events
+
+import (
+	"net/http"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/auth"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+)
+
+// WebhookProvider posts events as JSON to a generic webhook
+type WebhookProvider struct {
+	Client   *http.Client
+	URL      string
+	UserAuth *auth.UserAuth
+}
+
+// DeadLetter is what a WebhookProvider posts for an event which could not be sent
+type DeadLetter struct {
+	Event *Event `json:"event"`
+	Error string `json:"error"`
+}
+
+// NewWebhookProvider creates a provider posting events to the URL
+func NewWebhookProvider(u string, userAuth *auth.UserAuth) *WebhookProvider {
+	return &WebhookProvider{
+		Client:   util.GetClient(),
+		URL:      u,
+		UserAuth: userAuth,
+	}
+}
+
+// SendActivity posts the event for the PipelineActivity
+func (p *WebhookProvider) SendActivity(a *v1.PipelineActivity) error {
+	return p.send(NewActivityEvent(a))
+}
+
+// SendRelease posts the event for the Release
+func (p *WebhookProvider) SendRelease(r *v1.Release) error {
+	return p.send(NewReleaseEvent(r))
+}
+
+// DeadLetter posts the event which could not be sent along with why
+func (p *WebhookProvider) DeadLetter(event *Event, cause error) error {
+	return postJSON(p.Client, p.URL, "application/json", nil, p.UserAuth, &DeadLetter{
+		Event: event,
+		Error: cause.Error(),
+	})
+}
+
+func (p *WebhookProvider) send(event *Event) error {
+	return postJSON(p.Client, p.URL, "application/json", nil, p.UserAuth, event)
+}
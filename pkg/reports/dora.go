@@ -0,0 +1,179 @@
+package reports
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+)
+
+// DORAMetrics are the four DORA metrics of an application in an environment over a time window
+type DORAMetrics struct {
+	Application string `json:"application"`
+	Environment string `json:"environment"`
+	// Deployments is the number of successful and failed promotions
+	Deployments       int `json:"deployments"`
+	FailedDeployments int `json:"failedDeployments"`
+	// DeploymentFrequency is the number of successful deployments per day
+	DeploymentFrequency float64 `json:"deploymentFrequency"`
+	// LeadTime is the median time from a change being committed to it being deployed
+	LeadTime        time.Duration `json:"-"`
+	LeadTimeSeconds float64       `json:"leadTimeSeconds"`
+	// ChangeFailureRate is the fraction of deployments which failed
+	ChangeFailureRate float64 `json:"changeFailureRate"`
+	// TimeToRestore is the median time from a deployment failing to the next successful deployment
+	TimeToRestore        time.Duration `json:"-"`
+	TimeToRestoreSeconds float64       `json:"timeToRestoreSeconds"`
+
+	leadTimes      []time.Duration
+	restoreTimes   []time.Duration
+	deploymentList []deployment
+}
+
+type deployment struct {
+	version   string
+	time      time.Time
+	succeeded bool
+}
+
+// CalculateDORAMetrics calculates the DORA metrics for each application and environment from the promotions of the
+// pipeline activities completed in the time window, using the commits of the releases for the lead time
+func CalculateDORAMetrics(activities []v1.PipelineActivity, releases []v1.Release, from time.Time, to time.Time) []*DORAMetrics {
+	commitTimes := map[string][]time.Time{}
+	for _, r := range releases {
+		key := releaseKey(r.Spec.Name, r.Spec.Version)
+		if _, ok := commitTimes[key]; ok {
+			// the same release is copied into each environment
+			continue
+		}
+		var times []time.Time
+		for _, c := range r.Spec.Commits {
+			if c.Timestamp != nil {
+				times = append(times, c.Timestamp.Time)
+			}
+		}
+		commitTimes[key] = times
+	}
+
+	metricsByKey := map[string]*DORAMetrics{}
+	for _, a := range activities {
+		app := activityApplication(&a)
+		if app == "" {
+			continue
+		}
+		for _, step := range a.Spec.Steps {
+			promote := step.Promote
+			if promote == nil || promote.Environment == "" || promote.CompletedTimestamp == nil {
+				continue
+			}
+			t := promote.CompletedTimestamp.Time
+			if t.Before(from) || t.After(to) {
+				continue
+			}
+			var succeeded bool
+			switch promote.Status {
+			case v1.ActivityStatusTypeSucceeded:
+				succeeded = true
+			case v1.ActivityStatusTypeFailed, v1.ActivityStatusTypeError:
+				succeeded = false
+			default:
+				continue
+			}
+			key := app + "/" + promote.Environment
+			m := metricsByKey[key]
+			if m == nil {
+				m = &DORAMetrics{Application: app, Environment: promote.Environment}
+				metricsByKey[key] = m
+			}
+			m.deploymentList = append(m.deploymentList, deployment{version: a.Spec.Version, time: t, succeeded: succeeded})
+			if succeeded {
+				for _, ct := range commitTimes[releaseKey(app, a.Spec.Version)] {
+					if ct.Before(t) {
+						m.leadTimes = append(m.leadTimes, t.Sub(ct))
+					}
+				}
+			}
+		}
+	}
+
+	days := to.Sub(from).Hours() / 24
+	var answer []*DORAMetrics
+	for _, m := range metricsByKey {
+		m.calculate(days)
+		answer = append(answer, m)
+	}
+	sort.Slice(answer, func(i, j int) bool {
+		if answer[i].Application != answer[j].Application {
+			return answer[i].Application < answer[j].Application
+		}
+		return answer[i].Environment < answer[j].Environment
+	})
+	return answer
+}
+
+func (m *DORAMetrics) calculate(days float64) {
+	sort.Slice(m.deploymentList, func(i, j int) bool {
+		return m.deploymentList[i].time.Before(m.deploymentList[j].time)
+	})
+	var failedAt *time.Time
+	succeeded := 0
+	for i := range m.deploymentList {
+		d := &m.deploymentList[i]
+		if !d.succeeded {
+			m.FailedDeployments++
+			if failedAt == nil {
+				failedAt = &d.time
+			}
+			continue
+		}
+		succeeded++
+		if failedAt != nil {
+			m.restoreTimes = append(m.restoreTimes, d.time.Sub(*failedAt))
+			failedAt = nil
+		}
+	}
+	m.Deployments = len(m.deploymentList)
+	if days > 0 {
+		m.DeploymentFrequency = float64(succeeded) / days
+	}
+	if m.Deployments > 0 {
+		m.ChangeFailureRate = float64(m.FailedDeployments) / float64(m.Deployments)
+	}
+	m.LeadTime = median(m.leadTimes)
+	m.LeadTimeSeconds = m.LeadTime.Seconds()
+	m.TimeToRestore = median(m.restoreTimes)
+	m.TimeToRestoreSeconds = m.TimeToRestore.Seconds()
+}
+
+// activityApplication returns the name of the application an activity builds
+func activityApplication(a *v1.PipelineActivity) string {
+	if a.Spec.GitRepository != "" {
+		return a.Spec.GitRepository
+	}
+	// the pipeline is of the form owner/repository/branch
+	parts := strings.Split(a.Spec.Pipeline, "/")
+	if len(parts) >= 2 {
+		return parts[len(parts)-2]
+	}
+	return ""
+}
+
+func releaseKey(app string, version string) string {
+	return app + "@" + strings.TrimPrefix(version, "v")
+}
+
+func median(durations []time.Duration) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+	sorted := append([]time.Duration{}, durations...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i] < sorted[j]
+	})
+	n := len(sorted)
+	if n%2 == 1 {
+		return sorted[n/2]
+	}
+	return (sorted[n/2-1] + sorted[n/2]) / 2
+}
@@ -0,0 +1,118 @@
+// +build unit
+
+package reports_test
+
+import (
+	"testing"
+	"time"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/reports"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func promotion(env string, status v1.ActivityStatusType, completed time.Time) v1.PipelineActivityStep {
+	t := metav1.NewTime(completed)
+	return v1.PipelineActivityStep{
+		Kind: v1.ActivityStepKindTypePromote,
+		Promote: &v1.PromoteActivityStep{
+			CoreActivityStep: v1.CoreActivityStep{
+				Status:             status,
+				CompletedTimestamp: &t,
+			},
+			Environment: env,
+		},
+	}
+}
+
+func release(app string, version string, commits ...time.Time) v1.Release {
+	r := v1.Release{Spec: v1.ReleaseSpec{Name: app, Version: version}}
+	for _, c := range commits {
+		t := metav1.NewTime(c)
+		r.Spec.Commits = append(r.Spec.Commits, v1.CommitSummary{Timestamp: &t})
+	}
+	return r
+}
+
+func TestCalculateDORAMetrics(t *testing.T) {
+	t.Parallel()
+	to := time.Date(2020, time.March, 31, 0, 0, 0, 0, time.UTC)
+	from := to.Add(-10 * 24 * time.Hour)
+	day := func(d int, hour int) time.Time {
+		return from.Add(time.Duration(d)*24*time.Hour + time.Duration(hour)*time.Hour)
+	}
+
+	activities := []v1.PipelineActivity{
+		{
+			Spec: v1.PipelineActivitySpec{
+				Pipeline: "acme/api/master",
+				Version:  "1.0.0",
+				Steps: []v1.PipelineActivityStep{
+					promotion("staging", v1.ActivityStatusTypeSucceeded, day(1, 0)),
+					promotion("production", v1.ActivityStatusTypeSucceeded, day(1, 4)),
+				},
+			},
+		},
+		{
+			Spec: v1.PipelineActivitySpec{
+				GitRepository: "api",
+				Version:       "1.0.1",
+				Steps: []v1.PipelineActivityStep{
+					promotion("staging", v1.ActivityStatusTypeFailed, day(3, 0)),
+				},
+			},
+		},
+		{
+			Spec: v1.PipelineActivitySpec{
+				GitRepository: "api",
+				Version:       "1.0.2",
+				Steps: []v1.PipelineActivityStep{
+					promotion("staging", v1.ActivityStatusTypeSucceeded, day(3, 6)),
+					promotion("production", v1.ActivityStatusTypeRunning, day(3, 8)),
+				},
+			},
+		},
+		{
+			Spec: v1.PipelineActivitySpec{
+				GitRepository: "web",
+				Version:       "2.0.0",
+				Steps: []v1.PipelineActivityStep{
+					// before the time window
+					promotion("staging", v1.ActivityStatusTypeSucceeded, from.Add(-time.Hour)),
+				},
+			},
+		},
+	}
+	releases := []v1.Release{
+		release("api", "v1.0.0", day(0, 0), day(0, 20)),
+		// the copy of the release in the staging namespace
+		release("api", "1.0.0"),
+		release("api", "1.0.2", day(3, 4)),
+	}
+
+	metrics := reports.CalculateDORAMetrics(activities, releases, from, to)
+	require.Len(t, metrics, 2)
+
+	production := metrics[0]
+	assert.Equal(t, "api", production.Application)
+	assert.Equal(t, "production", production.Environment)
+	assert.Equal(t, 1, production.Deployments)
+	assert.Equal(t, 0.1, production.DeploymentFrequency)
+	assert.Equal(t, 0.0, production.ChangeFailureRate)
+	// the median of 28 and 8 hours
+	assert.Equal(t, 18*time.Hour, production.LeadTime)
+	assert.Equal(t, float64(18*60*60), production.LeadTimeSeconds)
+	assert.Equal(t, time.Duration(0), production.TimeToRestore)
+
+	staging := metrics[1]
+	assert.Equal(t, "staging", staging.Environment)
+	assert.Equal(t, 3, staging.Deployments)
+	assert.Equal(t, 1, staging.FailedDeployments)
+	assert.Equal(t, 0.2, staging.DeploymentFrequency)
+	assert.InDelta(t, 1.0/3, staging.ChangeFailureRate, 0.0001)
+	// the median of 24, 4 and 2 hours
+	assert.Equal(t, 4*time.Hour, staging.LeadTime)
+	assert.Equal(t, 6*time.Hour, staging.TimeToRestore)
+}
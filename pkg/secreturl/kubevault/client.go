@@ -0,0 +1,185 @@
+package kubevault
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/jenkins-x/jx/v2/pkg/kube"
+	"github.com/jenkins-x/jx/v2/pkg/kube/naming"
+	"github.com/jenkins-x/jx/v2/pkg/secreturl"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+var kubernetesURIRegex = regexp.MustCompile(`:[\s"]*kubernetes:[-_.\w\/:]*`)
+
+const (
+	// AnnotationSecretPath the annotation storing the secret URL path of a Secret written by the client
+	AnnotationSecretPath = "jenkins.io/secret-path"
+
+	// AnnotationJSONKeys the annotation listing the keys of a Secret whose values are JSON encoded
+	AnnotationJSONKeys = "jenkins.io/secret-json-keys"
+
+	// ValueKindSecretURL the kind label value of the Secrets written by the client
+	ValueKindSecretURL = "secret-url"
+)
+
+// Client a Kubernetes Secret based client loading/saving secrets in a namespace. Secrets are stored in a Secret named
+// after the path of the secret so that Secrets populated by tools such as External Secrets or Sealed Secrets can
+// be read too
+type Client struct {
+	kubeClient kubernetes.Interface
+	namespace  string
+}
+
+// NewClient create a new Kubernetes Secret based client loading/saving secrets in the given namespace
+func NewClient(kubeClient kubernetes.Interface, namespace string) secreturl.Client {
+	return &Client{
+		kubeClient: kubeClient,
+		namespace:  namespace,
+	}
+}
+
+// SecretName returns the name of the Kubernetes Secret used to store the secret with the given path
+func SecretName(secretName string) string {
+	return naming.ToValidNameTruncated(secretName, 253)
+}
+
+// Read reads a named secret from the Kubernetes Secret
+func (c *Client) Read(secretName string) (map[string]interface{}, error) {
+	name := SecretName(secretName)
+	secret, err := c.kubeClient.CoreV1().Secrets(c.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Secret %s in namespace %s", name, c.namespace)
+	}
+	path := secret.Annotations[AnnotationSecretPath]
+	if path != "" && path != secretName {
+		return nil, fmt.Errorf("the Secret %s in namespace %s stores the secret %s not %s", name, c.namespace, path, secretName)
+	}
+	jsonKeys := map[string]bool{}
+	for _, key := range strings.Split(secret.Annotations[AnnotationJSONKeys], ",") {
+		if key != "" {
+			jsonKeys[key] = true
+		}
+	}
+	answer := map[string]interface{}{}
+	for key, value := range secret.Data {
+		if !jsonKeys[key] {
+			answer[key] = string(value)
+			continue
+		}
+		var v interface{}
+		err = json.Unmarshal(value, &v)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to parse the value of key %s of Secret %s", key, name)
+		}
+		answer[key] = v
+	}
+	return answer, nil
+}
+
+// ReadObject reads a generic named object from the Kubernetes Secret.
+// The secret _must_ be serializable to JSON.
+func (c *Client) ReadObject(secretName string, secret interface{}) error {
+	m, err := c.Read(secretName)
+	if err != nil {
+		return errors.Wrapf(err, "reading the secret %q from Kubernetes", secretName)
+	}
+	err = util.ToStructFromMapStringInterface(m, &secret)
+	if err != nil {
+		return errors.Wrapf(err, "deserializing the secret %q from Kubernetes", secretName)
+	}
+	return nil
+}
+
+// Write writes a named secret to a Kubernetes Secret with the data provided, creating the Secret if it does not exist.
+// String values are stored as they are, any other values are stored as JSON. Existing Secrets which were not written by
+// the client for the same secret path are never overwritten
+func (c *Client) Write(secretName string, data map[string]interface{}) (map[string]interface{}, error) {
+	name := SecretName(secretName)
+	secretData := map[string][]byte{}
+	jsonKeys := []string{}
+	for key, value := range data {
+		if s, ok := value.(string); ok {
+			secretData[key] = []byte(s)
+			continue
+		}
+		j, err := json.Marshal(util.ConvertAllMapKeysToString(value))
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to marshal the value of key %s of secret %s", key, secretName)
+		}
+		secretData[key] = j
+		jsonKeys = append(jsonKeys, key)
+	}
+	sort.Strings(jsonKeys)
+
+	secrets := c.kubeClient.CoreV1().Secrets(c.namespace)
+	secret, err := secrets.Get(name, metav1.GetOptions{})
+	create := false
+	if err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get Secret %s in namespace %s", name, c.namespace)
+		}
+		create = true
+		secret = &corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: c.namespace,
+			},
+			Type: corev1.SecretTypeOpaque,
+		}
+	} else if path := secret.Annotations[AnnotationSecretPath]; path != secretName {
+		if path == "" {
+			return nil, fmt.Errorf("refusing to overwrite the Secret %s in namespace %s as it was not written by jx", name, c.namespace)
+		}
+		return nil, fmt.Errorf("the Secret %s in namespace %s stores the secret %s not %s", name, c.namespace, path, secretName)
+	}
+	if secret.Labels == nil {
+		secret.Labels = map[string]string{}
+	}
+	secret.Labels[kube.LabelKind] = ValueKindSecretURL
+	secret.Labels[kube.LabelCreatedBy] = kube.ValueCreatedByJX
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	secret.Annotations[AnnotationSecretPath] = secretName
+	if len(jsonKeys) > 0 {
+		secret.Annotations[AnnotationJSONKeys] = strings.Join(jsonKeys, ",")
+	} else {
+		delete(secret.Annotations, AnnotationJSONKeys)
+	}
+	secret.Data = secretData
+	secret.StringData = nil
+
+	if create {
+		_, err = secrets.Create(secret)
+	} else {
+		_, err = secrets.Update(secret)
+	}
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to save Secret %s in namespace %s", name, c.namespace)
+	}
+	return c.Read(secretName)
+}
+
+// WriteObject writes a generic named object to a Kubernetes Secret.
+// The secret _must_ be serializable to JSON.
+func (c *Client) WriteObject(secretName string, secret interface{}) (map[string]interface{}, error) {
+	m, err := util.ToMapStringInterfaceFromStruct(&secret)
+	if err != nil {
+		return nil, errors.Wrapf(err, "serializing the secret %q", secretName)
+	}
+	return c.Write(secretName, m)
+}
+
+// ReplaceURIs will replace any kubernetes: URIs in a string
+func (c *Client) ReplaceURIs(s string) (string, error) {
+	return secreturl.ReplaceURIs(s, c, kubernetesURIRegex, "kubernetes:")
+}
@@ -0,0 +1,114 @@
+// +build unit
+
+package kubevault_test
+
+import (
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/secreturl/kubevault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const ns = "jx"
+
+func TestKubeClientWriteAndRead(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	client := kubevault.NewClient(kubeClient, ns)
+
+	data := map[string]interface{}{
+		"password": "s3cr3t",
+		"config": map[string]interface{}{
+			"enabled": true,
+		},
+	}
+	_, err := client.Write("cluster/admin", data)
+	require.NoError(t, err)
+
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get("cluster-admin", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "s3cr3t", string(secret.Data["password"]))
+	assert.Equal(t, "cluster/admin", secret.Annotations[kubevault.AnnotationSecretPath])
+	assert.Equal(t, "config", secret.Annotations[kubevault.AnnotationJSONKeys])
+
+	result, err := client.Read("cluster/admin")
+	require.NoError(t, err)
+	assert.Equal(t, data, result)
+
+	_, err = client.Write("cluster/admin", map[string]interface{}{"password": "changed"})
+	require.NoError(t, err)
+	result, err = client.Read("cluster/admin")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"password": "changed"}, result)
+
+	s, err := client.ReplaceURIs("password: kubernetes:cluster/admin:password")
+	require.NoError(t, err)
+	assert.Equal(t, "password: changed", s)
+}
+
+func TestKubeClientReadExternalSecret(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "github",
+			Namespace: ns,
+		},
+		Data: map[string][]byte{
+			"token": []byte("abc"),
+		},
+	})
+	client := kubevault.NewClient(kubeClient, ns)
+
+	token := struct {
+		Token string `json:"token"`
+	}{}
+	err := client.ReadObject("github", &token)
+	require.NoError(t, err)
+	assert.Equal(t, "abc", token.Token)
+
+	_, err = client.Read("missing")
+	assert.Error(t, err)
+}
+
+func TestKubeClientReadPathMismatch(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset()
+	client := kubevault.NewClient(kubeClient, ns)
+
+	_, err := client.Write("cluster/admin", map[string]interface{}{"password": "s3cr3t"})
+	require.NoError(t, err)
+
+	_, err = client.Read("cluster.admin")
+	assert.Error(t, err)
+}
+
+func TestKubeClientWriteDoesNotOverwriteExternalSecret(t *testing.T) {
+	kubeClient := fake.NewSimpleClientset(&corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "github",
+			Namespace: ns,
+		},
+		Data: map[string][]byte{
+			"token": []byte("abc"),
+		},
+	})
+	client := kubevault.NewClient(kubeClient, ns)
+
+	_, err := client.Write("github", map[string]interface{}{"token": "changed"})
+	assert.Error(t, err)
+
+	secret, err := kubeClient.CoreV1().Secrets(ns).Get("github", metav1.GetOptions{})
+	require.NoError(t, err)
+	assert.Equal(t, "abc", string(secret.Data["token"]))
+	assert.Empty(t, secret.Annotations[kubevault.AnnotationSecretPath])
+
+	_, err = client.Write("cluster/admin", map[string]interface{}{"password": "s3cr3t"})
+	require.NoError(t, err)
+	_, err = client.Write("cluster.admin", map[string]interface{}{"password": "changed"})
+	assert.Error(t, err)
+
+	result, err := client.Read("cluster/admin")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"password": "s3cr3t"}, result)
+}
@@ -0,0 +1,129 @@
+package sopsvault
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+
+	"github.com/ghodss/yaml"
+	"github.com/jenkins-x/jx/v2/pkg/helm"
+	"github.com/jenkins-x/jx/v2/pkg/secreturl"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/pkg/errors"
+)
+
+var sopsURIRegex = regexp.MustCompile(`:[\s"]*sops:[-_.\w\/:]*`)
+
+// FileSystemClient a client loading/saving secrets in SOPS encrypted YAML files in a directory. The encryption keys
+// are configured in the usual SOPS way via a .sops.yaml file or the SOPS_* environment variables
+type FileSystemClient struct {
+	Dir    string
+	runner util.Commander
+}
+
+// NewFileSystemClient create a new client loading/saving secrets in SOPS encrypted files in the given directory
+func NewFileSystemClient(dir string) secreturl.Client {
+	return NewFileSystemClientWithCommander(dir, &util.Command{})
+}
+
+// NewFileSystemClientWithCommander create a new client loading/saving secrets in SOPS encrypted files in the given
+// directory using the given command runner to invoke sops
+func NewFileSystemClientWithCommander(dir string, runner util.Commander) secreturl.Client {
+	return &FileSystemClient{
+		Dir:    dir,
+		runner: runner,
+	}
+}
+
+// Read reads a named secret by decrypting its file
+func (c *FileSystemClient) Read(secretName string) (map[string]interface{}, error) {
+	name := c.fileName(secretName)
+	exists, err := util.FileExists(name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to check if file exists %s", name)
+	}
+	if !exists {
+		return nil, fmt.Errorf("sops secret file does not exist: %s", name)
+	}
+	out, err := c.sops("--decrypt", "--output-type", "yaml", name)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decrypt %s", name)
+	}
+	answer := map[string]interface{}{}
+	err = yaml.Unmarshal([]byte(out), &answer)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse the decrypted secret %s", name)
+	}
+	return answer, nil
+}
+
+// ReadObject reads a generic named object from the encrypted file.
+// The secret _must_ be serializable to JSON.
+func (c *FileSystemClient) ReadObject(secretName string, secret interface{}) error {
+	m, err := c.Read(secretName)
+	if err != nil {
+		return errors.Wrapf(err, "reading the secret %q from sops", secretName)
+	}
+	err = util.ToStructFromMapStringInterface(m, &secret)
+	if err != nil {
+		return errors.Wrapf(err, "deserializing the secret %q from sops", secretName)
+	}
+	return nil
+}
+
+// Write writes a named secret to an encrypted file with the data provided. Data can be a generic map of stuff, but
+// at all points in the map, keys _must_ be strings (not bool, int or even interface{}) otherwise you'll get an error
+func (c *FileSystemClient) Write(secretName string, data map[string]interface{}) (map[string]interface{}, error) {
+	err := c.save(secretName, data)
+	if err != nil {
+		return nil, err
+	}
+	return c.Read(secretName)
+}
+
+// WriteObject writes a generic named object to an encrypted file.
+// The secret _must_ be serializable to JSON.
+func (c *FileSystemClient) WriteObject(secretName string, secret interface{}) (map[string]interface{}, error) {
+	err := c.save(secretName, secret)
+	if err != nil {
+		return nil, err
+	}
+	return c.Read(secretName)
+}
+
+// ReplaceURIs will replace any sops: URIs in a string
+func (c *FileSystemClient) ReplaceURIs(s string) (string, error) {
+	return secreturl.ReplaceURIs(s, c, sopsURIRegex, "sops:")
+}
+
+// save saves the secret to its file and encrypts it in place, removing the file if it cannot be encrypted so that
+// no plain text secrets are left behind
+func (c *FileSystemClient) save(secretName string, secret interface{}) error {
+	path := c.fileName(secretName)
+	dir, _ := filepath.Split(path)
+	err := os.MkdirAll(dir, util.DefaultWritePermissions)
+	if err != nil {
+		return errors.Wrapf(err, "failed to ensure that parent directory exists %s", dir)
+	}
+	err = helm.SaveFile(path, secret)
+	if err != nil {
+		return err
+	}
+	_, err = c.sops("--encrypt", "--in-place", path)
+	if err != nil {
+		os.Remove(path) //nolint:errcheck
+		return errors.Wrapf(err, "failed to encrypt %s", path)
+	}
+	return nil
+}
+
+func (c *FileSystemClient) sops(args ...string) (string, error) {
+	c.runner.SetName("sops")
+	c.runner.SetArgs(args)
+	return c.runner.RunWithoutRetry()
+}
+
+func (c *FileSystemClient) fileName(secretName string) string {
+	return filepath.Join(c.Dir, secretName+".yaml")
+}
@@ -0,0 +1,59 @@
+// +build unit
+
+package sopsvault_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jenkins-x/jx/v2/pkg/secreturl/sopsvault"
+	mocks "github.com/jenkins-x/jx/v2/pkg/util/mocks"
+	. "github.com/petergtz/pegomock"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSopsClientWriteAndRead(t *testing.T) {
+	RegisterMockTestingT(t)
+	dir, err := ioutil.TempDir("", "test-sops-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn("", nil).ThenReturn("password: s3cr3t\n", nil)
+	client := sopsvault.NewFileSystemClientWithCommander(dir, runner)
+
+	result, err := client.Write("cluster/admin", map[string]interface{}{"password": "s3cr3t"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"password": "s3cr3t"}, result)
+
+	fileName := filepath.Join(dir, "cluster", "admin.yaml")
+	args := runner.VerifyWasCalled(Times(2)).SetArgs(AnyStringSlice()).GetAllCapturedArguments()
+	assert.Equal(t, []string{"--encrypt", "--in-place", fileName}, args[0])
+	assert.Equal(t, []string{"--decrypt", "--output-type", "yaml", fileName}, args[1])
+
+	s, err := client.ReplaceURIs("password: sops:cluster/admin:password")
+	require.NoError(t, err)
+	assert.Equal(t, "password: s3cr3t", s)
+}
+
+func TestSopsClientRemovesFileIfEncryptionFails(t *testing.T) {
+	RegisterMockTestingT(t)
+	dir, err := ioutil.TempDir("", "test-sops-")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+
+	runner := mocks.NewMockCommander()
+	When(runner.RunWithoutRetry()).ThenReturn("no matching creation rules found", errors.New("exit status 1"))
+	client := sopsvault.NewFileSystemClientWithCommander(dir, runner)
+
+	_, err = client.Write("github", map[string]interface{}{"token": "abc"})
+	require.Error(t, err)
+	assert.NoFileExists(t, filepath.Join(dir, "github.yaml"))
+
+	_, err = client.Read("github")
+	assert.Error(t, err)
+}
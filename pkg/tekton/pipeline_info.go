@@ -64,6 +64,9 @@ type StageInfo struct {
 
 	// This field will be non-empty if this is a nested stage, containing a list of  the names of all its parent stages with the top-level parent first
 	Parents []string
+
+	// Skipped is true if the stage was not run because its when conditions were not met
+	Skipped bool
 }
 
 // GetStageNameIncludingParents constructs a full stage name including its parents, if they exist.
@@ -460,7 +463,7 @@ func (si *StageInfo) findTaskStageInfo() *StageInfo {
 
 // GetFullChildStageNames gets the fully qualified (i.e., with parents appended) names of each stage underneath this one.
 func (si *StageInfo) GetFullChildStageNames(includeSelf bool) []string {
-	if si.Task != "" && includeSelf {
+	if (si.Task != "" || si.Skipped) && includeSelf {
 		return []string{si.GetStageNameIncludingParents()}
 	}
 
@@ -479,6 +482,7 @@ func stageAndChildrenToStageInfo(psc *v1.PipelineStageAndChildren, parents []str
 	si := &StageInfo{
 		Name:    psc.Stage.Name,
 		Parents: parents,
+		Skipped: psc.Stage.Skipped,
 	}
 	if psc.Stage.TaskRef != nil {
 		si.Task = *psc.Stage.TaskRef
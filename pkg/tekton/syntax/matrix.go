@@ -0,0 +1,203 @@
+package syntax
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var matrixAxisNameRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// Matrix expands a stage into parallel stages, one for each combination of the values of its axes. Each of those
+// stages has an environment variable for each axis set to its value in that combination.
+type Matrix struct {
+	Axes []MatrixAxis `json:"axes"`
+	// Exclude removes the combinations matching all of the axis values in any of the maps
+	Exclude []map[string]string `json:"exclude,omitempty"`
+}
+
+// MatrixAxis is a variable of a matrix, exposed as an environment variable, and the values it takes
+type MatrixAxis struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+}
+
+// Cells returns the combinations of the axis values which are not excluded, in the order of the axes and their values
+func (m *Matrix) Cells() [][]corev1.EnvVar {
+	cells := [][]corev1.EnvVar{{}}
+	for _, axis := range m.Axes {
+		var expanded [][]corev1.EnvVar
+		for _, cell := range cells {
+			for _, v := range axis.Values {
+				next := append(append([]corev1.EnvVar{}, cell...), corev1.EnvVar{Name: axis.Name, Value: v})
+				expanded = append(expanded, next)
+			}
+		}
+		cells = expanded
+	}
+
+	var answer [][]corev1.EnvVar
+	for _, cell := range cells {
+		if !m.isExcluded(cell) {
+			answer = append(answer, cell)
+		}
+	}
+	return answer
+}
+
+func (m *Matrix) isExcluded(cell []corev1.EnvVar) bool {
+	for _, exclude := range m.Exclude {
+		matches := len(exclude) > 0
+		for _, e := range cell {
+			if v, ok := exclude[e.Name]; ok && v != e.Value {
+				matches = false
+			}
+		}
+		if matches {
+			return true
+		}
+	}
+	return false
+}
+
+// matrixCellName returns the name of the stage for a combination of axis values
+func matrixCellName(stageName string, cell []corev1.EnvVar) string {
+	var values []string
+	for _, e := range cell {
+		values = append(values, e.Value)
+	}
+	return fmt.Sprintf("%s %s", stageName, strings.Join(values, " "))
+}
+
+// expandMatrix replaces a stage with a matrix by a parallel stage with the same name containing a stage for each
+// combination of the axis values. The steps, post blocks and unstash of the stage are run by each of those stages,
+// while its other configuration is inherited by them. Any ${AXIS} references in the image of the stage's agent are
+// replaced with the value of the axis for each combination.
+func (s Stage) expandMatrix() Stage {
+	if s.Matrix == nil {
+		return s
+	}
+	parent := Stage{
+		Name:        s.Name,
+		Agent:       s.Agent,
+		Env:         s.Env,
+		WorkingDir:  s.WorkingDir,
+		Environment: s.Environment,
+	}
+	var unstash *Unstash
+	if s.Options != nil {
+		options := *s.Options
+		unstash = options.Unstash
+		options.Unstash = nil
+		parent.Options = &options
+	}
+
+	for _, cell := range s.Matrix.Cells() {
+		child := Stage{
+			Name:  matrixCellName(s.Name, cell),
+			Env:   cell,
+			Steps: s.Steps,
+			Post:  s.Post,
+		}
+		if unstash != nil {
+			child.Options = &StageOptions{
+				RootOptions: &RootOptions{},
+				Unstash:     unstash,
+			}
+		}
+		if s.Agent != nil && strings.Contains(s.Agent.Image, "${") {
+			agent := s.Agent.DeepCopy()
+			for _, e := range cell {
+				agent.Image = strings.ReplaceAll(agent.Image, "${"+e.Name+"}", e.Value)
+			}
+			child.Agent = agent
+		}
+		parent.Parallel = append(parent.Parallel, child)
+	}
+	return parent
+}
+
+func validateMatrix(s Stage) *apis.FieldError {
+	m := s.Matrix
+	if len(s.Steps) == 0 {
+		return &apis.FieldError{
+			Message: "matrix can only be used on stages with steps",
+			Paths:   []string{"matrix"},
+		}
+	}
+	if s.Options != nil && s.Options.Stash != nil {
+		return (&apis.FieldError{
+			Message: "stash cannot be used on a stage with a matrix as every combination would stash with the same name",
+			Paths:   []string{"stash"},
+		}).ViaField("options")
+	}
+	if len(m.Axes) == 0 {
+		return apis.ErrMissingField("axes").ViaField("matrix")
+	}
+
+	axisValues := make(map[string][]string)
+	for i, axis := range m.Axes {
+		if axis.Name == "" {
+			return apis.ErrMissingField("name").ViaFieldIndex("axes", i).ViaField("matrix")
+		}
+		if !matrixAxisNameRegex.MatchString(axis.Name) {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("%s is not a valid matrix axis name", axis.Name),
+				Details: "Axis names are used as environment variable names so must only contain letters, digits and underscores, and not start with a digit",
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("axes", i).ViaField("matrix")
+		}
+		if _, exists := axisValues[axis.Name]; exists {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("matrix axis %s is defined more than once", axis.Name),
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("axes", i).ViaField("matrix")
+		}
+		if len(axis.Values) == 0 {
+			return apis.ErrMissingField("values").ViaFieldIndex("axes", i).ViaField("matrix")
+		}
+		axisValues[axis.Name] = axis.Values
+	}
+
+	for i, exclude := range m.Exclude {
+		var names []string
+		for name := range exclude {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			value := exclude[name]
+			values, exists := axisValues[name]
+			if !exists {
+				return (&apis.FieldError{
+					Message: fmt.Sprintf("%s is not an axis of the matrix", name),
+					Paths:   []string{name},
+				}).ViaFieldIndex("exclude", i).ViaField("matrix")
+			}
+			found := false
+			for _, v := range values {
+				if v == value {
+					found = true
+				}
+			}
+			if !found {
+				return (&apis.FieldError{
+					Message: fmt.Sprintf("%s is not a value of the matrix axis %s", value, name),
+					Paths:   []string{name},
+				}).ViaFieldIndex("exclude", i).ViaField("matrix")
+			}
+		}
+	}
+
+	if len(m.Cells()) == 0 {
+		return (&apis.FieldError{
+			Message: "all combinations of the matrix axes are excluded",
+			Paths:   []string{"exclude"},
+		}).ViaField("matrix")
+	}
+	return nil
+}
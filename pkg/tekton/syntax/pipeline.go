@@ -33,6 +33,8 @@ const (
 	// GitMergeImage is the default image name that is used in the git merge step of a pipeline
 	GitMergeImage = "gcr.io/jenkinsxio/builder-jx"
 
+	gitMergeStepName = "git-merge"
+
 	// WorkingDirRoot is the root directory for working directories.
 	WorkingDirRoot = "/workspace"
 
@@ -136,6 +138,7 @@ type RootOptions struct {
 // Stash defines files to be saved for use in a later stage, marked with a name
 type Stash struct {
 	Name string `json:"name"`
+	// Files is a space separated list of the paths to stash which may contain * and ? wildcards.
 	// Eventually make this optional so that you can do volumes instead
 	Files string `json:"files"`
 }
@@ -151,7 +154,6 @@ type Unstash struct {
 type StageOptions struct {
 	*RootOptions `json:",inline"`
 
-	// TODO: Not yet implemented in build-pipeline
 	Stash   *Stash   `json:"stash,omitempty"`
 	Unstash *Unstash `json:"unstash,omitempty"`
 
@@ -220,6 +222,8 @@ type Stage struct {
 	Parallel   []Stage         `json:"parallel,omitempty"`
 	Post       []Post          `json:"post,omitempty"`
 	WorkingDir *string         `json:"dir,omitempty"`
+	Matrix     *Matrix         `json:"matrix,omitempty"`
+	When       *When           `json:"when,omitempty"`
 
 	// Replaced by Env, retained for backwards compatibility
 	Environment []corev1.EnvVar `json:"environment,omitempty"`
@@ -236,15 +240,17 @@ const (
 )
 
 // Post contains a PostCondition and one more actions to be executed after a pipeline or stage if the condition is met.
+// The failure and always conditions rely on the steps of the stage running in a shell so that a failing step can be
+// recorded without stopping the Task, so they cannot be used with steps which are not run in a shell such as
+// /kaniko/warmer.
 type Post struct {
-	// TODO: Conditional execution of something after a Task or Pipeline completes is not yet implemented
 	Condition PostCondition `json:"condition"`
 	Actions   []PostAction  `json:"actions"`
 }
 
 // PostAction contains the name of a built-in post action and options to pass to that action.
 type PostAction struct {
-	// TODO: Notifications are not yet supported in Build Pipeline per se.
+	// Name is one of the built-in post actions in AllPostActions
 	Name string `json:"name"`
 	// Also, we'll need to do some magic to do type verification during translation - i.e., this action wants a number
 	// for this option, so translate the string value for that option to a number.
@@ -551,6 +557,18 @@ func (j *ParsedPipeline) ValidateInCluster(context context.Context, kubeClient k
 		return err
 	}
 
+	if err := validateStashes(j.Stages); err != nil {
+		return err
+	}
+
+	if err := validatePosts(j.Post).ViaField("post"); err != nil {
+		return err
+	}
+
+	if err := validatePostsAtEnd(j.Post, j.Stages); err != nil {
+		return err
+	}
+
 	if err := validateRootOptions(j.Options, volumes, kubeClient, ns).ViaField("options"); err != nil {
 		return err
 	}
@@ -620,6 +638,18 @@ func validateStage(s Stage, parentAgent *Agent, parentVolumes []*corev1.Volume,
 		}
 	}
 
+	if s.Matrix != nil {
+		if err := validateMatrix(s); err != nil {
+			return err
+		}
+	}
+
+	if s.When != nil {
+		if err := validateWhen(s.When); err != nil {
+			return err
+		}
+	}
+
 	if len(s.Steps) > 0 {
 		if len(s.Stages) > 0 || len(s.Parallel) > 0 {
 			return apis.ErrMultipleOneOf("steps", "stages", "parallel")
@@ -673,6 +703,15 @@ func validateStage(s Stage, parentAgent *Agent, parentVolumes []*corev1.Volume,
 		}
 	}
 
+	if err := validatePosts(s.Post).ViaField("post"); err != nil {
+		return err
+	}
+
+	// the stage itself counts as the last stage in the scope of its own post blocks
+	if err := validatePostsAtEnd(s.Post, []Stage{s}); err != nil {
+		return err
+	}
+
 	return validateStageOptions(s.Options, volumes, kubeClient, ns).ViaField("options")
 }
 
@@ -983,7 +1022,6 @@ func validateTimeout(t *Timeout) *apis.FieldError {
 
 func validateUnstash(u *Unstash) *apis.FieldError {
 	if u != nil {
-		// TODO: Check to make sure the corresponding stash is defined somewhere
 		if u.Name == "" {
 			return &apis.FieldError{
 				Message: "The unstash name must be provided",
@@ -1415,12 +1453,14 @@ type stageToTaskParams struct {
 	depth                int8
 	enclosingStage       *transformedStage
 	previousSiblingStage *transformedStage
+	inheritedPosts       []inheritedPost
 }
 
 func stageToTask(params stageToTaskParams) (*transformedStage, error) {
-	if len(params.stage.Post) != 0 {
-		return nil, errors.New("post on stages not yet supported")
-	}
+	params.stage = params.stage.expandMatrix()
+
+	// The post blocks of this stage are run before those of the enclosing stages and the pipeline
+	posts := append(ownPosts(params.stage.Post), params.inheritedPosts...)
 
 	stageContainer := &corev1.Container{}
 	var stageVolumes []*corev1.Volume
@@ -1438,12 +1478,6 @@ func stageToTask(params stageToTaskParams) (*transformedStage, error) {
 			}
 			stageVolumes = o.Volumes
 		}
-		if o.Stash != nil {
-			return nil, errors.New("Stash on stage not yet supported")
-		}
-		if o.Unstash != nil {
-			return nil, errors.New("Unstash on stage not yet supported")
-		}
 	}
 
 	// Don't overwrite the inherited working dir if we don't have one specified here.
@@ -1517,7 +1551,18 @@ func stageToTask(params stageToTaskParams) (*transformedStage, error) {
 			volumes[v.Name] = *v
 		}
 
-		for _, step := range params.stage.Steps {
+		// Unstashing happens before the stage's own steps run, and stashing once they have all succeeded
+		var steps []Step
+		o := params.stage.Options
+		if o != nil && o.Unstash != nil {
+			steps = append(steps, o.Unstash.toStep())
+		}
+		steps = append(steps, params.stage.Steps...)
+		if o != nil && o.Stash != nil {
+			steps = append(steps, o.Stash.toStep())
+		}
+
+		for _, step := range steps {
 			actualSteps, stepVolumes, newCounter, err := generateSteps(generateStepsParams{
 				stageParams:     params,
 				step:            step,
@@ -1538,6 +1583,17 @@ func stageToTask(params stageToTaskParams) (*transformedStage, error) {
 			}
 		}
 
+		if len(posts) > 0 {
+			withPostSteps, postVolumes, err := addPostSteps(params, posts, t.Spec.Steps, agent.Image, env, stageContainer, stepCounter)
+			if err != nil {
+				return nil, err
+			}
+			t.Spec.Steps = withPostSteps
+			for k, v := range postVolumes {
+				volumes[k] = v
+			}
+		}
+
 		// Avoid nondeterministic results by sorting the keys and appending volumes in that order.
 		var volNames []string
 		for k := range volumes {
@@ -1575,6 +1631,7 @@ func stageToTask(params stageToTaskParams) (*transformedStage, error) {
 				depth:                params.depth + 1,
 				enclosingStage:       &ts,
 				previousSiblingStage: nestedPreviousSibling,
+				inheritedPosts:       nestedPosts(posts, i == len(params.stage.Stages)-1),
 			})
 			if err != nil {
 				return nil, err
@@ -1587,6 +1644,9 @@ func stageToTask(params stageToTaskParams) (*transformedStage, error) {
 	}
 
 	if len(params.stage.Parallel) > 0 {
+		if hasPostRunningAtEnd(posts) {
+			return nil, errors.New("post conditions success and always are not supported when the last stage is parallel")
+		}
 		var tasks []*transformedStage
 		ts := transformedStage{Stage: params.stage, Depth: params.depth, EnclosingStage: params.enclosingStage, PreviousSiblingStage: params.previousSiblingStage}
 		ts.computeWorkspace(params.parentWorkspace)
@@ -1603,6 +1663,7 @@ func stageToTask(params stageToTaskParams) (*transformedStage, error) {
 				parentVolumes:   stageVolumes,
 				depth:           params.depth + 1,
 				enclosingStage:  &ts,
+				inheritedPosts:  posts,
 			})
 			if err != nil {
 				return nil, err
@@ -1867,14 +1928,12 @@ type CRDsFromPipelineParams struct {
 	Labels             map[string]string
 	DefaultImage       string
 	InterpretMode      bool
+	// When is what the when conditions of stages are evaluated against. All stages run if it is nil.
+	When *WhenContext
 }
 
 // GenerateCRDs translates the Pipeline structure into the corresponding Pipeline and Task CRDs
 func (j *ParsedPipeline) GenerateCRDs(params CRDsFromPipelineParams) (*tektonv1alpha1.Pipeline, []*tektonv1alpha1.Task, *v1.PipelineStructure, error) {
-	if len(j.Post) != 0 {
-		return nil, nil, nil, errors.New("Post at top level not yet supported")
-	}
-
 	var parentContainer *corev1.Container
 	var parentVolumes []*corev1.Volume
 
@@ -1927,8 +1986,13 @@ func (j *ParsedPipeline) GenerateCRDs(params CRDsFromPipelineParams) (*tektonv1a
 
 	baseEnv := j.GetEnv()
 
-	for i, s := range j.Stages {
-		isLastStage := i == len(j.Stages)-1
+	stages := skipStagesUnstashingSkippedStashes(j.Stages, filterStagesByWhen(j.Stages, params.When))
+	if len(stages) == 0 {
+		return nil, nil, nil, errors.New("the when conditions of every stage in the pipeline are not met so there is nothing to run")
+	}
+
+	for i, s := range stages {
+		isLastStage := i == len(stages)-1
 
 		stage, err := stageToTask(stageToTaskParams{
 			parentParams:         params,
@@ -1941,6 +2005,7 @@ func (j *ParsedPipeline) GenerateCRDs(params CRDsFromPipelineParams) (*tektonv1a
 			parentVolumes:        parentVolumes,
 			depth:                0,
 			previousSiblingStage: previousStage,
+			inheritedPosts:       nestedPosts(ownPosts(j.Post), isLastStage),
 		})
 		if err != nil {
 			return nil, nil, nil, err
@@ -1973,6 +2038,7 @@ func (j *ParsedPipeline) GenerateCRDs(params CRDsFromPipelineParams) (*tektonv1a
 		p.Spec.Tasks = append(p.Spec.Tasks, pipelineTasks...)
 		structure.Stages = append(structure.Stages, stage.getAllAsPipelineStructureStages()...)
 	}
+	structure.Stages = addSkippedStages(j.Stages, structure.Stages, params.When)
 
 	return p, tasks, structure, nil
 }
@@ -2194,7 +2260,7 @@ func getDefaultTaskSpec(envs []corev1.EnvVar, parentContainer *corev1.Container,
 	}
 
 	childContainer := &corev1.Container{
-		Name:       "git-merge",
+		Name:       gitMergeStepName,
 		Image:      image,
 		Command:    []string{"jx"},
 		Args:       []string{"step", "git", "merge", "--verbose"},
@@ -5,6 +5,10 @@ package syntax
 import (
 	"strings"
 	"testing"
+
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestFindDuplicates(t *testing.T) {
@@ -59,3 +63,87 @@ func TestFindDuplicates(t *testing.T) {
 		})
 	}
 }
+
+func TestQuoteStashFiles(t *testing.T) {
+	tests := []struct {
+		files    string
+		expected string
+	}{
+		{
+			files:    "bin/*",
+			expected: `'bin/'*`,
+		}, {
+			files:    "target/*.jar  docs/readme?.md",
+			expected: `'target/'*'.jar' 'docs/readme'?'.md'`,
+		}, {
+			files:    "it's;rm -rf $HOME",
+			expected: `'it'\''s;rm' '-rf' '$HOME'`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.files, func(t *testing.T) {
+			actual := quoteStashFiles(tt.files)
+			if actual != tt.expected {
+				t.Errorf("expected %s but got %s", tt.expected, actual)
+			}
+		})
+	}
+}
+
+func TestAddSkippedStagesMatchesByPath(t *testing.T) {
+	linux := "Linux"
+	windows := "Windows"
+	parallelStages := func(skipIntegration bool) []Stage {
+		integration := Stage{Name: "Integration"}
+		if skipIntegration {
+			integration.When = &When{Branch: []string{"master"}}
+		}
+		return []Stage{{Name: "Unit"}, integration}
+	}
+	stages := []Stage{
+		{Name: linux, Parallel: parallelStages(false)},
+		{Name: windows, Parallel: parallelStages(true)},
+	}
+	generated := []v1.PipelineStructureStage{
+		{Name: linux, Parallel: []string{"Unit", "Integration"}},
+		{Name: "Unit", Depth: 1, Parent: &linux},
+		{Name: "Integration", Depth: 1, Parent: &linux},
+		{Name: windows, Parallel: []string{"Unit"}, Previous: &linux},
+		{Name: "Unit", Depth: 1, Parent: &windows},
+	}
+
+	actual := addSkippedStages(stages, generated, &WhenContext{Branch: "PR-1"})
+
+	require.Len(t, actual, 6)
+	assert.Equal(t, "Integration", actual[2].Name)
+	assert.False(t, actual[2].Skipped, "the Integration stage of Linux should run")
+	assert.Equal(t, []string{"Unit", "Integration"}, actual[3].Parallel)
+	assert.Equal(t, "Integration", actual[5].Name)
+	assert.Equal(t, &windows, actual[5].Parent)
+	assert.True(t, actual[5].Skipped, "the Integration stage of Windows should be skipped")
+}
+
+func TestSkipStagesUnstashingSkippedStashes(t *testing.T) {
+	pipelineStages := []Stage{
+		{Name: "Build", Options: &StageOptions{Stash: &Stash{Name: "binaries", Files: "bin/*"}}},
+		{Name: "Site", Options: &StageOptions{Stash: &Stash{Name: "site", Files: "site/*"}}},
+		{Name: "Test", Options: &StageOptions{Unstash: &Unstash{Name: "binaries"}}},
+		{Name: "Docs", Options: &StageOptions{Unstash: &Unstash{Name: "site"}, Stash: &Stash{Name: "docs", Files: "docs/*"}}},
+		{Name: "Publish", Parallel: []Stage{
+			{Name: "Upload", Options: &StageOptions{Unstash: &Unstash{Name: "docs"}}},
+		}},
+		{Name: "Cache", Options: &StageOptions{Unstash: &Unstash{Name: "unknown"}}},
+	}
+	stages := []Stage{pipelineStages[0], pipelineStages[2], pipelineStages[3], pipelineStages[4], pipelineStages[5]}
+
+	actual := skipStagesUnstashingSkippedStashes(pipelineStages, stages)
+
+	var names []string
+	for _, s := range actual {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"Build", "Test", "Cache"}, names, "the stages depending on the skipped Site stage should be skipped")
+	require.Len(t, stages, 5, "the original stages should not be modified")
+	assert.Len(t, stages[3].Parallel, 1, "the original stages should not be modified")
+}
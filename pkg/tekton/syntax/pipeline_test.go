@@ -47,6 +47,7 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 		expectedErrorMsg   string
 		validationErrorMsg string
 		structure          *v1.PipelineStructure
+		when               *syntax.WhenContext
 	}{
 		{
 			name: "simple_jenkinsfile",
@@ -587,24 +588,351 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 				sh.PipelineStage("A Working Stage",
 					sh.StageStep(sh.StepCmd("echo"), sh.StepArg("hello"), sh.StepArg("world")),
 					sh.StagePost(syntax.PostConditionSuccess,
-						sh.PostAction("mail", map[string]string{
-							"to":      "foo@bar.com",
-							"subject": "Yay, it passed",
+						sh.PostAction("command", map[string]string{
+							"command": "echo \"it passed\"",
 						})),
 					sh.StagePost(syntax.PostConditionFailure,
-						sh.PostAction("slack", map[string]string{
-							"whatever": "the",
-							"slack":    "config",
-							"actually": "is. =)",
+						sh.PostAction("junit", map[string]string{
+							"pattern": "target/surefire-reports/**/*.xml",
 						})),
 					sh.StagePost(syntax.PostConditionAlways,
+						sh.PostAction("command", map[string]string{
+							"command": "rm -rf tmp",
+							"image":   "some-other-image",
+						}),
+					),
+				),
+			),
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("a-working-stage", "somepipeline-a-working-stage-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+				),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-a-working-stage-1", "jx", sh.TaskStageLabel("A Working Stage"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("git-merge", resolvedGitMergeImage, tb.StepCommand("jx"), tb.StepArgs("step", "git", "merge", "--verbose"), tb.StepWorkingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ -f /workspace/.jx-post-failed ]; then echo "skipping as a previous step failed"; exit 0; fi; ( echo hello world ) || { echo "step2" > /workspace/.jx-post-failed; echo jx-post-step-failed > /dev/termination-log; exit 0; }`),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-success-command-1", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ -f /workspace/.jx-post-failed ]; then exit 0; fi; echo "it passed"`),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-failure-junit-2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ ! -f /workspace/.jx-post-failed ]; then exit 0; fi; jx step stash -c tests -p "target/surefire-reports/**/*.xml"`),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-always-command-3", "some-other-image", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs("rm -rf tmp"),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-result", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ -f /workspace/.jx-post-failed ]; then echo "failed in step:"; cat /workspace/.jx-post-failed; exit 1; fi`),
+						tb.StepWorkingDir("/workspace/source")),
+				)),
+			},
+			structure: sh.PipelineStructure("somepipeline-1",
+				sh.StructureStage("A Working Stage", sh.StructureStageTaskRef("somepipeline-a-working-stage-1")),
+			),
+		},
+		{
+			name:             "post_non_shell_step",
+			expectedErrorMsg: "post conditions failure and always are not supported in stage A Working Stage as its step step2 is not run in a shell",
+		},
+		{
+			name: "post_nested",
+			expected: sh.ParsedPipeline(
+				sh.PipelineAgent("some-image"),
+				sh.PipelineStage("Build",
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("build")),
+				),
+				sh.PipelineStage("Test",
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("test")),
+					sh.StagePost(syntax.PostConditionFailure,
 						sh.PostAction("junit", map[string]string{
 							"pattern": "target/surefire-reports/**/*.xml",
-						}),
+						})),
+				),
+				sh.PipelinePost(syntax.PostConditionAlways,
+					sh.PostAction("command", map[string]string{
+						"command": "make clean",
+					})),
+			),
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("build", "somepipeline-build-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+					tb.PipelineTaskOutputResource("workspace", "somepipeline")),
+				tb.PipelineTask("test", "somepipeline-test-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline",
+						tb.From("build")),
+					tb.RunAfter("build")),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-build-1", "jx", sh.TaskStageLabel("Build"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.TaskOutputs(sh.OutputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit, tb.ResourceTargetPath("source"))),
+					tb.Step("git-merge", resolvedGitMergeImage, tb.StepCommand("jx"), tb.StepArgs("step", "git", "merge", "--verbose"), tb.StepWorkingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ -f /workspace/.jx-post-failed ]; then echo "skipping as a previous step failed"; exit 0; fi; ( make build ) || { echo "step2" > /workspace/.jx-post-failed; echo jx-post-step-failed > /dev/termination-log; exit 0; }`),
+						tb.StepWorkingDir("/workspace/source")),
+					// the pipeline is not complete until the Test stage has run, so always only runs here on failure
+					tb.Step("post-always-command-1", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ ! -f /workspace/.jx-post-failed ]; then exit 0; fi; make clean`),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-result", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ -f /workspace/.jx-post-failed ]; then echo "failed in step:"; cat /workspace/.jx-post-failed; exit 1; fi`),
+						tb.StepWorkingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-test-1", "jx", sh.TaskStageLabel("Test"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ -f /workspace/.jx-post-failed ]; then echo "skipping as a previous step failed"; exit 0; fi; ( make test ) || { echo "step2" > /workspace/.jx-post-failed; echo jx-post-step-failed > /dev/termination-log; exit 0; }`),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-failure-junit-1", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ ! -f /workspace/.jx-post-failed ]; then exit 0; fi; jx step stash -c tests -p "target/surefire-reports/**/*.xml"`),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-always-command-2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs("make clean"),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("post-result", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`if [ -f /workspace/.jx-post-failed ]; then echo "failed in step:"; cat /workspace/.jx-post-failed; exit 1; fi`),
+						tb.StepWorkingDir("/workspace/source")),
+				)),
+			},
+			structure: sh.PipelineStructure("somepipeline-1",
+				sh.StructureStage("Build", sh.StructureStageTaskRef("somepipeline-build-1")),
+				sh.StructureStage("Test", sh.StructureStageTaskRef("somepipeline-test-1"),
+					sh.StructureStagePrevious("Build")),
+			),
+		},
+		{
+			name: "stash_unstash",
+			expected: sh.ParsedPipeline(
+				sh.PipelineAgent("some-image"),
+				sh.PipelineStage("Build",
+					sh.StageOptions(
+						sh.StageOptionsStash("binaries", "bin/*"),
+					),
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("build")),
+				),
+				sh.PipelineStage("Test",
+					sh.StageOptions(
+						sh.StageOptionsUnstash("binaries", "bin"),
 					),
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("test")),
+				),
+			),
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("build", "somepipeline-build-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+					tb.PipelineTaskOutputResource("workspace", "somepipeline")),
+				tb.PipelineTask("test", "somepipeline-test-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline",
+						tb.From("build")),
+					tb.RunAfter("build")),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-build-1", "jx", sh.TaskStageLabel("Build"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.TaskOutputs(sh.OutputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit, tb.ResourceTargetPath("source"))),
+					tb.Step("git-merge", resolvedGitMergeImage, tb.StepCommand("jx"), tb.StepArgs("step", "git", "merge", "--verbose"), tb.StepWorkingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make build"), tb.StepWorkingDir("/workspace/source")),
+					tb.Step("stash-binaries", resolvedGitMergeImage, tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`mkdir -p /workspace/.jx-stash && tar -czf /workspace/.jx-stash/binaries.tar.gz 'bin/'* && jx step stash -c stash -p /workspace/.jx-stash/binaries.tar.gz --basedir /workspace/.jx-stash --project-git-url "$SOURCE_URL" --project-branch "$BRANCH_NAME"`),
+						tb.StepWorkingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-test-1", "jx", sh.TaskStageLabel("Test"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("unstash-binaries", resolvedGitMergeImage, tb.StepCommand("/bin/sh", "-c"),
+						tb.StepArgs(`mkdir -p /workspace/.jx-stash 'bin' && jx step unstash --stash 'binaries' -o /workspace/.jx-stash/binaries.tar.gz && tar -xzf /workspace/.jx-stash/binaries.tar.gz -C 'bin'`),
+						tb.StepWorkingDir("/workspace/source")),
+					tb.Step("step3", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make test"), tb.StepWorkingDir("/workspace/source")),
+				)),
+			},
+			structure: sh.PipelineStructure("somepipeline-1",
+				sh.StructureStage("Build", sh.StructureStageTaskRef("somepipeline-build-1")),
+				sh.StructureStage("Test", sh.StructureStageTaskRef("somepipeline-test-1"),
+					sh.StructureStagePrevious("Build")),
+			),
+		},
+		{
+			name: "matrix",
+			expected: sh.ParsedPipeline(
+				sh.PipelineAgent("some-image"),
+				sh.PipelineStage("Build",
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("build")),
+				),
+				sh.PipelineStage("Test",
+					sh.StageAgent("some-image-${OS}"),
+					sh.StageMatrix(
+						sh.MatrixAxis("JDK", "8", "11"),
+						sh.MatrixAxis("OS", "linux", "windows"),
+						sh.MatrixExclude(map[string]string{"JDK": "8", "OS": "windows"}),
+					),
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("test")),
+				),
+			),
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("build", "somepipeline-build-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+					tb.PipelineTaskOutputResource("workspace", "somepipeline")),
+				tb.PipelineTask("test-8-linux", "somepipeline-test-8-linux-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline", tb.From("build")),
+					tb.RunAfter("build")),
+				tb.PipelineTask("test-11-linux", "somepipeline-test-11-linux-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline", tb.From("build")),
+					tb.RunAfter("build")),
+				tb.PipelineTask("test-11-windows", "somepipeline-test-11-windows-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline", tb.From("build")),
+					tb.RunAfter("build")),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-build-1", "jx", sh.TaskStageLabel("Build"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.TaskOutputs(sh.OutputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit, tb.ResourceTargetPath("source"))),
+					tb.Step("git-merge", resolvedGitMergeImage, tb.StepCommand("jx"), tb.StepArgs("step", "git", "merge", "--verbose"), tb.StepWorkingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make build"), tb.StepWorkingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-test-8-linux-1", "jx", sh.TaskStageLabel("Test 8 linux"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image-linux", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make test"), tb.StepWorkingDir("/workspace/source"),
+						tb.StepEnvVar("JDK", "8"), tb.StepEnvVar("OS", "linux")),
+				)),
+				tb.Task("somepipeline-test-11-linux-1", "jx", sh.TaskStageLabel("Test 11 linux"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image-linux", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make test"), tb.StepWorkingDir("/workspace/source"),
+						tb.StepEnvVar("JDK", "11"), tb.StepEnvVar("OS", "linux")),
+				)),
+				tb.Task("somepipeline-test-11-windows-1", "jx", sh.TaskStageLabel("Test 11 windows"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image-windows", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make test"), tb.StepWorkingDir("/workspace/source"),
+						tb.StepEnvVar("JDK", "11"), tb.StepEnvVar("OS", "windows")),
+				)),
+			},
+			structure: sh.PipelineStructure("somepipeline-1",
+				sh.StructureStage("Build", sh.StructureStageTaskRef("somepipeline-build-1")),
+				sh.StructureStage("Test",
+					sh.StructureStageParallel("Test 8 linux", "Test 11 linux", "Test 11 windows"),
+					sh.StructureStagePrevious("Build"),
+				),
+				sh.StructureStage("Test 8 linux", sh.StructureStageTaskRef("somepipeline-test-8-linux-1"),
+					sh.StructureStageDepth(1),
+					sh.StructureStageParent("Test"),
+				),
+				sh.StructureStage("Test 11 linux", sh.StructureStageTaskRef("somepipeline-test-11-linux-1"),
+					sh.StructureStageDepth(1),
+					sh.StructureStageParent("Test"),
+				),
+				sh.StructureStage("Test 11 windows", sh.StructureStageTaskRef("somepipeline-test-11-windows-1"),
+					sh.StructureStageDepth(1),
+					sh.StructureStageParent("Test"),
 				),
 			),
-			expectedErrorMsg: "post on stages not yet supported",
+		},
+		{
+			name: "when_conditions",
+			expected: sh.ParsedPipeline(
+				sh.PipelineAgent("some-image"),
+				sh.PipelineStage("Build",
+					sh.StageWhen(sh.WhenBranch("master", "PR-*")),
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("build")),
+				),
+				sh.PipelineStage("Docs",
+					sh.StageWhen(sh.WhenChanges("docs/**")),
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("docs")),
+				),
+				sh.PipelineStage("Services",
+					sh.StageParallel("API",
+						sh.StageWhen(sh.WhenChanges("services/api/**")),
+						sh.StageStep(sh.StepCmd("make"), sh.StepArg("api"))),
+					sh.StageParallel("Web",
+						sh.StageWhen(sh.WhenChanges("services/web/**")),
+						sh.StageStep(sh.StepCmd("make"), sh.StepArg("web"))),
+				),
+				sh.PipelineStage("Integration",
+					sh.StageWhen(sh.WhenEnv("RUN_INTEGRATION", "true"), sh.WhenLabels("integration")),
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("integration")),
+				),
+				sh.PipelineStage("Deploy",
+					sh.StageWhen(sh.WhenBranch("master")),
+					sh.StageStep(sh.StepCmd("make"), sh.StepArg("deploy")),
+				),
+			),
+			when: &syntax.WhenContext{
+				Branch:       "PR-1",
+				Env:          map[string]string{"RUN_INTEGRATION": "true"},
+				PullRequest:  true,
+				ChangedFiles: []string{"README.md", "services/api/cmd/main.go"},
+				Labels:       []string{"integration"},
+			},
+			pipeline: tb.Pipeline("somepipeline-1", "jx", tb.PipelineSpec(
+				tb.PipelineTask("build", "somepipeline-build-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline"),
+					tb.PipelineTaskOutputResource("workspace", "somepipeline")),
+				tb.PipelineTask("api", "somepipeline-api-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline", tb.From("build")),
+					tb.RunAfter("build")),
+				tb.PipelineTask("integration", "somepipeline-integration-1",
+					tb.PipelineTaskInputResource("workspace", "somepipeline", tb.From("build")),
+					tb.RunAfter("api")),
+				tb.PipelineDeclaredResource("somepipeline", tektonv1alpha1.PipelineResourceTypeGit))),
+			tasks: []*tektonv1alpha1.Task{
+				tb.Task("somepipeline-build-1", "jx", sh.TaskStageLabel("Build"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.TaskOutputs(sh.OutputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit, tb.ResourceTargetPath("source"))),
+					tb.Step("git-merge", resolvedGitMergeImage, tb.StepCommand("jx"), tb.StepArgs("step", "git", "merge", "--verbose"), tb.StepWorkingDir("/workspace/source")),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make build"), tb.StepWorkingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-api-1", "jx", sh.TaskStageLabel("API"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make api"), tb.StepWorkingDir("/workspace/source")),
+				)),
+				tb.Task("somepipeline-integration-1", "jx", sh.TaskStageLabel("Integration"), tb.TaskSpec(
+					tb.TaskInputs(
+						tb.InputsResource("workspace", tektonv1alpha1.PipelineResourceTypeGit,
+							tb.ResourceTargetPath("source"))),
+					tb.Step("step2", "some-image:0.0.1", tb.StepCommand("/bin/sh", "-c"), tb.StepArgs("make integration"), tb.StepWorkingDir("/workspace/source")),
+				)),
+			},
+			structure: sh.PipelineStructure("somepipeline-1",
+				sh.StructureStage("Build", sh.StructureStageTaskRef("somepipeline-build-1")),
+				sh.StructureStage("Docs", sh.StructureStageSkipped(), sh.StructureStagePrevious("Build")),
+				sh.StructureStage("Services",
+					sh.StructureStageParallel("API", "Web"),
+					sh.StructureStagePrevious("Build"),
+				),
+				sh.StructureStage("API", sh.StructureStageTaskRef("somepipeline-api-1"),
+					sh.StructureStageDepth(1),
+					sh.StructureStageParent("Services"),
+				),
+				sh.StructureStage("Web", sh.StructureStageSkipped(),
+					sh.StructureStageDepth(1),
+					sh.StructureStageParent("Services"),
+				),
+				sh.StructureStage("Integration", sh.StructureStageTaskRef("somepipeline-integration-1"),
+					sh.StructureStagePrevious("Services")),
+				sh.StructureStage("Deploy", sh.StructureStageSkipped(), sh.StructureStagePrevious("Integration")),
+			),
 		},
 		{
 			name: "top_level_and_stage_options",
@@ -614,6 +942,12 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 					sh.PipelineOptionsTimeout(50, "minutes"),
 					sh.PipelineOptionsRetry(3),
 				),
+				sh.PipelineStage("An Earlier Stage",
+					sh.StageOptions(
+						sh.StageOptionsStash("Earlier Files", "some/sub/dir/*"),
+					),
+					sh.StageStep(sh.StepCmd("echo"), sh.StepArg("hello")),
+				),
 				sh.PipelineStage("A Working Stage",
 					sh.StageOptions(
 						sh.StageOptionsTimeout(5, "seconds"),
@@ -1424,6 +1758,7 @@ func TestParseJenkinsfileYaml(t *testing.T) {
 				SourceDir:          "source",
 				DefaultImage:       "",
 				InterpretMode:      false,
+				When:               tt.when,
 			}
 			pipeline, tasks, structure, err := parsed.GenerateCRDs(crdParams)
 
@@ -1634,6 +1969,67 @@ func TestFailedValidation(t *testing.T) {
 				Paths:   []string{"name"},
 			}).ViaField("unstash").ViaField("options").ViaFieldIndex("stages", 0),
 		},
+		{
+			name: "stash_name_duplicates",
+			expectedError: &apis.FieldError{
+				Message: "Stash names must be unique",
+				Details: "The following stash names are used more than once: 'Binaries', 'binaries'",
+			},
+		},
+		{
+			name: "unstash_missing_stash",
+			expectedError: (&apis.FieldError{
+				Message: "no stash named binaries is defined in the pipeline",
+				Paths:   []string{"name"},
+			}).ViaField("unstash").ViaField("options").ViaFieldIndex("stages", 1),
+		},
+		{
+			name: "unstash_from_parallel_sibling",
+			expectedError: (&apis.FieldError{
+				Message: "stash binaries is not made by a stage which completes before this one",
+				Paths:   []string{"name"},
+			}).ViaField("unstash").ViaField("options").ViaFieldIndex("parallel", 1).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "matrix_with_stages",
+			expectedError: (&apis.FieldError{
+				Message: "matrix can only be used on stages with steps",
+				Paths:   []string{"matrix"},
+			}).ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "matrix_invalid_axis_name",
+			expectedError: (&apis.FieldError{
+				Message: "JDK-VERSION is not a valid matrix axis name",
+				Details: "Axis names are used as environment variable names so must only contain letters, digits and underscores, and not start with a digit",
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("axes", 0).ViaField("matrix").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "matrix_exclude_unknown_value",
+			expectedError: (&apis.FieldError{
+				Message: "9 is not a value of the matrix axis JDK",
+				Paths:   []string{"JDK"},
+			}).ViaFieldIndex("exclude", 0).ViaField("matrix").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "matrix_all_excluded",
+			expectedError: (&apis.FieldError{
+				Message: "all combinations of the matrix axes are excluded",
+				Paths:   []string{"exclude"},
+			}).ViaField("matrix").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "when_invalid_changes_glob",
+			expectedError: (&apis.FieldError{
+				Message: "\"services/[api/**\" is not a valid glob",
+				Paths:   []string{"changes[0]"},
+			}).ViaField("when").ViaFieldIndex("stages", 0),
+		},
+		{
+			name:          "when_env_missing_name",
+			expectedError: apis.ErrMissingField("name").ViaFieldIndex("env", 0).ViaField("when").ViaFieldIndex("stages", 0),
+		},
 		{
 			name: "blank_stage_name",
 			expectedError: (&apis.FieldError{
@@ -1767,6 +2163,40 @@ func TestFailedValidation(t *testing.T) {
 				Paths:   []string{"name"},
 			}).ViaFieldIndex("volumeMounts", 0).ViaField("containerOptions").ViaField("options"),
 		},
+		{
+			name: "post_unknown_action",
+			expectedError: (&apis.FieldError{
+				Message: "mail is not a valid post action",
+				Details: "Valid post actions are command, junit",
+				Paths:   []string{"name"},
+			}).ViaFieldIndex("actions", 0).ViaIndex(0).ViaField("post").ViaFieldIndex("stages", 0),
+		},
+		{
+			name: "post_invalid_condition",
+			expectedError: (&apis.FieldError{
+				Message: "sometimes is not a valid post condition",
+				Details: "Valid post conditions are success, failure, always",
+				Paths:   []string{"condition"},
+			}).ViaIndex(0).ViaField("post").ViaFieldIndex("stages", 0),
+		},
+		{
+			name:          "post_action_missing_option",
+			expectedError: apis.ErrMissingField("pattern").ViaField("options").ViaFieldIndex("actions", 0).ViaIndex(0).ViaField("post"),
+		},
+		{
+			name: "post_success_after_parallel",
+			expectedError: &apis.FieldError{
+				Message: "post conditions success and always are not supported when the last stage is parallel",
+				Paths:   []string{"post"},
+			},
+		},
+		{
+			name: "post_success_after_matrix",
+			expectedError: &apis.FieldError{
+				Message: "post conditions success and always are not supported when the last stage is parallel",
+				Paths:   []string{"post"},
+			},
+		},
 		{
 			name: "volume_does_not_exist",
 			expectedError: (&apis.FieldError{
@@ -2309,3 +2739,45 @@ func TestParsedPipelineHelpers(t *testing.T) {
 		t.Fatalf("ParsedPipeline diff -want, +got: %v", d)
 	}
 }
+
+func TestWhenMatches(t *testing.T) {
+	pr := &syntax.WhenContext{
+		Branch:       "PR-12",
+		Env:          map[string]string{"DEPLOY": "false"},
+		PullRequest:  true,
+		ChangedFiles: []string{"services/api/pkg/handler.go"},
+		Labels:       []string{"ok-to-test"},
+	}
+	release := &syntax.WhenContext{
+		Branch: "master",
+		Env:    map[string]string{"DEPLOY": "true"},
+	}
+
+	tests := []struct {
+		name     string
+		when     *syntax.When
+		ctx      *syntax.WhenContext
+		expected bool
+	}{
+		{name: "no conditions", when: &syntax.When{}, ctx: pr, expected: true},
+		{name: "no context", when: &syntax.When{Branch: []string{"master"}}, ctx: nil, expected: true},
+		{name: "branch pattern", when: &syntax.When{Branch: []string{"master", "PR-*"}}, ctx: pr, expected: true},
+		{name: "branch mismatch", when: &syntax.When{Branch: []string{"master"}}, ctx: pr, expected: false},
+		{name: "nested change", when: &syntax.When{Changes: []string{"services/api/**"}}, ctx: pr, expected: true},
+		{name: "single star does not cross directories", when: &syntax.When{Changes: []string{"services/*.go"}}, ctx: pr, expected: false},
+		{name: "changes outside a pull request", when: &syntax.When{Changes: []string{"docs/**"}}, ctx: release, expected: true},
+		{name: "env value", when: &syntax.When{Env: []syntax.WhenEnv{{Name: "DEPLOY", Value: "true"}}}, ctx: release, expected: true},
+		{name: "env value mismatch", when: &syntax.When{Env: []syntax.WhenEnv{{Name: "DEPLOY", Value: "true"}}}, ctx: pr, expected: false},
+		{name: "env not value", when: &syntax.When{Env: []syntax.WhenEnv{{Name: "DEPLOY", Value: "true", Not: true}}}, ctx: pr, expected: true},
+		{name: "env unset", when: &syntax.When{Env: []syntax.WhenEnv{{Name: "MISSING"}}}, ctx: pr, expected: false},
+		{name: "label", when: &syntax.When{Labels: []string{"ok-to-test", "integration"}}, ctx: pr, expected: true},
+		{name: "labels outside a pull request", when: &syntax.When{Labels: []string{"ok-to-test"}}, ctx: release, expected: false},
+		{name: "all conditions must match", when: &syntax.When{Branch: []string{"PR-*"}, Labels: []string{"integration"}}, ctx: pr, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.when.Matches(tt.ctx))
+		})
+	}
+}
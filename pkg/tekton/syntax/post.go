@@ -0,0 +1,304 @@
+package syntax
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+	"github.com/pkg/errors"
+	tektonv1alpha1 "github.com/tektoncd/pipeline/pkg/apis/pipeline/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PostActionCommand runs the "command" option in a shell
+	PostActionCommand = "command"
+	// PostActionJUnit stashes the test reports matching the "pattern" option so they can be viewed after the build
+	PostActionJUnit = "junit"
+
+	// PostActionOptionImage is the option to override the image a post action runs in, defaulting to the stage agent
+	PostActionOptionImage = "image"
+
+	// postFailedFile is written by the first step of a Task to fail so that later steps are skipped and post steps
+	// know the outcome of the stage
+	postFailedFile = "/workspace/.jx-post-failed"
+
+	// PostResultStepName is the name of the step which fails the Task if any step failed before the post steps ran
+	PostResultStepName = "post-result"
+
+	// PostStepFailedMessage is the termination message of a step which failed in a Task with post steps. The step
+	// has to exit successfully so that the post steps run, so the message is used to report the step as failed
+	PostStepFailedMessage = "jx-post-step-failed"
+
+	// PostStepNamePrefix is the prefix of the names of the steps running post actions
+	PostStepNamePrefix = "post-"
+
+	terminationMessagePath = "/dev/termination-log"
+)
+
+var (
+	// AllPostActions the built-in post actions which can be used in a post block
+	AllPostActions = []string{PostActionCommand, PostActionJUnit}
+
+	allPostConditions = []PostCondition{PostConditionSuccess, PostConditionFailure, PostConditionAlways}
+
+	requiredPostActionOptions = map[string]string{
+		PostActionCommand: "command",
+		PostActionJUnit:   "pattern",
+	}
+)
+
+// inheritedPost is a post block of a stage or of the pipeline, as applied to one of the stages within its scope
+type inheritedPost struct {
+	post Post
+	// last is true if the stage is the last one to run within the scope of the post block
+	last bool
+}
+
+func ownPosts(posts []Post) []inheritedPost {
+	var answer []inheritedPost
+	for _, p := range posts {
+		answer = append(answer, inheritedPost{post: p, last: true})
+	}
+	return answer
+}
+
+// nestedPosts returns the post blocks which apply to a nested sequential stage
+func nestedPosts(posts []inheritedPost, last bool) []inheritedPost {
+	var answer []inheritedPost
+	for _, p := range posts {
+		answer = append(answer, inheritedPost{post: p.post, last: p.last && last})
+	}
+	return answer
+}
+
+// hasPostRunningAtEnd returns true if any of the posts need to run once the last stage in their scope has completed
+func hasPostRunningAtEnd(posts []inheritedPost) bool {
+	for _, p := range posts {
+		if p.last && p.post.Condition != PostConditionFailure {
+			return true
+		}
+	}
+	return false
+}
+
+// postGuard returns the shell expression to prefix a post action with so it only runs under the right condition, and
+// false if the post does not need to run in this Task at all.
+func (p inheritedPost) postGuard() (string, bool) {
+	onlyIfFailed := fmt.Sprintf("if [ ! -f %s ]; then exit 0; fi; ", postFailedFile)
+	switch p.post.Condition {
+	case PostConditionFailure:
+		return onlyIfFailed, true
+	case PostConditionSuccess:
+		if !p.last {
+			// a later stage will run once this one succeeds
+			return "", false
+		}
+		return fmt.Sprintf("if [ -f %s ]; then exit 0; fi; ", postFailedFile), true
+	case PostConditionAlways:
+		if !p.last {
+			// later stages only run if this one succeeds so we only need to run here if it failed
+			return onlyIfFailed, true
+		}
+		return "", true
+	}
+	return "", false
+}
+
+// toStep converts the post action into the step which runs it
+func (a PostAction) toStep() Step {
+	step := Step{
+		Image: a.Options[PostActionOptionImage],
+	}
+	switch a.Name {
+	case PostActionCommand:
+		step.Command = a.Options["command"]
+	case PostActionJUnit:
+		step.Command = fmt.Sprintf("jx step stash -c tests -p \"%s\"", a.Options["pattern"])
+		if basedir := a.Options["basedir"]; basedir != "" {
+			step.Command += fmt.Sprintf(" --basedir \"%s\"", basedir)
+		}
+	}
+	return step
+}
+
+// isShellStep returns true if the step runs a single script in a shell, so that it can safely be wrapped
+func isShellStep(step tektonv1alpha1.Step) bool {
+	return len(step.Command) == 2 && step.Command[1] == "-c" && len(step.Args) == 1
+}
+
+// wrapStepForPost ensures a failure of the step is recorded rather than failing the Task so that the post steps can
+// run, and skips the step if an earlier step has failed. The failure is also written to the termination message of
+// the step so that it is reported as failed even though it exits successfully.
+func wrapStepForPost(step tektonv1alpha1.Step) tektonv1alpha1.Step {
+	if !isShellStep(step) {
+		return step
+	}
+	step.Args = []string{fmt.Sprintf("if [ -f %s ]; then echo \"skipping as a previous step failed\"; exit 0; fi; ( %s ) || { echo \"%s\" > %s; echo %s > %s; exit 0; }",
+		postFailedFile, step.Args[0], step.Name, postFailedFile, PostStepFailedMessage, terminationMessagePath)}
+	return step
+}
+
+// requiresShellSteps returns true if any of the posts run when a step fails, which relies on every step of the Task
+// being wrapped in a shell
+func requiresShellSteps(posts []inheritedPost) bool {
+	for _, p := range posts {
+		if p.post.Condition == PostConditionFailure || p.post.Condition == PostConditionAlways {
+			return true
+		}
+	}
+	return false
+}
+
+// addPostSteps appends the steps for the post blocks which apply to this Task, along with a final step which fails
+// the Task if one of its steps failed. Steps which are not run via a shell cannot be wrapped, as they would fail the
+// Task immediately without running any post steps, so the failure and always conditions are rejected for them.
+func addPostSteps(params stageToTaskParams, posts []inheritedPost, steps []tektonv1alpha1.Step, agentImage string,
+	env []corev1.EnvVar, stageContainer *corev1.Container, stepCounter int) ([]tektonv1alpha1.Step, map[string]corev1.Volume, error) {
+	volumes := make(map[string]corev1.Volume)
+
+	var postSteps []tektonv1alpha1.Step
+	for _, p := range posts {
+		guard, applies := p.postGuard()
+		if !applies {
+			continue
+		}
+		for _, action := range p.post.Actions {
+			step := action.toStep()
+			step.Name = fmt.Sprintf("%s%s-%s-%d", PostStepNamePrefix, p.post.Condition, action.Name, len(postSteps)+1)
+			generated, stepVolumes, newCounter, err := generateSteps(generateStepsParams{
+				stageParams:     params,
+				step:            step,
+				inheritedAgent:  agentImage,
+				env:             env,
+				parentContainer: stageContainer,
+				stepCounter:     stepCounter,
+			})
+			if err != nil {
+				return nil, nil, errors.Wrapf(err, "generating step for post action %s", action.Name)
+			}
+			stepCounter = newCounter
+			for _, s := range generated {
+				if isShellStep(s) {
+					s.Args = []string{guard + s.Args[0]}
+				} else if guard != "" {
+					return nil, nil, errors.Errorf("post action %s in stage %s must run in a shell to check the post condition %s",
+						action.Name, params.stage.Name, p.post.Condition)
+				}
+				postSteps = append(postSteps, s)
+			}
+			for k, v := range stepVolumes {
+				volumes[k] = v
+			}
+		}
+	}
+	if len(postSteps) == 0 {
+		return steps, volumes, nil
+	}
+
+	var answer []tektonv1alpha1.Step
+	for _, s := range steps {
+		// the git merge step runs before any of the steps of the stage so a failure there has nothing to clean up
+		if !isShellStep(s) && s.Name != gitMergeStepName && requiresShellSteps(posts) {
+			return nil, nil, errors.Errorf("post conditions failure and always are not supported in stage %s as its step %s is not run in a shell",
+				params.stage.Name, s.Name)
+		}
+		answer = append(answer, wrapStepForPost(s))
+	}
+	answer = append(answer, postSteps...)
+
+	resultSteps, _, _, err := generateSteps(generateStepsParams{
+		stageParams: params,
+		step: Step{
+			Name:    PostResultStepName,
+			Command: fmt.Sprintf("if [ -f %s ]; then echo \"failed in step:\"; cat %s; exit 1; fi", postFailedFile, postFailedFile),
+		},
+		inheritedAgent:  agentImage,
+		env:             env,
+		parentContainer: stageContainer,
+		stepCounter:     stepCounter,
+	})
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "generating post result step")
+	}
+	answer = append(answer, resultSteps...)
+	return answer, volumes, nil
+}
+
+func validatePosts(posts []Post) *apis.FieldError {
+	for i, p := range posts {
+		if err := validatePost(p).ViaIndex(i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePost(p Post) *apis.FieldError {
+	validCondition := false
+	var conditions []string
+	for _, c := range allPostConditions {
+		conditions = append(conditions, string(c))
+		if p.Condition == c {
+			validCondition = true
+		}
+	}
+	if !validCondition {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("%s is not a valid post condition", p.Condition),
+			Details: fmt.Sprintf("Valid post conditions are %s", strings.Join(conditions, ", ")),
+			Paths:   []string{"condition"},
+		}
+	}
+	if len(p.Actions) == 0 {
+		return apis.ErrMissingField("actions")
+	}
+	for i, a := range p.Actions {
+		if err := validatePostAction(a).ViaFieldIndex("actions", i); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validatePostAction(a PostAction) *apis.FieldError {
+	required, known := requiredPostActionOptions[a.Name]
+	if !known {
+		return &apis.FieldError{
+			Message: fmt.Sprintf("%s is not a valid post action", a.Name),
+			Details: fmt.Sprintf("Valid post actions are %s", strings.Join(AllPostActions, ", ")),
+			Paths:   []string{"name"},
+		}
+	}
+	if a.Options[required] == "" {
+		return apis.ErrMissingField(required).ViaField("options")
+	}
+	return nil
+}
+
+// validatePostsAtEnd checks that post blocks which run when their scope completes are not used when the last stage
+// within that scope runs in parallel, since there is no single Task to run them in.
+func validatePostsAtEnd(posts []Post, stages []Stage) *apis.FieldError {
+	if !hasPostRunningAtEnd(ownPosts(posts)) || len(stages) == 0 {
+		return nil
+	}
+	if lastStageIsParallel(stages[len(stages)-1]) {
+		return &apis.FieldError{
+			Message: "post conditions success and always are not supported when the last stage is parallel",
+			Paths:   []string{"post"},
+		}
+	}
+	return nil
+}
+
+// lastStageIsParallel returns true if the stage, or the last of its nested sequential stages, runs in parallel. Matrix
+// stages are expanded into parallel stages so count as parallel too
+func lastStageIsParallel(s Stage) bool {
+	if len(s.Parallel) > 0 || s.Matrix != nil {
+		return true
+	}
+	if len(s.Stages) > 0 {
+		return lastStageIsParallel(s.Stages[len(s.Stages)-1])
+	}
+	return false
+}
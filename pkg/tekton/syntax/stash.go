@@ -0,0 +1,189 @@
+package syntax
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/knative/pkg/apis"
+)
+
+const (
+	// StashClassifier is the storage classifier stashes are stored under in the team's storage location
+	StashClassifier = "stash"
+
+	// stashDir is where stash archives are created and downloaded to within a Task
+	stashDir = "/workspace/.jx-stash"
+)
+
+// StashArchiveName returns the file name of the archive a stash is stored in
+func StashArchiveName(name string) string {
+	return MangleToRfc1035Label(name, "") + ".tar.gz"
+}
+
+// toStep converts the stash into the step which archives its files and stores the archive in the team's storage
+// location for the current build. The step runs in the builder image rather than the agent image as it needs jx.
+func (s *Stash) toStep() Step {
+	archive := stashDir + "/" + StashArchiveName(s.Name)
+	return Step{
+		Name:  "stash-" + s.Name,
+		Image: GitMergeImage,
+		Command: fmt.Sprintf("mkdir -p %s && tar -czf %s %s && jx step stash -c %s -p %s --basedir %s --project-git-url \"$SOURCE_URL\" --project-branch \"$BRANCH_NAME\"",
+			stashDir, archive, quoteStashFiles(s.Files), StashClassifier, archive, stashDir),
+	}
+}
+
+// toStep converts the unstash into the step which fetches the archive of the stash for the current build and
+// extracts it into the unstash directory. The step runs in the builder image rather than the agent image as it
+// needs jx.
+func (u *Unstash) toStep() Step {
+	archive := stashDir + "/" + StashArchiveName(u.Name)
+	dir := u.Dir
+	if dir == "" {
+		dir = "."
+	}
+	return Step{
+		Name:  "unstash-" + u.Name,
+		Image: GitMergeImage,
+		Command: fmt.Sprintf("mkdir -p %s %s && jx step unstash --stash %s -o %s && tar -xzf %s -C %s",
+			stashDir, shellQuote(dir), shellQuote(u.Name), archive, archive, shellQuote(dir)),
+	}
+}
+
+// quoteStashFiles quotes each of the space separated paths to stash so that they are passed to tar as they are,
+// leaving any * and ? wildcards unquoted so that the shell still expands them
+func quoteStashFiles(files string) string {
+	var answer []string
+	for _, path := range strings.Fields(files) {
+		var quoted strings.Builder
+		literal := ""
+		for _, r := range path {
+			if r == '*' || r == '?' {
+				if literal != "" {
+					quoted.WriteString(shellQuote(literal))
+					literal = ""
+				}
+				quoted.WriteRune(r)
+				continue
+			}
+			literal += string(r)
+		}
+		if literal != "" {
+			quoted.WriteString(shellQuote(literal))
+		}
+		answer = append(answer, quoted.String())
+	}
+	return strings.Join(answer, " ")
+}
+
+// shellQuote quotes the value in single quotes so that the shell does not interpret any of its characters
+func shellQuote(value string) string {
+	return "'" + strings.Replace(value, "'", `'\''`, -1) + "'"
+}
+
+// validateStashes checks that stash names are unique within the pipeline and that every unstash refers to a stash
+// made by a stage which will have completed before the unstashing stage starts.
+func validateStashes(stages []Stage) *apis.FieldError {
+	var names []string
+	var collect func(stages []Stage)
+	collect = func(stages []Stage) {
+		for _, s := range stages {
+			if s.Options != nil && s.Options.Stash != nil {
+				names = append(names, s.Options.Stash.Name)
+			}
+			collect(s.Stages)
+			collect(s.Parallel)
+		}
+	}
+	collect(stages)
+
+	// Stashes are stored by their mangled name so names which only differ in case or punctuation also collide
+	counts := make(map[string]int)
+	all := make(map[string]bool)
+	for _, n := range names {
+		counts[StashArchiveName(n)]++
+		all[StashArchiveName(n)] = true
+	}
+	seen := make(map[string]bool)
+	var duplicates []string
+	for _, n := range names {
+		if counts[StashArchiveName(n)] > 1 && !seen[n] {
+			seen[n] = true
+			duplicates = append(duplicates, "'"+n+"'")
+		}
+	}
+	if len(duplicates) > 0 {
+		sort.Strings(duplicates)
+		return &apis.FieldError{
+			Message: "Stash names must be unique",
+			Details: "The following stash names are used more than once: " + strings.Join(duplicates, ", "),
+		}
+	}
+
+	_, err := validateUnstashesInOrder(stages, map[string]bool{}, all)
+	return err
+}
+
+// validateUnstashesInOrder walks the stages in the order they run, returning the stashes made by them
+func validateUnstashesInOrder(stages []Stage, available map[string]bool, all map[string]bool) (map[string]bool, *apis.FieldError) {
+	made := make(map[string]bool)
+	availableToNext := copyStashNames(available)
+	for i, s := range stages {
+		stageMade, err := validateStageUnstash(s, availableToNext, all)
+		if err != nil {
+			return nil, err.ViaFieldIndex("stages", i)
+		}
+		for k := range stageMade {
+			made[k] = true
+			availableToNext[k] = true
+		}
+	}
+	return made, nil
+}
+
+func validateStageUnstash(s Stage, available map[string]bool, all map[string]bool) (map[string]bool, *apis.FieldError) {
+	made := make(map[string]bool)
+	if s.Options != nil {
+		if len(s.Steps) == 0 && (s.Options.Stash != nil || s.Options.Unstash != nil) {
+			return nil, &apis.FieldError{
+				Message: "stash and unstash can only be used on stages with steps",
+				Paths:   []string{"options"},
+			}
+		}
+		if u := s.Options.Unstash; u != nil && !available[StashArchiveName(u.Name)] {
+			message := fmt.Sprintf("no stash named %s is defined in the pipeline", u.Name)
+			if all[StashArchiveName(u.Name)] {
+				message = fmt.Sprintf("stash %s is not made by a stage which completes before this one", u.Name)
+			}
+			return nil, (&apis.FieldError{
+				Message: message,
+				Paths:   []string{"name"},
+			}).ViaField("unstash").ViaField("options")
+		}
+		if s.Options.Stash != nil {
+			made[StashArchiveName(s.Options.Stash.Name)] = true
+		}
+	}
+	if len(s.Stages) > 0 {
+		return validateUnstashesInOrder(s.Stages, available, all)
+	}
+	for i, p := range s.Parallel {
+		// parallel stages cannot see each other's stashes, only those made before the parallel stage
+		parallelMade, err := validateStageUnstash(p, available, all)
+		if err != nil {
+			return nil, err.ViaFieldIndex("parallel", i)
+		}
+		for k := range parallelMade {
+			made[k] = true
+		}
+	}
+	return made, nil
+}
+
+func copyStashNames(names map[string]bool) map[string]bool {
+	answer := make(map[string]bool)
+	for k, v := range names {
+		answer[k] = v
+	}
+	return answer
+}
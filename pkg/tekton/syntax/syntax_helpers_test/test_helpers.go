@@ -101,6 +101,13 @@ func StructureStageStages(stages ...string) PipelineStructureStageOp {
 	}
 }
 
+// StructureStageSkipped marks the stage as skipped
+func StructureStageSkipped() PipelineStructureStageOp {
+	return func(stage *v1.PipelineStructureStage) {
+		stage.Skipped = true
+	}
+}
+
 // StructureStageParallel sets the nested parallel stages for the stage
 func StructureStageParallel(stages ...string) PipelineStructureStageOp {
 	return func(stage *v1.PipelineStructureStage) {
@@ -123,6 +130,12 @@ type StageOp func(*syntax.Stage)
 // StageOptionsOp is an operation on StageOptions
 type StageOptionsOp func(*syntax.StageOptions)
 
+// MatrixOp is an operation on a Matrix
+type MatrixOp func(*syntax.Matrix)
+
+// WhenOp is an operation on a When
+type WhenOp func(*syntax.When)
+
 // StepOp is an operation on a step
 type StepOp func(*syntax.Step)
 
@@ -635,6 +648,76 @@ func StageParallel(name string, ops ...StageOp) StageOp {
 	}
 }
 
+// StageMatrix sets the matrix for a stage
+func StageMatrix(ops ...MatrixOp) StageOp {
+	return func(stage *syntax.Stage) {
+		stage.Matrix = &syntax.Matrix{}
+
+		for _, op := range ops {
+			op(stage.Matrix)
+		}
+	}
+}
+
+// MatrixAxis adds an axis with the given values to the matrix
+func MatrixAxis(name string, values ...string) MatrixOp {
+	return func(matrix *syntax.Matrix) {
+		matrix.Axes = append(matrix.Axes, syntax.MatrixAxis{
+			Name:   name,
+			Values: values,
+		})
+	}
+}
+
+// MatrixExclude adds an exclusion of the combinations with the given axis values to the matrix
+func MatrixExclude(exclude map[string]string) MatrixOp {
+	return func(matrix *syntax.Matrix) {
+		matrix.Exclude = append(matrix.Exclude, exclude)
+	}
+}
+
+// StageWhen adds the conditions for the stage to run
+func StageWhen(ops ...WhenOp) StageOp {
+	return func(stage *syntax.Stage) {
+		stage.When = &syntax.When{}
+
+		for _, op := range ops {
+			op(stage.When)
+		}
+	}
+}
+
+// WhenBranch adds branch patterns to the conditions
+func WhenBranch(patterns ...string) WhenOp {
+	return func(when *syntax.When) {
+		when.Branch = append(when.Branch, patterns...)
+	}
+}
+
+// WhenChanges adds changed file globs to the conditions
+func WhenChanges(globs ...string) WhenOp {
+	return func(when *syntax.When) {
+		when.Changes = append(when.Changes, globs...)
+	}
+}
+
+// WhenEnv adds an environment variable comparison to the conditions
+func WhenEnv(name string, value string) WhenOp {
+	return func(when *syntax.When) {
+		when.Env = append(when.Env, syntax.WhenEnv{
+			Name:  name,
+			Value: value,
+		})
+	}
+}
+
+// WhenLabels adds pull request labels to the conditions
+func WhenLabels(labels ...string) WhenOp {
+	return func(when *syntax.When) {
+		when.Labels = append(when.Labels, labels...)
+	}
+}
+
 // StageSequential adds a nested sequential stage to the stage
 func StageSequential(name string, ops ...StageOp) StageOp {
 	return func(stage *syntax.Stage) {
@@ -0,0 +1,305 @@
+package syntax
+
+import (
+	"fmt"
+
+	"github.com/gobwas/glob"
+	v1 "github.com/jenkins-x/jx/v2/pkg/apis/jenkins.io/v1"
+	"github.com/jenkins-x/jx/v2/pkg/log"
+	"github.com/jenkins-x/jx/v2/pkg/util"
+	"github.com/knative/pkg/apis"
+)
+
+// When contains the conditions which must all be met for a stage to run. A condition with a list of values is met if
+// any of them match.
+type When struct {
+	// Branch contains patterns, such as "master" or "release-*", matched against the name of the branch being built
+	Branch []string `json:"branch,omitempty"`
+	// Changes contains globs, such as "services/api/**", matched against the files changed by a pull request. It is
+	// always met when not building a pull request.
+	Changes []string `json:"changes,omitempty"`
+	// Env contains environment variables which must have the given values
+	Env []WhenEnv `json:"env,omitempty"`
+	// Labels contains labels the pull request must have one of. It is never met when not building a pull request.
+	Labels []string `json:"labels,omitempty"`
+}
+
+// WhenEnv compares the value of an environment variable with a pattern, such as "true" or "release-*"
+type WhenEnv struct {
+	Name  string `json:"name"`
+	Value string `json:"value,omitempty"`
+	// Not makes the condition met when the value does not match
+	Not bool `json:"not,omitempty"`
+}
+
+// WhenContext contains what the when conditions of stages are evaluated against for a build
+type WhenContext struct {
+	Branch       string
+	Env          map[string]string
+	PullRequest  bool
+	ChangedFiles []string
+	Labels       []string
+}
+
+// Matches returns true if all the conditions are met in the given context. If there is no context, such as when
+// generating CRDs outside of a build, all conditions are treated as met.
+func (w *When) Matches(ctx *WhenContext) bool {
+	if w == nil || ctx == nil {
+		return true
+	}
+	if len(w.Branch) > 0 && !util.StringMatchesAny(ctx.Branch, w.Branch, nil) {
+		return false
+	}
+	for _, e := range w.Env {
+		if e.matches(ctx.Env) == e.Not {
+			return false
+		}
+	}
+	if len(w.Changes) > 0 && ctx.PullRequest && !anyFileChanged(w.Changes, ctx.ChangedFiles) {
+		return false
+	}
+	if len(w.Labels) > 0 {
+		if !ctx.PullRequest {
+			return false
+		}
+		found := false
+		for _, l := range ctx.Labels {
+			if util.StringArrayIndex(w.Labels, l) >= 0 {
+				found = true
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matches returns true if the variable has a value matching the pattern, or any non-empty value if there isn't one
+func (e WhenEnv) matches(env map[string]string) bool {
+	value := env[e.Name]
+	if e.Value == "" {
+		return value != ""
+	}
+	return util.StringMatchesPattern(value, e.Value)
+}
+
+func anyFileChanged(patterns []string, files []string) bool {
+	for _, p := range patterns {
+		g, err := glob.Compile(p, '/')
+		if err != nil {
+			// invalid globs are reported by validation
+			continue
+		}
+		for _, f := range files {
+			if g.Match(f) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Whens returns the when conditions of all the stages in the pipeline which have them
+func (j *ParsedPipeline) Whens() []*When {
+	var answer []*When
+	var collect func(stages []Stage)
+	collect = func(stages []Stage) {
+		for _, s := range stages {
+			if s.When != nil {
+				answer = append(answer, s.When)
+			}
+			collect(s.Stages)
+			collect(s.Parallel)
+		}
+	}
+	collect(j.Stages)
+	return answer
+}
+
+// filterStagesByWhen returns the stages which should run in the given context. Stages containing nested stages are
+// skipped if all of those are.
+func filterStagesByWhen(stages []Stage, ctx *WhenContext) []Stage {
+	if ctx == nil {
+		return stages
+	}
+	var answer []Stage
+	for _, s := range stages {
+		if !s.When.Matches(ctx) {
+			continue
+		}
+		if len(s.Stages) > 0 {
+			s.Stages = filterStagesByWhen(s.Stages, ctx)
+			if len(s.Stages) == 0 {
+				continue
+			}
+		}
+		if len(s.Parallel) > 0 {
+			s.Parallel = filterStagesByWhen(s.Parallel, ctx)
+			if len(s.Parallel) == 0 {
+				continue
+			}
+		}
+		answer = append(answer, s)
+	}
+	return answer
+}
+
+// skipStagesUnstashingSkippedStashes returns the stages which run without those unstashing a stash made by a stage of
+// the pipeline which is skipped, so that they are marked as skipped too rather than running without their inputs.
+// Skipping a stage can skip the stages unstashing its own stash so this is repeated until no more stages are skipped.
+func skipStagesUnstashingSkippedStashes(pipelineStages []Stage, stages []Stage) []Stage {
+	pipelineStashes := stashNames(pipelineStages)
+	for {
+		stashes := stashNames(stages)
+		skipped := false
+		var skip func(stages []Stage) []Stage
+		skip = func(stages []Stage) []Stage {
+			var answer []Stage
+			for _, s := range stages {
+				if s.Options != nil && s.Options.Unstash != nil {
+					name := StashArchiveName(s.Options.Unstash.Name)
+					if pipelineStashes[name] && !stashes[name] {
+						log.Logger().Infof("Skipping stage %s as the stage stashing %s is skipped", s.Name, s.Options.Unstash.Name)
+						skipped = true
+						continue
+					}
+				}
+				if len(s.Stages) > 0 {
+					s.Stages = skip(s.Stages)
+					if len(s.Stages) == 0 {
+						continue
+					}
+				}
+				if len(s.Parallel) > 0 {
+					s.Parallel = skip(s.Parallel)
+					if len(s.Parallel) == 0 {
+						continue
+					}
+				}
+				answer = append(answer, s)
+			}
+			return answer
+		}
+		stages = skip(stages)
+		if !skipped {
+			return stages
+		}
+	}
+}
+
+// stashNames returns the archive names of the stashes made by the given stages and their nested stages
+func stashNames(stages []Stage) map[string]bool {
+	answer := make(map[string]bool)
+	var collect func(stages []Stage)
+	collect = func(stages []Stage) {
+		for _, s := range stages {
+			if s.Options != nil && s.Options.Stash != nil {
+				answer[StashArchiveName(s.Options.Stash.Name)] = true
+			}
+			collect(s.Stages)
+			collect(s.Parallel)
+		}
+	}
+	collect(stages)
+	return answer
+}
+
+// addSkippedStages returns the structure stages generated for the stages which run with stages marked as skipped
+// added for those which don't, keeping the order of the stages in the pipeline. Stages are matched by their path
+// from the top level stage as parallel stages in different parents can have the same name.
+func addSkippedStages(stages []Stage, generated []v1.PipelineStructureStage, ctx *WhenContext) []v1.PipelineStructureStage {
+	if ctx == nil {
+		return generated
+	}
+	// the generated stages are ordered with each stage followed by its nested stages
+	byPath := make(map[string]v1.PipelineStructureStage)
+	var paths []string
+	for _, s := range generated {
+		depth := int(s.Depth)
+		if depth > len(paths) {
+			depth = len(paths)
+		}
+		parentPath := ""
+		if depth > 0 {
+			parentPath = paths[depth-1]
+		}
+		paths = append(paths[:depth], stagePath(parentPath, s.Name))
+		byPath[paths[depth]] = s
+	}
+	var answer []v1.PipelineStructureStage
+	var add func(stages []Stage, parentPath string, parent *string, depth int8, parallel bool) []string
+	add = func(stages []Stage, parentPath string, parent *string, depth int8, parallel bool) []string {
+		var names []string
+		var previous *string
+		for _, s := range stages {
+			s = s.expandMatrix()
+			name := s.Name
+			path := stagePath(parentPath, name)
+			names = append(names, name)
+			existing, ok := byPath[path]
+			if !ok {
+				answer = append(answer, v1.PipelineStructureStage{
+					Name:     name,
+					Depth:    depth,
+					Parent:   parent,
+					Previous: previous,
+					Skipped:  true,
+				})
+				if !parallel {
+					previous = &name
+				}
+				continue
+			}
+			index := len(answer)
+			answer = append(answer, existing)
+			if len(s.Stages) > 0 {
+				answer[index].Stages = add(s.Stages, path, &name, depth+1, false)
+			}
+			if len(s.Parallel) > 0 {
+				answer[index].Parallel = add(s.Parallel, path, &name, depth+1, true)
+			}
+			if !parallel {
+				previous = &name
+			}
+		}
+		return names
+	}
+	add(stages, "", nil, 0, false)
+	return answer
+}
+
+// stagePath returns the path of the stage with the given name nested within the stage with the given path
+func stagePath(parentPath string, name string) string {
+	if parentPath == "" {
+		return name
+	}
+	return parentPath + "/" + name
+}
+
+func validateWhen(w *When) *apis.FieldError {
+	for i, b := range w.Branch {
+		if b == "" {
+			return apis.ErrInvalidArrayValue(b, "branch", i).ViaField("when")
+		}
+	}
+	for i, c := range w.Changes {
+		if _, err := glob.Compile(c, '/'); c == "" || err != nil {
+			return (&apis.FieldError{
+				Message: fmt.Sprintf("%q is not a valid glob", c),
+				Paths:   []string{fmt.Sprintf("changes[%d]", i)},
+			}).ViaField("when")
+		}
+	}
+	for i, e := range w.Env {
+		if e.Name == "" {
+			return apis.ErrMissingField("name").ViaFieldIndex("env", i).ViaField("when")
+		}
+	}
+	for i, l := range w.Labels {
+		if l == "" {
+			return apis.ErrInvalidArrayValue(l, "labels", i).ViaField("when")
+		}
+	}
+	return nil
+}
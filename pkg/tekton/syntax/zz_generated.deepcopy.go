@@ -98,6 +98,63 @@ func (in *Loop) DeepCopy() *Loop {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *Matrix) DeepCopyInto(out *Matrix) {
+	*out = *in
+	if in.Axes != nil {
+		in, out := &in.Axes, &out.Axes
+		*out = make([]MatrixAxis, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Exclude != nil {
+		in, out := &in.Exclude, &out.Exclude
+		*out = make([]map[string]string, len(*in))
+		for i := range *in {
+			if (*in)[i] != nil {
+				in, out := &(*in)[i], &(*out)[i]
+				*out = make(map[string]string, len(*in))
+				for key, val := range *in {
+					(*out)[key] = val
+				}
+			}
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new Matrix.
+func (in *Matrix) DeepCopy() *Matrix {
+	if in == nil {
+		return nil
+	}
+	out := new(Matrix)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MatrixAxis) DeepCopyInto(out *MatrixAxis) {
+	*out = *in
+	if in.Values != nil {
+		in, out := &in.Values, &out.Values
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MatrixAxis.
+func (in *MatrixAxis) DeepCopy() *MatrixAxis {
+	if in == nil {
+		return nil
+	}
+	out := new(MatrixAxis)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ParsedPipeline) DeepCopyInto(out *ParsedPipeline) {
 	*out = *in
@@ -365,6 +422,16 @@ func (in *Stage) DeepCopyInto(out *Stage) {
 		*out = new(string)
 		**out = **in
 	}
+	if in.Matrix != nil {
+		in, out := &in.Matrix, &out.Matrix
+		*out = new(Matrix)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.When != nil {
+		in, out := &in.When, &out.When
+		*out = new(When)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Environment != nil {
 		in, out := &in.Environment, &out.Environment
 		*out = make([]v1.EnvVar, len(*in))
@@ -540,3 +607,55 @@ func (in *Unstash) DeepCopy() *Unstash {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *When) DeepCopyInto(out *When) {
+	*out = *in
+	if in.Branch != nil {
+		in, out := &in.Branch, &out.Branch
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Changes != nil {
+		in, out := &in.Changes, &out.Changes
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Env != nil {
+		in, out := &in.Env, &out.Env
+		*out = make([]WhenEnv, len(*in))
+		copy(*out, *in)
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new When.
+func (in *When) DeepCopy() *When {
+	if in == nil {
+		return nil
+	}
+	out := new(When)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *WhenEnv) DeepCopyInto(out *WhenEnv) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new WhenEnv.
+func (in *WhenEnv) DeepCopy() *WhenEnv {
+	if in == nil {
+		return nil
+	}
+	out := new(WhenEnv)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -67,6 +67,20 @@ func LocalFileSystemSecretsDir() (string, error) {
 	return filepath.Join(home, "localSecrets"), nil
 }
 
+// SopsSecretsDir returns the directory of the SOPS encrypted secret files which can be overridden via the
+// $JX_SOPS_SECRETS_DIR environment variable
+func SopsSecretsDir() (string, error) {
+	path := os.Getenv("JX_SOPS_SECRETS_DIR")
+	if path != "" {
+		return path, nil
+	}
+	home, err := ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, "sopsSecrets"), nil
+}
+
 // KubeConfigFile gets the .kube/config file
 func KubeConfigFile() string {
 	path := os.Getenv("KUBECONFIG")
@@ -2,7 +2,9 @@ package util
 
 import (
 	"bytes"
-
+	"encoding/json"
+	"fmt"
+	"io"
 	"io/ioutil"
 	"net"
 	"net/http"
@@ -125,3 +127,66 @@ func GetBasicAuthUserAndPassword(auth string) (string, string) {
 	}
 	return "", ""
 }
+
+// HTTPStatusError is returned by DoJSON when the server responds with a status other than a 2xx
+type HTTPStatusError struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Body       string
+}
+
+// Error returns the status, request and response body
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("status %d from %s %s: %s", e.StatusCode, e.Method, e.URL, e.Body)
+}
+
+// DoJSON sends the body, if any, as JSON with the given headers, which override the default Accept and Content-Type
+// headers, and unmarshals the JSON response into result, if it isn't nil. Responses other than a 2xx are returned as
+// an *HTTPStatusError
+func DoJSON(client *http.Client, method string, u string, headers map[string]string, body interface{}, result interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return errors.Wrapf(err, "marshalling the request to %s", u)
+		}
+		reader = bytes.NewReader(data)
+	}
+	req, err := http.NewRequest(method, u, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return errors.Wrapf(err, "sending %s %s", method, u)
+	}
+	defer resp.Body.Close()
+	data, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return errors.Wrapf(err, "reading the response from %s", u)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &HTTPStatusError{
+			Method:     method,
+			URL:        u,
+			StatusCode: resp.StatusCode,
+			Body:       strings.TrimSpace(string(data)),
+		}
+	}
+	if result == nil || len(data) == 0 {
+		return nil
+	}
+	err = json.Unmarshal(data, result)
+	if err != nil {
+		return errors.Wrapf(err, "unmarshalling the response from %s", u)
+	}
+	return nil
+}
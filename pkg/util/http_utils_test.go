@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // transport for testing, using easy to spot values
@@ -75,3 +76,38 @@ func TestGetClient(t *testing.T) {
 	myClient2 := GetClient()
 	assert.Equal(t, myClient, myClient2)
 }
+
+func TestDoJSON(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("not found\n")) //nolint:errcheck
+			return
+		}
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/vnd.kafka.json.v2+json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		w.Write([]byte(`{"name":"cheese"}`)) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	headers := map[string]string{
+		"Content-Type":  "application/vnd.kafka.json.v2+json",
+		"Authorization": "Bearer secret",
+	}
+	result := struct {
+		Name string `json:"name"`
+	}{}
+	err := DoJSON(server.Client(), http.MethodPost, server.URL+"/things", headers, map[string]string{"name": "cheese"}, &result)
+	require.NoError(t, err)
+	assert.Equal(t, "cheese", result.Name)
+
+	err = DoJSON(server.Client(), http.MethodGet, server.URL+"/missing", nil, nil, nil)
+	require.Error(t, err)
+	statusErr, ok := err.(*HTTPStatusError)
+	require.True(t, ok, "expected an HTTPStatusError but got %#v", err)
+	assert.Equal(t, http.StatusNotFound, statusErr.StatusCode)
+	assert.Equal(t, "not found", statusErr.Body)
+}